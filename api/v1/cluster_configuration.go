@@ -17,7 +17,11 @@ limitations under the License.
 package v1
 
 import (
+	"fmt"
+
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
@@ -49,7 +53,17 @@ func (cluster *Cluster) GetSyncReplicasData() (syncReplicas int, electableSyncRe
 			"maxSyncReplicas", cluster.Spec.MaxSyncReplicas)
 	}
 
-	electableSyncReplicas = cluster.getElectableSyncReplicas()
+	// Lower to the number of desired replicas, so that the synchronous
+	// replication is relaxed as soon as the cluster is scaled down to
+	// the primary alone, and restored when replicas are scaled back up
+	if desiredReplicas := cluster.Spec.Instances - 1; desiredReplicas < syncReplicas {
+		syncReplicas = desiredReplicas
+		log.Info("Lowering sync replicas to the number of desired replicas",
+			"syncReplicas", desiredReplicas,
+			"instances", cluster.Spec.Instances)
+	}
+
+	electableSyncReplicas = cluster.filterSynchronousStandbyNames(cluster.getElectableSyncReplicas())
 	numberOfElectableSyncReplicas := len(electableSyncReplicas)
 	if numberOfElectableSyncReplicas < syncReplicas {
 		log.Warning("lowering sync replicas due to not enough electable instances for sync replication "+
@@ -63,11 +77,52 @@ func (cluster *Cluster) GetSyncReplicasData() (syncReplicas int, electableSyncRe
 	return syncReplicas, electableSyncReplicas
 }
 
+// GetSynchronousReplicationMethod gets the keyword to be used in the
+// "synchronous_standby_names" parameter, defaulting to quorum-based
+// synchronous replication
+func (cluster *Cluster) GetSynchronousReplicationMethod() string {
+	synchronous := cluster.Spec.PostgresConfiguration.Synchronous
+	if synchronous == nil || synchronous.Method == "" {
+		return SynchronousReplicaConfigurationMethodAny.ToPostgreSQLConfigurationKeyword()
+	}
+
+	return synchronous.Method.ToPostgreSQLConfigurationKeyword()
+}
+
+// HasSynchronousStandbyNames checks whether the user restricted the
+// synchronous standbys to an explicit list, in order of priority
+func (cluster *Cluster) HasSynchronousStandbyNames() bool {
+	synchronous := cluster.Spec.PostgresConfiguration.Synchronous
+	return synchronous != nil && len(synchronous.StandbyNames) > 0
+}
+
+// filterSynchronousStandbyNames restricts the electable sync replicas
+// to the ones listed by the user, if any, preserving the order of the list
+func (cluster *Cluster) filterSynchronousStandbyNames(electable []string) []string {
+	if !cluster.HasSynchronousStandbyNames() {
+		return electable
+	}
+
+	electableSet := stringset.From(electable)
+	var result []string
+	for _, name := range cluster.Spec.PostgresConfiguration.Synchronous.StandbyNames {
+		if electableSet.Has(name) {
+			result = append(result, name)
+		}
+	}
+
+	return result
+}
+
 // getElectableSyncReplicas computes the names of the instances that can be elected to sync replicas
 func (cluster *Cluster) getElectableSyncReplicas() []string {
 	var nonPrimaryInstances []string
 	for _, instance := range cluster.Status.InstancesStatus[utils.PodHealthy] {
-		if cluster.Status.CurrentPrimary != instance {
+		// Delayed and paused replicas would hold back every commit waiting
+		// for a remote apply, hence they are never chosen as sync replicas
+		if cluster.Status.CurrentPrimary != instance &&
+			cluster.GetInstanceMinApplyDelay(instance) == 0 &&
+			!cluster.IsInstanceReplayPaused(instance) {
 			nonPrimaryInstances = append(nonPrimaryInstances, instance)
 		}
 	}
@@ -119,3 +174,22 @@ func (cluster *Cluster) getElectableSyncReplicas() []string {
 
 	return electableReplicas
 }
+
+// GetStandbySettings computes the PostgreSQL settings that only apply to
+// a given instance when it is running as a standby
+func (cluster *Cluster) GetStandbySettings(instance string) map[string]string {
+	settings := make(map[string]string)
+
+	if cluster.Spec.PostgresConfiguration.HotStandbyFeedback != nil {
+		settings[postgres.HotStandbyFeedback] = "off"
+		if *cluster.Spec.PostgresConfiguration.HotStandbyFeedback {
+			settings[postgres.HotStandbyFeedback] = "on"
+		}
+	}
+
+	if minApplyDelay := cluster.GetInstanceMinApplyDelay(instance); minApplyDelay > 0 {
+		settings[postgres.RecoveryMinApplyDelay] = fmt.Sprintf("%ds", minApplyDelay)
+	}
+
+	return settings
+}
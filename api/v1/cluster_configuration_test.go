@@ -31,6 +31,49 @@ var _ = Describe("ensuring the correctness of synchronous replica data calculati
 		Expect(names).To(Equal([]string{"example-2", "example-3"}))
 	})
 
+	It("should never return the delayed replicas as electable", func() {
+		cluster := createFakeCluster("example")
+		cluster.Spec.DelayedReplicas = &DelayedReplicasConfiguration{
+			Instances:     []string{"example-3"},
+			MinApplyDelay: 3600,
+		}
+		number, names := cluster.GetSyncReplicasData()
+		Expect(number).To(Equal(1))
+		Expect(names).To(Equal([]string{"example-2"}))
+	})
+
+	It("should never return the replicas whose WAL replay is paused as electable", func() {
+		cluster := createFakeCluster("example")
+		cluster.Annotations = map[string]string{
+			utils.ReplayPausedInstancesAnnotation: `["example-2"]`,
+		}
+		number, names := cluster.GetSyncReplicasData()
+		Expect(number).To(Equal(1))
+		Expect(names).To(Equal([]string{"example-3"}))
+	})
+
+	It("should restrict the electable replicas to the listed standbys, in order of priority", func() {
+		cluster := createFakeCluster("example")
+		cluster.Spec.PostgresConfiguration.Synchronous = &SynchronousReplicaConfiguration{
+			Method:       SynchronousReplicaConfigurationMethodFirst,
+			StandbyNames: []string{"example-3", "example-4", "example-2"},
+		}
+		number, names := cluster.GetSyncReplicasData()
+		Expect(number).To(Equal(2))
+		Expect(names).To(Equal([]string{"example-3", "example-2"}))
+	})
+
+	It("should return the keyword of the synchronous replication method", func() {
+		cluster := createFakeCluster("example")
+		Expect(cluster.GetSynchronousReplicationMethod()).To(Equal("ANY"))
+
+		cluster.Spec.PostgresConfiguration.Synchronous = &SynchronousReplicaConfiguration{
+			Method: SynchronousReplicaConfigurationMethodFirst,
+		}
+		Expect(cluster.GetSynchronousReplicationMethod()).To(Equal("FIRST"))
+		Expect(cluster.HasSynchronousStandbyNames()).To(BeFalse())
+	})
+
 	It("should return only the pod in the different AZ", func() {
 		const (
 			primaryPod     = "example-1"
@@ -79,4 +122,48 @@ var _ = Describe("ensuring the correctness of synchronous replica data calculati
 		Expect(names).To(HaveLen(0))
 		Expect(cluster.Spec.MinSyncReplicas).To(Equal(1))
 	})
+
+	It("should disable the synchronous replication when the cluster has no replicas", func() {
+		cluster := createFakeCluster("example")
+		cluster.Spec.Instances = 1
+		number, _ := cluster.GetSyncReplicasData()
+		Expect(number).To(BeZero())
+
+		cluster.Spec.Instances = 3
+		number, _ = cluster.GetSyncReplicasData()
+		Expect(number).To(Equal(2))
+	})
+})
+
+var _ = Describe("standby settings", func() {
+	It("is empty when no standby setting is configured", func() {
+		cluster := createFakeCluster("example")
+		Expect(cluster.GetStandbySettings("example-2")).To(BeEmpty())
+	})
+
+	It("contains the hot standby feedback when configured", func() {
+		disabled := false
+		cluster := createFakeCluster("example")
+		cluster.Spec.PostgresConfiguration.HotStandbyFeedback = &disabled
+		Expect(cluster.GetStandbySettings("example-2")).To(Equal(map[string]string{
+			"hot_standby_feedback": "off",
+		}))
+	})
+
+	It("contains the apply delay only for the delayed replicas", func() {
+		enabled := true
+		cluster := createFakeCluster("example")
+		cluster.Spec.PostgresConfiguration.HotStandbyFeedback = &enabled
+		cluster.Spec.DelayedReplicas = &DelayedReplicasConfiguration{
+			Instances:     []string{"example-3"},
+			MinApplyDelay: 300,
+		}
+		Expect(cluster.GetStandbySettings("example-2")).To(Equal(map[string]string{
+			"hot_standby_feedback": "on",
+		}))
+		Expect(cluster.GetStandbySettings("example-3")).To(Equal(map[string]string{
+			"hot_standby_feedback":     "on",
+			"recovery_min_apply_delay": "300s",
+		}))
+	})
 })
@@ -24,6 +24,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
@@ -108,6 +109,50 @@ const (
 	PGBouncerPoolerUserName = "cnpg_pooler_pgbouncer"
 )
 
+// ReservedEnvironmentVariables are the environment variables set by the
+// operator in the PostgreSQL containers, that can't be set by the user
+var ReservedEnvironmentVariables = []string{
+	"PGDATA",
+	"POD_NAME",
+	"NAMESPACE",
+	"CLUSTER_NAME",
+	"PGPORT",
+	"PGHOST",
+	"POD_IP",
+}
+
+// ReservedVolumeNames are the names of the volumes created by the
+// operator in the PostgreSQL pods, that can't be used by the user
+var ReservedVolumeNames = []string{
+	"pgdata",
+	"pg-wal",
+	"scratch-data",
+	"shm",
+	"superuser-secret",
+	"app-secret",
+	"barman-endpoint-ca",
+}
+
+// ReservedVolumeNameSuffixes are the suffixes of the names of the volumes
+// created by the operator in the PostgreSQL pods and bootstrap jobs, that
+// can't be used by the user
+var ReservedVolumeNameSuffixes = []string{
+	"-post-init-application-sql",
+}
+
+// ReservedVolumeMountPaths are the paths where the operator mounts its
+// volumes in the PostgreSQL containers, that can't be used by the user
+var ReservedVolumeMountPaths = []string{
+	"/var/lib/postgresql/data",
+	"/var/lib/postgresql/wal",
+	"/run",
+	postgres.ScratchDataDirectory,
+	"/dev/shm",
+	"/etc/superuser-secret",
+	"/etc/app-secret",
+	"/etc/post-init-application-sql",
+}
+
 // ClusterSpec defines the desired state of Cluster
 type ClusterSpec struct {
 	// Description of this PostgreSQL cluster
@@ -123,12 +168,44 @@ type ClusterSpec struct {
 
 	// Image pull policy.
 	// One of `Always`, `Never` or `IfNotPresent`.
-	// If not defined, it defaults to `IfNotPresent`.
+	// If not defined, it defaults to `Always` when the image has the
+	// `latest` tag or no tag nor digest, and to `IfNotPresent` otherwise.
 	// Cannot be updated.
 	// More info: https://kubernetes.io/docs/concepts/containers/images#updating-images
 	// +optional
 	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
 
+	// Overrides the command of the `postgres` container, for images
+	// requiring a custom entrypoint. The command line, made by the command
+	// followed by the arguments, must still run the instance manager
+	// (`/controller/manager instance run`).
+	// Requires `acknowledgeCommandOverride` to be set to `true`.
+	// +optional
+	CommandOverride []string `json:"commandOverride,omitempty"`
+
+	// Arguments to be passed to the command of the `postgres` container.
+	// Requires `acknowledgeCommandOverride` to be set to `true`.
+	// +optional
+	ArgsOverride []string `json:"argsOverride,omitempty"`
+
+	// Acknowledges that overriding the command and the arguments of the
+	// `postgres` container is not supported, and the resulting instances
+	// may not work as expected
+	// +optional
+	AcknowledgeCommandOverride bool `json:"acknowledgeCommandOverride,omitempty"`
+
+	// Environment variables to be added to the `postgres` container, like
+	// the proxy settings. The variables set by the operator, like `PGDATA`,
+	// can't be overridden
+	// +optional
+	Env []corev1.EnvVar `json:"env,omitempty"`
+
+	// Sources of the environment variables to be added to the `postgres`
+	// container. The variables set by the operator take precedence
+	// over the ones defined here
+	// +optional
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
 	// The UID of the `postgres` user inside the image, defaults to `26`
 	// +kubebuilder:default:=26
 	PostgresUID int64 `json:"postgresUID,omitempty"`
@@ -137,6 +214,17 @@ type ClusterSpec struct {
 	// +kubebuilder:default:=26
 	PostgresGID int64 `json:"postgresGID,omitempty"`
 
+	// The fsGroup of the pods of the cluster, owning the mounted volumes.
+	// Defaults to the GID of the `postgres` user
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FSGroup *int64 `json:"fsGroup,omitempty"`
+
+	// The SeccompProfile applied to every Pod and Container of the cluster.
+	// Defaults to `RuntimeDefault`, when supported by the Kubernetes cluster
+	// +optional
+	SeccompProfile *corev1.SeccompProfile `json:"seccompProfile,omitempty"`
+
 	// Number of instances required in the cluster
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:default:=1
@@ -163,6 +251,10 @@ type ClusterSpec struct {
 	// Replication slots management configuration
 	ReplicationSlots *ReplicationSlotsConfiguration `json:"replicationSlots,omitempty"`
 
+	// Configuration of the replicas applying the WAL with a delay
+	// +optional
+	DelayedReplicas *DelayedReplicasConfiguration `json:"delayedReplicas,omitempty"`
+
 	// Instructions to bootstrap this cluster
 	// +optional
 	Bootstrap *BootstrapConfiguration `json:"bootstrap,omitempty"`
@@ -203,16 +295,54 @@ type ClusterSpec struct {
 	// Configuration of the storage for PostgreSQL WAL (Write-Ahead Log)
 	WalStorage *StorageConfiguration `json:"walStorage,omitempty"`
 
+	// Additional volumes to be added to the PostgreSQL pods, like the
+	// ones shared with a sidecar. Their names can't clash with the ones
+	// of the volumes managed by the operator
+	// +optional
+	AdditionalVolumes []corev1.Volume `json:"additionalVolumes,omitempty"`
+
+	// Additional volume mounts to be added to the `postgres` container,
+	// referring to the additional volumes. They can't be mounted onto the
+	// paths used by the operator, like the PostgreSQL data directory
+	// +optional
+	AdditionalVolumeMounts []corev1.VolumeMount `json:"additionalVolumeMounts,omitempty"`
+
+	// The policy applied to the PVCs that aren't used by any instance and
+	// are no longer needed by the cluster, e.g. after a scale down:
+	// `Delete` removes them, while `Retain` releases them from the cluster,
+	// removing the owner reference, so that they can be inspected and
+	// deleted manually
+	// +kubebuilder:validation:Enum:=Delete;Retain
+	// +kubebuilder:default:=Delete
+	// +optional
+	DanglingPVCPolicy DanglingPVCPolicy `json:"danglingPVCPolicy,omitempty"`
+
 	// The time in seconds that is allowed for a PostgreSQL instance to
 	// successfully start up (default 30)
 	// +kubebuilder:default:=30
 	MaxStartDelay int32 `json:"startDelay,omitempty"`
 
+	// The time in seconds that is allowed for the job bootstrapping the
+	// primary instance to complete, including the restore of a backup and
+	// the replay of the WAL files. By default there is no limit
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	BootstrapTimeout int64 `json:"bootstrapTimeout,omitempty"`
+
 	// The time in seconds that is allowed for a PostgreSQL instance to
 	// gracefully shutdown (default 30)
 	// +kubebuilder:default:=30
 	MaxStopDelay int32 `json:"stopDelay,omitempty"`
 
+	// The time in seconds, within the `stopDelay`, reserved to the smart
+	// shutdown of PostgreSQL, which waits for the clients to disconnect.
+	// When this time expires, a fast shutdown is requested.
+	// By default, the smart shutdown can last half of the `stopDelay` when
+	// the pod is deleted, and the whole `stopDelay` otherwise
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	SmartShutdownTimeout int32 `json:"smartShutdownTimeout,omitempty"`
+
 	// The time in seconds that is allowed for a primary PostgreSQL instance
 	// to gracefully shutdown during a switchover.
 	// Default value is 40000000, greater than one year in seconds,
@@ -220,16 +350,53 @@ type ClusterSpec struct {
 	// +kubebuilder:default:=40000000
 	MaxSwitchoverDelay int32 `json:"switchoverDelay,omitempty"`
 
+	// The configuration of the probes to be injected
+	// in the PostgreSQL Pods.
+	// +optional
+	Probes *ProbesConfiguration `json:"probes,omitempty"`
+
 	// Affinity/Anti-affinity rules for Pods
 	// +optional
 	Affinity AffinityConfiguration `json:"affinity,omitempty"`
 
+	// TopologySpreadConstraints specifies how to spread matching pods among the given topology.
+	// When the label selector of a constraint is not set, it defaults to
+	// the instances of the cluster.
+	// More info:
+	// https://kubernetes.io/docs/concepts/scheduling-eviction/topology-spread-constraints/
+	// +optional
+	TopologySpreadConstraints []corev1.TopologySpreadConstraint `json:"topologySpreadConstraints,omitempty"`
+
+	// Name of the priority class which will be used in every generated Pod.
+	// If the PriorityClass does not exist, the pods will not be able to be
+	// scheduled. Please refer to
+	// https://kubernetes.io/docs/concepts/scheduling-eviction/pod-priority-preemption/#priorityclass
+	// for more information.
+	// +optional
+	PriorityClassName string `json:"priorityClassName,omitempty"`
+
 	// Resources requirements of every generated Pod. Please refer to
 	// https://kubernetes.io/docs/concepts/configuration/manage-resources-containers/
 	// for more information.
 	// +optional
 	Resources corev1.ResourceRequirements `json:"resources,omitempty"`
 
+	// Resources requirements overriding the ones in `resources` for
+	// specific instances (e.g. a bigger replica used for reporting),
+	// keyed by instance name. Every resource listed in an override
+	// replaces the corresponding one in `resources`, while the others
+	// are inherited.
+	// +optional
+	ResourcesOverrides map[string]corev1.ResourceRequirements `json:"resourcesOverrides,omitempty"`
+
+	// Resources requirements of the jobs bootstrapping the instances, like
+	// the recovery from a backup or the logical import, which might need
+	// more memory than the running database. Every resource listed here
+	// replaces the corresponding one in `resources`, while the others are
+	// inherited.
+	// +optional
+	BootstrapResources *corev1.ResourceRequirements `json:"bootstrapResources,omitempty"`
+
 	// Strategy to follow to upgrade the primary server during a rolling
 	// update procedure, after all replicas have been successfully updated:
 	// it can be automated (`unsupervised` - default) or manual (`supervised`)
@@ -244,12 +411,42 @@ type ClusterSpec struct {
 	// +kubebuilder:validation:Enum:=switchover;restart
 	PrimaryUpdateMethod PrimaryUpdateMethod `json:"primaryUpdateMethod,omitempty"`
 
+	// Allow the operator to promote a replica when the primary instance
+	// isn't healthy. When configured as `true` (default setting), the
+	// operator automatically fails over to the most aligned replica.
+	// Setting it to `false` leaves the failover to an external
+	// orchestrator, while the other reconciliation activities proceed
+	// +optional
+	EnableAutomaticFailover *bool `json:"enableAutomaticFailover,omitempty"`
+
+	// An additional health check of the primary instance, detecting a
+	// primary which is running but not working as expected: when the
+	// check fails repeatedly, the primary is considered not healthy
+	// and the operator fails over to the most aligned replica
+	// +optional
+	PrimaryHealthCheck *PrimaryHealthCheckConfiguration `json:"primaryHealthCheck,omitempty"`
+
 	// The configuration to be used for backups
 	Backup *BackupConfiguration `json:"backup,omitempty"`
 
 	// Define a maintenance window for the Kubernetes nodes
 	NodeMaintenanceWindow *NodeMaintenanceWindow `json:"nodeMaintenanceWindow,omitempty"`
 
+	// Manage the `PodDisruptionBudget` resources within the cluster. When
+	// configured as `true` (default setting for clusters with more than one
+	// instance), the pod disruption budgets will safeguard the primary node
+	// from being terminated. Conversely, setting it to `false` (default
+	// setting for single instance clusters) will result in the absence of
+	// any `PodDisruptionBudget` resource, permitting the shutdown of all
+	// nodes hosting the PostgreSQL cluster.
+	// +optional
+	EnablePDB *bool `json:"enablePDB,omitempty"`
+
+	// The configuration of the `NetworkPolicy` restricting the access
+	// to the PostgreSQL port of the instances
+	// +optional
+	NetworkPolicy *NetworkPolicyConfiguration `json:"networkPolicy,omitempty"`
+
 	// The configuration of the monitoring infrastructure of this cluster
 	Monitoring *MonitoringConfiguration `json:"monitoring,omitempty"`
 
@@ -260,6 +457,10 @@ type ClusterSpec struct {
 	// +kubebuilder:default:=info
 	// +kubebuilder:validation:Enum:=error;warning;info;debug;trace
 	LogLevel string `json:"logLevel,omitempty"`
+
+	// The configuration that is used by the portions of PostgreSQL that are managed by the instance manager
+	// +optional
+	Managed *ManagedConfiguration `json:"managed,omitempty"`
 }
 
 const (
@@ -281,6 +482,10 @@ const (
 	// PhaseWaitingForUser set the status to wait for an action from the user
 	PhaseWaitingForUser = "Waiting for user action"
 
+	// PhaseWaitingForExternalFailover is set when the primary isn't healthy
+	// but the automatic failover has been disabled
+	PhaseWaitingForExternalFailover = "Waiting for an external failover"
+
 	// PhaseInplacePrimaryRestart for a cluster restarting the primary instance in-place
 	PhaseInplacePrimaryRestart = "Primary instance is being restarted in-place"
 
@@ -299,8 +504,36 @@ const (
 	// PhaseApplyingConfiguration is set by the instance manager when a configuration
 	// change is being detected
 	PhaseApplyingConfiguration = "Applying configuration"
+
+	// PhaseWaitingForFirstArchive is set at the end of the bootstrap, when the
+	// cluster is waiting for the first WAL file to be archived
+	PhaseWaitingForFirstArchive = "Waiting for the first WAL file to be archived"
+
+	// PhaseHibernating is set while the instances of a cluster whose
+	// hibernation was requested are being shut down
+	PhaseHibernating = "Cluster is being hibernated"
+
+	// PhaseHibernated is set when every instance of a cluster has been
+	// shut down because of the hibernation annotation
+	PhaseHibernated = "Cluster is hibernated"
+
+	// PhaseResumingFromHibernation is set when the hibernation annotation
+	// is removed and the instances of the cluster are being started again
+	PhaseResumingFromHibernation = "Resuming from hibernation"
+
+	// PhaseWaitingForUpgradeBackup is set while the backup requested
+	// before upgrading the instances to a new image is running
+	PhaseWaitingForUpgradeBackup = "Waiting for the backup preceding the upgrade"
 )
 
+// GetPrimaryRestartRequiredReason returns the reason of the
+// PhaseWaitingForUser phase, set when the user has to switch over or to
+// restart the passed primary instance to complete a supervised update
+func GetPrimaryRestartRequiredReason(primaryName, reason string) string {
+	return fmt.Sprintf("User must issue a supervised switchover or a restart of %s, because: %s",
+		primaryName, reason)
+}
+
 // ServiceAccountTemplate contains the template needed to generate the service accounts
 type ServiceAccountTemplate struct {
 	// Metadata are the metadata to be used for the generated
@@ -368,6 +601,11 @@ type ClusterStatus struct {
 	// The timeline of the Postgres cluster
 	TimelineID int `json:"timelineID,omitempty"`
 
+	// The most recent timeline switches leading to the current timeline
+	// of the Postgres cluster, as recorded in the timeline history files
+	// +optional
+	TimelineHistory []TimelineHistoryEntry `json:"timelineHistory,omitempty"`
+
 	// Instances topology.
 	Topology Topology `json:"topology,omitempty"`
 
@@ -409,6 +647,9 @@ type ClusterStatus struct {
 	// Current list of read pods
 	ReadService string `json:"readService,omitempty"`
 
+	// Current list of read-only pods, excluding the primary
+	ReadOnlyService string `json:"readOnlyService,omitempty"`
+
 	// Current phase of the cluster
 	Phase string `json:"phase,omitempty"`
 
@@ -442,6 +683,10 @@ type ClusterStatus struct {
 	// The timestamp when the last request for a new primary has occurred
 	TargetPrimaryTimestamp string `json:"targetPrimaryTimestamp,omitempty"`
 
+	// The timestamp when the read-write service has last been switched
+	// to the current primary
+	ReadWriteServiceTimestamp string `json:"readWriteServiceTimestamp,omitempty"`
+
 	// The integration needed by poolers referencing the cluster
 	PoolerIntegrations *PoolerIntegrations `json:"poolerIntegrations,omitempty"`
 
@@ -459,6 +704,37 @@ type ClusterStatus struct {
 
 	// List of instance names in the cluster
 	InstanceNames []string `json:"instanceNames,omitempty"`
+
+	// The timestamp when the outcome of the reconciliation loops of this
+	// cluster last changed, from success to failure, from failure to
+	// success, or to a different error
+	// +optional
+	LastReconcileOutcomeChangeTime string `json:"lastReconcileOutcomeChangeTime,omitempty"`
+
+	// The timestamp when the reconciliation loops of this cluster
+	// started completing without errors
+	// +optional
+	LastSuccessfulReconcileTime string `json:"lastSuccessfulReconcileTime,omitempty"`
+
+	// The timestamp when the reconciliation loops of this cluster
+	// started failing with the last reported error
+	// +optional
+	LastFailedReconcileTime string `json:"lastFailedReconcileTime,omitempty"`
+
+	// The error of the last failed reconciliation loop of this cluster
+	// +optional
+	LastReconcileError string `json:"lastReconcileError,omitempty"`
+
+	// The value of the restart annotation of the cluster once every
+	// instance has been restarted after it was set
+	// +optional
+	LastCompletedRestart string `json:"lastCompletedRestart,omitempty"`
+
+	// The errors raised while reconciling the managed databases,
+	// including the installation of their extensions, indexed by
+	// database name
+	// +optional
+	ManagedDatabasesErrors map[string]string `json:"managedDatabasesErrors,omitempty"`
 }
 
 // InstanceReportedState describes the last reported state of an instance during a reconciliation loop
@@ -467,6 +743,33 @@ type InstanceReportedState struct {
 	IsPrimary bool `json:"isPrimary"`
 	// indicates on which TimelineId the instance is
 	TimeLineID int `json:"timeLineID,omitempty"`
+	// indicates if the instance is waiting to be restarted to apply
+	// the changed PostgreSQL settings
+	// +optional
+	PendingRestart bool `json:"pendingRestart,omitempty"`
+	// the PostgreSQL settings waiting for an instance restart to be applied
+	// +optional
+	PendingRestartSettings []string `json:"pendingRestartSettings,omitempty"`
+	// the value of `recovery_min_apply_delay` in use by a replica instance
+	// +optional
+	RecoveryMinApplyDelay string `json:"recoveryMinApplyDelay,omitempty"`
+	// the status of the Pod running the instance: healthy,
+	// replicating or failed
+	// +optional
+	Phase utils.PodStatus `json:"phase,omitempty"`
+}
+
+// TimelineHistoryEntry describes a timeline switch of the Postgres cluster
+type TimelineHistoryEntry struct {
+	// The timeline created by the switch
+	TimelineID int `json:"timelineID"`
+	// The timeline from which the switch happened
+	ParentTimelineID int `json:"parentTimelineID"`
+	// The LSN at which the switch happened
+	SwitchPoint string `json:"switchPoint"`
+	// The reason of the switch, as recorded by PostgreSQL
+	// +optional
+	Reason string `json:"reason,omitempty"`
 }
 
 // ClusterConditionType defines types of cluster conditions
@@ -481,6 +784,18 @@ const (
 	ConditionBackup ClusterConditionType = "LastBackupSucceeded"
 	// ConditionClusterReady represents whether a cluster is Ready
 	ConditionClusterReady ClusterConditionType = "Ready"
+	// ConditionTimelineDivergence represents whether a replica is on a timeline
+	// that the primary has never reached, like in a split-brain scenario
+	ConditionTimelineDivergence ClusterConditionType = "TimelineDivergence"
+
+	// ConditionReconciliationDisabled represents whether the reconciliation
+	// loop of the cluster has been disabled through the
+	// `cnpg.io/reconciliationLoop` annotation
+	ConditionReconciliationDisabled ClusterConditionType = "ReconciliationDisabled"
+
+	// ConditionPostImportQueries represents whether the queries defined in
+	// `postImportApplicationSQL` were executed successfully after a logical import
+	ConditionPostImportQueries ClusterConditionType = "PostImportQueriesSucceeded"
 )
 
 // ConditionStatus defines conditions of resources
@@ -526,6 +841,30 @@ const (
 
 	// ClusterIsNotReady means that the condition changed because the cluster is not ready
 	ClusterIsNotReady ConditionReason = "ClusterIsNotReady"
+
+	// ConditionReasonTimelineDiverged means that the condition changed because at least
+	// one replica is on a timeline that diverged from the one of the primary
+	ConditionReasonTimelineDiverged ConditionReason = "TimelineDiverged"
+
+	// ConditionReasonTimelineAligned means that the condition changed because every
+	// replica is on a timeline that the primary has reached
+	ConditionReasonTimelineAligned ConditionReason = "TimelineAligned"
+
+	// ConditionReasonReconciliationLoopDisabled means that the condition changed because
+	// the reconciliation loop of the cluster has been disabled by the user
+	ConditionReasonReconciliationLoopDisabled ConditionReason = "ReconciliationLoopDisabled"
+
+	// ConditionReasonReconciliationLoopEnabled means that the condition changed because
+	// the reconciliation loop of the cluster has been enabled again
+	ConditionReasonReconciliationLoopEnabled ConditionReason = "ReconciliationLoopEnabled"
+
+	// ConditionReasonPostImportQueriesSucceeded means that the condition changed because
+	// the post import queries have been executed successfully
+	ConditionReasonPostImportQueriesSucceeded ConditionReason = "PostImportQueriesSucceeded"
+
+	// ConditionReasonPostImportQueriesFailed means that the condition changed because
+	// one of the post import queries failed
+	ConditionReasonPostImportQueriesFailed ConditionReason = "PostImportQueriesFailed"
 )
 
 // EmbeddedObjectMetadata contains metadata to be inherited by all resources related to a Cluster
@@ -559,6 +898,77 @@ type ReplicaClusterConfiguration struct {
 	Source string `json:"source"`
 }
 
+const (
+	// DefaultPrimaryHealthCheckPeriod is the default number of seconds
+	// between two primary health checks
+	DefaultPrimaryHealthCheckPeriod = 10
+
+	// DefaultPrimaryHealthCheckTimeout is the default number of seconds
+	// after which the primary health check query times out
+	DefaultPrimaryHealthCheckTimeout = 5
+
+	// DefaultPrimaryHealthCheckFailureThreshold is the default number of
+	// consecutive failures after which the primary is considered not healthy
+	DefaultPrimaryHealthCheckFailureThreshold = 3
+)
+
+// PrimaryHealthCheckConfiguration contains the configuration of the
+// additional health check the instance manager periodically runs
+// on the primary instance
+type PrimaryHealthCheckConfiguration struct {
+	// The query executed on the primary by the superuser. The check
+	// succeeds when the query returns a row with a single column set to `true`
+	// +kubebuilder:validation:MinLength=1
+	Query string `json:"query"`
+
+	// How often (in seconds) the check is executed (default 10)
+	// +kubebuilder:default:=10
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds int32 `json:"periodSeconds,omitempty"`
+
+	// The number of seconds after which the query times out, counting
+	// as a failure (default 5). It must be lower than `periodSeconds`
+	// +kubebuilder:default:=5
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds int32 `json:"timeoutSeconds,omitempty"`
+
+	// The number of consecutive failures after which the primary
+	// is considered not healthy (default 3)
+	// +kubebuilder:default:=3
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold int32 `json:"failureThreshold,omitempty"`
+}
+
+// GetPeriod returns the interval between two checks,
+// defaulting to DefaultPrimaryHealthCheckPeriod if empty
+func (r *PrimaryHealthCheckConfiguration) GetPeriod() time.Duration {
+	if r == nil || r.PeriodSeconds <= 0 {
+		return DefaultPrimaryHealthCheckPeriod * time.Second
+	}
+	return time.Duration(r.PeriodSeconds) * time.Second
+}
+
+// GetTimeout returns the timeout of the query,
+// defaulting to DefaultPrimaryHealthCheckTimeout if empty
+func (r *PrimaryHealthCheckConfiguration) GetTimeout() time.Duration {
+	if r == nil || r.TimeoutSeconds <= 0 {
+		return DefaultPrimaryHealthCheckTimeout * time.Second
+	}
+	return time.Duration(r.TimeoutSeconds) * time.Second
+}
+
+// GetFailureThreshold returns the number of consecutive failures making the
+// primary not healthy, defaulting to DefaultPrimaryHealthCheckFailureThreshold if empty
+func (r *PrimaryHealthCheckConfiguration) GetFailureThreshold() int {
+	if r == nil || r.FailureThreshold <= 0 {
+		return DefaultPrimaryHealthCheckFailureThreshold
+	}
+	return int(r.FailureThreshold)
+}
+
 // DefaultReplicationSlotsUpdateInterval is the default in seconds for the replication slots update interval
 const DefaultReplicationSlotsUpdateInterval = 30
 
@@ -586,6 +996,22 @@ func (r *ReplicationSlotsConfiguration) GetUpdateInterval() time.Duration {
 	return time.Duration(r.UpdateInterval) * time.Second
 }
 
+// DelayedReplicasConfiguration encapsulates the configuration of the
+// replicas that intentionally apply the WAL with a delay with respect
+// to the primary, i.e. setting the `recovery_min_apply_delay` PostgreSQL
+// parameter. A delayed replica can be used to recover from user errors,
+// like an accidentally dropped table, before they are replayed there.
+type DelayedReplicasConfiguration struct {
+	// The names of the instances that should apply the WAL with a delay
+	// +kubebuilder:validation:MinItems=1
+	Instances []string `json:"instances"`
+
+	// The minimum amount of time, in seconds, the delayed replicas wait
+	// before applying a transaction received from the primary
+	// +kubebuilder:validation:Minimum=1
+	MinApplyDelay int32 `json:"minApplyDelay"`
+}
+
 // ReplicationSlotsHAConfiguration encapsulates the configuration
 // of the replication slots that are automatically managed by
 // the operator to control the streaming replication connections
@@ -639,6 +1065,195 @@ func (r *ReplicationSlotsHAConfiguration) GetSlotNameFromInstanceName(instanceNa
 	return sanitizedName
 }
 
+// ManagedConfiguration represents the portions of PostgreSQL that are managed
+// by the instance manager
+type ManagedConfiguration struct {
+	// Database roles managed by the `Cluster`
+	// +optional
+	Roles []RoleConfiguration `json:"roles,omitempty"`
+
+	// Databases managed by the `Cluster`
+	// +optional
+	Databases []DatabaseConfiguration `json:"databases,omitempty"`
+
+	// Customizations of the services generated by the operator
+	// +optional
+	Services *ManagedServices `json:"services,omitempty"`
+}
+
+// ManagedServices contains the customizations of the services generated
+// by the operator for the `Cluster`
+type ManagedServices struct {
+	// Customizations of the read-write (`-rw`) service
+	// +optional
+	ReadWrite *ServiceTemplate `json:"rw,omitempty"`
+
+	// Customizations of the read-only (`-ro`) service
+	// +optional
+	ReadOnly *ServiceTemplate `json:"ro,omitempty"`
+
+	// Customizations of the read (`-r`) service
+	// +optional
+	Read *ServiceTemplate `json:"r,omitempty"`
+}
+
+// ServiceTemplate contains the customizations applied on top of a
+// service generated by the operator. The selector and the ports of the
+// service are always managed by the operator.
+type ServiceTemplate struct {
+	// Labels and annotations to be added to the service
+	// +optional
+	Metadata Metadata `json:"metadata,omitempty"`
+
+	// Type of the service, defaults to `ClusterIP`
+	// More info: https://kubernetes.io/docs/concepts/services-networking/service/#publishing-services-service-types
+	// +kubebuilder:validation:Enum:=ClusterIP;NodePort;LoadBalancer
+	// +optional
+	Type corev1.ServiceType `json:"type,omitempty"`
+}
+
+// MergeInto applies the customizations of the template to the passed
+// service
+func (st *ServiceTemplate) MergeInto(service *corev1.Service) {
+	if st == nil {
+		return
+	}
+	if service.Labels == nil {
+		service.Labels = map[string]string{}
+	}
+	if service.Annotations == nil {
+		service.Annotations = map[string]string{}
+	}
+
+	utils.MergeMap(service.Labels, st.Metadata.Labels)
+	utils.MergeMap(service.Annotations, st.Metadata.Annotations)
+	if st.Type != "" {
+		service.Spec.Type = st.Type
+	}
+}
+
+// EnsureOption represents whether we should enforce the presence or absence of
+// a Role in a PostgreSQL instance
+type EnsureOption string
+
+// values taken by EnsureOption
+const (
+	// EnsurePresent means the role must exist in the database
+	EnsurePresent EnsureOption = "present"
+
+	// EnsureAbsent means the role must not exist in the database
+	EnsureAbsent EnsureOption = "absent"
+)
+
+// RoleConfiguration is the representation, in Kubernetes, of a PostgreSQL role
+// with the additional field Ensure specifying whether to ensure the presence or
+// absence of the role in the database
+//
+// The defaults of the CREATE ROLE command are applied.
+// Reference: https://www.postgresql.org/docs/current/sql-createrole.html
+type RoleConfiguration struct {
+	// Name of the role
+	Name string `json:"name"`
+
+	// Ensure the role is `present` or `absent` - defaults to "present"
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+
+	// Secret containing the password of the role (if present).
+	// The secret must be of type `kubernetes.io/basic-auth` and its
+	// `username` must match the name of the role
+	// +optional
+	PasswordSecret *LocalObjectReference `json:"passwordSecret,omitempty"`
+
+	// If the role can log in, this specifies how many concurrent
+	// connections the role can make. `-1` (the default) means no limit.
+	// +kubebuilder:default:=-1
+	// +optional
+	ConnectionLimit *int64 `json:"connectionLimit,omitempty"`
+
+	// Whether the role is a `superuser` who can override all access
+	// restrictions within the database - superuser status is dangerous and
+	// should be used only when really needed. You must yourself be a
+	// superuser to create a new superuser. Defaults is `false`.
+	// +optional
+	Superuser bool `json:"superuser,omitempty"`
+
+	// When set to `true`, the role being defined will be allowed to create
+	// new databases. Specifying `false` (default) will deny a role the
+	// ability to create databases.
+	// +optional
+	CreateDB bool `json:"createdb,omitempty"`
+
+	// Whether the role is allowed to log in. A role having the `login`
+	// attribute can be thought of as a user. Roles without this attribute
+	// are useful for managing database privileges, but are not users in
+	// the usual sense of the word. Default is `false`.
+	// +optional
+	Login bool `json:"login,omitempty"`
+}
+
+// GetEnsure returns the expected state of the role, defaulting to EnsurePresent
+func (roleConfiguration *RoleConfiguration) GetEnsure() EnsureOption {
+	if roleConfiguration.Ensure == "" {
+		return EnsurePresent
+	}
+	return roleConfiguration.Ensure
+}
+
+// GetConnectionLimit returns the maximum number of concurrent connections
+// of the role, defaulting to -1, meaning no limit
+func (roleConfiguration *RoleConfiguration) GetConnectionLimit() int64 {
+	if roleConfiguration.ConnectionLimit == nil {
+		return -1
+	}
+	return *roleConfiguration.ConnectionLimit
+}
+
+// DatabaseConfiguration is the representation, in Kubernetes, of a
+// PostgreSQL database with the additional field Ensure specifying whether
+// to ensure the presence or absence of the database in the instance
+//
+// The defaults of the CREATE DATABASE command are applied.
+// Reference: https://www.postgresql.org/docs/current/sql-createdatabase.html
+type DatabaseConfiguration struct {
+	// Name of the database
+	Name string `json:"name"`
+
+	// Ensure the database is `present` or `absent` - defaults to "present"
+	// +kubebuilder:default:="present"
+	// +kubebuilder:validation:Enum=present;absent
+	// +optional
+	Ensure EnsureOption `json:"ensure,omitempty"`
+
+	// The role owning the database. It must be one of the managed roles
+	// or the owner of the application database, and is required when
+	// the database should be present. Changing it transfers the
+	// ownership of the database to the new role
+	// +optional
+	Owner string `json:"owner,omitempty"`
+
+	// The character set encoding to use in the database, defaulting to
+	// the one of the template database. It is only used when the database
+	// is created, and cannot be changed afterwards
+	// +optional
+	Encoding string `json:"encoding,omitempty"`
+
+	// The extensions to be installed in the database, when missing.
+	// Extensions removed from this list are not dropped
+	// +optional
+	Extensions []string `json:"extensions,omitempty"`
+}
+
+// GetEnsure returns the expected state of the database, defaulting to EnsurePresent
+func (databaseConfiguration *DatabaseConfiguration) GetEnsure() EnsureOption {
+	if databaseConfiguration.Ensure == "" {
+		return EnsurePresent
+	}
+	return databaseConfiguration.Ensure
+}
+
 // KubernetesUpgradeStrategy tells the operator if the user want to
 // allocate more space while upgrading a k8s node which is hosting
 // the PostgreSQL Pods or just wait for the node to come up
@@ -720,10 +1335,21 @@ type PostgresConfiguration struct {
 	// +optional
 	PgHBA []string `json:"pg_hba,omitempty"`
 
+	// PostgreSQL User Name Maps rules (lines to be appended
+	// to the pg_ident.conf file)
+	// +optional
+	PgIdent []string `json:"pg_ident,omitempty"`
+
 	// Requirements to be met by sync replicas. This will affect how the "synchronous_standby_names" parameter will be
 	// set up.
 	SyncReplicaElectionConstraint SyncReplicaElectionConstraints `json:"syncReplicaElectionConstraint,omitempty"`
 
+	// Configuration of the expression used for the "synchronous_standby_names"
+	// parameter. When not set, quorum-based synchronous replication is used
+	// among all the electable replicas
+	// +optional
+	Synchronous *SynchronousReplicaConfiguration `json:"synchronous,omitempty"`
+
 	// Specifies the maximum number of seconds to wait when promoting an instance to primary.
 	// Default value is 40000000, greater than one year in seconds,
 	// big enough to simulate an infinite timeout
@@ -737,8 +1363,92 @@ type PostgresConfiguration struct {
 	// Options to specify LDAP configuration
 	// +optional
 	LDAP *LDAPConfig `json:"ldap,omitempty"`
+
+	// The value of the `hot_standby_feedback` parameter, which is only
+	// set on the standby instances. When enabled, the standby instances
+	// send feedback to the primary about the queries they are running,
+	// preventing the removal of the rows they still need.
+	// When not set, the PostgreSQL default is used
+	// +optional
+	HotStandbyFeedback *bool `json:"hotStandbyFeedback,omitempty"`
+
+	// The value in seconds of the `wal_sender_timeout` parameter, after
+	// which the primary terminates an inactive replication connection.
+	// Zero disables the timeout. Defaults to 5 seconds
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	WalSenderTimeout *int32 `json:"walSenderTimeout,omitempty"`
+
+	// The value in seconds of the `wal_receiver_timeout` parameter, after
+	// which a standby terminates an inactive replication connection.
+	// Zero disables the timeout. Defaults to 5 seconds
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	WalReceiverTimeout *int32 `json:"walReceiverTimeout,omitempty"`
+
+	// The value in seconds of the `statement_timeout` parameter, after
+	// which any statement is aborted, to prevent runaway queries.
+	// Zero disables the timeout, which is the PostgreSQL default
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	StatementTimeout *int32 `json:"statementTimeout,omitempty"`
+
+	// The value in seconds of the `idle_in_transaction_session_timeout`
+	// parameter, after which a session idling within an open transaction
+	// is terminated, releasing its locks.
+	// Zero disables the timeout, which is the PostgreSQL default
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	IdleInTransactionSessionTimeout *int32 `json:"idleInTransactionSessionTimeout,omitempty"`
+
+	// The value of the `cluster_name` parameter, which identifies the
+	// cluster in the process titles of the PostgreSQL instances. It is
+	// especially useful in monitoring environments shared among many
+	// clusters. Defaults to the name of the `Cluster`.
+	// +kubebuilder:validation:MaxLength=63
+	// +optional
+	ClusterName string `json:"clusterName,omitempty"`
+
+	// The network interfaces PostgreSQL listens on for TCP/IP connections,
+	// translated by the operator into the `listen_addresses` parameter:
+	// `AllInterfaces` (default) or `PodIPOnly`, accepting connections only
+	// on the IP address of the pod
+	// +kubebuilder:validation:Enum=AllInterfaces;PodIPOnly
+	// +optional
+	ListenScope ListenScope `json:"listenScope,omitempty"`
+
+	// When enabled, the instance manager verifies the data checksums
+	// of the data directory with `pg_checksums --check` before starting
+	// PostgreSQL, refusing to start the instance when a corruption is
+	// detected. Requires data checksums to be enabled.
+	// Default: false.
+	// +optional
+	VerifyChecksumsOnStart bool `json:"verifyChecksumsOnStart,omitempty"`
+
+	// When enabled, the owner of the application database has to
+	// authenticate with a TLS client certificate, signed by the client CA
+	// of the cluster, when connecting to the application database.
+	// Client certificates can be issued with the `cnpg certificate`
+	// plugin command. As PgBouncer doesn't present the certificate of the
+	// application user, the connections through a Pooler are rejected.
+	// Default: false.
+	// +optional
+	ApplicationCertificateAuthentication bool `json:"applicationCertificateAuthentication,omitempty"`
 }
 
+// ListenScope defines the network interfaces PostgreSQL listens on
+type ListenScope string
+
+const (
+	// ListenOnAllInterfaces makes PostgreSQL listen on every network
+	// interface of the pod
+	ListenOnAllInterfaces ListenScope = "AllInterfaces"
+
+	// ListenOnPodIPOnly makes PostgreSQL listen only on the IP address
+	// of the pod
+	ListenOnPodIPOnly ListenScope = "PodIPOnly"
+)
+
 // BootstrapConfiguration contains information about how to create the PostgreSQL
 // cluster. Only a single bootstrap method can be defined among the supported
 // ones. `initdb` will be used as the bootstrap method if left
@@ -858,6 +1568,11 @@ type CertificatesStatus struct {
 
 	// Expiration dates for all certificates.
 	Expirations map[string]string `json:"expirations,omitempty"`
+
+	// The time when the first of the certificates managed by the
+	// operator will be renewed
+	// +optional
+	NextRenewal string `json:"nextRenewal,omitempty"`
 }
 
 // BootstrapInitDB is the configuration of the bootstrap process when
@@ -929,8 +1644,37 @@ type BootstrapInitDB struct {
 	// the implementation order is same as the order of each array
 	// (by default empty)
 	PostInitApplicationSQLRefs *PostInitApplicationSQLRefs `json:"postInitApplicationSQLRefs,omitempty"`
+
+	// When enabled, the cluster is not marked as healthy at the end of the
+	// bootstrap until the first WAL file has been successfully archived,
+	// making sure that continuous archiving works from the start.
+	// It requires backups to be configured (by default disabled)
+	// +optional
+	WaitForArchive bool `json:"waitForArchive,omitempty"`
+
+	// Refresh the planner statistics of the application database at the
+	// end of the bootstrap, after the data has been loaded through
+	// `postInitApplicationSQL`, `postInitApplicationSQLRefs` or `import`:
+	// `analyze` runs `ANALYZE`, while `vacuumAnalyze` runs `VACUUM ANALYZE`
+	// (by default disabled)
+	// +kubebuilder:validation:Enum=analyze;vacuumAnalyze
+	// +optional
+	PostInitAnalyze PostInitAnalyzeMode `json:"postInitAnalyze,omitempty"`
 }
 
+// PostInitAnalyzeMode is the command refreshing the planner statistics
+// at the end of the bootstrap
+type PostInitAnalyzeMode string
+
+const (
+	// PostInitAnalyzeModeAnalyze runs `ANALYZE` at the end of the bootstrap
+	PostInitAnalyzeModeAnalyze PostInitAnalyzeMode = "analyze"
+
+	// PostInitAnalyzeModeVacuumAnalyze runs `VACUUM ANALYZE` at the end
+	// of the bootstrap
+	PostInitAnalyzeModeVacuumAnalyze PostInitAnalyzeMode = "vacuumAnalyze"
+)
+
 // SnapshotType is a type of allowed import
 type SnapshotType string
 
@@ -957,9 +1701,22 @@ type Import struct {
 	// The roles to import
 	Roles []string `json:"roles,omitempty"`
 
+	// The roles that must not be imported, even if they are matched
+	// by the `roles` list. Only available in monolith type.
+	// +optional
+	ExcludedRoles []string `json:"excludedRoles,omitempty"`
+
+	// When set to true, the roles having the superuser attribute in the
+	// source instance are not imported, instead of being imported without it.
+	// Only available in monolith type.
+	// +optional
+	ExcludeSuperusers bool `json:"excludeSuperusers,omitempty"`
+
 	// List of SQL queries to be executed as a superuser in the application
 	// database right after is imported - to be used with extreme care
 	// (by default empty). Only available in microservice type.
+	// A failure doesn't stop the bootstrap, and is reported in the
+	// `PostImportQueriesSucceeded` condition of the cluster
 	PostImportApplicationSQL []string `json:"postImportApplicationSQL,omitempty"`
 }
 
@@ -1084,6 +1841,19 @@ type RecoveryTarget struct {
 	Exclusive *bool `json:"exclusive,omitempty"`
 }
 
+// DanglingPVCPolicy defines what happens to the PVCs that are
+// no longer needed by the cluster
+type DanglingPVCPolicy string
+
+const (
+	// DanglingPVCPolicyDelete deletes the PVCs that are no longer needed
+	DanglingPVCPolicyDelete DanglingPVCPolicy = "Delete"
+
+	// DanglingPVCPolicyRetain releases the PVCs that are no longer needed,
+	// keeping them in the namespace
+	DanglingPVCPolicyRetain DanglingPVCPolicy = "Retain"
+)
+
 // StorageConfiguration is the configuration of the storage of the PostgreSQL instances
 type StorageConfiguration struct {
 	// StorageClass to use for database data (`PGDATA`). Applied after
@@ -1122,6 +1892,213 @@ type SyncReplicaElectionConstraints struct {
 	NodeLabelsAntiAffinity []string `json:"nodeLabelsAntiAffinity,omitempty"`
 }
 
+// SynchronousReplicaConfigurationMethod configures how the synchronous
+// standbys are chosen among the listed ones
+type SynchronousReplicaConfigurationMethod string
+
+const (
+	// SynchronousReplicaConfigurationMethodFirst means that the synchronous
+	// standbys are chosen in order of priority among the listed ones
+	SynchronousReplicaConfigurationMethodFirst = SynchronousReplicaConfigurationMethod("first")
+
+	// SynchronousReplicaConfigurationMethodAny means that the synchronous
+	// replication is quorum-based, and any of the listed standbys can
+	// satisfy it
+	SynchronousReplicaConfigurationMethodAny = SynchronousReplicaConfigurationMethod("any")
+)
+
+// ToPostgreSQLConfigurationKeyword returns the keyword used in the
+// "synchronous_standby_names" parameter for this method
+func (s SynchronousReplicaConfigurationMethod) ToPostgreSQLConfigurationKeyword() string {
+	return strings.ToUpper(string(s))
+}
+
+// SynchronousReplicaConfiguration contains the configuration of the
+// expression used for the "synchronous_standby_names" parameter.
+// The number of synchronous standbys is still computed from
+// minSyncReplicas and maxSyncReplicas
+type SynchronousReplicaConfiguration struct {
+	// Method to select the synchronous standbys among the listed ones:
+	// `any` for quorum-based synchronous replication, `first` for
+	// priority-based synchronous replication
+	// +kubebuilder:validation:Enum=any;first
+	Method SynchronousReplicaConfigurationMethod `json:"method"`
+
+	// The names of the instances which can be chosen as synchronous
+	// standbys, in order of priority. Defaults to every electable replica
+	// +optional
+	StandbyNames []string `json:"standbyNames,omitempty"`
+}
+
+// NetworkPolicyConfiguration contains the configuration of the
+// `NetworkPolicy` generated by the operator
+type NetworkPolicyConfiguration struct {
+	// When enabled, the operator generates a `NetworkPolicy` allowing
+	// connections to the PostgreSQL port of the instances only from
+	// the other instances, the poolers, the operator and the listed
+	// clients
+	Enabled bool `json:"enabled"`
+
+	// The clients allowed to connect to the PostgreSQL port
+	// +optional
+	AllowedClients []NetworkPolicyClient `json:"allowedClients,omitempty"`
+}
+
+// NetworkPolicyClient selects a set of pods allowed to connect to the
+// PostgreSQL port of the instances. At least one selector is required
+type NetworkPolicyClient struct {
+	// Selects the namespaces of the allowed pods. When not set, only
+	// the pods in the namespace of the cluster are selected
+	// +optional
+	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// Selects the allowed pods. When not set, every pod in the selected
+	// namespaces is allowed
+	// +optional
+	PodSelector *metav1.LabelSelector `json:"podSelector,omitempty"`
+}
+
+// ProbesConfiguration represents the configuration for the probes
+// to be injected in the PostgreSQL Pods
+type ProbesConfiguration struct {
+	// The startup probe configuration. When set, a startup probe is
+	// added to the PostgreSQL container, deferring the liveness probe
+	// until the instance has started
+	// +optional
+	Startup *Probe `json:"startup,omitempty"`
+
+	// The liveness probe configuration
+	// +optional
+	Liveness *LivenessProbe `json:"liveness,omitempty"`
+
+	// The readiness probe configuration
+	// +optional
+	Readiness *Probe `json:"readiness,omitempty"`
+}
+
+// Probe describes the tuning of a probe generated by the operator.
+// Every field left unset keeps the value chosen by the operator
+type Probe struct {
+	// Number of seconds after the container has started before
+	// the probe is initiated
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	InitialDelaySeconds *int32 `json:"initialDelaySeconds,omitempty"`
+
+	// Number of seconds after which the probe times out
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds *int32 `json:"timeoutSeconds,omitempty"`
+
+	// How often (in seconds) to perform the probe
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	PeriodSeconds *int32 `json:"periodSeconds,omitempty"`
+
+	// Minimum consecutive failures for the probe to be considered failed
+	// after having succeeded
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	FailureThreshold *int32 `json:"failureThreshold,omitempty"`
+}
+
+// ApplyInto applies the tuning of this probe to a Kubernetes probe,
+// overriding only the fields that have been set
+func (p *Probe) ApplyInto(k8sProbe *corev1.Probe) {
+	if p == nil {
+		return
+	}
+
+	if p.InitialDelaySeconds != nil {
+		k8sProbe.InitialDelaySeconds = *p.InitialDelaySeconds
+	}
+	if p.TimeoutSeconds != nil {
+		k8sProbe.TimeoutSeconds = *p.TimeoutSeconds
+	}
+	if p.PeriodSeconds != nil {
+		k8sProbe.PeriodSeconds = *p.PeriodSeconds
+	}
+	if p.FailureThreshold != nil {
+		k8sProbe.FailureThreshold = *p.FailureThreshold
+	}
+}
+
+// DefaultIsolationCheckRequestTimeout is the default number of milliseconds
+// after which the request to the Kubernetes API server issued by the
+// isolation check times out
+const DefaultIsolationCheckRequestTimeout = 1000
+
+// DefaultLivenessProbeTimeoutSeconds is the default number of seconds after
+// which the liveness probe of the PostgreSQL container times out
+const DefaultLivenessProbeTimeoutSeconds = 5
+
+// LivenessProbe describes the tuning of the liveness probe of the
+// PostgreSQL container and how its failures are detected
+type LivenessProbe struct {
+	Probe `json:",inline"`
+
+	// Configure the check verifying whether the primary instance is
+	// isolated from the rest of the cluster, making the liveness probe
+	// fail when it is. This prevents an isolated primary from accepting
+	// writes while another instance gets promoted
+	// +optional
+	IsolationCheck *IsolationCheckConfiguration `json:"isolationCheck,omitempty"`
+}
+
+// GetProbe returns the tuning of the liveness probe, or nil
+// if it has not been set
+func (p *LivenessProbe) GetProbe() *Probe {
+	if p == nil {
+		return nil
+	}
+
+	return &p.Probe
+}
+
+// GetIsolationCheck returns the configuration of the isolation check,
+// or nil if it has not been set
+func (p *LivenessProbe) GetIsolationCheck() *IsolationCheckConfiguration {
+	if p == nil {
+		return nil
+	}
+
+	return p.IsolationCheck
+}
+
+// IsolationCheckConfiguration contains the configuration of the check
+// detecting whether the primary instance is isolated from the Kubernetes
+// API server and from its replicas
+type IsolationCheckConfiguration struct {
+	// Whether the isolation check is enabled. When enabled, the liveness
+	// probe of the primary instance fails if the instance can reach
+	// neither the Kubernetes API server nor any of its replicas
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Timeout in milliseconds of the request to the Kubernetes API
+	// server issued by the isolation check. It must be lower than the
+	// timeout of the liveness probe
+	// +kubebuilder:default:=1000
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RequestTimeout int `json:"requestTimeout,omitempty"`
+}
+
+// IsEnabled checks whether the isolation check is enabled
+func (c *IsolationCheckConfiguration) IsEnabled() bool {
+	return c != nil && c.Enabled
+}
+
+// GetRequestTimeout gets the timeout of the request to the
+// Kubernetes API server issued by the isolation check
+func (c *IsolationCheckConfiguration) GetRequestTimeout() time.Duration {
+	if c == nil || c.RequestTimeout <= 0 {
+		return DefaultIsolationCheckRequestTimeout * time.Millisecond
+	}
+
+	return time.Duration(c.RequestTimeout) * time.Millisecond
+}
+
 // AffinityConfiguration contains the info we need to create the
 // affinity rules for Pods
 type AffinityConfiguration struct {
@@ -1147,6 +2124,14 @@ type AffinityConfiguration struct {
 	// +optional
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 
+	// DedicatedNodesTaintKey is the key of a taint (and of a node label with
+	// the same name) used to mark the nodes dedicated to PostgreSQL.
+	// When set, the operator tolerates that taint and requires the pods
+	// to be scheduled on the nodes having that label.
+	// More info: https://kubernetes.io/docs/concepts/scheduling-eviction/taint-and-toleration/
+	// +optional
+	DedicatedNodesTaintKey string `json:"dedicatedNodesTaintKey,omitempty"`
+
 	// PodAntiAffinityType allows the user to decide whether pod anti-affinity between cluster instance has to be
 	// considered a strong requirement during scheduling or not. Allowed values are: "preferred" (default if empty) or
 	// "required". Setting it to "required", could lead to instances remaining pending until new kubernetes nodes are
@@ -1268,6 +2253,13 @@ type BarmanObjectStoreConfiguration struct {
 	// HistoryTags is a list of key value pairs that will be passed to the
 	// Barman --history-tags option.
 	HistoryTags map[string]string `json:"historyTags,omitempty"`
+
+	// The AWS KMS key used to encrypt the base backups and the WAL files
+	// whose encryption is set to `aws:kms`. It can be specified using the
+	// key ID, the key ARN or any alias or alias ARN, and is passed to the
+	// Barman --sse-kms-key-id option. Requires `s3Credentials`
+	// +optional
+	SSEKMSKeyID string `json:"sseKmsKeyId,omitempty"`
 }
 
 // BackupConfiguration defines how the backup of the cluster are taken.
@@ -1278,15 +2270,67 @@ type BackupConfiguration struct {
 	// The configuration for the barman-cloud tool suite
 	BarmanObjectStore *BarmanObjectStoreConfiguration `json:"barmanObjectStore,omitempty"`
 
+	// The custom commands used to archive the WAL files, and to restore
+	// them on the replicas, in place of the barman-cloud tool suite.
+	// Incompatible with `barmanObjectStore`
+	// +optional
+	CustomWalArchive *CustomWalArchiveConfiguration `json:"customWalArchive,omitempty"`
+
 	// RetentionPolicy is the retention policy to be used for backups
 	// and WALs (i.e. '60d'). The retention policy is expressed in the form
-	// of `XXu` where `XX` is a positive integer and `u` is in `[dwm]` -
-	// days, weeks, months.
-	// +kubebuilder:validation:Pattern=^[1-9][0-9]*[dwm]$
+	// of `XXu` where `XX` is a positive integer and `u` is in `[dwmb]` -
+	// days, weeks, months, or the number of backups to keep.
+	// +kubebuilder:validation:Pattern=^[1-9][0-9]*[dwmb]$
 	// +optional
 	RetentionPolicy string `json:"retentionPolicy,omitempty"`
+
+	// The policy to decide which instance should perform backups. Available
+	// options are empty string, which will default to `prefer-standby` policy,
+	// `primary` to have backups run always on primary instances, `prefer-standby`
+	// to have backups run preferably on a ready standby, if available.
+	// +kubebuilder:validation:Enum=primary;prefer-standby
+	// +kubebuilder:default:=prefer-standby
+	// +optional
+	Target BackupTarget `json:"target,omitempty"`
+
+	// When enabled, the operator takes a backup of the cluster before
+	// upgrading the instances to a new PostgreSQL image, and proceeds
+	// with the upgrade only when the backup is completed.
+	// Requires `barmanObjectStore`
+	// +optional
+	BeforeUpgrade bool `json:"beforeUpgrade,omitempty"`
+}
+
+// CustomWalArchiveConfiguration contains the commands used to archive and
+// restore the WAL files. Every command is executed directly, without a shell,
+// from the PGDATA directory of the instance. Like in the PostgreSQL
+// `archive_command` and `restore_command` options, any `%p` in the arguments
+// is replaced by the path of the WAL file, any `%f` by its name and `%%`
+// by a single `%` character
+type CustomWalArchiveConfiguration struct {
+	// The command archiving a WAL file. It must exit with a zero status
+	// only when the WAL file has been successfully archived
+	// +kubebuilder:validation:MinItems=1
+	ArchiveCommand []string `json:"archiveCommand"`
+
+	// The command restoring a WAL file on the replicas. It must exit with a
+	// zero status only when the WAL file has been restored in `%p`.
+	// If not specified, the replicas will only rely on streaming replication
+	// +optional
+	RestoreCommand []string `json:"restoreCommand,omitempty"`
 }
 
+// BackupTarget describes the preferred targets for a backup
+type BackupTarget string
+
+const (
+	// BackupTargetPrimary means backups will be performed on the primary instance
+	BackupTargetPrimary = BackupTarget("primary")
+
+	// BackupTargetStandby means backups will be performed on a standby instance if available
+	BackupTargetStandby = BackupTarget("prefer-standby")
+)
+
 // WalBackupConfiguration is the configuration of the backup of the
 // WAL stream
 type WalBackupConfiguration struct {
@@ -1424,6 +2468,21 @@ type MonitoringConfiguration struct {
 	// Enable or disable the `PodMonitor`
 	// +kubebuilder:default:=false
 	EnablePodMonitor bool `json:"enablePodMonitor,omitempty"`
+
+	// Configure TLS communication for the metrics endpoint
+	// +optional
+	TLSConfig *ClusterMonitoringTLSConfiguration `json:"tls,omitempty"`
+}
+
+// ClusterMonitoringTLSConfiguration is the type containing the TLS configuration
+// for the cluster's monitoring
+type ClusterMonitoringTLSConfiguration struct {
+	// Enable TLS for the monitoring endpoint, using the server
+	// certificate of the cluster.
+	// Changing this option will force a rollout of all instances.
+	// +kubebuilder:default:=false
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
 }
 
 // AreDefaultQueriesDisabled checks whether default monitoring queries should be disabled
@@ -1477,6 +2536,7 @@ func (in ExternalCluster) GetServerName() string {
 // +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.readyInstances",description="Number of ready instances"
 // +kubebuilder:printcolumn:name="Status",type="string",JSONPath=".status.phase",description="Cluster current status"
 // +kubebuilder:printcolumn:name="Primary",type="string",JSONPath=".status.currentPrimary",description="Primary pod"
+// +kubebuilder:printcolumn:name="Target Primary",type="string",JSONPath=".status.targetPrimary",description="Pod about to become primary",priority=1
 
 // Cluster is the Schema for the PostgreSQL API
 type Cluster struct {
@@ -1534,6 +2594,11 @@ type SecretsResourceVersion struct {
 	// A map with the versions of all the secrets used to pass metrics.
 	// Map keys are the secret names, map values are the versions
 	Metrics map[string]string `json:"metrics,omitempty"`
+
+	// A map with the versions of all the secrets used to pass the
+	// passwords of the managed roles.
+	// Map keys are the secret names, map values are the versions
+	ManagedRoleSecretVersions map[string]string `json:"managedRoleSecretVersion,omitempty"`
 }
 
 // ConfigMapResourceVersion is the resource versions of the secrets
@@ -1554,6 +2619,23 @@ func (cluster *Cluster) GetImageName() string {
 	return configuration.Current.PostgresImageName
 }
 
+// GetImagePullPolicy gets the pull policy of the PostgreSQL image. When not
+// set, images referenced with a digest or with a tag other than "latest" are
+// pulled only if not present on the node, and the others are always pulled,
+// following the same rules of Kubernetes
+func (cluster *Cluster) GetImagePullPolicy() corev1.PullPolicy {
+	if cluster.Spec.ImagePullPolicy != "" {
+		return cluster.Spec.ImagePullPolicy
+	}
+
+	reference := utils.NewReference(cluster.GetImageName())
+	if reference.Digest == "" && reference.Tag == "latest" {
+		return corev1.PullAlways
+	}
+
+	return corev1.PullIfNotPresent
+}
+
 // GetPostgresqlVersion gets the PostgreSQL image version detecting it from the
 // image name.
 // Example:
@@ -1819,6 +2901,40 @@ func (cluster *Cluster) GetPgCtlTimeoutForPromotion() int32 {
 	return timeout
 }
 
+// GetManagedServices gets the customizations of the services generated by
+// the operator
+func (cluster *Cluster) GetManagedServices() ManagedServices {
+	if cluster.Spec.Managed == nil || cluster.Spec.Managed.Services == nil {
+		return ManagedServices{}
+	}
+	return *cluster.Spec.Managed.Services
+}
+
+// IsPodDisruptionBudgetEnabled check if the operator should manage the
+// PodDisruptionBudgets of this cluster. Unless requested, they are not
+// managed in single instance clusters, where they would block the drain
+// of the node running the primary, except during a maintenance window
+// not reusing the PVCs, where draining the node would lose the only instance
+func (cluster *Cluster) IsPodDisruptionBudgetEnabled() bool {
+	if cluster.Spec.EnablePDB == nil {
+		return cluster.Spec.Instances > 1 ||
+			(cluster.IsNodeMaintenanceWindowInProgress() && !cluster.IsReusePVCEnabled())
+	}
+	return *cluster.Spec.EnablePDB
+}
+
+// IsNetworkPolicyEnabled checks whether the operator should generate
+// a NetworkPolicy restricting the access to the instances
+func (cluster *Cluster) IsNetworkPolicyEnabled() bool {
+	return cluster.Spec.NetworkPolicy != nil && cluster.Spec.NetworkPolicy.Enabled
+}
+
+// IsAutomaticFailoverEnabled check if the operator should promote a
+// replica when the primary instance isn't healthy
+func (cluster *Cluster) IsAutomaticFailoverEnabled() bool {
+	return cluster.Spec.EnableAutomaticFailover == nil || *cluster.Spec.EnableAutomaticFailover
+}
+
 // IsReusePVCEnabled check if in a maintenance window we should reuse PVCs
 func (cluster *Cluster) IsReusePVCEnabled() bool {
 	reusePVC := true
@@ -1828,6 +2944,15 @@ func (cluster *Cluster) IsReusePVCEnabled() bool {
 	return reusePVC
 }
 
+// GetDanglingPVCPolicy gets the policy applied to the PVCs that are no
+// longer needed by the cluster, defaulting to their deletion
+func (cluster *Cluster) GetDanglingPVCPolicy() DanglingPVCPolicy {
+	if cluster.Spec.DanglingPVCPolicy == "" {
+		return DanglingPVCPolicyDelete
+	}
+	return cluster.Spec.DanglingPVCPolicy
+}
+
 // IsInstanceFenced check if in a given instance should be fenced
 func (cluster *Cluster) IsInstanceFenced(instance string) bool {
 	fencedInstances, err := utils.GetFencedInstances(cluster.Annotations)
@@ -1841,6 +2966,93 @@ func (cluster *Cluster) IsInstanceFenced(instance string) bool {
 	return fencedInstances.Has(instance)
 }
 
+// IsInstanceReplayPaused check if the WAL replay should be paused
+// on a given instance
+func (cluster *Cluster) IsInstanceReplayPaused(instance string) bool {
+	replayPausedInstances, err := utils.GetReplayPausedInstances(cluster.Annotations)
+	if err != nil {
+		return false
+	}
+
+	return replayPausedInstances.Has(instance)
+}
+
+// GetInstanceResources gets the resource requirements of a given
+// instance, merging its override, if any, with the ones of the cluster
+func (cluster *Cluster) GetInstanceResources(instance string) corev1.ResourceRequirements {
+	override, ok := cluster.Spec.ResourcesOverrides[instance]
+	if !ok {
+		return *cluster.Spec.Resources.DeepCopy()
+	}
+
+	return mergeResourceRequirements(cluster.Spec.Resources, override)
+}
+
+// GetBootstrapResources gets the resource requirements of the jobs
+// bootstrapping the instances, merging the bootstrap ones, if any,
+// with the ones of the cluster
+func (cluster *Cluster) GetBootstrapResources() corev1.ResourceRequirements {
+	if cluster.Spec.BootstrapResources == nil {
+		return *cluster.Spec.Resources.DeepCopy()
+	}
+
+	return mergeResourceRequirements(cluster.Spec.Resources, *cluster.Spec.BootstrapResources)
+}
+
+// mergeResourceRequirements returns a copy of the passed resource
+// requirements where every resource listed in the override replaces
+// the corresponding one
+func mergeResourceRequirements(
+	resources corev1.ResourceRequirements,
+	override corev1.ResourceRequirements,
+) corev1.ResourceRequirements {
+	result := *resources.DeepCopy()
+
+	if len(override.Requests) > 0 && result.Requests == nil {
+		result.Requests = make(corev1.ResourceList, len(override.Requests))
+	}
+	for name, quantity := range override.Requests {
+		result.Requests[name] = quantity.DeepCopy()
+	}
+
+	if len(override.Limits) > 0 && result.Limits == nil {
+		result.Limits = make(corev1.ResourceList, len(override.Limits))
+	}
+	for name, quantity := range override.Limits {
+		result.Limits[name] = quantity.DeepCopy()
+	}
+
+	return result
+}
+
+// IsTimelineDiverged checks if a replica running on the passed timeline
+// diverged from the primary running on primaryTimelineID, which never
+// reached that timeline. This is a symptom of a split-brain or of a wrong
+// rejoin of a former primary. The timeline of the primary is not known
+// in replica clusters
+func (cluster *Cluster) IsTimelineDiverged(primaryTimelineID, timelineID int) bool {
+	return !cluster.IsReplica() &&
+		primaryTimelineID > 0 &&
+		timelineID > primaryTimelineID
+}
+
+// GetInstanceMinApplyDelay gets the minimum amount of time, in seconds,
+// after which a given instance should apply the WAL received from the
+// primary. Zero is returned if the instance is not a delayed replica
+func (cluster *Cluster) GetInstanceMinApplyDelay(instance string) int32 {
+	if cluster.Spec.DelayedReplicas == nil {
+		return 0
+	}
+
+	for _, name := range cluster.Spec.DelayedReplicas.Instances {
+		if name == instance {
+			return cluster.Spec.DelayedReplicas.MinApplyDelay
+		}
+	}
+
+	return 0
+}
+
 // ShouldResizeInUseVolumes is true when we should resize PVC we already
 // created
 func (cluster *Cluster) ShouldResizeInUseVolumes() bool {
@@ -1965,6 +3177,31 @@ func (cluster *Cluster) ShouldRecoveryCreateApplicationDatabase() bool {
 	return recoveryParameters.Owner != "" && recoveryParameters.Database != ""
 }
 
+// GetBackupTarget returns the instance where the backups should be
+// preferably taken, defaulting to BackupTargetStandby
+func (cluster *Cluster) GetBackupTarget() BackupTarget {
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.Target == "" {
+		return BackupTargetStandby
+	}
+	return cluster.Spec.Backup.Target
+}
+
+// ShouldWaitForFirstArchive returns whether the cluster has been bootstrapped
+// requiring the first WAL file to be archived, and it has not been archived yet
+func (cluster *Cluster) ShouldWaitForFirstArchive() bool {
+	if cluster.Spec.Bootstrap == nil ||
+		cluster.Spec.Bootstrap.InitDB == nil ||
+		!cluster.Spec.Bootstrap.InitDB.WaitForArchive {
+		return false
+	}
+
+	if !cluster.Spec.Backup.IsWalArchivingConfigured() {
+		return false
+	}
+
+	return !meta.IsStatusConditionTrue(cluster.Status.Conditions, string(ConditionContinuousArchiving))
+}
+
 // ShouldCreateWalArchiveVolume returns whether we should create the wal archive volume
 func (cluster *Cluster) ShouldCreateWalArchiveVolume() bool {
 	return cluster.Spec.WalStorage != nil
@@ -2058,6 +3295,15 @@ func (cluster *Cluster) GetClusterAltDNSNames() []string {
 	return append(defaultAltDNSNames, cluster.Spec.Certificates.ServerAltDNSNames...)
 }
 
+// GetPostgresClusterName gets the value of the `cluster_name` PostgreSQL
+// parameter, defaulting to the name of the Cluster
+func (cluster *Cluster) GetPostgresClusterName() string {
+	if cluster.Spec.PostgresConfiguration.ClusterName != "" {
+		return cluster.Spec.PostgresConfiguration.ClusterName
+	}
+	return cluster.Name
+}
+
 // UsesSecret checks whether a given secret is used by a Cluster.
 //
 // This function is also used to discover the set of clusters that
@@ -2066,6 +3312,9 @@ func (cluster *Cluster) UsesSecret(secret string) bool {
 	if _, ok := cluster.Status.SecretsResourceVersion.Metrics[secret]; ok {
 		return true
 	}
+	if _, ok := cluster.Status.SecretsResourceVersion.ManagedRoleSecretVersions[secret]; ok {
+		return true
+	}
 	certificates := cluster.Status.Certificates
 	switch secret {
 	case cluster.GetSuperuserSecretName(),
@@ -2113,6 +3362,15 @@ func (cluster *Cluster) IsPodMonitorEnabled() bool {
 	return false
 }
 
+// IsMetricsTLSEnabled checks if the metrics endpoint should use TLS
+func (cluster *Cluster) IsMetricsTLSEnabled() bool {
+	if cluster.Spec.Monitoring != nil && cluster.Spec.Monitoring.TLSConfig != nil {
+		return cluster.Spec.Monitoring.TLSConfig.Enabled
+	}
+
+	return false
+}
+
 // LogTimestampsWithMessage prints useful information about timestamps in stdout
 func (cluster *Cluster) LogTimestampsWithMessage(ctx context.Context, logMessage string) {
 	contextLogger := log.FromContext(ctx)
@@ -2123,6 +3381,7 @@ func (cluster *Cluster) LogTimestampsWithMessage(ctx context.Context, logMessage
 		"currentTimestamp", currentTimestamp,
 		"targetPrimaryTimestamp", cluster.Status.TargetPrimaryTimestamp,
 		"currentPrimaryTimestamp", cluster.Status.CurrentPrimaryTimestamp,
+		"readWriteServiceTimestamp", cluster.Status.ReadWriteServiceTimestamp,
 	}
 
 	var errs []string
@@ -2186,6 +3445,20 @@ func (backupConfiguration *BackupConfiguration) IsBarmanBackupConfigured() bool
 		backupConfiguration.BarmanObjectStore.BarmanCredentials.ArePopulated()
 }
 
+// IsWalArchivingConfigured returns true if the WAL files are archived, either
+// in the barman object store or with the custom WAL archive commands
+func (backupConfiguration *BackupConfiguration) IsWalArchivingConfigured() bool {
+	return backupConfiguration != nil &&
+		(backupConfiguration.BarmanObjectStore != nil || backupConfiguration.CustomWalArchive != nil)
+}
+
+// ShouldBackupBeforeUpgrade returns true if a backup has to be completed
+// before upgrading the instances to a new PostgreSQL image
+func (backupConfiguration *BackupConfiguration) ShouldBackupBeforeUpgrade() bool {
+	return backupConfiguration != nil && backupConfiguration.BeforeUpgrade &&
+		backupConfiguration.BarmanObjectStore != nil
+}
+
 // IsBarmanEndpointCASet returns true if we have a CA bundle for the endpoint
 // false otherwise
 func (backupConfiguration *BackupConfiguration) IsBarmanEndpointCASet() bool {
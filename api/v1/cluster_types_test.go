@@ -17,6 +17,10 @@ limitations under the License.
 package v1
 
 import (
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
@@ -99,6 +103,55 @@ var _ = Describe("Primary update strategy", func() {
 	})
 })
 
+var _ = Describe("PodDisruptionBudget management", func() {
+	It("is enabled by default in clusters with replicas", func() {
+		cluster := Cluster{Spec: ClusterSpec{Instances: 3}}
+		Expect(cluster.IsPodDisruptionBudgetEnabled()).To(BeTrue())
+	})
+
+	It("is disabled by default in single instance clusters", func() {
+		cluster := Cluster{Spec: ClusterSpec{Instances: 1}}
+		Expect(cluster.IsPodDisruptionBudgetEnabled()).To(BeFalse())
+	})
+
+	It("protects single instance clusters during a maintenance window not reusing the PVCs", func() {
+		reusePVC := false
+		cluster := Cluster{Spec: ClusterSpec{
+			Instances:             1,
+			NodeMaintenanceWindow: &NodeMaintenanceWindow{InProgress: true, ReusePVC: &reusePVC},
+		}}
+		Expect(cluster.IsPodDisruptionBudgetEnabled()).To(BeTrue())
+
+		reusePVC = true
+		Expect(cluster.IsPodDisruptionBudgetEnabled()).To(BeFalse())
+	})
+
+	It("can be explicitly enabled or disabled", func() {
+		trueVal := true
+		falseVal := false
+		cluster := Cluster{Spec: ClusterSpec{Instances: 1, EnablePDB: &trueVal}}
+		Expect(cluster.IsPodDisruptionBudgetEnabled()).To(BeTrue())
+		cluster.Spec.EnablePDB = &falseVal
+		Expect(cluster.IsPodDisruptionBudgetEnabled()).To(BeFalse())
+	})
+})
+
+var _ = Describe("Automatic failover", func() {
+	It("is enabled by default", func() {
+		cluster := Cluster{}
+		Expect(cluster.IsAutomaticFailoverEnabled()).To(BeTrue())
+	})
+
+	It("can be explicitly enabled or disabled", func() {
+		trueVal := true
+		falseVal := false
+		cluster := Cluster{Spec: ClusterSpec{EnableAutomaticFailover: &trueVal}}
+		Expect(cluster.IsAutomaticFailoverEnabled()).To(BeTrue())
+		cluster.Spec.EnableAutomaticFailover = &falseVal
+		Expect(cluster.IsAutomaticFailoverEnabled()).To(BeFalse())
+	})
+})
+
 var _ = Describe("Node maintenance window", func() {
 	It("default maintenance not in progress", func() {
 		cluster := Cluster{}
@@ -736,6 +789,142 @@ var _ = Describe("Barman Endpoint CA for replica cluster", func() {
 	})
 })
 
+var _ = Describe("Replay paused instances annotation", func() {
+	It("detects when the WAL replay of an instance should be paused", func() {
+		cluster := Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.ReplayPausedInstancesAnnotation: "[\"two\"]",
+				},
+			},
+		}
+		Expect(cluster.IsInstanceReplayPaused("two")).To(BeTrue())
+		Expect(cluster.IsInstanceReplayPaused("three")).To(BeFalse())
+	})
+
+	It("doesn't pause any instance when the annotation is invalid", func() {
+		cluster := Cluster{
+			ObjectMeta: v1.ObjectMeta{
+				Annotations: map[string]string{
+					utils.ReplayPausedInstancesAnnotation: "two",
+				},
+			},
+		}
+		Expect(cluster.IsInstanceReplayPaused("two")).To(BeFalse())
+	})
+})
+
+var _ = Describe("Instance resources", func() {
+	cluster := Cluster{
+		Spec: ClusterSpec{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1"),
+					corev1.ResourceMemory: resource.MustParse("1Gi"),
+				},
+			},
+			ResourcesOverrides: map[string]corev1.ResourceRequirements{
+				"cluster-example-3": {
+					Requests: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("8Gi"),
+					},
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("8Gi"),
+					},
+				},
+			},
+		},
+	}
+
+	It("uses the cluster resources when there is no override", func() {
+		Expect(cluster.GetInstanceResources("cluster-example-1")).To(Equal(cluster.Spec.Resources))
+	})
+
+	It("merges the override with the cluster resources", func() {
+		resources := cluster.GetInstanceResources("cluster-example-3")
+		Expect(resources.Requests.Cpu().String()).To(Equal("1"))
+		Expect(resources.Requests.Memory().String()).To(Equal("8Gi"))
+		Expect(resources.Limits.Memory().String()).To(Equal("8Gi"))
+		Expect(cluster.Spec.Resources.Requests.Memory().String()).To(Equal("1Gi"))
+		Expect(cluster.Spec.Resources.Limits).To(BeNil())
+	})
+})
+
+var _ = Describe("Bootstrap resources", func() {
+	resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("1Gi"),
+		},
+	}
+
+	It("uses the cluster resources when the bootstrap ones are not set", func() {
+		cluster := Cluster{Spec: ClusterSpec{Resources: resources}}
+		Expect(cluster.GetBootstrapResources()).To(Equal(resources))
+	})
+
+	It("merges the bootstrap resources with the cluster ones", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Resources: resources,
+				BootstrapResources: &corev1.ResourceRequirements{
+					Limits: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("4Gi"),
+					},
+				},
+			},
+		}
+		bootstrapResources := cluster.GetBootstrapResources()
+		Expect(bootstrapResources.Requests.Cpu().String()).To(Equal("1"))
+		Expect(bootstrapResources.Requests.Memory().String()).To(Equal("1Gi"))
+		Expect(bootstrapResources.Limits.Memory().String()).To(Equal("4Gi"))
+		Expect(cluster.Spec.Resources.Limits).To(BeNil())
+	})
+})
+
+var _ = Describe("Timeline divergence", func() {
+	It("detects the replicas on a timeline never reached by the primary", func() {
+		cluster := Cluster{}
+		Expect(cluster.IsTimelineDiverged(3, 2)).To(BeFalse())
+		Expect(cluster.IsTimelineDiverged(3, 3)).To(BeFalse())
+		Expect(cluster.IsTimelineDiverged(3, 4)).To(BeTrue())
+	})
+
+	It("doesn't detect any divergence if the primary timeline is not known", func() {
+		cluster := Cluster{}
+		Expect(cluster.IsTimelineDiverged(0, 4)).To(BeFalse())
+	})
+
+	It("doesn't detect any divergence in a replica cluster", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{Enabled: true, Source: "origin"},
+			},
+		}
+		Expect(cluster.IsTimelineDiverged(3, 4)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Delayed replicas", func() {
+	It("returns the apply delay only for the delayed replicas", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				DelayedReplicas: &DelayedReplicasConfiguration{
+					Instances:     []string{"two"},
+					MinApplyDelay: 600,
+				},
+			},
+		}
+		Expect(cluster.GetInstanceMinApplyDelay("two")).To(BeEquivalentTo(600))
+		Expect(cluster.GetInstanceMinApplyDelay("three")).To(BeZero())
+	})
+
+	It("doesn't delay any instance when not configured", func() {
+		cluster := Cluster{}
+		Expect(cluster.GetInstanceMinApplyDelay("two")).To(BeZero())
+	})
+})
+
 var _ = Describe("Fencing annotation", func() {
 	When("one instance is fenced", func() {
 		cluster := Cluster{
@@ -843,3 +1032,112 @@ var _ = Describe("Replication slots names for instances", func() {
 			"_232_test_cluster_example_1"))
 	})
 })
+
+var _ = Describe("Waiting for the first WAL file to be archived", func() {
+	newCluster := func(waitForArchive bool) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						WaitForArchive: waitForArchive,
+					},
+				},
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://bucket/",
+					},
+				},
+			},
+		}
+	}
+
+	It("does not wait when not requested", func() {
+		Expect(newCluster(false).ShouldWaitForFirstArchive()).To(BeFalse())
+	})
+
+	It("does not wait when backups are not configured", func() {
+		cluster := newCluster(true)
+		cluster.Spec.Backup = nil
+		Expect(cluster.ShouldWaitForFirstArchive()).To(BeFalse())
+	})
+
+	It("waits until continuous archiving is working", func() {
+		cluster := newCluster(true)
+		Expect(cluster.ShouldWaitForFirstArchive()).To(BeTrue())
+
+		cluster.Status.Conditions = []v1.Condition{
+			{
+				Type:   string(ConditionContinuousArchiving),
+				Status: v1.ConditionFalse,
+			},
+		}
+		Expect(cluster.ShouldWaitForFirstArchive()).To(BeTrue())
+
+		cluster.Status.Conditions[0].Status = v1.ConditionTrue
+		Expect(cluster.ShouldWaitForFirstArchive()).To(BeFalse())
+	})
+})
+
+var _ = Describe("Liveness probe isolation check", func() {
+	It("is disabled unless requested", func() {
+		var liveness *LivenessProbe
+		Expect(liveness.GetProbe()).To(BeNil())
+		Expect(liveness.GetIsolationCheck().IsEnabled()).To(BeFalse())
+
+		timeoutSeconds := int32(10)
+		liveness = &LivenessProbe{Probe: Probe{TimeoutSeconds: &timeoutSeconds}}
+		Expect(*liveness.GetProbe().TimeoutSeconds).To(BeEquivalentTo(10))
+		Expect(liveness.GetIsolationCheck().IsEnabled()).To(BeFalse())
+
+		liveness.IsolationCheck = &IsolationCheckConfiguration{Enabled: true}
+		Expect(liveness.GetIsolationCheck().IsEnabled()).To(BeTrue())
+	})
+
+	It("uses the default request timeout when not set", func() {
+		var isolationCheck *IsolationCheckConfiguration
+		Expect(isolationCheck.GetRequestTimeout()).To(Equal(time.Second))
+
+		isolationCheck = &IsolationCheckConfiguration{Enabled: true, RequestTimeout: 2500}
+		Expect(isolationCheck.GetRequestTimeout()).To(Equal(2500 * time.Millisecond))
+	})
+})
+
+var _ = Describe("Image pull policy", func() {
+	DescribeTable("computes the default pull policy from the image name",
+		func(imageName string, expected corev1.PullPolicy) {
+			cluster := Cluster{Spec: ClusterSpec{ImageName: imageName}}
+			Expect(cluster.GetImagePullPolicy()).To(Equal(expected))
+		},
+		Entry("image without tag", "postgres", corev1.PullAlways),
+		Entry("image with the latest tag", "ghcr.io/cloudnative-pg/postgresql:latest", corev1.PullAlways),
+		Entry("image with a version tag", "ghcr.io/cloudnative-pg/postgresql:15.2", corev1.PullIfNotPresent),
+		Entry("image with a digest",
+			"ghcr.io/cloudnative-pg/postgresql@sha256:"+
+				"3f0a5716a8b03c4a3d7a8ba5d8d4b5b1a4bcd9e8a9e1f4a76c9c0cfa2b5d1e2f",
+			corev1.PullIfNotPresent),
+		Entry("image with the latest tag and a digest",
+			"ghcr.io/cloudnative-pg/postgresql:latest@sha256:"+
+				"3f0a5716a8b03c4a3d7a8ba5d8d4b5b1a4bcd9e8a9e1f4a76c9c0cfa2b5d1e2f",
+			corev1.PullIfNotPresent),
+	)
+
+	It("uses the pull policy chosen by the user", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName:       "ghcr.io/cloudnative-pg/postgresql:15.2",
+				ImagePullPolicy: corev1.PullAlways,
+			},
+		}
+		Expect(cluster.GetImagePullPolicy()).To(Equal(corev1.PullAlways))
+	})
+
+	It("doesn't store the default pull policy while defaulting", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:15.2",
+			},
+		}
+		cluster.Default()
+		Expect(cluster.Spec.ImagePullPolicy).To(BeEmpty())
+	})
+})
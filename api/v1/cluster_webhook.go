@@ -17,12 +17,17 @@ limitations under the License.
 package v1
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	admissionv1 "k8s.io/api/admission/v1"
 	v1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -34,6 +39,7 @@ import (
 	"k8s.io/utils/strings/slices"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -60,13 +66,63 @@ const (
 // clusterLog is for logging in this package.
 var clusterLog = log.WithName("cluster-resource").WithValues("version", "v1")
 
+// clusterValidatingWebhookPath is the path of the validating webhook for clusters
+const clusterValidatingWebhookPath = "/validate-postgresql-cnpg-io-v1-cluster"
+
+// maxRecommendedInstances is the number of instances above which the
+// admission webhook warns the user
+const maxRecommendedInstances = 10
+
 // SetupWebhookWithManager setup the webhook inside the controller manager
 func (r *Cluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	// The validating webhook is registered here, instead of leaving
+	// it to controller-runtime, to be able to return warnings too
+	validatingWebhook := &webhook.Admission{
+		Handler: &clusterWarningsHandler{validator: admission.ValidatingWebhookFor(r).Handler},
+	}
+	mgr.GetWebhookServer().Register(clusterValidatingWebhookPath, validatingWebhook.WithRecoverPanic(true))
+
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
 		Complete()
 }
 
+// clusterWarningsHandler wraps the validating webhook for clusters,
+// adding the warnings about the accepted clusters to the responses
+type clusterWarningsHandler struct {
+	validator admission.Handler
+	decoder   *admission.Decoder
+}
+
+var _ admission.DecoderInjector = &clusterWarningsHandler{}
+
+// InjectDecoder injects the decoder into the handler and into the wrapped one
+func (h *clusterWarningsHandler) InjectDecoder(decoder *admission.Decoder) error {
+	h.decoder = decoder
+	_, err := admission.InjectDecoderInto(decoder, h.validator)
+	return err
+}
+
+// Handle handles admission requests
+func (h *clusterWarningsHandler) Handle(ctx context.Context, req admission.Request) admission.Response {
+	response := h.validator.Handle(ctx, req)
+	if !response.Allowed || req.Operation == admissionv1.Delete {
+		return response
+	}
+
+	var cluster Cluster
+	if err := h.decoder.DecodeRaw(req.Object, &cluster); err != nil {
+		// The object has already been decoded by the validator,
+		// so this should never happen
+		return response
+	}
+
+	if warnings := cluster.getAdmissionWarnings(); len(warnings) > 0 {
+		return response.WithWarnings(warnings...)
+	}
+	return response
+}
+
 // +kubebuilder:webhook:webhookVersions={v1},admissionReviewVersions={v1},path=/mutate-postgresql-cnpg-io-v1-cluster,mutating=true,failurePolicy=fail,groups=postgresql.cnpg.io,resources=clusters,verbs=create;update,versions=v1,name=mcluster.kb.io,sideEffects=None
 
 var _ webhook.Defaulter = &Cluster{}
@@ -110,10 +166,12 @@ func (r *Cluster) setDefaults(preserveUserSettings bool) {
 		r.Spec.Affinity.PodAntiAffinityType = PodAntiAffinityTypePreferred
 	}
 
+	// The default values of the parameters depend on the PostgreSQL version.
+	// When it can't be detected, like when the image is referenced only by
+	// its digest, the defaults are applied by the instance manager, which
+	// reads the version from the PostgreSQL executable
 	psqlVersion, err := r.GetPostgresqlVersion()
 	if err == nil {
-		// The validation error will be already raised by the
-		// validateImageName function
 		info := postgres.ConfigurationInfo{
 			Settings:                      postgres.CnpgConfigurationSettings,
 			MajorVersion:                  psqlVersion,
@@ -254,6 +312,8 @@ var _ webhook.Validator = &Cluster{}
 func (r *Cluster) ValidateCreate() error {
 	clusterLog.Info("validate create", "name", r.Name, "namespace", r.Namespace)
 	allErrs := r.Validate()
+	allErrs = append(allErrs, r.validateMetadata(nil)...)
+	allErrs = append(allErrs, r.validateRecoveryTargetPresence()...)
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -263,6 +323,27 @@ func (r *Cluster) ValidateCreate() error {
 		r.Name, allErrs)
 }
 
+// getAdmissionWarnings groups the checks that don't prevent the cluster
+// from being accepted, but whose results should be reported to the user
+func (r *Cluster) getAdmissionWarnings() (warnings []string) {
+	type warningFunc func() []string
+	checks := []warningFunc{
+		r.getMaxSyncReplicasTopologyWarnings,
+		r.getMinSyncReplicasWarnings,
+		r.getSingleInstanceSyncReplicasWarnings,
+		r.getEvenInstancesWarnings,
+		r.getManyInstancesWarnings,
+		r.getInitDBOptionsWarnings,
+		r.getDurabilityWarnings,
+	}
+
+	for _, check := range checks {
+		warnings = append(warnings, check()...)
+	}
+
+	return warnings
+}
+
 // Validate groups the validation logic for clusters returning a list of all encountered errors
 func (r *Cluster) Validate() (allErrs field.ErrorList) {
 	type validationFunc func() field.ErrorList
@@ -275,24 +356,61 @@ func (r *Cluster) Validate() (allErrs field.ErrorList) {
 		r.validateCerts,
 		r.validateBootstrapMethod,
 		r.validateImageName,
+		r.validateCommandOverride,
 		r.validateImagePullPolicy,
 		r.validateRecoveryTarget,
 		r.validatePrimaryUpdateStrategy,
+		r.validateAutomaticFailover,
 		r.validateMinSyncReplicas,
 		r.validateMaxSyncReplicas,
 		r.validateStorageSize,
 		r.validateWalStorageSize,
+		r.validateStorageConfiguration,
 		r.validateName,
 		r.validateBootstrapPgBaseBackupSource,
 		r.validateBootstrapRecoverySource,
+		r.validateBootstrapImportSource,
 		r.validateExternalClusters,
 		r.validateTolerations,
+		r.validateDedicatedNodesTaintKey,
 		r.validateAntiAffinity,
 		r.validateReplicaMode,
 		r.validateBackupConfiguration,
 		r.validateConfiguration,
 		r.validateLDAP,
 		r.validateReplicationSlots,
+		r.validateManagedRoles,
+		r.validateManagedDatabases,
+		r.validateReplayPausedInstances,
+		r.validateFencedInstances,
+		r.validateDelayedReplicas,
+		r.validateSmartShutdownTimeout,
+		r.validateHotStandbyFeedback,
+		r.validateReplicationTimeouts,
+		r.validateSessionTimeouts,
+		r.validateSynchronousReplicaConfiguration,
+		r.validateHibernationAnnotation,
+		r.validateNetworkPolicy,
+		r.validateImagePullSecrets,
+		r.validateResourcesOverrides,
+		r.validateBootstrapResources,
+		r.validateEnv,
+		r.validateChecksumsVerification,
+		r.validateApplicationCertificateAuthentication,
+		r.validateMetricsTLS,
+		r.validateListenScope,
+		r.validateDanglingPVCPolicy,
+		r.validateCustomWalArchive,
+		r.validatePrimaryHealthCheck,
+		r.validateBackupBeforeUpgrade,
+		r.validateBackupEncryption,
+		r.validateBarmanEndpointCA,
+		r.validatePgHBA,
+		r.validatePgIdent,
+		r.validateInstances,
+		r.validateSecurityContext,
+		r.validateLivenessIsolationCheck,
+		r.validateAdditionalVolumes,
 	}
 
 	for _, validate := range validations {
@@ -338,7 +456,9 @@ func (r *Cluster) ValidateChanges(old *Cluster) (allErrs field.ErrorList) {
 	allErrs = append(allErrs, r.validateWalStorageChange(old)...)
 	allErrs = append(allErrs, r.validateReplicaModeChange(old)...)
 	allErrs = append(allErrs, r.validateUnixPermissionIdentifierChange(old)...)
+	allErrs = append(allErrs, r.validateInitDBChange(old)...)
 	allErrs = append(allErrs, r.validateReplicationSlotsChange(old)...)
+	allErrs = append(allErrs, r.validateMetadata(old)...)
 	return allErrs
 }
 
@@ -406,6 +526,17 @@ func (r *Cluster) validateInitDB() field.ErrorList {
 				"WAL segment size must be a power of 2"))
 	}
 
+	if initDBOptions.WaitForArchive && !r.Spec.Backup.IsWalArchivingConfigured() {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "initdb", "waitForArchive"),
+				initDBOptions.WaitForArchive,
+				"Waiting for the first WAL file to be archived requires backups to be configured"))
+	}
+
+	result = append(result, r.validatePostInitAnalyze()...)
+
 	if initDBOptions.PostInitApplicationSQLRefs != nil {
 		for _, item := range initDBOptions.PostInitApplicationSQLRefs.SecretRefs {
 			if item.Name == "" || item.Key == "" {
@@ -486,6 +617,26 @@ func (s Import) validateMicroservice() field.ErrorList {
 		)
 	}
 
+	if len(s.ExcludedRoles) != 0 {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "initdb", "import", "excludedRoles"),
+				s.ExcludedRoles,
+				"You cannot specify roles to exclude for the `microservice` import type"),
+		)
+	}
+
+	if s.ExcludeSuperusers {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "initdb", "import", "excludeSuperusers"),
+				s.ExcludeSuperusers,
+				"excludeSuperusers is not allowed for the `microservice` import type"),
+		)
+	}
+
 	if len(s.Databases) == 1 && strings.Contains(s.Databases[0], "*") {
 		result = append(
 			result,
@@ -532,6 +683,33 @@ func (s Import) validateMonolith() field.ErrorList {
 		)
 	}
 
+	for idx, role := range s.ExcludedRoles {
+		rolePath := field.NewPath("spec", "bootstrap", "initdb", "import", "excludedRoles").Index(idx)
+		switch {
+		case role == "" || strings.Contains(role, "*"):
+			result = append(
+				result,
+				field.Invalid(
+					rolePath,
+					role,
+					"Excluded roles must be role names, wildcards are not allowed"),
+			)
+		case slices.Contains(s.Roles, role):
+			result = append(
+				result,
+				field.Invalid(
+					rolePath,
+					role,
+					"A role cannot be both imported and excluded"),
+			)
+		case slices.Index(s.ExcludedRoles, role) != idx:
+			result = append(
+				result,
+				field.Duplicate(rolePath, role),
+			)
+		}
+	}
+
 	if len(s.PostImportApplicationSQL) > 0 {
 		result = append(
 			result,
@@ -583,6 +761,41 @@ func (r *Cluster) validatePgBaseBackupApplicationDatabase() field.ErrorList {
 		"pg_basebackup")
 }
 
+// validatePostInitAnalyze checks that the planner statistics are refreshed
+// only when the bootstrap loads some data in the application database
+func (r *Cluster) validatePostInitAnalyze() field.ErrorList {
+	initDBOptions := r.Spec.Bootstrap.InitDB
+	path := field.NewPath("spec", "bootstrap", "initdb", "postInitAnalyze")
+
+	switch initDBOptions.PostInitAnalyze {
+	case "":
+		return nil
+	case PostInitAnalyzeModeAnalyze, PostInitAnalyzeModeVacuumAnalyze:
+	default:
+		return field.ErrorList{
+			field.NotSupported(
+				path,
+				initDBOptions.PostInitAnalyze,
+				[]string{string(PostInitAnalyzeModeAnalyze), string(PostInitAnalyzeModeVacuumAnalyze)}),
+		}
+	}
+
+	loadsData := len(initDBOptions.PostInitApplicationSQL) > 0 ||
+		initDBOptions.PostInitApplicationSQLRefs != nil ||
+		initDBOptions.Import != nil
+	if !loadsData {
+		return field.ErrorList{
+			field.Invalid(
+				path,
+				initDBOptions.PostInitAnalyze,
+				"refreshing the planner statistics requires loading data in the application database "+
+					"through postInitApplicationSQL, postInitApplicationSQLRefs or import"),
+		}
+	}
+
+	return nil
+}
+
 // validateApplicationDatabase validate the configuration for application database
 func (r *Cluster) validateApplicationDatabase(database string, owner string, command string,
 ) field.ErrorList {
@@ -741,14 +954,60 @@ func (r *Cluster) validateBootstrapRecoverySource() field.ErrorList {
 		return result
 	}
 
-	_, found := r.ExternalCluster(r.Spec.Bootstrap.Recovery.Source)
-	if !found {
+	externalCluster, found := r.ExternalCluster(r.Spec.Bootstrap.Recovery.Source)
+	switch {
+	case !found:
 		result = append(
 			result,
 			field.Invalid(
 				field.NewPath("spec", "bootstrap", "recovery", "source"),
 				r.Spec.Bootstrap.Recovery.Source,
 				fmt.Sprintf("External cluster %v not found", r.Spec.Bootstrap.Recovery.Source)))
+	case externalCluster.BarmanObjectStore == nil:
+		// The recovery restores a base backup from the object store, a
+		// streaming connection to the source is only used by pg_basebackup
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "recovery", "source"),
+				r.Spec.Bootstrap.Recovery.Source,
+				fmt.Sprintf("External cluster %v has no barmanObjectStore to recover from, "+
+					"use the pg_basebackup bootstrap method to clone it via streaming",
+					r.Spec.Bootstrap.Recovery.Source)))
+	}
+
+	return result
+}
+
+// validateBootstrapImportSource is used to ensure that the source
+// server of a logical import is correctly defined
+func (r *Cluster) validateBootstrapImportSource() field.ErrorList {
+	var result field.ErrorList
+
+	// This validation is only applicable for the import of the databases
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.InitDB == nil || r.Spec.Bootstrap.InitDB.Import == nil {
+		return result
+	}
+
+	sourceName := r.Spec.Bootstrap.InitDB.Import.Source.ExternalCluster
+	externalCluster, found := r.ExternalCluster(sourceName)
+	switch {
+	case !found:
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "initdb", "import", "source", "externalCluster"),
+				sourceName,
+				fmt.Sprintf("External cluster %v not found", sourceName)))
+	case len(externalCluster.ConnectionParameters) == 0:
+		// pg_dump connects to the source via libpq, a backup in an
+		// object store cannot be used for a logical import
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "bootstrap", "initdb", "import", "source", "externalCluster"),
+				sourceName,
+				fmt.Sprintf("External cluster %v has no connectionParameters to import from", sourceName)))
 	}
 
 	return result
@@ -774,12 +1033,10 @@ func (r *Cluster) validateImageName() field.ErrorList {
 				r.Spec.ImageName,
 				"Can't use 'latest' as image tag as we can't detect upgrades"))
 	case "":
-		result = append(
-			result,
-			field.Invalid(
-				field.NewPath("spec", "imageName"),
-				r.Spec.ImageName,
-				"Can't use just the image sha as we can't detect upgrades"))
+		// The image is referenced only by its digest, and we can't detect
+		// the PostgreSQL version from it: the instance manager will read it
+		// from the PostgreSQL executable, and the version checks are skipped
+		return result
 	default:
 		_, err := postgres.GetPostgresVersionFromTag(tag)
 		if err != nil {
@@ -794,6 +1051,86 @@ func (r *Cluster) validateImageName() field.ErrorList {
 	return result
 }
 
+// instanceManagerCommandLine is the command line running the instance manager
+var instanceManagerCommandLine = []string{"/controller/manager", "instance", "run"}
+
+// MetricsPortTLSOption is the option of the instance manager serving
+// the metrics over TLS
+const MetricsPortTLSOption = "--metrics-port-tls"
+
+// validateCommandOverride validates the overrides of the command and of
+// the arguments of the postgres container, ensuring that the user
+// acknowledged them and that the instance manager is still being run
+func (r *Cluster) validateCommandOverride() field.ErrorList {
+	var result field.ErrorList
+
+	if len(r.Spec.CommandOverride) == 0 && len(r.Spec.ArgsOverride) == 0 {
+		return result
+	}
+
+	if !r.Spec.AcknowledgeCommandOverride {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "acknowledgeCommandOverride"),
+				r.Spec.AcknowledgeCommandOverride,
+				"overriding the command or the arguments of the postgres container "+
+					"requires acknowledgeCommandOverride to be set to true"))
+	}
+
+	// When the command is not overridden, the arguments are just
+	// passed to the instance manager
+	if len(r.Spec.CommandOverride) == 0 {
+		return result
+	}
+
+	commandLine := append(append([]string{}, r.Spec.CommandOverride...), r.Spec.ArgsOverride...)
+	if !containsSequence(commandLine, instanceManagerCommandLine) {
+		result = append(
+			result,
+			field.Invalid(
+				field.NewPath("spec", "commandOverride"),
+				r.Spec.CommandOverride,
+				fmt.Sprintf("the command line must run the instance manager with %q",
+					strings.Join(instanceManagerCommandLine, " "))))
+	}
+
+	return result
+}
+
+// validateMetricsTLS checks that an overridden command line asks the
+// instance manager to serve the metrics over TLS when that is requested,
+// as the operator can't inject the corresponding option into it
+func (r *Cluster) validateMetricsTLS() field.ErrorList {
+	if !r.IsMetricsTLSEnabled() || len(r.Spec.CommandOverride) == 0 {
+		return nil
+	}
+
+	commandLine := append(append([]string{}, r.Spec.CommandOverride...), r.Spec.ArgsOverride...)
+	if slices.Contains(commandLine, MetricsPortTLSOption) {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "monitoring", "tls", "enabled"),
+			r.Spec.Monitoring.TLSConfig.Enabled,
+			fmt.Sprintf("serving the metrics over TLS with an overridden command requires "+
+				"the command line to contain the %q option of the instance manager", MetricsPortTLSOption)),
+	}
+}
+
+// containsSequence checks if the passed sequence is contained, as
+// consecutive elements, in the list
+func containsSequence(list []string, sequence []string) bool {
+	for start := 0; start+len(sequence) <= len(list); start++ {
+		if reflect.DeepEqual(list[start:start+len(sequence)], sequence) {
+			return true
+		}
+	}
+	return false
+}
+
 // validateImagePullPolicy validates the image pull policy,
 // ensuring it is one of "Always", "Never" or "IfNotPresent" when defined
 func (r *Cluster) validateImagePullPolicy() field.ErrorList {
@@ -817,12 +1154,11 @@ func (r *Cluster) validateImagePullPolicy() field.ErrorList {
 func (r *Cluster) validateConfiguration() field.ErrorList {
 	var result field.ErrorList
 
-	psqlVersion, err := r.GetPostgresqlVersion()
-	if err != nil {
-		// The validation error will be already raised by the
-		// validateImageName function
-		return result
-	}
+	// The PostgreSQL version only changes the default values of the
+	// parameters, not the fixed ones, so we check them even when the
+	// version can't be detected, like when the image is referenced only
+	// by its digest or has an invalid tag, rejected by validateImageName
+	psqlVersion, _ := r.GetPostgresqlVersion()
 	info := postgres.ConfigurationInfo{
 		Settings:         postgres.CnpgConfigurationSettings,
 		MajorVersion:     psqlVersion,
@@ -850,9 +1186,42 @@ func (r *Cluster) validateConfiguration() field.ErrorList {
 		result = append(result, err)
 	}
 
+	result = append(result, r.validatePostgresClusterName()...)
+
 	return result
 }
 
+// validatePostgresClusterName checks the value used for the `cluster_name`
+// parameter, which must be printable ASCII and must not be confused with the
+// names of the instances, used as `application_name` by the replicas
+func (r *Cluster) validatePostgresClusterName() field.ErrorList {
+	clusterName := r.Spec.PostgresConfiguration.ClusterName
+	if clusterName == "" {
+		return nil
+	}
+
+	path := field.NewPath("spec", "postgresql", "clusterName")
+
+	for _, c := range clusterName {
+		if c < 32 || c > 126 {
+			return field.ErrorList{
+				field.Invalid(path, clusterName, "Only printable ASCII characters are allowed"),
+			}
+		}
+	}
+
+	if r.isInstanceName(clusterName) {
+		return field.ErrorList{
+			field.Invalid(
+				path,
+				clusterName,
+				"Conflicts with the name of the instances, used by the streaming replication connections"),
+		}
+	}
+
+	return nil
+}
+
 // validateConfigurationChange determines whether a PostgreSQL configuration
 // change can be applied
 func (r *Cluster) validateConfigurationChange(old *Cluster) field.ErrorList {
@@ -945,6 +1314,15 @@ func (r *Cluster) validateRecoveryTarget() field.ErrorList {
 
 	result := validateTargetExclusiveness(recoveryTarget)
 
+	// the Backup object already selects the backup to restore
+	if r.Spec.Bootstrap.Recovery.Backup != nil && recoveryTarget.BackupID != "" {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "bootstrap", "recovery", "recoveryTarget", "backupID"),
+			recoveryTarget.BackupID,
+			"backupID can only be used when recovering from an external cluster, "+
+				"not from a Backup object"))
+	}
+
 	// validate format of TargetTime
 	if recoveryTarget.TargetTime != "" {
 		if _, err := utils.ParseTargetTime(nil, recoveryTarget.TargetTime); err != nil {
@@ -968,7 +1346,7 @@ func (r *Cluster) validateRecoveryTarget() field.ErrorList {
 	// validate BackupID is defined when TargetName or TargetXID or TargetImmediate are set
 	if (recoveryTarget.TargetName != "" ||
 		recoveryTarget.TargetXID != "" ||
-		recoveryTarget.TargetImmediate != nil) && recoveryTarget.BackupID == "" {
+		isTargetImmediate(recoveryTarget)) && recoveryTarget.BackupID == "" {
 		result = append(result, field.Required(
 			field.NewPath("spec", "bootstrap", "recovery", "recoveryTarget"),
 			"BackupID is missing"))
@@ -990,11 +1368,45 @@ func (r *Cluster) validateRecoveryTarget() field.ErrorList {
 	return result
 }
 
+// validateRecoveryTargetPresence checks that a recovery target section has
+// an effect on the recovery, rejecting the ones which would be ignored.
+// This is only checked at creation time, not to prevent the update of the
+// clusters that have already been bootstrapped with such a section
+func (r *Cluster) validateRecoveryTargetPresence() field.ErrorList {
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.Recovery == nil ||
+		r.Spec.Bootstrap.Recovery.RecoveryTarget == nil {
+		return nil
+	}
+
+	recoveryTarget := r.Spec.Bootstrap.Recovery.RecoveryTarget
+	hasTarget := isTargetImmediate(recoveryTarget) ||
+		recoveryTarget.TargetLSN != "" ||
+		recoveryTarget.TargetName != "" ||
+		recoveryTarget.TargetXID != "" ||
+		recoveryTarget.TargetTime != ""
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "bootstrap", "recovery", "recoveryTarget")
+
+	if !hasTarget && recoveryTarget.BackupID == "" && recoveryTarget.TargetTLI == "" {
+		result = append(result, field.Invalid(
+			path,
+			recoveryTarget,
+			"The recovery target doesn't specify any target, BackupID or TargetTLI"))
+	}
+
+	if !hasTarget && recoveryTarget.Exclusive != nil {
+		result = append(result, field.Invalid(
+			path.Child("exclusive"),
+			*recoveryTarget.Exclusive,
+			"Exclusive can only be set together with a recovery target"))
+	}
+
+	return result
+}
+
 func validateTargetExclusiveness(recoveryTarget *RecoveryTarget) field.ErrorList {
 	targets := 0
-	if recoveryTarget.TargetImmediate != nil {
-		targets++
-	}
 	if recoveryTarget.TargetLSN != "" {
 		targets++
 	}
@@ -1010,7 +1422,13 @@ func validateTargetExclusiveness(recoveryTarget *RecoveryTarget) field.ErrorList
 
 	var result field.ErrorList
 
-	if targets > 1 {
+	switch {
+	case isTargetImmediate(recoveryTarget) && targets > 0:
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "bootstrap", "recovery", "recoveryTarget", "targetImmediate"),
+			*recoveryTarget.TargetImmediate,
+			"targetImmediate can't be combined with targetTime, targetXID, targetLSN or targetName"))
+	case targets > 1:
 		result = append(result, field.Invalid(
 			field.NewPath("spec", "bootstrap", "recovery", "recoveryTarget"),
 			recoveryTarget,
@@ -1019,6 +1437,13 @@ func validateTargetExclusiveness(recoveryTarget *RecoveryTarget) field.ErrorList
 	return result
 }
 
+// isTargetImmediate checks whether the recovery should end as soon as
+// a consistent state is reached. Setting `targetImmediate` to false
+// has no effect on the recovery
+func isTargetImmediate(recoveryTarget *RecoveryTarget) bool {
+	return recoveryTarget.TargetImmediate != nil && *recoveryTarget.TargetImmediate
+}
+
 // Validate the update strategy related to the number of required
 // instances
 func (r *Cluster) validatePrimaryUpdateStrategy() field.ErrorList {
@@ -1048,6 +1473,28 @@ func (r *Cluster) validatePrimaryUpdateStrategy() field.ErrorList {
 	return nil
 }
 
+// Validate that disabling the automatic failover is not contradicted
+// by an unsupervised primary update, which would let the operator
+// promote a replica on its own
+func (r *Cluster) validateAutomaticFailover() field.ErrorList {
+	if r.IsAutomaticFailoverEnabled() || r.Spec.Instances <= 1 {
+		return nil
+	}
+
+	if r.GetPrimaryUpdateStrategy() == PrimaryUpdateStrategyUnsupervised &&
+		r.GetPrimaryUpdateMethod() == PrimaryUpdateMethodSwitchover {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "enableAutomaticFailover"),
+				*r.Spec.EnableAutomaticFailover,
+				"disabling the automatic failover requires either the 'supervised' primaryUpdateStrategy "+
+					"or the 'restart' primaryUpdateMethod"),
+		}
+	}
+
+	return nil
+}
+
 // Validate the maximum number of synchronous instances
 // that should be kept in sync with the primary server
 func (r *Cluster) validateMaxSyncReplicas() field.ErrorList {
@@ -1060,7 +1507,9 @@ func (r *Cluster) validateMaxSyncReplicas() field.ErrorList {
 			"maxSyncReplicas must be a non negative integer"))
 	}
 
-	if r.Spec.MaxSyncReplicas >= r.Spec.Instances {
+	// A cluster made by the primary alone is allowed to keep its synchronous
+	// replication settings, which are relaxed until it is scaled back up
+	if r.Spec.Instances > 1 && r.Spec.MaxSyncReplicas >= r.Spec.Instances {
 		result = append(result, field.Invalid(
 			field.NewPath("spec", "maxSyncReplicas"),
 			r.Spec.MaxSyncReplicas,
@@ -1070,66 +1519,329 @@ func (r *Cluster) validateMaxSyncReplicas() field.ErrorList {
 	return result
 }
 
-// Validate the minimum number of synchronous instances
-func (r *Cluster) validateMinSyncReplicas() field.ErrorList {
-	var result field.ErrorList
+// likelyAvailableZones is the number of availability zones provided by
+// most Kubernetes regions. It is used to detect synchronous replication
+// settings that can hardly be satisfied.
+const likelyAvailableZones = 3
 
-	if r.Spec.MinSyncReplicas < 0 {
-		result = append(result, field.Invalid(
-			field.NewPath("spec", "minSyncReplicas"),
-			r.Spec.MinSyncReplicas,
-			"minSyncReplicas must be a non negative integer"))
+// zoneTopologyKeys are the well-known node labels identifying a zone
+var zoneTopologyKeys = []string{
+	"topology.kubernetes.io/zone",
+	"failure-domain.beta.kubernetes.io/zone",
+}
+
+// getMaxSyncReplicasTopologyWarnings warns the user when the instances
+// are required to run in different zones, and the synchronous replicas
+// together with the primary need more zones than the ones likely available
+func (r *Cluster) getMaxSyncReplicasTopologyWarnings() []string {
+	if r.Spec.MaxSyncReplicas <= 0 {
+		return nil
 	}
 
-	if r.Spec.MinSyncReplicas > r.Spec.MaxSyncReplicas {
-		result = append(result, field.Invalid(
-			field.NewPath("spec", "minSyncReplicas"),
-			r.Spec.MinSyncReplicas,
-			"minSyncReplicas cannot be greater than maxSyncReplicas"))
+	zoneKey := r.getZoneAntiAffinityKey()
+	if zoneKey == "" {
+		return nil
 	}
 
-	return result
-}
+	requiredZones := r.Spec.MaxSyncReplicas + 1
+	if requiredZones <= likelyAvailableZones {
+		return nil
+	}
 
-func (r *Cluster) validateStorageSize() field.ErrorList {
-	return validateStorageConfigurationSize("Storage", r.Spec.StorageConfiguration)
+	return []string{
+		fmt.Sprintf("maxSyncReplicas is set to %d and the instances are spread across the zones "+
+			"defined by the %q node label: synchronous replication needs at least %d zones, "+
+			"while most Kubernetes regions provide %d of them",
+			r.Spec.MaxSyncReplicas, zoneKey, requiredZones, likelyAvailableZones),
+	}
 }
 
-func (r *Cluster) validateWalStorageSize() field.ErrorList {
-	var result field.ErrorList
-
-	if r.ShouldCreateWalArchiveVolume() {
-		result = append(result, validateStorageConfigurationSize("walStorage", *r.Spec.WalStorage)...)
+// getMinSyncReplicasWarnings warns the user when synchronous replication
+// is requested without a minimum number of synchronous replicas, as it is
+// disabled whenever fewer than maxSyncReplicas replicas are ready
+func (r *Cluster) getMinSyncReplicasWarnings() []string {
+	if r.Spec.MaxSyncReplicas <= 0 || r.Spec.MinSyncReplicas > 0 {
+		return nil
 	}
 
-	return result
+	return []string{
+		fmt.Sprintf("maxSyncReplicas is set to %d while minSyncReplicas is 0: the synchronous "+
+			"replication is disabled whenever fewer than %d replicas are ready. "+
+			"Set minSyncReplicas to require at least a synchronous standby",
+			r.Spec.MaxSyncReplicas, r.Spec.MaxSyncReplicas),
+	}
 }
 
-func validateStorageConfigurationSize(structPath string, storageConfiguration StorageConfiguration) field.ErrorList {
-	var result field.ErrorList
-
-	if storageConfiguration.Size != "" {
-		if _, err := resource.ParseQuantity(storageConfiguration.Size); err != nil {
-			result = append(result, field.Invalid(
-				field.NewPath("spec", structPath, "size"),
-				storageConfiguration.Size,
-				"Size value isn't valid"))
-		}
+// getSingleInstanceSyncReplicasWarnings warns the user when a cluster
+// made by the primary alone is requesting synchronous replication, which
+// is disabled until the cluster is scaled back up
+func (r *Cluster) getSingleInstanceSyncReplicasWarnings() []string {
+	if r.Spec.Instances != 1 || r.Spec.MaxSyncReplicas <= 0 {
+		return nil
 	}
 
-	if storageConfiguration.Size == "" &&
-		(storageConfiguration.PersistentVolumeClaimTemplate == nil ||
-			storageConfiguration.PersistentVolumeClaimTemplate.Resources.Requests.Storage().IsZero()) {
-		result = append(result, field.Invalid(
-			field.NewPath("spec", structPath, "size"),
-			storageConfiguration.Size,
-			"Size not configured. Please add it, or a storage request in the pvcTemplate."))
+	return []string{
+		fmt.Sprintf("the cluster has no replicas while maxSyncReplicas is set to %d: the synchronous "+
+			"replication is disabled until the cluster is scaled back up",
+			r.Spec.MaxSyncReplicas),
 	}
-	return result
 }
 
-// Validate a change in the storage
-func (r *Cluster) validateStorageChange(old *Cluster) field.ErrorList {
+// getDurabilityWarnings warns the user when the parameters disable
+// durability guarantees on a cluster with backups enabled, as the
+// backups could contain corrupted or missing data
+func (r *Cluster) getDurabilityWarnings() []string {
+	if !r.Spec.Backup.IsWalArchivingConfigured() {
+		return nil
+	}
+
+	settings := []struct {
+		parameter string
+		risk      string
+	}{
+		{
+			parameter: "fsync",
+			risk: "a crash of the operating system can lead to unrecoverable corruption, " +
+				"both in the instances and in the backups",
+		},
+		{
+			parameter: "full_page_writes",
+			risk: "partially written pages after a crash can lead to unrecoverable corruption, " +
+				"both in the instances and in the backups",
+		},
+		{
+			parameter: "synchronous_commit",
+			risk: "the most recently committed transactions can be lost after a crash, " +
+				"although the database stays consistent",
+		},
+	}
+
+	var warnings []string
+	for _, setting := range settings {
+		value, isSet := r.Spec.PostgresConfiguration.Parameters[setting.parameter]
+		if !isSet || !isPostgresBooleanOff(value) {
+			continue
+		}
+
+		warnings = append(warnings,
+			fmt.Sprintf("%s is set to '%s' on a cluster with backups enabled: %s",
+				setting.parameter, value, setting.risk))
+	}
+
+	return warnings
+}
+
+// isPostgresBooleanOff checks whether a PostgreSQL configuration value
+// is one of the spellings accepted for a false boolean
+func isPostgresBooleanOff(value string) bool {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" {
+		return false
+	}
+
+	return value == "0" ||
+		strings.HasPrefix("false", value) ||
+		strings.HasPrefix("no", value) ||
+		(len(value) >= 2 && strings.HasPrefix("off", value))
+}
+
+// validateInstances checks that the cluster has at least one instance
+func (r *Cluster) validateInstances() field.ErrorList {
+	if r.Spec.Instances >= 1 {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "instances"),
+			r.Spec.Instances,
+			"the cluster requires at least one instance"),
+	}
+}
+
+// getEvenInstancesWarnings advises the user to use an odd number of
+// instances, as high availability is usually reasoned in terms of quorum
+func (r *Cluster) getEvenInstancesWarnings() []string {
+	if r.Spec.Instances <= 1 || r.Spec.Instances%2 != 0 {
+		return nil
+	}
+
+	return []string{
+		fmt.Sprintf("instances is set to %d: an odd number of instances, such as 3, "+
+			"is recommended for high availability", r.Spec.Instances),
+	}
+}
+
+// getManyInstancesWarnings warns the user when the cluster has more
+// instances than usually needed, as every replica adds load to the primary
+func (r *Cluster) getManyInstancesWarnings() []string {
+	if r.Spec.Instances <= maxRecommendedInstances {
+		return nil
+	}
+
+	return []string{
+		fmt.Sprintf("instances is set to %d: every replica streams the WAL from the primary, "+
+			"more than %d instances are rarely needed", r.Spec.Instances, maxRecommendedInstances),
+	}
+}
+
+// getInitDBOptionsWarnings warns the user when the explicit initdb
+// settings are ignored because of the deprecated options field
+func (r *Cluster) getInitDBOptionsWarnings() []string {
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.InitDB == nil {
+		return nil
+	}
+
+	initDB := r.Spec.Bootstrap.InitDB
+	if len(initDB.Options) == 0 { //nolint:staticcheck
+		return nil
+	}
+
+	var warnings []string
+	explicitSettings := []struct {
+		name         string
+		value        string
+		defaultValue string
+	}{
+		{name: "encoding", value: initDB.Encoding, defaultValue: "UTF8"},
+		{name: "localeCollate", value: initDB.LocaleCollate, defaultValue: "C"},
+		{name: "localeCType", value: initDB.LocaleCType, defaultValue: "C"},
+	}
+	for _, setting := range explicitSettings {
+		if setting.value != "" && setting.value != setting.defaultValue {
+			warnings = append(warnings, fmt.Sprintf(
+				"bootstrap.initdb.%s is ignored as the deprecated bootstrap.initdb.options is set: "+
+					"please move the initdb options to the explicit settings", setting.name))
+		}
+	}
+
+	return warnings
+}
+
+// getZoneAntiAffinityKey gets the node label used to keep the instances,
+// or the synchronous replicas, in different zones. It returns an empty
+// string when the instances are not required to be in different zones
+func (r *Cluster) getZoneAntiAffinityKey() string {
+	affinity := r.Spec.Affinity
+	if (affinity.EnablePodAntiAffinity == nil || *affinity.EnablePodAntiAffinity) &&
+		affinity.PodAntiAffinityType == PodAntiAffinityTypeRequired &&
+		slices.Contains(zoneTopologyKeys, affinity.TopologyKey) {
+		return affinity.TopologyKey
+	}
+
+	constraint := r.Spec.PostgresConfiguration.SyncReplicaElectionConstraint
+	if constraint.Enabled {
+		for _, label := range constraint.NodeLabelsAntiAffinity {
+			if slices.Contains(zoneTopologyKeys, label) {
+				return label
+			}
+		}
+	}
+
+	return ""
+}
+
+// Validate the minimum number of synchronous instances
+func (r *Cluster) validateMinSyncReplicas() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.MinSyncReplicas < 0 {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "minSyncReplicas"),
+			r.Spec.MinSyncReplicas,
+			"minSyncReplicas must be a non negative integer"))
+	}
+
+	if r.Spec.MinSyncReplicas > r.Spec.MaxSyncReplicas {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "minSyncReplicas"),
+			r.Spec.MinSyncReplicas,
+			"minSyncReplicas cannot be greater than maxSyncReplicas"))
+	}
+
+	return result
+}
+
+func (r *Cluster) validateStorageSize() field.ErrorList {
+	return validateStorageConfigurationSize("Storage", r.Spec.StorageConfiguration)
+}
+
+func (r *Cluster) validateWalStorageSize() field.ErrorList {
+	var result field.ErrorList
+
+	if r.ShouldCreateWalArchiveVolume() {
+		result = append(result, validateStorageConfigurationSize("walStorage", *r.Spec.WalStorage)...)
+	}
+
+	return result
+}
+
+// validateStorageConfiguration checks that the PVC templates of the WAL
+// storage and of the data storage don't collide, as every instance needs
+// two distinct volumes when the WAL storage is configured
+func (r *Cluster) validateStorageConfiguration() field.ErrorList {
+	if !r.ShouldCreateWalArchiveVolume() {
+		return nil
+	}
+
+	dataTemplate := r.Spec.StorageConfiguration.PersistentVolumeClaimTemplate
+	walTemplate := r.Spec.WalStorage.PersistentVolumeClaimTemplate
+	if dataTemplate == nil || walTemplate == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	walTemplatePath := field.NewPath("spec", "walStorage", "pvcTemplate")
+
+	if walTemplate.VolumeName != "" && walTemplate.VolumeName == dataTemplate.VolumeName {
+		result = append(result, field.Invalid(
+			walTemplatePath.Child("volumeName"),
+			walTemplate.VolumeName,
+			"the WAL storage can't be bound to the same persistent volume of the data storage"))
+	}
+
+	if walTemplate.DataSource != nil && reflect.DeepEqual(walTemplate.DataSource, dataTemplate.DataSource) {
+		result = append(result, field.Invalid(
+			walTemplatePath.Child("dataSource"),
+			walTemplate.DataSource.Name,
+			"the WAL storage can't be populated from the same data source of the data storage"))
+	}
+
+	if walTemplate.DataSourceRef != nil && reflect.DeepEqual(walTemplate.DataSourceRef, dataTemplate.DataSourceRef) {
+		result = append(result, field.Invalid(
+			walTemplatePath.Child("dataSourceRef"),
+			walTemplate.DataSourceRef.Name,
+			"the WAL storage can't be populated from the same data source of the data storage"))
+	}
+
+	return result
+}
+
+func validateStorageConfigurationSize(structPath string, storageConfiguration StorageConfiguration) field.ErrorList {
+	var result field.ErrorList
+
+	if storageConfiguration.Size != "" {
+		if _, err := resource.ParseQuantity(storageConfiguration.Size); err != nil {
+			result = append(result, field.Invalid(
+				field.NewPath("spec", structPath, "size"),
+				storageConfiguration.Size,
+				"Size value isn't valid"))
+		}
+	}
+
+	if storageConfiguration.Size == "" &&
+		(storageConfiguration.PersistentVolumeClaimTemplate == nil ||
+			storageConfiguration.PersistentVolumeClaimTemplate.Resources.Requests.Storage().IsZero()) {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", structPath, "size"),
+			storageConfiguration.Size,
+			"Size not configured. Please add it, or a storage request in the pvcTemplate."))
+	}
+	return result
+}
+
+// Validate a change in the storage
+func (r *Cluster) validateStorageChange(old *Cluster) field.ErrorList {
 	var result field.ErrorList
 
 	return append(
@@ -1265,58 +1977,940 @@ func (r *Cluster) validateExternalClusters() field.ErrorList {
 			"the list of external clusters contains duplicate values"))
 	}
 
-	return result
+	return result
+}
+
+// validateExternalCluster check the validity of a certain ExternalCluster
+func (r *Cluster) validateExternalCluster(externalCluster *ExternalCluster, path *field.Path) field.ErrorList {
+	var result field.ErrorList
+
+	if externalCluster.ConnectionParameters == nil && externalCluster.BarmanObjectStore == nil {
+		result = append(result,
+			field.Invalid(
+				path,
+				externalCluster,
+				"one of connectionParameters and barmanObjectStore is required"))
+	}
+
+	return result
+}
+
+// Check replica mode is enabled only at cluster creation time
+func (r *Cluster) validateReplicaModeChange(old *Cluster) field.ErrorList {
+	var result field.ErrorList
+	// if we are not specifying any replica cluster configuration or disabling it, nothing to do
+	if r.Spec.ReplicaCluster == nil || !r.Spec.ReplicaCluster.Enabled {
+		return result
+	}
+
+	// otherwise if it was not defined before or it was just not enabled, add an error
+	if old.Spec.ReplicaCluster == nil || !old.Spec.ReplicaCluster.Enabled {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "replicaCluster"),
+			r.Spec.ReplicaCluster,
+			"Can not enable replication on existing clusters"))
+	}
+
+	return result
+}
+
+// validateSecurityContext ensures that the pods of the cluster don't run
+// with root privileges, and that the requested seccomp profile is complete
+func (r *Cluster) validateSecurityContext() field.ErrorList {
+	var result field.ErrorList
+
+	// A zero UID or GID is replaced by the default one
+	if r.Spec.PostgresUID < 0 {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "postgresUID"),
+			r.Spec.PostgresUID,
+			"UID must be a positive number"))
+	}
+
+	if r.Spec.PostgresGID < 0 {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "postgresGID"),
+			r.Spec.PostgresGID,
+			"GID must be a positive number"))
+	}
+
+	if r.Spec.SeccompProfile != nil &&
+		r.Spec.SeccompProfile.Type == v1.SeccompProfileTypeLocalhost &&
+		(r.Spec.SeccompProfile.LocalhostProfile == nil || *r.Spec.SeccompProfile.LocalhostProfile == "") {
+		result = append(result, field.Required(
+			field.NewPath("spec", "seccompProfile", "localhostProfile"),
+			"localhostProfile is required by the Localhost seccomp profile type"))
+	}
+
+	return result
+}
+
+// validateLivenessIsolationCheck ensures that the request issued by the
+// isolation check can complete before the liveness probe times out
+func (r *Cluster) validateLivenessIsolationCheck() field.ErrorList {
+	if r.Spec.Probes == nil || r.Spec.Probes.Liveness == nil {
+		return nil
+	}
+
+	liveness := r.Spec.Probes.Liveness
+	isolationCheck := liveness.IsolationCheck
+	if !isolationCheck.IsEnabled() {
+		return nil
+	}
+
+	livenessTimeoutSeconds := int32(DefaultLivenessProbeTimeoutSeconds)
+	if liveness.TimeoutSeconds != nil {
+		livenessTimeoutSeconds = *liveness.TimeoutSeconds
+	}
+	// Kubernetes defaults a zero timeout of a probe to one second
+	if livenessTimeoutSeconds <= 0 {
+		livenessTimeoutSeconds = 1
+	}
+
+	if isolationCheck.GetRequestTimeout() >= time.Duration(livenessTimeoutSeconds)*time.Second {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "probes", "liveness", "isolationCheck", "requestTimeout"),
+				isolationCheck.RequestTimeout,
+				"the request timeout of the isolation check must be lower than the timeout of the liveness probe"),
+		}
+	}
+
+	return nil
+}
+
+func (r *Cluster) validateUnixPermissionIdentifierChange(old *Cluster) field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.PostgresGID != old.Spec.PostgresGID {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "postgresGID"),
+			r.Spec.PostgresGID,
+			"GID is an immutable field in the spec"))
+	}
+
+	if r.Spec.PostgresUID != old.Spec.PostgresUID {
+		result = append(result, field.Invalid(
+			field.NewPath("spec", "postgresUID"),
+			r.Spec.PostgresUID,
+			"UID is an immutable field in the spec"))
+	}
+
+	return result
+}
+
+// validateReplayPausedInstances validates the annotation pausing the WAL
+// replay on a set of replicas, which are not electable as synchronous
+// replicas while paused
+func (r *Cluster) validateReplayPausedInstances() field.ErrorList {
+	annotationPath := field.NewPath("metadata", "annotations", utils.ReplayPausedInstancesAnnotation)
+
+	replayPausedInstances, err := utils.GetReplayPausedInstances(r.Annotations)
+	if err != nil {
+		return field.ErrorList{
+			field.Invalid(
+				annotationPath,
+				r.Annotations[utils.ReplayPausedInstancesAnnotation],
+				err.Error()),
+		}
+	}
+
+	if replayPausedInstances.Len() == 0 {
+		return nil
+	}
+
+	return r.validateElectableReplicas(
+		annotationPath,
+		r.Annotations[utils.ReplayPausedInstancesAnnotation],
+		replayPausedInstances,
+		"pausing")
+}
+
+// isInstanceName checks whether the passed name is the one of an instance
+// of this cluster, which is the name of the cluster followed by the serial
+// of the instance. The serial must be written as the operator does it, so
+// names like "cluster-example-01" and "cluster-example--0" are rejected
+func (r *Cluster) isInstanceName(name string) bool {
+	serialText := strings.TrimPrefix(name, r.Name+"-")
+	if serialText == name {
+		return false
+	}
+
+	serial, err := strconv.Atoi(serialText)
+	return err == nil && serial > 0 && strconv.Itoa(serial) == serialText
+}
+
+// validateFencedInstances validates the annotation fencing the instances,
+// since an invalid value would silently leave every instance running
+func (r *Cluster) validateFencedInstances() field.ErrorList {
+	annotationPath := field.NewPath("metadata", "annotations", utils.FencedInstanceAnnotation)
+
+	fencedInstances, err := utils.GetFencedInstances(r.Annotations)
+	if err != nil {
+		return field.ErrorList{
+			field.Invalid(
+				annotationPath,
+				r.Annotations[utils.FencedInstanceAnnotation],
+				err.Error()),
+		}
+	}
+
+	fencedInstancesList := fencedInstances.ToList()
+	sort.Strings(fencedInstancesList)
+	for _, instanceName := range fencedInstancesList {
+		if instanceName == utils.FenceAllServers {
+			continue
+		}
+
+		if !r.isInstanceName(instanceName) {
+			return field.ErrorList{
+				field.Invalid(
+					annotationPath,
+					r.Annotations[utils.FencedInstanceAnnotation],
+					fmt.Sprintf("%q is not an instance of this cluster, use %q to fence all of them",
+						instanceName, utils.FenceAllServers)),
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateResourcesOverrides validates that the resources overrides refer
+// to instances of this cluster, and that the resulting resource requests
+// don't exceed the corresponding limits
+func (r *Cluster) validateResourcesOverrides() field.ErrorList {
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "resourcesOverrides")
+
+	for instanceName := range r.Spec.ResourcesOverrides {
+		instancePath := basePath.Key(instanceName)
+
+		if !r.isInstanceName(instanceName) {
+			result = append(result,
+				field.Invalid(
+					instancePath,
+					instanceName,
+					fmt.Sprintf("the name of an instance of this cluster is required, like %s-1", r.Name)))
+			continue
+		}
+
+		result = append(result, validateRequestsWithinLimits(instancePath, r.GetInstanceResources(instanceName))...)
+	}
+
+	return result
+}
+
+// validateBootstrapResources validates that the resource requests of
+// the bootstrap jobs don't exceed the corresponding limits
+func (r *Cluster) validateBootstrapResources() field.ErrorList {
+	if r.Spec.BootstrapResources == nil {
+		return nil
+	}
+
+	return validateRequestsWithinLimits(field.NewPath("spec", "bootstrapResources"), r.GetBootstrapResources())
+}
+
+// validateRequestsWithinLimits checks that every resource request doesn't
+// exceed the corresponding limit, if any
+func validateRequestsWithinLimits(path *field.Path, resources v1.ResourceRequirements) field.ErrorList {
+	var result field.ErrorList
+
+	for name, request := range resources.Requests {
+		limit, hasLimit := resources.Limits[name]
+		if hasLimit && request.Cmp(limit) > 0 {
+			result = append(result,
+				field.Invalid(
+					path.Child("requests").Key(string(name)),
+					request.String(),
+					fmt.Sprintf("the request must be lower than or equal to the limit (%s)", limit.String())))
+		}
+	}
+
+	return result
+}
+
+// validateHotStandbyFeedback validates that `hot_standby_feedback` is
+// not set both in the dedicated field and among the parameters, and that
+// the PostgreSQL version supports the standby settings
+func (r *Cluster) validateHotStandbyFeedback() field.ErrorList {
+	if r.Spec.PostgresConfiguration.HotStandbyFeedback == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+
+	if value, isSet := r.Spec.PostgresConfiguration.Parameters[postgres.HotStandbyFeedback]; isSet {
+		result = append(result,
+			field.Invalid(
+				field.NewPath("spec", "postgresql", "parameters", postgres.HotStandbyFeedback),
+				value,
+				"hot_standby_feedback cannot be set among the parameters when "+
+					"the hotStandbyFeedback field is used"))
+	}
+
+	result = append(result, r.validateMinimumPostgresVersion(
+		field.NewPath("spec", "postgresql", "hotStandbyFeedback"),
+		*r.Spec.PostgresConfiguration.HotStandbyFeedback,
+		"the hotStandbyFeedback field",
+		120000)...)
+
+	return result
+}
+
+// timeoutField is a timeout, expressed in seconds, that can be set
+// through a dedicated field of the postgresql section
+type timeoutField struct {
+	fieldName string
+	parameter string
+	value     *int32
+}
+
+// validateReplicationTimeouts validates the wal_sender_timeout and
+// wal_receiver_timeout settings
+func (r *Cluster) validateReplicationTimeouts() field.ErrorList {
+	return r.validateTimeoutFields([]timeoutField{
+		{"walSenderTimeout", postgres.WalSenderTimeout, r.Spec.PostgresConfiguration.WalSenderTimeout},
+		{"walReceiverTimeout", postgres.WalReceiverTimeout, r.Spec.PostgresConfiguration.WalReceiverTimeout},
+	})
+}
+
+// validateSessionTimeouts validates the statement_timeout and
+// idle_in_transaction_session_timeout settings
+func (r *Cluster) validateSessionTimeouts() field.ErrorList {
+	return r.validateTimeoutFields([]timeoutField{
+		{"statementTimeout", postgres.StatementTimeout, r.Spec.PostgresConfiguration.StatementTimeout},
+		{
+			"idleInTransactionSessionTimeout",
+			postgres.IdleInTransactionSessionTimeout,
+			r.Spec.PostgresConfiguration.IdleInTransactionSessionTimeout,
+		},
+	})
+}
+
+// validateTimeoutFields checks that the passed timeouts are not negative
+// and don't conflict with the corresponding parameters
+func (r *Cluster) validateTimeoutFields(timeouts []timeoutField) field.ErrorList {
+	var result field.ErrorList
+
+	for _, timeout := range timeouts {
+		if timeout.value == nil {
+			continue
+		}
+
+		if *timeout.value < 0 {
+			result = append(result,
+				field.Invalid(
+					field.NewPath("spec", "postgresql", timeout.fieldName),
+					*timeout.value,
+					"the timeout must not be negative"))
+			continue
+		}
+
+		// The field is applied while generating the configuration, overriding
+		// the parameter. The defaulting webhook stores the default value of
+		// the parameter, so we only complain when it has been changed
+		value, isSet := r.Spec.PostgresConfiguration.Parameters[timeout.parameter]
+		if isSet && value != postgres.FormatSeconds(*timeout.value) &&
+			value != postgres.CnpgConfigurationSettings.GlobalDefaultSettings[timeout.parameter] {
+			result = append(result,
+				field.Invalid(
+					field.NewPath("spec", "postgresql", "parameters", timeout.parameter),
+					value,
+					fmt.Sprintf("%s cannot be set among the parameters when "+
+						"the %s field is used", timeout.parameter, timeout.fieldName)))
+		}
+	}
+
+	return result
+}
+
+// validateSynchronousReplicaConfiguration validates the configuration
+// of the "synchronous_standby_names" expression
+func (r *Cluster) validateSynchronousReplicaConfiguration() field.ErrorList {
+	synchronous := r.Spec.PostgresConfiguration.Synchronous
+	if synchronous == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "postgresql", "synchronous")
+
+	switch synchronous.Method {
+	case SynchronousReplicaConfigurationMethodAny, SynchronousReplicaConfigurationMethodFirst:
+	default:
+		result = append(result,
+			field.NotSupported(
+				basePath.Child("method"),
+				synchronous.Method,
+				[]string{
+					string(SynchronousReplicaConfigurationMethodAny),
+					string(SynchronousReplicaConfigurationMethodFirst),
+				}))
+	}
+
+	for idx, name := range synchronous.StandbyNames {
+		if !r.isInstanceName(name) {
+			result = append(result,
+				field.Invalid(
+					basePath.Child("standbyNames").Index(idx),
+					name,
+					"the name of an instance of this cluster is required"))
+		}
+	}
+
+	if stringset.From(synchronous.StandbyNames).Len() != len(synchronous.StandbyNames) {
+		result = append(result,
+			field.Invalid(
+				basePath.Child("standbyNames"),
+				synchronous.StandbyNames,
+				"duplicate instance names are not allowed"))
+	}
+
+	return result
+}
+
+// validateHibernationAnnotation validates the value of the annotation
+// requesting the hibernation of the cluster
+func (r *Cluster) validateHibernationAnnotation() field.ErrorList {
+	value, isSet := r.Annotations[utils.HibernationAnnotationName]
+	if !isSet {
+		return nil
+	}
+
+	switch utils.HibernationAnnotationValue(value) {
+	case utils.HibernationAnnotationValueOn, utils.HibernationAnnotationValueOff:
+		return nil
+	}
+
+	return field.ErrorList{
+		field.NotSupported(
+			field.NewPath("metadata", "annotations", utils.HibernationAnnotationName),
+			value,
+			[]string{
+				string(utils.HibernationAnnotationValueOn),
+				string(utils.HibernationAnnotationValueOff),
+			}),
+	}
+}
+
+// validateNetworkPolicy validates the selectors of the clients allowed
+// to connect to the instances by the generated NetworkPolicy
+func (r *Cluster) validateNetworkPolicy() field.ErrorList {
+	if r.Spec.NetworkPolicy == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "networkPolicy", "allowedClients")
+
+	for idx, allowedClient := range r.Spec.NetworkPolicy.AllowedClients {
+		path := basePath.Index(idx)
+		if allowedClient.NamespaceSelector == nil && allowedClient.PodSelector == nil {
+			result = append(result,
+				field.Required(path, "at least one of namespaceSelector and podSelector is required"))
+			continue
+		}
+
+		result = append(result,
+			validation.ValidateLabelSelector(allowedClient.NamespaceSelector, path.Child("namespaceSelector"))...)
+		result = append(result,
+			validation.ValidateLabelSelector(allowedClient.PodSelector, path.Child("podSelector"))...)
+	}
+
+	return result
+}
+
+// userMetadata is a set of labels or annotations defined by the user
+type userMetadata struct {
+	path   *field.Path
+	values map[string]string
+}
+
+// getUserMetadata gets the labels and annotations defined by the user
+// that the operator applies to the objects it generates
+func (r *Cluster) getUserMetadata() []userMetadata {
+	var metadata []userMetadata
+	if r.Spec.InheritedMetadata != nil {
+		basePath := field.NewPath("spec", "inheritedMetadata")
+		metadata = append(metadata,
+			userMetadata{basePath.Child("labels"), r.Spec.InheritedMetadata.Labels},
+			userMetadata{basePath.Child("annotations"), r.Spec.InheritedMetadata.Annotations},
+		)
+	}
+	if r.Spec.ServiceAccountTemplate != nil {
+		basePath := field.NewPath("spec", "serviceAccountTemplate", "metadata")
+		metadata = append(metadata,
+			userMetadata{basePath.Child("labels"), r.Spec.ServiceAccountTemplate.Metadata.Labels},
+			userMetadata{basePath.Child("annotations"), r.Spec.ServiceAccountTemplate.Metadata.Annotations},
+		)
+	}
+
+	return metadata
+}
+
+// validateMetadata checks that the metadata inherited by the objects
+// of the cluster, and the one of the generated service account, don't use
+// the prefixes reserved to the operator, that would clobber the labels
+// and annotations it manages. When an existing cluster is updated, only
+// the keys that weren't already defined are checked, so that the clusters
+// created before this check was introduced can still be changed
+func (r *Cluster) validateMetadata(old *Cluster) field.ErrorList {
+	existingKeys := make(map[string]bool)
+	if old != nil {
+		for _, entry := range old.getUserMetadata() {
+			for key := range entry.values {
+				existingKeys[entry.path.Key(key).String()] = true
+			}
+		}
+	}
+
+	var result field.ErrorList
+	for _, entry := range r.getUserMetadata() {
+		keys := make([]string, 0, len(entry.values))
+		for key := range entry.values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			keyPath := entry.path.Key(key)
+			if existingKeys[keyPath.String()] || !utils.IsReservedMetadataKey(key) {
+				continue
+			}
+
+			result = append(result,
+				field.Invalid(
+					keyPath,
+					entry.values[key],
+					fmt.Sprintf("the %s/ prefix is reserved to the operator", utils.MetadataNamespace)))
+		}
+	}
+
+	return result
+}
+
+// validateImagePullSecrets validates the names of the pull secrets
+// that will be added to the service account of the cluster
+func (r *Cluster) validateImagePullSecrets() field.ErrorList {
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "imagePullSecrets")
+
+	seen := make(map[string]bool, len(r.Spec.ImagePullSecrets))
+	for idx, pullSecret := range r.Spec.ImagePullSecrets {
+		path := basePath.Index(idx).Child("name")
+		if pullSecret.Name == "" {
+			result = append(result, field.Required(path, "the name of the pull secret is required"))
+			continue
+		}
+
+		if errs := validationutil.IsDNS1123Subdomain(pullSecret.Name); len(errs) > 0 {
+			result = append(result, field.Invalid(path, pullSecret.Name, strings.Join(errs, ";")))
+			continue
+		}
+
+		if seen[pullSecret.Name] {
+			result = append(result, field.Duplicate(path, pullSecret.Name))
+		}
+		seen[pullSecret.Name] = true
+	}
+
+	return result
+}
+
+// validateEnv validates the environment variables requested for the
+// PostgreSQL containers, which can't override the ones of the operator
+func (r *Cluster) validateEnv() field.ErrorList {
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "env")
+
+	for idx, envVar := range r.Spec.Env {
+		path := basePath.Index(idx).Child("name")
+		if envVar.Name == "" {
+			result = append(result, field.Required(path, "the name of the environment variable is required"))
+			continue
+		}
+
+		if slices.Contains(ReservedEnvironmentVariables, envVar.Name) {
+			result = append(result, field.Invalid(
+				path,
+				envVar.Name,
+				"the environment variable is reserved to the operator and can't be overridden"))
+		}
+	}
+
+	return result
+}
+
+// validateAdditionalVolumes ensures that the additional volumes and volume
+// mounts don't clash with the ones managed by the operator
+func (r *Cluster) validateAdditionalVolumes() field.ErrorList {
+	var result field.ErrorList
+
+	volumeNames := stringset.New()
+	volumesPath := field.NewPath("spec", "additionalVolumes")
+	for idx, volume := range r.Spec.AdditionalVolumes {
+		fieldPath := volumesPath.Index(idx).Child("name")
+		switch {
+		case volume.Name == "":
+			result = append(result, field.Required(fieldPath, "the name of the volume is required"))
+		case isReservedVolumeName(volume.Name):
+			result = append(result, field.Invalid(
+				fieldPath,
+				volume.Name,
+				"the volume name is reserved to the operator"))
+		case volumeNames.Has(volume.Name):
+			result = append(result, field.Duplicate(fieldPath, volume.Name))
+		}
+		volumeNames.Put(volume.Name)
+	}
+
+	volumeMountsPath := field.NewPath("spec", "additionalVolumeMounts")
+	for idx, volumeMount := range r.Spec.AdditionalVolumeMounts {
+		if !volumeNames.Has(volumeMount.Name) {
+			result = append(result, field.Invalid(
+				volumeMountsPath.Index(idx).Child("name"),
+				volumeMount.Name,
+				"the volume mount must refer to one of the additional volumes"))
+		}
+
+		fieldPath := volumeMountsPath.Index(idx).Child("mountPath")
+		if !filepath.IsAbs(volumeMount.MountPath) {
+			result = append(result, field.Invalid(
+				fieldPath,
+				volumeMount.MountPath,
+				"the mount path must be an absolute path"))
+			continue
+		}
+
+		if reservedPath := getClashingReservedVolumeMountPath(volumeMount.MountPath); reservedPath != "" {
+			result = append(result, field.Invalid(
+				fieldPath,
+				volumeMount.MountPath,
+				fmt.Sprintf("the mount path clashes with %s, which is reserved to the operator", reservedPath)))
+		}
+	}
+
+	return result
+}
+
+// isReservedVolumeName checks whether the passed volume name is used, or
+// could be used, by a volume created by the operator
+func isReservedVolumeName(name string) bool {
+	if slices.Contains(ReservedVolumeNames, name) {
+		return true
+	}
+
+	for _, suffix := range ReservedVolumeNameSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getClashingReservedVolumeMountPath gets the reserved path which is equal to,
+// contained in or containing the passed mount path, or an empty string
+// if there isn't any
+func getClashingReservedVolumeMountPath(mountPath string) string {
+	mountPath = filepath.Clean(mountPath)
+	for _, reservedPath := range ReservedVolumeMountPaths {
+		if mountPath == reservedPath ||
+			strings.HasPrefix(mountPath, reservedPath+"/") ||
+			strings.HasPrefix(reservedPath, strings.TrimSuffix(mountPath, "/")+"/") {
+			return reservedPath
+		}
+	}
+
+	return ""
+}
+
+// validateListenScope validates the network interfaces PostgreSQL should
+// listen on
+func (r *Cluster) validateListenScope() field.ErrorList {
+	switch r.Spec.PostgresConfiguration.ListenScope {
+	case "", ListenOnAllInterfaces, ListenOnPodIPOnly:
+		return nil
+	}
+
+	return field.ErrorList{
+		field.NotSupported(
+			field.NewPath("spec", "postgresql", "listenScope"),
+			r.Spec.PostgresConfiguration.ListenScope,
+			[]string{string(ListenOnAllInterfaces), string(ListenOnPodIPOnly)}),
+	}
+}
+
+// validateDanglingPVCPolicy checks the policy applied to the
+// PVCs that are no longer needed by the cluster
+func (r *Cluster) validateDanglingPVCPolicy() field.ErrorList {
+	switch r.Spec.DanglingPVCPolicy {
+	case "", DanglingPVCPolicyDelete, DanglingPVCPolicyRetain:
+		return nil
+	}
+
+	return field.ErrorList{
+		field.NotSupported(
+			field.NewPath("spec", "danglingPVCPolicy"),
+			r.Spec.DanglingPVCPolicy,
+			[]string{string(DanglingPVCPolicyDelete), string(DanglingPVCPolicyRetain)}),
+	}
+}
+
+// validateChecksumsVerification checks that the verification of the
+// data checksums on start is only requested when the cluster is created
+// with data checksums. The data directories restored from a backup or
+// cloned from another instance are checked by the instance manager
+func (r *Cluster) validateChecksumsVerification() field.ErrorList {
+	if !r.Spec.PostgresConfiguration.VerifyChecksumsOnStart {
+		return nil
+	}
+
+	var result field.ErrorList
+	path := field.NewPath("spec", "postgresql", "verifyChecksumsOnStart")
+
+	isInitDB := r.Spec.Bootstrap == nil || r.Spec.Bootstrap.InitDB != nil
+	if isInitDB && (r.Spec.Bootstrap == nil || r.Spec.Bootstrap.InitDB.DataChecksums == nil ||
+		!*r.Spec.Bootstrap.InitDB.DataChecksums) {
+		result = append(result,
+			field.Invalid(
+				path,
+				r.Spec.PostgresConfiguration.VerifyChecksumsOnStart,
+				"the verification of the data checksums requires "+
+					"'spec.bootstrap.initdb.dataChecksums' to be enabled"))
+	}
+
+	result = append(result, r.validateMinimumPostgresVersion(
+		path,
+		r.Spec.PostgresConfiguration.VerifyChecksumsOnStart,
+		"the verification of the data checksums",
+		120000)...)
+
+	return result
+}
+
+// validateApplicationCertificateAuthentication checks that the names of the
+// application database and of its owner can be used in the pg_hba.conf
+// rules requiring a client certificate, which can't contain a double quote
+func (r *Cluster) validateApplicationCertificateAuthentication() field.ErrorList {
+	if !r.Spec.PostgresConfiguration.ApplicationCertificateAuthentication {
+		return nil
+	}
+
+	database, owner := r.GetApplicationDatabaseName(), r.GetApplicationDatabaseOwner()
+	if !strings.Contains(database, `"`) && !strings.Contains(owner, `"`) {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "postgresql", "applicationCertificateAuthentication"),
+			r.Spec.PostgresConfiguration.ApplicationCertificateAuthentication,
+			"the names of the application database and of its owner cannot contain a double quote "+
+				"when the client certificate authentication is required"),
+	}
+}
+
+// validateSmartShutdownTimeout validates that the smart shutdown
+// timeout leaves time for the fast shutdown within the stop delay
+func (r *Cluster) validateSmartShutdownTimeout() field.ErrorList {
+	if r.Spec.SmartShutdownTimeout == 0 {
+		return nil
+	}
+
+	if r.Spec.SmartShutdownTimeout < 0 || r.Spec.SmartShutdownTimeout >= r.GetMaxStopDelay() {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "smartShutdownTimeout"),
+				r.Spec.SmartShutdownTimeout,
+				fmt.Sprintf("smartShutdownTimeout must be a positive number of seconds, "+
+					"lower than stopDelay (%d)", r.GetMaxStopDelay())),
+		}
+	}
+
+	return nil
+}
+
+// validateDelayedReplicas validates the configuration of the replicas
+// applying the WAL with a delay
+func (r *Cluster) validateDelayedReplicas() field.ErrorList {
+	delayedReplicas := r.Spec.DelayedReplicas
+	if delayedReplicas == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "delayedReplicas")
+
+	if delayedReplicas.MinApplyDelay <= 0 {
+		result = append(result,
+			field.Invalid(
+				basePath.Child("minApplyDelay"),
+				delayedReplicas.MinApplyDelay,
+				"the minimum apply delay must be a positive number of seconds"))
+	}
+
+	if len(delayedReplicas.Instances) == 0 {
+		result = append(result,
+			field.Required(
+				basePath.Child("instances"),
+				"at least one delayed replica is required"))
+	}
+
+	delayedInstances := stringset.From(delayedReplicas.Instances)
+	if delayedInstances.Len() != len(delayedReplicas.Instances) {
+		result = append(result,
+			field.Invalid(
+				basePath.Child("instances"),
+				delayedReplicas.Instances,
+				"duplicate instance names are not allowed"))
+	}
+
+	result = append(result, r.validateElectableReplicas(
+		basePath.Child("instances"),
+		delayedReplicas.Instances,
+		delayedInstances,
+		"delaying")...)
+
+	result = append(result, r.validateMinimumPostgresVersion(
+		basePath,
+		delayedReplicas,
+		"the delayed replicas",
+		120000)...)
+
+	return result
+}
+
+// validateMinimumPostgresVersion checks that the PostgreSQL version of the
+// image is at least the one required by the passed feature, which is
+// expressed as a major version number, like 120000
+func (r *Cluster) validateMinimumPostgresVersion(
+	path *field.Path,
+	value interface{},
+	feature string,
+	minVersion int,
+) field.ErrorList {
+	psqlVersion, err := r.GetPostgresqlVersion()
+	if err != nil {
+		if utils.GetImageTag(r.GetImageName()) != "" {
+			// The invalid tag is already rejected by the
+			// validateImageName function
+			return nil
+		}
+
+		return field.ErrorList{
+			field.Invalid(
+				path,
+				value,
+				fmt.Sprintf("%s requires PostgreSQL %d or above, which can't be verified "+
+					"when the image is referenced only by its digest", feature, minVersion/10000)),
+		}
+	}
+
+	if psqlVersion < minVersion {
+		return field.ErrorList{
+			field.Invalid(
+				path,
+				value,
+				fmt.Sprintf("%s requires PostgreSQL %d or above", feature, minVersion/10000)),
+		}
+	}
+
+	return nil
 }
 
-// validateExternalCluster check the validity of a certain ExternalCluster
-func (r *Cluster) validateExternalCluster(externalCluster *ExternalCluster, path *field.Path) field.ErrorList {
-	var result field.ErrorList
-
-	if externalCluster.ConnectionParameters == nil && externalCluster.BarmanObjectStore == nil {
-		result = append(result,
-			field.Invalid(
-				path,
-				externalCluster,
-				"one of connectionParameters and barmanObjectStore is required"))
+// validateElectableReplicas checks that, excluding the passed instances,
+// there are still enough replicas electable for synchronous replication
+// to satisfy minSyncReplicas. The action is the one the user requested
+// on the excluded instances, like "pausing" or "delaying"
+func (r *Cluster) validateElectableReplicas(
+	path *field.Path,
+	value interface{},
+	excludedInstances *stringset.Data,
+	action string,
+) field.ErrorList {
+	if r.countElectableReplicas(excludedInstances) >= r.Spec.MinSyncReplicas {
+		return nil
 	}
 
-	return result
+	return field.ErrorList{
+		field.Invalid(
+			path,
+			value,
+			fmt.Sprintf("%s the WAL replay on these instances would leave less than minSyncReplicas "+
+				"replicas electable for synchronous replication", action)),
+	}
 }
 
-// Check replica mode is enabled only at cluster creation time
-func (r *Cluster) validateReplicaModeChange(old *Cluster) field.ErrorList {
-	var result field.ErrorList
-	// if we are not specifying any replica cluster configuration or disabling it, nothing to do
-	if r.Spec.ReplicaCluster == nil || !r.Spec.ReplicaCluster.Enabled {
-		return result
+// countElectableReplicas counts the replicas electable for synchronous
+// replication, which are the ones not contained in the passed set of
+// delayed or paused instances.
+// The instances are the ones reported in the status, or the ones the
+// cluster is going to be created with
+func (r *Cluster) countElectableReplicas(excludedInstances *stringset.Data) int {
+	instanceNames := r.Status.InstanceNames
+	if len(instanceNames) == 0 {
+		for serial := 1; serial <= r.Spec.Instances; serial++ {
+			instanceNames = append(instanceNames, fmt.Sprintf("%s-%d", r.Name, serial))
+		}
 	}
 
-	// otherwise if it was not defined before or it was just not enabled, add an error
-	if old.Spec.ReplicaCluster == nil || !old.Spec.ReplicaCluster.Enabled {
-		result = append(result, field.Invalid(
-			field.NewPath("spec", "replicaCluster"),
-			r.Spec.ReplicaCluster,
-			"Can not enable replication on existing clusters"))
+	primary := r.Status.CurrentPrimary
+	if primary == "" {
+		primary = fmt.Sprintf("%s-1", r.Name)
+	}
+
+	result := 0
+	for _, instanceName := range instanceNames {
+		if instanceName != primary && !excludedInstances.Has(instanceName) {
+			result++
+		}
 	}
 
 	return result
 }
 
-func (r *Cluster) validateUnixPermissionIdentifierChange(old *Cluster) field.ErrorList {
+// validateInitDBChange validates that the initdb settings defining
+// the PGDATA, which cannot be changed after its creation, are not modified
+func (r *Cluster) validateInitDBChange(old *Cluster) field.ErrorList {
+	if r.Spec.Bootstrap == nil || r.Spec.Bootstrap.InitDB == nil ||
+		old.Spec.Bootstrap == nil || old.Spec.Bootstrap.InitDB == nil {
+		return nil
+	}
+
 	var result field.ErrorList
+	newInitDB := r.Spec.Bootstrap.InitDB
+	oldInitDB := old.Spec.Bootstrap.InitDB
+	basePath := field.NewPath("spec", "bootstrap", "initdb")
 
-	if r.Spec.PostgresGID != old.Spec.PostgresGID {
-		result = append(result, field.Invalid(
-			field.NewPath("spec", "postgresGID"),
-			r.Spec.PostgresGID,
-			"GID is an immutable field in the spec"))
+	isDataChecksumsEnabled := func(initDB *BootstrapInitDB) bool {
+		return initDB.DataChecksums != nil && *initDB.DataChecksums
 	}
-
-	if r.Spec.PostgresUID != old.Spec.PostgresUID {
+	if isDataChecksumsEnabled(newInitDB) != isDataChecksumsEnabled(oldInitDB) {
 		result = append(result, field.Invalid(
-			field.NewPath("spec", "postgresUID"),
-			r.Spec.PostgresUID,
-			"UID is an immutable field in the spec"))
+			basePath.Child("dataChecksums"),
+			isDataChecksumsEnabled(newInitDB),
+			"dataChecksums cannot be changed after the cluster creation"))
+	}
+
+	fixedSettings := []struct {
+		name     string
+		newValue interface{}
+		oldValue interface{}
+	}{
+		{name: "encoding", newValue: newInitDB.Encoding, oldValue: oldInitDB.Encoding},
+		{name: "localeCollate", newValue: newInitDB.LocaleCollate, oldValue: oldInitDB.LocaleCollate},
+		{name: "localeCType", newValue: newInitDB.LocaleCType, oldValue: oldInitDB.LocaleCType},
+		{name: "walSegmentSize", newValue: newInitDB.WalSegmentSize, oldValue: oldInitDB.WalSegmentSize},
+	}
+	for _, setting := range fixedSettings {
+		if setting.newValue != setting.oldValue {
+			result = append(result, field.Invalid(
+				basePath.Child(setting.name),
+				setting.newValue,
+				fmt.Sprintf("%s cannot be changed after the cluster creation", setting.name)))
+		}
 	}
 
 	return result
@@ -1355,6 +2949,17 @@ func (r *Cluster) validateReplicaMode() field.ErrorList {
 	return result
 }
 
+// validateDedicatedNodesTaintKey checks that the dedicated nodes taint key
+// can be used both as a taint key and as a node label name
+func (r *Cluster) validateDedicatedNodesTaintKey() field.ErrorList {
+	taintKey := r.Spec.Affinity.DedicatedNodesTaintKey
+	if taintKey == "" {
+		return nil
+	}
+
+	return validation.ValidateLabelName(taintKey, field.NewPath("spec", "affinity", "dedicatedNodesTaintKey"))
+}
+
 // validateTolerations check and validate the tolerations field
 // This code is almost a verbatim copy of
 // https://github.com/kubernetes/kubernetes/blob/4d38d21/pkg/apis/core/validation/validation.go#L3147
@@ -1513,6 +3118,214 @@ func (r *Cluster) validateBackupConfiguration() field.ErrorList {
 	return allErrors
 }
 
+// validatePrimaryHealthCheck validates the additional health
+// check of the primary instance
+func (r *Cluster) validatePrimaryHealthCheck() field.ErrorList {
+	healthCheck := r.Spec.PrimaryHealthCheck
+	if healthCheck == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "primaryHealthCheck")
+
+	if strings.TrimSpace(healthCheck.Query) == "" {
+		result = append(result, field.Required(
+			basePath.Child("query"),
+			"the query checking the health of the primary is required"))
+	}
+
+	if healthCheck.PeriodSeconds < 0 {
+		result = append(result, field.Invalid(
+			basePath.Child("periodSeconds"),
+			healthCheck.PeriodSeconds,
+			"must be a positive integer"))
+	}
+
+	if healthCheck.TimeoutSeconds < 0 {
+		result = append(result, field.Invalid(
+			basePath.Child("timeoutSeconds"),
+			healthCheck.TimeoutSeconds,
+			"must be a positive integer"))
+	}
+
+	if healthCheck.FailureThreshold < 0 {
+		result = append(result, field.Invalid(
+			basePath.Child("failureThreshold"),
+			healthCheck.FailureThreshold,
+			"must be a positive integer"))
+	}
+
+	if healthCheck.GetTimeout() >= healthCheck.GetPeriod() {
+		result = append(result, field.Invalid(
+			basePath.Child("timeoutSeconds"),
+			healthCheck.TimeoutSeconds,
+			"the timeout of the query must be lower than the period of the check"))
+	}
+
+	return result
+}
+
+// validateBackupEncryption checks the server-side encryption of the
+// base backups and of the WAL files in the object store
+func (r *Cluster) validateBackupEncryption() field.ErrorList {
+	if r.Spec.Backup == nil || r.Spec.Backup.BarmanObjectStore == nil {
+		return nil
+	}
+
+	configuration := r.Spec.Backup.BarmanObjectStore
+	if configuration.SSEKMSKeyID == "" {
+		return nil
+	}
+
+	var result field.ErrorList
+	basePath := field.NewPath("spec", "backup", "barmanObjectStore")
+
+	if configuration.AWS == nil {
+		result = append(result, field.Invalid(
+			basePath.Child("sseKmsKeyId"),
+			configuration.SSEKMSKeyID,
+			"a KMS key can only be used with s3Credentials"))
+	}
+
+	// The encryption types are validated by the CRD
+	usesKMS := (configuration.Wal != nil && configuration.Wal.Encryption == EncryptionTypeNoneAWSKMS) ||
+		(configuration.Data != nil && configuration.Data.Encryption == EncryptionTypeNoneAWSKMS)
+	if !usesKMS {
+		result = append(result, field.Invalid(
+			basePath.Child("sseKmsKeyId"),
+			configuration.SSEKMSKeyID,
+			"a KMS key requires the aws:kms encryption of the data or of the WAL files"))
+	}
+
+	return result
+}
+
+// validateBarmanEndpointCA checks the secrets containing the CA bundle
+// used to verify the certificates of the object store endpoints
+func (r *Cluster) validateBarmanEndpointCA() field.ErrorList {
+	var result field.ErrorList
+
+	validate := func(configuration *BarmanObjectStoreConfiguration, path *field.Path) {
+		if configuration == nil || configuration.EndpointCA == nil {
+			return
+		}
+
+		endpointCAPath := path.Child("endpointCA")
+		if configuration.EndpointCA.Name == "" {
+			result = append(result, field.Required(
+				endpointCAPath.Child("name"),
+				"the name of the secret containing the CA bundle is required"))
+		}
+
+		if configuration.EndpointCA.Key == "" {
+			result = append(result, field.Required(
+				endpointCAPath.Child("key"),
+				"the key of the CA bundle in the secret is required"))
+		}
+
+		if strings.HasPrefix(strings.ToLower(configuration.EndpointURL), "http://") {
+			result = append(result, field.Invalid(
+				endpointCAPath,
+				configuration.EndpointCA.Name,
+				"the CA bundle can only be used with an https endpointURL"))
+		}
+	}
+
+	if r.Spec.Backup != nil {
+		validate(r.Spec.Backup.BarmanObjectStore, field.NewPath("spec", "backup", "barmanObjectStore"))
+	}
+
+	for idx := range r.Spec.ExternalClusters {
+		validate(
+			r.Spec.ExternalClusters[idx].BarmanObjectStore,
+			field.NewPath("spec", "externalClusters").Index(idx).Child("barmanObjectStore"))
+	}
+
+	return result
+}
+
+// validateBackupBeforeUpgrade checks that the backup requested before
+// upgrading the instances can be taken
+func (r *Cluster) validateBackupBeforeUpgrade() field.ErrorList {
+	if r.Spec.Backup == nil || !r.Spec.Backup.BeforeUpgrade || r.Spec.Backup.BarmanObjectStore != nil {
+		return nil
+	}
+
+	return field.ErrorList{
+		field.Invalid(
+			field.NewPath("spec", "backup", "beforeUpgrade"),
+			r.Spec.Backup.BeforeUpgrade,
+			"taking a backup before the upgrade requires barmanObjectStore"),
+	}
+}
+
+// validateCustomWalArchive validates the custom commands used to archive and
+// restore the WAL files
+func (r *Cluster) validateCustomWalArchive() field.ErrorList {
+	if r.Spec.Backup == nil || r.Spec.Backup.CustomWalArchive == nil {
+		return nil
+	}
+
+	var result field.ErrorList
+	customWalArchive := r.Spec.Backup.CustomWalArchive
+	basePath := field.NewPath("spec", "backup", "customWalArchive")
+
+	if r.Spec.Backup.BarmanObjectStore != nil {
+		result = append(result, field.Invalid(
+			basePath,
+			customWalArchive,
+			"customWalArchive and barmanObjectStore are mutually exclusive"))
+	}
+
+	if len(customWalArchive.ArchiveCommand) == 0 || customWalArchive.ArchiveCommand[0] == "" {
+		result = append(result, field.Required(
+			basePath.Child("archiveCommand"),
+			"the executable archiving the WAL files is required"))
+	} else if !hasWalPlaceholders(customWalArchive.ArchiveCommand, "%p") {
+		result = append(result, field.Invalid(
+			basePath.Child("archiveCommand"),
+			customWalArchive.ArchiveCommand,
+			"the archive command must refer to the WAL file to archive with %p"))
+	}
+
+	if len(customWalArchive.RestoreCommand) == 0 {
+		return result
+	}
+
+	if customWalArchive.RestoreCommand[0] == "" {
+		result = append(result, field.Required(
+			basePath.Child("restoreCommand"),
+			"the executable restoring the WAL files is required"))
+	} else if !hasWalPlaceholders(customWalArchive.RestoreCommand, "%f", "%p") {
+		result = append(result, field.Invalid(
+			basePath.Child("restoreCommand"),
+			customWalArchive.RestoreCommand,
+			"the restore command must refer to the WAL file to restore with %f, "+
+				"and to its destination with %p"))
+	}
+
+	return result
+}
+
+// hasWalPlaceholders checks whether every placeholder is used in at
+// least one of the arguments of the command. The escaped `%%` sequences
+// are not considered as placeholders
+func hasWalPlaceholders(command []string, placeholders ...string) bool {
+	arguments := strings.ReplaceAll(strings.Join(command, " "), "%%", "")
+	for _, placeholder := range placeholders {
+		if !strings.Contains(arguments, placeholder) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// maxReplicationSlotNameLength is the maximum length of a replication
+// slot name accepted by PostgreSQL (NAMEDATALEN - 1)
+const maxReplicationSlotNameLength = 63
+
 func (r *Cluster) validateReplicationSlots() field.ErrorList {
 	replicationSlots := r.Spec.ReplicationSlots
 	if replicationSlots == nil ||
@@ -1521,23 +3334,42 @@ func (r *Cluster) validateReplicationSlots() field.ErrorList {
 		return nil
 	}
 
+	var result field.ErrorList
+
+	// PostgreSQL refuses replication slot names longer than 63 characters,
+	// and the longest slot name is the one of the instance having the
+	// highest serial
+	maxSerial := r.Spec.Instances
+	if r.Status.LatestGeneratedNode > maxSerial {
+		maxSerial = r.Status.LatestGeneratedNode
+	}
+	if slotName := replicationSlots.HighAvailability.GetSlotNameFromInstanceName(
+		fmt.Sprintf("%s-%d", r.Name, maxSerial)); len(slotName) > maxReplicationSlotNameLength {
+		result = append(result,
+			field.Invalid(
+				field.NewPath("spec", "replicationSlots", "highAvailability", "slotPrefix"),
+				replicationSlots.HighAvailability.SlotPrefix,
+				fmt.Sprintf("The replication slot names generated with this prefix, such as %q, "+
+					"would be longer than %d characters", slotName, maxReplicationSlotNameLength)))
+	}
+
 	psqlVersion, err := r.GetPostgresqlVersion()
 	if err != nil {
 		// The validation error will be already raised by the
 		// validateImageName function
-		return nil
+		return result
 	}
 
 	if psqlVersion >= 110000 {
-		return nil
+		return result
 	}
 
-	return field.ErrorList{
+	return append(result,
 		field.Invalid(
 			field.NewPath("spec", "replicationSlots", "highAvailability", "enabled"),
 			replicationSlots.HighAvailability.Enabled,
 			"Cannot enable replication slot high availability. It requires PostgreSQL 11 or above"),
-	}
+	)
 }
 
 func (r *Cluster) validateReplicationSlotsChange(old *Cluster) field.ErrorList {
@@ -1576,6 +3408,127 @@ func (r *Cluster) validateReplicationSlotsChange(old *Cluster) field.ErrorList {
 	return errs
 }
 
+// validateManagedRoles validate the role management configuration
+func (r *Cluster) validateManagedRoles() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.Managed == nil {
+		return nil
+	}
+
+	path := field.NewPath("spec", "managed", "roles")
+	seen := make(map[string]bool, len(r.Spec.Managed.Roles))
+	for idx, role := range r.Spec.Managed.Roles {
+		rolePath := path.Index(idx).Child("name")
+
+		if seen[role.Name] {
+			result = append(result, field.Duplicate(rolePath, role.Name))
+			continue
+		}
+		seen[role.Name] = true
+
+		switch {
+		case role.Name == "postgres" || role.Name == StreamingReplicationUser || role.Name == PGBouncerPoolerUserName:
+			result = append(result, field.Invalid(
+				rolePath,
+				role.Name,
+				"This role is reserved for operator use"))
+		case strings.HasPrefix(role.Name, "pg_"):
+			result = append(result, field.Invalid(
+				rolePath,
+				role.Name,
+				"The 'pg_' prefix is reserved by PostgreSQL for system roles"))
+		}
+	}
+
+	return result
+}
+
+// validateManagedDatabases validate the database management configuration
+func (r *Cluster) validateManagedDatabases() field.ErrorList {
+	var result field.ErrorList
+
+	if r.Spec.Managed == nil || len(r.Spec.Managed.Databases) == 0 {
+		return nil
+	}
+
+	owners := map[string]bool{}
+	if owner := r.GetApplicationDatabaseOwner(); owner != "" {
+		owners[owner] = true
+	}
+	for _, role := range r.Spec.Managed.Roles {
+		if role.GetEnsure() == EnsurePresent {
+			owners[role.Name] = true
+		}
+	}
+
+	path := field.NewPath("spec", "managed", "databases")
+	seen := make(map[string]bool, len(r.Spec.Managed.Databases))
+	for idx, database := range r.Spec.Managed.Databases {
+		databasePath := path.Index(idx)
+		namePath := databasePath.Child("name")
+
+		if seen[database.Name] {
+			result = append(result, field.Duplicate(namePath, database.Name))
+			continue
+		}
+		seen[database.Name] = true
+
+		switch {
+		case database.Name == "":
+			result = append(result, field.Required(namePath, "the name of the database is required"))
+			continue
+		case database.Name == "postgres" || database.Name == "template0" || database.Name == "template1":
+			result = append(result, field.Invalid(
+				namePath,
+				database.Name,
+				"This database is reserved for PostgreSQL"))
+			continue
+		}
+
+		if database.GetEnsure() == EnsureAbsent {
+			if database.Name == r.GetApplicationDatabaseName() {
+				result = append(result, field.Invalid(
+					databasePath.Child("ensure"),
+					database.Ensure,
+					"The application database cannot be dropped"))
+			}
+			if len(database.Extensions) > 0 {
+				result = append(result, field.Invalid(
+					databasePath.Child("extensions"),
+					database.Extensions,
+					"The extensions cannot be installed in a database which should be absent"))
+			}
+			continue
+		}
+
+		if database.Owner == "" {
+			result = append(result, field.Required(
+				databasePath.Child("owner"),
+				"the owner of a database which should be present is required"))
+		} else if !owners[database.Owner] {
+			result = append(result, field.Invalid(
+				databasePath.Child("owner"),
+				database.Owner,
+				"The owner must be one of the managed roles or the owner of the application database"))
+		}
+
+		seenExtensions := make(map[string]bool, len(database.Extensions))
+		for extensionIdx, extension := range database.Extensions {
+			extensionPath := databasePath.Child("extensions").Index(extensionIdx)
+			switch {
+			case extension == "":
+				result = append(result, field.Required(extensionPath, "the name of the extension is required"))
+			case seenExtensions[extension]:
+				result = append(result, field.Duplicate(extensionPath, extension))
+			}
+			seenExtensions[extension] = true
+		}
+	}
+
+	return result
+}
+
 // validateAzureCredentials checks and validates the azure credentials
 func (azure *AzureCredentials) validateAzureCredentials(path *field.Path) field.ErrorList {
 	allErrors := field.ErrorList{}
@@ -1685,3 +3638,118 @@ func (gcs *GoogleCredentials) validateGCSCredentials(path *field.Path) field.Err
 
 	return allErrors
 }
+
+// pgHBAMinFields is the minimum number of fields required by a
+// pg_hba.conf rule, depending on its connection type
+var pgHBAMinFields = map[string]int{
+	"local":        4,
+	"host":         5,
+	"hostssl":      5,
+	"hostnossl":    5,
+	"hostgssenc":   5,
+	"hostnogssenc": 5,
+}
+
+// validatePgHBA checks that every user-defined rule which will be
+// added to pg_hba.conf has the right number of fields
+func (r *Cluster) validatePgHBA() field.ErrorList {
+	var result field.ErrorList
+
+	path := field.NewPath("spec", "postgresql", "pg_hba")
+	for idx, rule := range r.Spec.PostgresConfiguration.PgHBA {
+		fields := splitAuthenticationRule(rule)
+		if len(fields) == 0 {
+			// empty lines and comments are allowed
+			continue
+		}
+
+		minFields, ok := pgHBAMinFields[fields[0]]
+		if !ok {
+			result = append(result, field.Invalid(
+				path.Index(idx),
+				rule,
+				fmt.Sprintf("unknown connection type %q", fields[0])))
+			continue
+		}
+
+		if len(fields) < minFields {
+			result = append(result, field.Invalid(
+				path.Index(idx),
+				rule,
+				fmt.Sprintf("a %q rule requires at least %d fields, found %d",
+					fields[0], minFields, len(fields))))
+		}
+	}
+
+	return result
+}
+
+// validatePgIdent checks that every user-defined rule which will be
+// added to pg_ident.conf is made of a map name, a system user name and
+// a database user name, and doesn't use the map reserved to the operator
+func (r *Cluster) validatePgIdent() field.ErrorList {
+	var result field.ErrorList
+
+	path := field.NewPath("spec", "postgresql", "pg_ident")
+	for idx, rule := range r.Spec.PostgresConfiguration.PgIdent {
+		fields := splitAuthenticationRule(rule)
+		if len(fields) == 0 {
+			// empty lines and comments are allowed
+			continue
+		}
+
+		if len(fields) != 3 {
+			result = append(result, field.Invalid(
+				path.Index(idx),
+				rule,
+				fmt.Sprintf("a user name map requires 3 fields, found %d", len(fields))))
+			continue
+		}
+
+		if fields[0] == "local" {
+			result = append(result, field.Invalid(
+				path.Index(idx),
+				rule,
+				"the \"local\" map is reserved to the operator"))
+		}
+	}
+
+	return result
+}
+
+// splitAuthenticationRule splits a pg_hba.conf or pg_ident.conf rule in
+// its fields, following the PostgreSQL rules: fields are separated by
+// spaces unless quoted, and everything following a "#" is a comment
+func splitAuthenticationRule(rule string) []string {
+	var fields []string
+	var current strings.Builder
+	inQuotes := false
+
+	for _, c := range rule {
+		switch {
+		case c == '"':
+			inQuotes = !inQuotes
+			current.WriteRune(c)
+		case inQuotes:
+			current.WriteRune(c)
+		case c == '#':
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+			}
+			return fields
+		case c == ' ' || c == '\t':
+			if current.Len() > 0 {
+				fields = append(fields, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(c)
+		}
+	}
+
+	if current.Len() > 0 {
+		fields = append(fields, current.String())
+	}
+
+	return fields
+}
@@ -17,13 +17,21 @@ limitations under the License.
 package v1
 
 import (
+	"context"
+	"encoding/json"
 	"strings"
 
+	admissionv1 "k8s.io/api/admission/v1"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -234,6 +242,69 @@ var _ = Describe("initdb options validation", func() {
 		Expect(result).To(BeEmpty())
 	})
 
+	It("complains if waiting for the first archived WAL file without backups", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						WaitForArchive: true,
+					},
+				},
+			},
+		}
+
+		result := cluster.validateInitDB()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.waitForArchive"))
+	})
+
+	It("doesn't complain if waiting for the first archived WAL file with backups", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						WaitForArchive: true,
+					},
+				},
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						DestinationPath: "s3://bucket/",
+					},
+				},
+			},
+		}
+
+		Expect(cluster.validateInitDB()).To(BeEmpty())
+	})
+
+	It("accepts a WAL segment size which is a power of two in the allowed range", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						WalSegmentSize: 64,
+					},
+				},
+			},
+		}
+
+		Expect(cluster.validateInitDB()).To(BeEmpty())
+	})
+
+	It("complains if the WAL segment size is not a power of two", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						WalSegmentSize: 48,
+					},
+				},
+			},
+		}
+
+		Expect(cluster.validateInitDB()).To(HaveLen(1))
+	})
+
 	It("complains if you specify the database name but not the owner", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
@@ -614,6 +685,368 @@ var _ = Describe("Defaulting webhook", func() {
 	})
 })
 
+var _ = Describe("maxSyncReplicas and topology warnings", func() {
+	newCluster := func() *Cluster {
+		return &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: ClusterSpec{
+				Instances:       5,
+				MaxSyncReplicas: 3,
+				Affinity: AffinityConfiguration{
+					PodAntiAffinityType: PodAntiAffinityTypeRequired,
+					TopologyKey:         "topology.kubernetes.io/zone",
+				},
+			},
+		}
+	}
+
+	It("warns when the synchronous replicas need too many zones", func() {
+		Expect(newCluster().getMaxSyncReplicasTopologyWarnings()).To(HaveLen(1))
+	})
+
+	It("doesn't warn when the zones are likely enough", func() {
+		cluster := newCluster()
+		cluster.Spec.MaxSyncReplicas = 2
+		Expect(cluster.getMaxSyncReplicasTopologyWarnings()).To(BeEmpty())
+	})
+
+	It("doesn't warn when the instances are not required to be in different zones", func() {
+		cluster := newCluster()
+		cluster.Spec.Affinity.PodAntiAffinityType = PodAntiAffinityTypePreferred
+		Expect(cluster.getMaxSyncReplicasTopologyWarnings()).To(BeEmpty())
+
+		cluster = newCluster()
+		cluster.Spec.Affinity.TopologyKey = "kubernetes.io/hostname"
+		Expect(cluster.getMaxSyncReplicasTopologyWarnings()).To(BeEmpty())
+
+		cluster = newCluster()
+		disabled := false
+		cluster.Spec.Affinity.EnablePodAntiAffinity = &disabled
+		Expect(cluster.getMaxSyncReplicasTopologyWarnings()).To(BeEmpty())
+	})
+
+	It("warns when the synchronous replica election constraints use zones", func() {
+		cluster := newCluster()
+		cluster.Spec.Affinity = AffinityConfiguration{}
+		cluster.Spec.PostgresConfiguration.SyncReplicaElectionConstraint = SyncReplicaElectionConstraints{
+			Enabled:                true,
+			NodeLabelsAntiAffinity: []string{"topology.kubernetes.io/zone"},
+		}
+		Expect(cluster.getMaxSyncReplicasTopologyWarnings()).To(HaveLen(1))
+	})
+
+	It("reports the warnings in the admission response", func() {
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).To(Succeed())
+		decoder, err := admission.NewDecoder(scheme)
+		Expect(err).ToNot(HaveOccurred())
+
+		handler := &clusterWarningsHandler{validator: admission.ValidatingWebhookFor(&Cluster{}).Handler}
+		Expect(handler.InjectDecoder(decoder)).To(Succeed())
+
+		cluster := newCluster()
+		cluster.Spec.MinSyncReplicas = 1
+		cluster.Spec.StorageConfiguration = StorageConfiguration{Size: "1Gi"}
+		cluster.Default()
+		rawCluster, err := json.Marshal(cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		response := handler.Handle(context.TODO(), admission.Request{
+			AdmissionRequest: admissionv1.AdmissionRequest{
+				Operation: admissionv1.Create,
+				Object:    runtime.RawExtension{Raw: rawCluster},
+			},
+		})
+		Expect(response.Allowed).To(BeTrue())
+		Expect(response.Warnings).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("initdb options warnings", func() {
+	It("doesn't warn when the deprecated options are not used", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Encoding:      "UTF8",
+						LocaleCollate: "de_DE.UTF-8",
+						LocaleCType:   "de_DE.UTF-8",
+					},
+				},
+			},
+		}
+		Expect(cluster.getInitDBOptionsWarnings()).To(BeEmpty())
+	})
+
+	It("doesn't warn when the explicit settings have the default values", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Options:       []string{"--locale=de_DE.UTF-8"},
+						Encoding:      "UTF8",
+						LocaleCollate: "C",
+						LocaleCType:   "C",
+					},
+				},
+			},
+		}
+		Expect(cluster.getInitDBOptionsWarnings()).To(BeEmpty())
+	})
+
+	It("warns when the explicit settings would be ignored", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Options:       []string{"-k"},
+						Encoding:      "UTF8",
+						LocaleCollate: "de_DE.UTF-8",
+						LocaleCType:   "de_DE.UTF-8",
+					},
+				},
+			},
+		}
+		Expect(cluster.getInitDBOptionsWarnings()).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("automatic failover validation", func() {
+	disabled := false
+
+	It("doesn't complain when the automatic failover is enabled", func() {
+		cluster := Cluster{Spec: ClusterSpec{Instances: 3}}
+		Expect(cluster.validateAutomaticFailover()).To(BeEmpty())
+	})
+
+	It("complains when the operator could promote a replica during updates", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Instances:               3,
+				EnableAutomaticFailover: &disabled,
+				PrimaryUpdateStrategy:   PrimaryUpdateStrategyUnsupervised,
+			},
+		}
+		Expect(cluster.validateAutomaticFailover()).To(HaveLen(1))
+	})
+
+	It("accepts a supervised update strategy or the restart update method", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Instances:               3,
+				EnableAutomaticFailover: &disabled,
+				PrimaryUpdateStrategy:   PrimaryUpdateStrategySupervised,
+			},
+		}
+		Expect(cluster.validateAutomaticFailover()).To(BeEmpty())
+
+		cluster.Spec.PrimaryUpdateStrategy = PrimaryUpdateStrategyUnsupervised
+		cluster.Spec.PrimaryUpdateMethod = PrimaryUpdateMethodRestart
+		Expect(cluster.validateAutomaticFailover()).To(BeEmpty())
+	})
+
+	It("doesn't complain for single instance clusters", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Instances:               1,
+				EnableAutomaticFailover: &disabled,
+			},
+		}
+		Expect(cluster.validateAutomaticFailover()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("even number of instances warnings", func() {
+	It("warns when the number of instances is even", func() {
+		for _, instances := range []int{2, 4} {
+			cluster := Cluster{Spec: ClusterSpec{Instances: instances}}
+			Expect(cluster.getEvenInstancesWarnings()).To(HaveLen(1))
+		}
+	})
+
+	It("doesn't warn when the number of instances is odd or one", func() {
+		for _, instances := range []int{1, 3, 5} {
+			cluster := Cluster{Spec: ClusterSpec{Instances: instances}}
+			Expect(cluster.getEvenInstancesWarnings()).To(BeEmpty())
+		}
+	})
+})
+
+var _ = Describe("durability warnings", func() {
+	backup := &BackupConfiguration{
+		BarmanObjectStore: &BarmanObjectStoreConfiguration{DestinationPath: "s3://bucket/"},
+	}
+
+	It("warns when durability is disabled on a cluster with backups", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: backup,
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"fsync":              "off",
+						"full_page_writes":   "False",
+						"synchronous_commit": "0",
+					},
+				},
+			},
+		}
+		warnings := cluster.getDurabilityWarnings()
+		Expect(warnings).To(HaveLen(3))
+		Expect(warnings[0]).To(ContainSubstring("unrecoverable corruption"))
+		Expect(warnings[1]).To(ContainSubstring("unrecoverable corruption"))
+		Expect(warnings[2]).ToNot(ContainSubstring("corruption"))
+		Expect(warnings[2]).To(ContainSubstring("committed transactions can be lost"))
+	})
+
+	It("doesn't warn when durability is preserved", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: backup,
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{
+						"fsync":              "on",
+						"synchronous_commit": "local",
+					},
+				},
+			},
+		}
+		Expect(cluster.getDurabilityWarnings()).To(BeEmpty())
+	})
+
+	It("doesn't warn when backups are not enabled", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"fsync": "off"},
+				},
+			},
+		}
+		Expect(cluster.getDurabilityWarnings()).To(BeEmpty())
+	})
+
+	It("recognizes the spellings of a false boolean", func() {
+		for _, value := range []string{"off", "of", "OFF", "false", "f", "no", "n", "0"} {
+			Expect(isPostgresBooleanOff(value)).To(BeTrue(), value)
+		}
+		for _, value := range []string{"on", "o", "true", "yes", "1", "", "local"} {
+			Expect(isPostgresBooleanOff(value)).To(BeFalse(), value)
+		}
+	})
+})
+
+var _ = Describe("min sync replicas warnings", func() {
+	It("warns when maxSyncReplicas is set without minSyncReplicas", func() {
+		cluster := Cluster{Spec: ClusterSpec{Instances: 3, MaxSyncReplicas: 2}}
+		Expect(cluster.getMinSyncReplicasWarnings()).To(HaveLen(1))
+	})
+
+	It("doesn't warn when minSyncReplicas is set", func() {
+		cluster := Cluster{Spec: ClusterSpec{Instances: 3, MinSyncReplicas: 1, MaxSyncReplicas: 2}}
+		Expect(cluster.getMinSyncReplicasWarnings()).To(BeEmpty())
+	})
+
+	It("doesn't warn when synchronous replication is not used", func() {
+		cluster := Cluster{Spec: ClusterSpec{Instances: 3}}
+		Expect(cluster.getMinSyncReplicasWarnings()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("single instance sync replicas warnings", func() {
+	It("warns when a cluster with no replicas requests synchronous replication", func() {
+		cluster := Cluster{Spec: ClusterSpec{Instances: 1, MinSyncReplicas: 1, MaxSyncReplicas: 2}}
+		Expect(cluster.getSingleInstanceSyncReplicasWarnings()).To(HaveLen(1))
+	})
+
+	It("doesn't warn when the cluster has replicas", func() {
+		cluster := Cluster{Spec: ClusterSpec{Instances: 3, MinSyncReplicas: 1, MaxSyncReplicas: 2}}
+		Expect(cluster.getSingleInstanceSyncReplicasWarnings()).To(BeEmpty())
+	})
+
+	It("doesn't warn when synchronous replication is not used", func() {
+		cluster := Cluster{Spec: ClusterSpec{Instances: 1}}
+		Expect(cluster.getSingleInstanceSyncReplicasWarnings()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Command override validation", func() {
+	It("doesn't complain if there are no overrides", func() {
+		var cluster Cluster
+		Expect(cluster.validateCommandOverride()).To(BeEmpty())
+	})
+
+	It("complains if the overrides are not acknowledged", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ArgsOverride: []string{"--log-level", "debug"},
+			},
+		}
+		result := cluster.validateCommandOverride()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.acknowledgeCommandOverride"))
+
+		cluster.Spec.AcknowledgeCommandOverride = true
+		Expect(cluster.validateCommandOverride()).To(BeEmpty())
+	})
+
+	It("accepts a command wrapping the instance manager", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				CommandOverride:            []string{"/usr/bin/tini", "--"},
+				ArgsOverride:               []string{"/controller/manager", "instance", "run"},
+				AcknowledgeCommandOverride: true,
+			},
+		}
+		Expect(cluster.validateCommandOverride()).To(BeEmpty())
+	})
+
+	It("complains if the command doesn't run the instance manager", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				CommandOverride:            []string{"/bin/sh", "-c", "/controller/manager instance run"},
+				AcknowledgeCommandOverride: true,
+			},
+		}
+		result := cluster.validateCommandOverride()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.commandOverride"))
+	})
+})
+
+var _ = Describe("Metrics TLS validation", func() {
+	It("accepts TLS when the command is not overridden", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Monitoring: &MonitoringConfiguration{
+					TLSConfig: &ClusterMonitoringTLSConfiguration{Enabled: true},
+				},
+			},
+		}
+		Expect(cluster.validateMetricsTLS()).To(BeEmpty())
+	})
+
+	It("requires the TLS option in an overridden command line", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				CommandOverride:            []string{"/usr/bin/tini", "--"},
+				ArgsOverride:               []string{"/controller/manager", "instance", "run"},
+				AcknowledgeCommandOverride: true,
+				Monitoring: &MonitoringConfiguration{
+					TLSConfig: &ClusterMonitoringTLSConfiguration{Enabled: true},
+				},
+			},
+		}
+		result := cluster.validateMetricsTLS()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.monitoring.tls.enabled"))
+
+		cluster.Spec.ArgsOverride = append(cluster.Spec.ArgsOverride, "--metrics-port-tls")
+		Expect(cluster.validateMetricsTLS()).To(BeEmpty())
+	})
+})
+
 var _ = Describe("Image name validation", func() {
 	It("doesn't complain if the user simply accept the default", func() {
 		var cluster Cluster
@@ -660,13 +1093,13 @@ var _ = Describe("Image name validation", func() {
 		Expect(len(cluster.validateImageName())).To(Equal(0))
 	})
 
-	It("complains when only the sha is passed", func() {
+	It("doesn't complain when only the sha is passed", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
 				ImageName: "postgres@sha256:cff94de382ca538861622bbe84cfe03f44f307a9846a5c5eda672cf4dc692866",
 			},
 		}
-		Expect(len(cluster.validateImageName())).To(Equal(1))
+		Expect(cluster.validateImageName()).To(BeEmpty())
 	})
 
 	It("doesn't complain if the tag is valid", func() {
@@ -790,6 +1223,38 @@ var _ = Describe("validate image name change", func() {
 		}
 		Expect(len(clusterNew.validateImageChange("postgres:12.1"))).To(Equal(0))
 	})
+
+	It("doesn't complain when changing the digest of an image", func() {
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:12.1@sha256:cff94de382ca538861622bbe84cfe03f44f307a9846a5c5eda672cf4dc692866",
+			},
+		}
+		Expect(clusterNew.validateImageChange(
+			"postgres:12.0@sha256:3f0a5716a8b03c4a3d7a8ba5d8d4b5b1a4bcd9e8a9e1f4a76c9c0cfa2b5d1e2f")).To(BeEmpty())
+		Expect(clusterNew.validateImageChange("postgres:12.0")).To(BeEmpty())
+	})
+
+	It("complains when changing the digest of an image to a different major version", func() {
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:13.1@sha256:cff94de382ca538861622bbe84cfe03f44f307a9846a5c5eda672cf4dc692866",
+			},
+		}
+		Expect(clusterNew.validateImageChange(
+			"postgres:12.0@sha256:3f0a5716a8b03c4a3d7a8ba5d8d4b5b1a4bcd9e8a9e1f4a76c9c0cfa2b5d1e2f")).To(HaveLen(1))
+	})
+
+	It("doesn't complain when changing an image referenced only by its digest", func() {
+		clusterNew := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres@sha256:cff94de382ca538861622bbe84cfe03f44f307a9846a5c5eda672cf4dc692866",
+			},
+		}
+		Expect(clusterNew.validateImageChange(
+			"postgres@sha256:3f0a5716a8b03c4a3d7a8ba5d8d4b5b1a4bcd9e8a9e1f4a76c9c0cfa2b5d1e2f")).To(BeEmpty())
+		Expect(clusterNew.validateImageChange("postgres:12.0")).To(BeEmpty())
+	})
 })
 
 var _ = Describe("recovery target", func() {
@@ -1063,15 +1528,157 @@ var _ = Describe("recovery target", func() {
 			Expect(len(cluster.validateRecoveryTarget())).To(Equal(1))
 		})
 	})
-})
 
-var _ = Describe("primary update strategy", func() {
-	It("allows 'unsupervised'", func() {
+	It("rejects an empty recovery target", func() {
 		cluster := Cluster{
 			Spec: ClusterSpec{
-				PrimaryUpdateStrategy: PrimaryUpdateStrategyUnsupervised,
-				Instances:             3,
-			},
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						RecoveryTarget: &RecoveryTarget{},
+					},
+				},
+			},
+		}
+		errs := cluster.validateRecoveryTargetPresence()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.bootstrap.recovery.recoveryTarget"))
+	})
+
+	It("accepts a recovery target only choosing the backup", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						RecoveryTarget: &RecoveryTarget{BackupID: "20220616T031500"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateRecoveryTargetPresence()).To(BeEmpty())
+	})
+
+	It("rejects exclusive without a recovery target", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						RecoveryTarget: &RecoveryTarget{
+							TargetTLI: "latest",
+							Exclusive: pointer.Bool(true),
+						},
+					},
+				},
+			},
+		}
+		errs := cluster.validateRecoveryTargetPresence()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.bootstrap.recovery.recoveryTarget.exclusive"))
+
+		cluster.Spec.Bootstrap.Recovery.RecoveryTarget.TargetLSN = "0/1000000"
+		Expect(cluster.validateRecoveryTargetPresence()).To(BeEmpty())
+	})
+
+	It("accepts the update of a cluster with a recovery target without effect", func() {
+		oldCluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						RecoveryTarget: &RecoveryTarget{
+							Exclusive: pointer.Bool(true),
+						},
+					},
+				},
+			},
+		}
+		cluster := oldCluster.DeepCopy()
+		cluster.Spec.Instances = 3
+		Expect(cluster.validateRecoveryTarget()).To(BeEmpty())
+		Expect(cluster.ValidateChanges(&oldCluster)).To(BeEmpty())
+	})
+
+	It("rejects backupID when recovering from a Backup object", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Backup: &BackupSource{
+							LocalObjectReference: LocalObjectReference{Name: "backup-example"},
+						},
+						RecoveryTarget: &RecoveryTarget{
+							BackupID:  "20220616T031500",
+							TargetLSN: "0/1000000",
+						},
+					},
+				},
+			},
+		}
+		errs := cluster.validateRecoveryTarget()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.bootstrap.recovery.recoveryTarget.backupID"))
+
+		cluster.Spec.Bootstrap.Recovery.RecoveryTarget.BackupID = ""
+		Expect(cluster.validateRecoveryTarget()).To(BeEmpty())
+	})
+
+	It("rejects targetImmediate combined with a specific target", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						RecoveryTarget: &RecoveryTarget{
+							BackupID:        "20220616T031500",
+							TargetImmediate: pointer.Bool(true),
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateRecoveryTarget()).To(BeEmpty())
+
+		for _, target := range []RecoveryTarget{
+			{TargetTime: "2021-09-01 10:22:47.000000+06"},
+			{TargetXID: "3"},
+			{TargetLSN: "0/1000000"},
+			{TargetName: "restore_point"},
+		} {
+			target.BackupID = "20220616T031500"
+			target.TargetImmediate = pointer.Bool(true)
+			cluster.Spec.Bootstrap.Recovery.RecoveryTarget = target.DeepCopy()
+			errs := cluster.validateRecoveryTarget()
+			Expect(errs).To(HaveLen(1))
+			Expect(errs[0].Field).To(Equal("spec.bootstrap.recovery.recoveryTarget.targetImmediate"))
+		}
+	})
+
+	It("ignores targetImmediate when it is disabled", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						RecoveryTarget: &RecoveryTarget{
+							TargetLSN:       "0/1000000",
+							TargetImmediate: pointer.Bool(false),
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateRecoveryTarget()).To(BeEmpty())
+
+		cluster.Spec.Bootstrap.Recovery.RecoveryTarget.TargetLSN = ""
+		errs := cluster.validateRecoveryTargetPresence()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.bootstrap.recovery.recoveryTarget"))
+	})
+})
+
+var _ = Describe("primary update strategy", func() {
+	It("allows 'unsupervised'", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PrimaryUpdateStrategy: PrimaryUpdateStrategyUnsupervised,
+				Instances:             3,
+			},
 		}
 		Expect(cluster.validatePrimaryUpdateStrategy()).To(BeEmpty())
 	})
@@ -1167,6 +1774,26 @@ var _ = Describe("Number of synchronous replicas", func() {
 		}
 		Expect(cluster.validateMaxSyncReplicas()).To(BeEmpty())
 	})
+
+	It("can be kept when the cluster is scaled down to the primary alone", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Instances:       1,
+				MaxSyncReplicas: 2,
+			},
+		}
+		Expect(cluster.validateMaxSyncReplicas()).To(BeEmpty())
+	})
+
+	It("should be lower than the number of instances when scaling back up", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Instances:       2,
+				MaxSyncReplicas: 2,
+			},
+		}
+		Expect(cluster.validateMaxSyncReplicas()).ToNot(BeEmpty())
+	})
 })
 
 var _ = Describe("storage configuration validation", func() {
@@ -1497,7 +2124,8 @@ var _ = Describe("bootstrap recovery validation", func() {
 				},
 				ExternalClusters: []ExternalCluster{
 					{
-						Name: "test",
+						Name:              "test",
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{},
 					},
 				},
 			},
@@ -1506,6 +2134,29 @@ var _ = Describe("bootstrap recovery validation", func() {
 		Expect(errorsList).To(BeEmpty())
 	})
 
+	It("complains when bootstrap recovery source can only be reached via streaming", func() {
+		recoveryCluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{
+						Source: "test",
+					},
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "test",
+						ConnectionParameters: map[string]string{
+							"host": "cluster-example-rw",
+						},
+					},
+				},
+			},
+		}
+		errorsList := recoveryCluster.validateBootstrapRecoverySource()
+		Expect(errorsList).To(HaveLen(1))
+		Expect(errorsList[0].Field).To(Equal("spec.bootstrap.recovery.source"))
+	})
+
 	It("complains when bootstrap recovery source does not match one of the names of external clusters", func() {
 		recoveryCluster := &Cluster{
 			Spec: ClusterSpec{
@@ -1564,6 +2215,35 @@ var _ = Describe("toleration validation", func() {
 	})
 })
 
+var _ = Describe("dedicated nodes taint key validation", func() {
+	It("doesn't complain if the taint key is not set", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateDedicatedNodesTaintKey()).To(BeEmpty())
+	})
+
+	It("doesn't complain if we provide a proper taint key", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Affinity: AffinityConfiguration{
+					DedicatedNodesTaintKey: "node-role.kubernetes.io/postgres",
+				},
+			},
+		}
+		Expect(cluster.validateDedicatedNodesTaintKey()).To(BeEmpty())
+	})
+
+	It("complains if the taint key is not a valid label name", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Affinity: AffinityConfiguration{
+					DedicatedNodesTaintKey: "postgres nodes",
+				},
+			},
+		}
+		Expect(cluster.validateDedicatedNodesTaintKey()).To(HaveLen(1))
+	})
+})
+
 var _ = Describe("validate anti-affinity", func() {
 	t := true
 	f := false
@@ -1762,628 +2442,2783 @@ var _ = Describe("unix permissions identifiers change validation", func() {
 	})
 })
 
-var _ = Describe("replica mode validation", func() {
-	It("complains if the bootstrap method is not specified", func() {
-		cluster := &Cluster{
-			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "test",
-					},
+var _ = Describe("minimum PostgreSQL version validation", func() {
+	path := field.NewPath("spec", "feature")
+
+	It("complains when the image is older than the required version", func() {
+		cluster := Cluster{Spec: ClusterSpec{ImageName: "postgres:11.19"}}
+		result := cluster.validateMinimumPostgresVersion(path, true, "the feature", 120000)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Detail).To(Equal("the feature requires PostgreSQL 12 or above"))
+	})
+
+	It("accepts an image satisfying the required version", func() {
+		cluster := Cluster{Spec: ClusterSpec{ImageName: "postgres:12.14"}}
+		Expect(cluster.validateMinimumPostgresVersion(path, true, "the feature", 120000)).To(BeEmpty())
+	})
+
+	It("complains when the version can't be detected from an image referenced only by its digest", func() {
+		cluster := Cluster{Spec: ClusterSpec{
+			ImageName: "postgres@sha256:cff94de382ca538861622bbe84cfe03f44f307a9846a5c5eda672cf4dc692866",
+		}}
+		Expect(cluster.validateMinimumPostgresVersion(path, true, "the feature", 120000)).To(HaveLen(1))
+	})
+
+	It("accepts an image referenced by its tag and digest", func() {
+		cluster := Cluster{Spec: ClusterSpec{
+			ImageName: "postgres:12.14@sha256:cff94de382ca538861622bbe84cfe03f44f307a9846a5c5eda672cf4dc692866",
+		}}
+		Expect(cluster.validateMinimumPostgresVersion(path, true, "the feature", 120000)).To(BeEmpty())
+	})
+
+	It("leaves the invalid tags to the validation of the image name", func() {
+		cluster := Cluster{Spec: ClusterSpec{ImageName: "postgres:test_12"}}
+		Expect(cluster.validateMinimumPostgresVersion(path, true, "the feature", 120000)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("instance names", func() {
+	cluster := Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"}}
+
+	It("recognizes the names of the instances", func() {
+		Expect(cluster.isInstanceName("cluster-example-1")).To(BeTrue())
+		Expect(cluster.isInstanceName("cluster-example-12")).To(BeTrue())
+	})
+
+	It("rejects the serials not written as the operator does", func() {
+		Expect(cluster.isInstanceName("cluster-example-01")).To(BeFalse())
+		Expect(cluster.isInstanceName("cluster-example-0")).To(BeFalse())
+		Expect(cluster.isInstanceName("cluster-example--1")).To(BeFalse())
+		Expect(cluster.isInstanceName("cluster-example-+1")).To(BeFalse())
+	})
+
+	It("rejects the names of other objects", func() {
+		Expect(cluster.isInstanceName("cluster-example")).To(BeFalse())
+		Expect(cluster.isInstanceName("cluster-example-rw")).To(BeFalse())
+		Expect(cluster.isInstanceName("other-cluster-1")).To(BeFalse())
+	})
+})
+
+var _ = Describe("replay paused instances validation", func() {
+	newCluster := func(annotation string) *Cluster {
+		return &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example",
+				Annotations: map[string]string{
+					utils.ReplayPausedInstancesAnnotation: annotation,
 				},
 			},
+			Spec: ClusterSpec{
+				Instances:       3,
+				MinSyncReplicas: 1,
+				MaxSyncReplicas: 1,
+			},
 		}
-		Expect(cluster.validateReplicaMode()).ToNot(BeEmpty())
+	}
+
+	It("doesn't complain when there is no annotation", func() {
+		cluster := Cluster{Spec: ClusterSpec{Instances: 3}}
+		Expect(cluster.validateReplayPausedInstances()).To(BeEmpty())
 	})
 
-	It("complains if the initdb bootstrap method is used", func() {
-		cluster := &Cluster{
+	It("complains when the annotation has an invalid syntax", func() {
+		Expect(newCluster("cluster-example-2").validateReplayPausedInstances()).To(HaveLen(1))
+	})
+
+	It("accepts pausing a replica not needed for synchronous replication", func() {
+		Expect(newCluster(`["cluster-example-2"]`).validateReplayPausedInstances()).To(BeEmpty())
+	})
+
+	It("complains when the synchronous replicas would be paused", func() {
+		cluster := newCluster(`["cluster-example-2","cluster-example-3"]`)
+		Expect(cluster.validateReplayPausedInstances()).To(HaveLen(1))
+	})
+
+	It("only counts the paused instances which are replicas of the cluster", func() {
+		cluster := newCluster(`["cluster-example-1","cluster-example-2","cluster-example-7"]`)
+		Expect(cluster.validateReplayPausedInstances()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("resources overrides validation", func() {
+	newCluster := func(overrides map[string]v1.ResourceRequirements) *Cluster {
+		return &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{},
-				},
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "test",
+				Resources: v1.ResourceRequirements{
+					Requests: v1.ResourceList{
+						v1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+					Limits: v1.ResourceList{
+						v1.ResourceMemory: resource.MustParse("2Gi"),
 					},
 				},
+				ResourcesOverrides: overrides,
 			},
 		}
-		Expect(cluster.validateReplicaMode()).ToNot(BeEmpty())
+	}
+
+	It("doesn't complain when there are no overrides", func() {
+		Expect(newCluster(nil).validateResourcesOverrides()).To(BeEmpty())
 	})
 
-	It("is valid when the pg_basebackup bootstrap option is used", func() {
-		cluster := &Cluster{
-			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
-				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{},
-				},
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "test",
-					},
-				},
+	It("accepts an override with requests lower than the limits", func() {
+		cluster := newCluster(map[string]v1.ResourceRequirements{
+			"cluster-example-3": {
+				Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("4Gi")},
+				Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("4Gi")},
+			},
+		})
+		Expect(cluster.validateResourcesOverrides()).To(BeEmpty())
+	})
+
+	It("complains when the request exceeds the inherited limit", func() {
+		cluster := newCluster(map[string]v1.ResourceRequirements{
+			"cluster-example-3": {
+				Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("4Gi")},
 			},
+		})
+		Expect(cluster.validateResourcesOverrides()).To(HaveLen(1))
+	})
+
+	It("complains when the override doesn't refer to an instance of the cluster", func() {
+		override := v1.ResourceRequirements{
+			Requests: v1.ResourceList{v1.ResourceCPU: resource.MustParse("1")},
 		}
-		result := cluster.validateReplicaMode()
-		Expect(result).To(BeEmpty())
+		cluster := newCluster(map[string]v1.ResourceRequirements{
+			"other-cluster-1":    override,
+			"cluster-example":    override,
+			"cluster-example-0":  override,
+			"cluster-example--0": override,
+			"cluster-example-+1": override,
+			"cluster-example-01": override,
+			"cluster-example-ab": override,
+		})
+		Expect(cluster.validateResourcesOverrides()).To(HaveLen(7))
 	})
+})
 
-	It("is valid when the restore bootstrap option is used", func() {
-		cluster := &Cluster{
+var _ = Describe("bootstrap resources validation", func() {
+	resources := v1.ResourceRequirements{
+		Limits: v1.ResourceList{v1.ResourceMemory: resource.MustParse("1Gi")},
+	}
+
+	It("doesn't complain when the bootstrap resources are not set", func() {
+		cluster := Cluster{Spec: ClusterSpec{Resources: resources}}
+		Expect(cluster.validateBootstrapResources()).To(BeEmpty())
+	})
+
+	It("accepts bootstrap resources raising the limits", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
-				Bootstrap: &BootstrapConfiguration{
-					Recovery: &BootstrapRecovery{},
-				},
-				ExternalClusters: []ExternalCluster{
-					{
-						Name: "test",
-					},
+				Resources: resources,
+				BootstrapResources: &v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("4Gi")},
+					Limits:   v1.ResourceList{v1.ResourceMemory: resource.MustParse("4Gi")},
 				},
 			},
 		}
-		result := cluster.validateReplicaMode()
-		Expect(result).To(BeEmpty())
+		Expect(cluster.validateBootstrapResources()).To(BeEmpty())
+	})
+
+	It("complains when the request exceeds the inherited limit", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Resources: resources,
+				BootstrapResources: &v1.ResourceRequirements{
+					Requests: v1.ResourceList{v1.ResourceMemory: resource.MustParse("4Gi")},
+				},
+			},
+		}
+		Expect(cluster.validateBootstrapResources()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("hot standby feedback validation", func() {
+	enabled := true
+
+	It("doesn't complain when only the parameter is used", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"hot_standby_feedback": "on"},
+				},
+			},
+		}
+		Expect(cluster.validateHotStandbyFeedback()).To(BeEmpty())
+	})
+
+	It("doesn't complain when only the dedicated field is used", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					HotStandbyFeedback: &enabled,
+				},
+			},
+		}
+		Expect(cluster.validateHotStandbyFeedback()).To(BeEmpty())
+	})
+
+	It("complains when both the dedicated field and the parameter are used", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					HotStandbyFeedback: &enabled,
+					Parameters:         map[string]string{"hot_standby_feedback": "on"},
+				},
+			},
+		}
+		Expect(cluster.validateHotStandbyFeedback()).To(HaveLen(1))
+	})
+	It("complains when the PostgreSQL version is older than 12", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:11",
+				PostgresConfiguration: PostgresConfiguration{
+					HotStandbyFeedback: &enabled,
+				},
+			},
+		}
+		Expect(cluster.validateHotStandbyFeedback()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("replication timeouts validation", func() {
+	It("doesn't complain when the timeouts are not set", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateReplicationTimeouts()).To(BeEmpty())
+	})
+
+	It("doesn't complain when the timeouts are zero or positive", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					WalSenderTimeout:   pointer.Int32(0),
+					WalReceiverTimeout: pointer.Int32(30),
+				},
+			},
+		}
+		Expect(cluster.validateReplicationTimeouts()).To(BeEmpty())
+	})
+
+	It("complains when the timeouts are negative", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					WalSenderTimeout:   pointer.Int32(-1),
+					WalReceiverTimeout: pointer.Int32(-5),
+				},
+			},
+		}
+		Expect(cluster.validateReplicationTimeouts()).To(HaveLen(2))
+	})
+
+	It("doesn't complain when the parameter matches the dedicated field", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					WalSenderTimeout: pointer.Int32(30),
+					Parameters:       map[string]string{"wal_sender_timeout": "30s"},
+				},
+			},
+		}
+		Expect(cluster.validateReplicationTimeouts()).To(BeEmpty())
+	})
+
+	It("complains when the parameter conflicts with the dedicated field", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					WalReceiverTimeout: pointer.Int32(30),
+					Parameters:         map[string]string{"wal_receiver_timeout": "1min"},
+				},
+			},
+		}
+		Expect(cluster.validateReplicationTimeouts()).To(HaveLen(1))
+	})
+
+	It("doesn't store the timeouts among the parameters while defaulting", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:14",
+				PostgresConfiguration: PostgresConfiguration{
+					WalSenderTimeout: pointer.Int32(30),
+				},
+			},
+		}
+		cluster.Default()
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).To(HaveKeyWithValue("wal_sender_timeout", "5s"))
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).To(HaveKeyWithValue("wal_receiver_timeout", "5s"))
+		Expect(cluster.validateReplicationTimeouts()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("session timeouts validation", func() {
+	It("doesn't complain when the timeouts are zero or positive", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					StatementTimeout:                pointer.Int32(0),
+					IdleInTransactionSessionTimeout: pointer.Int32(600),
+				},
+			},
+		}
+		Expect(cluster.validateSessionTimeouts()).To(BeEmpty())
+	})
+
+	It("complains when the timeouts are negative", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					StatementTimeout:                pointer.Int32(-1),
+					IdleInTransactionSessionTimeout: pointer.Int32(-1),
+				},
+			},
+		}
+		Expect(cluster.validateSessionTimeouts()).To(HaveLen(2))
+	})
+
+	It("complains when the parameter conflicts with the dedicated field", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					StatementTimeout: pointer.Int32(60),
+					Parameters:       map[string]string{"statement_timeout": "10min"},
+				},
+			},
+		}
+		Expect(cluster.validateSessionTimeouts()).To(HaveLen(1))
+	})
+
+	It("doesn't store the timeouts among the parameters while defaulting", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:14",
+				PostgresConfiguration: PostgresConfiguration{
+					IdleInTransactionSessionTimeout: pointer.Int32(600),
+				},
+			},
+		}
+		cluster.Default()
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).ToNot(HaveKey("idle_in_transaction_session_timeout"))
+		Expect(cluster.Spec.PostgresConfiguration.Parameters).ToNot(HaveKey("statement_timeout"))
+		Expect(cluster.validateSessionTimeouts()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("synchronous replica configuration validation", func() {
+	It("doesn't complain when the configuration is not set", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateSynchronousReplicaConfiguration()).To(BeEmpty())
+	})
+
+	It("doesn't complain with a valid configuration", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Synchronous: &SynchronousReplicaConfiguration{
+						Method:       SynchronousReplicaConfigurationMethodFirst,
+						StandbyNames: []string{"cluster-example-2", "cluster-example-3"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateSynchronousReplicaConfiguration()).To(BeEmpty())
+	})
+
+	It("complains when the method is not supported", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Synchronous: &SynchronousReplicaConfiguration{
+						Method: "some",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateSynchronousReplicaConfiguration()).To(HaveLen(1))
+	})
+
+	It("complains when the standbys are not instances of the cluster", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Synchronous: &SynchronousReplicaConfiguration{
+						Method:       SynchronousReplicaConfigurationMethodAny,
+						StandbyNames: []string{"other-cluster-2", "cluster-example-3"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateSynchronousReplicaConfiguration()).To(HaveLen(1))
+	})
+
+	It("complains when the standbys are duplicated", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					Synchronous: &SynchronousReplicaConfiguration{
+						Method:       SynchronousReplicaConfigurationMethodAny,
+						StandbyNames: []string{"cluster-example-2", "cluster-example-2"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateSynchronousReplicaConfiguration()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("hibernation annotation validation", func() {
+	It("doesn't complain when the annotation is not set", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateHibernationAnnotation()).To(BeEmpty())
+	})
+
+	It("accepts the supported values", func() {
+		for _, value := range []string{"on", "off"} {
+			cluster := Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{utils.HibernationAnnotationName: value},
+				},
+			}
+			Expect(cluster.validateHibernationAnnotation()).To(BeEmpty())
+		}
+	})
+
+	It("complains about an unsupported value", func() {
+		cluster := Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Annotations: map[string]string{utils.HibernationAnnotationName: "true"},
+			},
+		}
+		Expect(cluster.validateHibernationAnnotation()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("network policy validation", func() {
+	It("doesn't complain when the network policy is not configured", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateNetworkPolicy()).To(BeEmpty())
+	})
+
+	It("doesn't complain with valid selectors", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				NetworkPolicy: &NetworkPolicyConfiguration{
+					Enabled: true,
+					AllowedClients: []NetworkPolicyClient{
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"team": "billing"},
+							},
+							PodSelector: &metav1.LabelSelector{
+								MatchExpressions: []metav1.LabelSelectorRequirement{
+									{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"web"}},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateNetworkPolicy()).To(BeEmpty())
+	})
+
+	It("complains when a client has no selector", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				NetworkPolicy: &NetworkPolicyConfiguration{
+					Enabled:        true,
+					AllowedClients: []NetworkPolicyClient{{}},
+				},
+			},
+		}
+		Expect(cluster.validateNetworkPolicy()).To(HaveLen(1))
+	})
+
+	It("complains about invalid selectors", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				NetworkPolicy: &NetworkPolicyConfiguration{
+					Enabled: true,
+					AllowedClients: []NetworkPolicyClient{
+						{
+							PodSelector: &metav1.LabelSelector{
+								MatchLabels: map[string]string{"app": "not valid!"},
+							},
+						},
+						{
+							NamespaceSelector: &metav1.LabelSelector{
+								MatchExpressions: []metav1.LabelSelectorRequirement{
+									{Key: "team", Operator: metav1.LabelSelectorOpIn},
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateNetworkPolicy()).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("smart shutdown timeout validation", func() {
+	It("doesn't complain when the smart shutdown timeout is not set", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateSmartShutdownTimeout()).To(BeEmpty())
+	})
+
+	It("accepts a smart shutdown timeout lower than the stop delay", func() {
+		cluster := Cluster{Spec: ClusterSpec{MaxStopDelay: 60, SmartShutdownTimeout: 20}}
+		Expect(cluster.validateSmartShutdownTimeout()).To(BeEmpty())
+	})
+
+	It("complains when there is no time left for the fast shutdown", func() {
+		cluster := Cluster{Spec: ClusterSpec{MaxStopDelay: 60, SmartShutdownTimeout: 60}}
+		Expect(cluster.validateSmartShutdownTimeout()).To(HaveLen(1))
+	})
+
+	It("compares the smart shutdown timeout with the default stop delay", func() {
+		cluster := Cluster{Spec: ClusterSpec{SmartShutdownTimeout: 40}}
+		Expect(cluster.validateSmartShutdownTimeout()).To(HaveLen(1))
+	})
+
+	It("complains when the smart shutdown timeout is negative", func() {
+		cluster := Cluster{Spec: ClusterSpec{SmartShutdownTimeout: -1}}
+		Expect(cluster.validateSmartShutdownTimeout()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("delayed replicas validation", func() {
+	newCluster := func(minApplyDelay int32, instances ...string) *Cluster {
+		return &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				ImageName:       "postgres:14",
+				Instances:       3,
+				MinSyncReplicas: 1,
+				MaxSyncReplicas: 1,
+				DelayedReplicas: &DelayedReplicasConfiguration{
+					Instances:     instances,
+					MinApplyDelay: minApplyDelay,
+				},
+			},
+		}
+	}
+
+	It("doesn't complain when there are no delayed replicas", func() {
+		cluster := Cluster{Spec: ClusterSpec{Instances: 3}}
+		Expect(cluster.validateDelayedReplicas()).To(BeEmpty())
+	})
+
+	It("accepts delaying a replica not needed for synchronous replication", func() {
+		Expect(newCluster(3600, "cluster-example-3").validateDelayedReplicas()).To(BeEmpty())
+	})
+
+	It("complains when the delay is not positive", func() {
+		Expect(newCluster(0, "cluster-example-3").validateDelayedReplicas()).To(HaveLen(1))
+		Expect(newCluster(-10, "cluster-example-3").validateDelayedReplicas()).To(HaveLen(1))
+	})
+
+	It("complains when no instance is listed", func() {
+		Expect(newCluster(3600).validateDelayedReplicas()).To(HaveLen(1))
+	})
+
+	It("complains when an instance is listed twice", func() {
+		cluster := newCluster(3600, "cluster-example-3", "cluster-example-3")
+		Expect(cluster.validateDelayedReplicas()).To(HaveLen(1))
+	})
+
+	It("complains when the synchronous replicas would be delayed", func() {
+		cluster := newCluster(3600, "cluster-example-2", "cluster-example-3")
+		Expect(cluster.validateDelayedReplicas()).To(HaveLen(1))
+	})
+
+	It("doesn't count the instances that are not part of the cluster", func() {
+		cluster := newCluster(3600, "cluster-example-3", "cluster-example-4")
+		Expect(cluster.validateDelayedReplicas()).To(BeEmpty())
+
+		cluster = newCluster(3600, "cluster-example-3")
+		cluster.Status.CurrentPrimary = "cluster-example-4"
+		cluster.Status.InstanceNames = []string{"cluster-example-3", "cluster-example-4", "cluster-example-5"}
+		Expect(cluster.validateDelayedReplicas()).To(BeEmpty())
+	})
+
+	It("complains when the PostgreSQL version is older than 12", func() {
+		cluster := newCluster(3600, "cluster-example-3")
+		cluster.Spec.ImageName = "postgres:11"
+		Expect(cluster.validateDelayedReplicas()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("initdb settings change validation", func() {
+	newCluster := func() *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Encoding:      "UTF8",
+						LocaleCollate: "de_DE.UTF-8",
+						LocaleCType:   "de_DE.UTF-8",
+					},
+				},
+			},
+		}
+	}
+
+	It("doesn't complain if the settings are not changed", func() {
+		Expect(newCluster().validateInitDBChange(newCluster())).To(BeEmpty())
+	})
+
+	It("doesn't complain if the data checksums are explicitly disabled", func() {
+		disabled := false
+		cluster := newCluster()
+		cluster.Spec.Bootstrap.InitDB.DataChecksums = &disabled
+		Expect(cluster.validateInitDBChange(newCluster())).To(BeEmpty())
+	})
+
+	It("complains if the data checksums are enabled", func() {
+		enabled := true
+		cluster := newCluster()
+		cluster.Spec.Bootstrap.InitDB.DataChecksums = &enabled
+		Expect(cluster.validateInitDBChange(newCluster())).To(HaveLen(1))
+	})
+
+	It("complains if the locale settings are changed", func() {
+		cluster := newCluster()
+		cluster.Spec.Bootstrap.InitDB.LocaleCollate = "C"
+		cluster.Spec.Bootstrap.InitDB.LocaleCType = "C"
+		Expect(cluster.validateInitDBChange(newCluster())).To(HaveLen(2))
+	})
+
+	It("complains if the WAL segment size is changed", func() {
+		cluster := newCluster()
+		cluster.Spec.Bootstrap.InitDB.WalSegmentSize = 64
+		Expect(cluster.validateInitDBChange(newCluster())).To(HaveLen(1))
+	})
+
+	It("doesn't complain if the cluster was not bootstrapped with initdb", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{Source: "origin"},
+				},
+			},
+		}
+		Expect(newCluster().validateInitDBChange(oldCluster)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("replica mode validation", func() {
+	It("complains if the bootstrap method is not specified", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "test",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateReplicaMode()).ToNot(BeEmpty())
+	})
+
+	It("complains if the initdb bootstrap method is used", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "test",
+					},
+				},
+			},
+		}
+		Expect(cluster.validateReplicaMode()).ToNot(BeEmpty())
+	})
+
+	It("is valid when the pg_basebackup bootstrap option is used", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "test",
+					},
+				},
+			},
+		}
+		result := cluster.validateReplicaMode()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("is valid when the restore bootstrap option is used", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "test",
+					},
+				},
+			},
+		}
+		result := cluster.validateReplicaMode()
+		Expect(result).To(BeEmpty())
 	})
 
 	It("complains when the external cluster doesn't exist", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{},
+				},
+				ExternalClusters: []ExternalCluster{},
+			},
+		}
+
+		cluster.Spec.Bootstrap.PgBaseBackup = nil
+		result := cluster.validateReplicaMode()
+		Expect(result).ToNot(BeEmpty())
+	})
+
+	It("complains when enabled on an existing cluster with no replica mode configured", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{},
+		}
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{Name: "test"},
+				},
+			},
+		}
+		Expect(cluster.validateReplicaMode()).To(BeEmpty())
+		Expect(cluster.validateReplicaModeChange(oldCluster)).ToNot(BeEmpty())
+	})
+
+	It("complains when enabled on an existing cluster with replica mode disabled", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: false,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{Name: "test"},
+				},
+			},
+		}
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ReplicaCluster: &ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "test",
+				},
+				Bootstrap: &BootstrapConfiguration{
+					PgBaseBackup: &BootstrapPgBaseBackup{},
+				},
+				ExternalClusters: []ExternalCluster{
+					{Name: "test"},
+				},
+			},
+		}
+		Expect(cluster.validateReplicaMode()).To(BeEmpty())
+		Expect(cluster.validateReplicaModeChange(oldCluster)).ToNot(BeEmpty())
+	})
+})
+
+var _ = Describe("Validation changes", func() {
+	It("doesn't complain if given old cluster is nil", func() {
+		newCluster := &Cluster{}
+		err := newCluster.ValidateChanges(nil)
+		Expect(err).To(BeNil())
+	})
+})
+
+var _ = Describe("Backup validation", func() {
+	It("complain if there's no credentials", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(len(err)).To(Equal(1))
+	})
+
+	It("doesn't complain if given policy is not provided", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(err).To(BeNil())
+	})
+
+	It("doesn't complain if given policy is valid", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					RetentionPolicy: "90d",
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(err).To(BeNil())
+	})
+
+	It("complain if a given policy is not valid", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+					RetentionPolicy:   "09",
+				},
+			},
+		}
+		err := cluster.validateBackupConfiguration()
+		Expect(len(err)).To(Equal(2))
+	})
+})
+
+var _ = Describe("Primary health check validation", func() {
+	It("doesn't complain if the check is not configured", func() {
+		Expect((&Cluster{}).validatePrimaryHealthCheck()).To(BeEmpty())
+	})
+
+	It("accepts a query with the default thresholds", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PrimaryHealthCheck: &PrimaryHealthCheckConfiguration{
+					Query: "SELECT count(*) < 100 FROM pg_stat_activity WHERE wait_event = 'LWLock'",
+				},
+			},
+		}
+		Expect(cluster.validatePrimaryHealthCheck()).To(BeEmpty())
+	})
+
+	It("complains if the query is missing", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PrimaryHealthCheck: &PrimaryHealthCheckConfiguration{
+					Query: "  ",
+				},
+			},
+		}
+		errs := cluster.validatePrimaryHealthCheck()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeRequired))
+		Expect(errs[0].Field).To(Equal("spec.primaryHealthCheck.query"))
+	})
+
+	It("complains about negative thresholds", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PrimaryHealthCheck: &PrimaryHealthCheckConfiguration{
+					Query:            "SELECT true",
+					PeriodSeconds:    -1,
+					FailureThreshold: -1,
+				},
+			},
+		}
+		errs := cluster.validatePrimaryHealthCheck()
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.primaryHealthCheck.periodSeconds"))
+		Expect(errs[1].Field).To(Equal("spec.primaryHealthCheck.failureThreshold"))
+	})
+
+	It("complains if the query can time out after the next check", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PrimaryHealthCheck: &PrimaryHealthCheckConfiguration{
+					Query:          "SELECT true",
+					PeriodSeconds:  5,
+					TimeoutSeconds: 5,
+				},
+			},
+		}
+		errs := cluster.validatePrimaryHealthCheck()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.primaryHealthCheck.timeoutSeconds"))
+
+		cluster.Spec.PrimaryHealthCheck.TimeoutSeconds = 0
+		Expect(cluster.validatePrimaryHealthCheck()).To(HaveLen(1))
+
+		cluster.Spec.PrimaryHealthCheck.TimeoutSeconds = 4
+		Expect(cluster.validatePrimaryHealthCheck()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Custom WAL archive validation", func() {
+	newCluster := func(customWalArchive *CustomWalArchiveConfiguration) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					CustomWalArchive: customWalArchive,
+				},
+			},
+		}
+	}
+
+	It("doesn't complain if the custom WAL archive is not configured", func() {
+		Expect(newCluster(nil).validateCustomWalArchive()).To(BeEmpty())
+		Expect((&Cluster{}).validateCustomWalArchive()).To(BeEmpty())
+	})
+
+	It("accepts valid commands", func() {
+		cluster := newCluster(&CustomWalArchiveConfiguration{
+			ArchiveCommand: []string{"/usr/bin/archive", "%p"},
+			RestoreCommand: []string{"/usr/bin/restore", "%f", "%p"},
+		})
+		Expect(cluster.validateCustomWalArchive()).To(BeEmpty())
+
+		cluster.Spec.Backup.CustomWalArchive.RestoreCommand = nil
+		Expect(cluster.validateCustomWalArchive()).To(BeEmpty())
+	})
+
+	It("complains if it is used together with the barman object store", func() {
+		cluster := newCluster(&CustomWalArchiveConfiguration{
+			ArchiveCommand: []string{"/usr/bin/archive", "%p"},
+		})
+		cluster.Spec.Backup.BarmanObjectStore = &BarmanObjectStoreConfiguration{}
+		errs := cluster.validateCustomWalArchive()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.backup.customWalArchive"))
+	})
+
+	It("complains if the archive command is missing", func() {
+		errs := newCluster(&CustomWalArchiveConfiguration{}).validateCustomWalArchive()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeRequired))
+		Expect(errs[0].Field).To(Equal("spec.backup.customWalArchive.archiveCommand"))
+	})
+
+	It("complains if the archive command doesn't refer to the WAL file", func() {
+		errs := newCluster(&CustomWalArchiveConfiguration{
+			ArchiveCommand: []string{"/usr/bin/archive", "%%p"},
+		}).validateCustomWalArchive()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.backup.customWalArchive.archiveCommand"))
+	})
+
+	It("complains if the restore command doesn't refer to the WAL file and its destination", func() {
+		cluster := newCluster(&CustomWalArchiveConfiguration{
+			ArchiveCommand: []string{"/usr/bin/archive", "%p"},
+			RestoreCommand: []string{"/usr/bin/restore", "%f"},
+		})
+		errs := cluster.validateCustomWalArchive()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.backup.customWalArchive.restoreCommand"))
+
+		cluster.Spec.Backup.CustomWalArchive.RestoreCommand = []string{"", "%f", "%p"}
+		errs = cluster.validateCustomWalArchive()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeRequired))
+	})
+
+	It("allows waiting for the first WAL file to be archived", func() {
+		cluster := newCluster(&CustomWalArchiveConfiguration{
+			ArchiveCommand: []string{"/usr/bin/archive", "%p"},
+		})
+		cluster.Spec.Bootstrap = &BootstrapConfiguration{
+			InitDB: &BootstrapInitDB{
+				WaitForArchive: true,
+			},
+		}
+		Expect(cluster.validateInitDB()).To(BeEmpty())
+		Expect(cluster.ShouldWaitForFirstArchive()).To(BeTrue())
+	})
+})
+
+var _ = Describe("Default monitoring queries", func() {
+	It("correctly set the default monitoring queries configmap and secret when none is already specified", func() {
+		cluster := &Cluster{}
+		cluster.defaultMonitoringQueries(&configuration.Data{
+			MonitoringQueriesSecret:    "test-secret",
+			MonitoringQueriesConfigmap: "test-configmap",
+		})
+		Expect(cluster.Spec.Monitoring).NotTo(BeNil())
+		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
+		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).
+			To(ContainElement(ConfigMapKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
+				Key:                  DefaultMonitoringKey,
+			}))
+		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
+		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).
+			To(ContainElement(SecretKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringSecretName},
+				Key:                  DefaultMonitoringKey,
+			}))
+	})
+	testCluster := &Cluster{Spec: ClusterSpec{Monitoring: &MonitoringConfiguration{
+		CustomQueriesConfigMap: []ConfigMapKeySelector{
+			{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
+				Key:                  "test2",
+			},
+		},
+		CustomQueriesSecret: []SecretKeySelector{
+			{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
+				Key:                  "test3",
+			},
+		},
+	}}}
+	It("correctly set the default monitoring queries configmap when other metrics are already specified", func() {
+		modifiedCluster := testCluster.DeepCopy()
+		modifiedCluster.defaultMonitoringQueries(&configuration.Data{
+			MonitoringQueriesConfigmap: "test-configmap",
+		})
+
+		Expect(modifiedCluster.Spec.Monitoring).NotTo(BeNil())
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).
+			To(ContainElement(ConfigMapKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
+				Key:                  "test2",
+			}))
+
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).
+			To(BeEquivalentTo(testCluster.Spec.Monitoring.CustomQueriesSecret))
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).
+			To(ContainElements(testCluster.Spec.Monitoring.CustomQueriesConfigMap))
+	})
+	It("correctly set the default monitoring queries secret when other metrics are already specified", func() {
+		modifiedCluster := testCluster.DeepCopy()
+		modifiedCluster.defaultMonitoringQueries(&configuration.Data{
+			MonitoringQueriesSecret: "test-secret",
+		})
+
+		Expect(modifiedCluster.Spec.Monitoring).NotTo(BeNil())
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).
+			To(ContainElement(SecretKeySelector{
+				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringSecretName},
+				Key:                  "test3",
+			}))
+
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).
+			To(BeEquivalentTo(testCluster.Spec.Monitoring.CustomQueriesConfigMap))
+		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).
+			To(ContainElements(testCluster.Spec.Monitoring.CustomQueriesSecret))
+	})
+})
+
+var _ = Describe("validation of imports", func() {
+	It("rejects unrecognized import type", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type: "fooBar",
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("rejects microservice import with roles", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MicroserviceSnapshotType,
+							Databases: []string{"foo"},
+							Roles:     []string{"bar"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("rejects microservice import without exactly one database", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MicroserviceSnapshotType,
+							Databases: []string{"foo", "bar"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("rejects microservice import with a wildcard on the database name", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MicroserviceSnapshotType,
+							Databases: []string{"*foo"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("accepts microservice import when well specified", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MicroserviceSnapshotType,
+							Databases: []string{"foo"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("rejects monolith import with no databases", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MonolithSnapshotType,
+							Databases: []string{},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("rejects monolith import with PostImport Application SQL", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:                     MonolithSnapshotType,
+							Databases:                []string{"foo"},
+							PostImportApplicationSQL: []string{"select * from bar"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("rejects monolith import with wildcards alongside specific values", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MonolithSnapshotType,
+							Databases: []string{"bar", "*"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+
+		cluster = &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MonolithSnapshotType,
+							Databases: []string{"foo"},
+							Roles:     []string{"baz", "*"},
+						},
+					},
+				},
+			},
+		}
+
+		result = cluster.validateImport()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("accepts monolith import with proper values", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MonolithSnapshotType,
+							Databases: []string{"foo"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("accepts monolith import with wildcards", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:      MonolithSnapshotType,
+							Databases: []string{"*"},
+							Roles:     []string{"*"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("accepts monolith import excluding roles and superusers", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:              MonolithSnapshotType,
+							Databases:         []string{"*"},
+							Roles:             []string{"*"},
+							ExcludedRoles:     []string{"dba", "monitoring"},
+							ExcludeSuperusers: true,
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("rejects invalid lists of roles to exclude in monolith import", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:          MonolithSnapshotType,
+							Databases:     []string{"foo"},
+							Roles:         []string{"bar", "baz"},
+							ExcludedRoles: []string{"*", "", "bar", "dba", "dba"},
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(4))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.import.excludedRoles[0]"))
+		Expect(result[1].Field).To(Equal("spec.bootstrap.initdb.import.excludedRoles[1]"))
+		Expect(result[2].Field).To(Equal("spec.bootstrap.initdb.import.excludedRoles[2]"))
+		Expect(result[3].Field).To(Equal("spec.bootstrap.initdb.import.excludedRoles[4]"))
+		Expect(result[3].Type).To(Equal(field.ErrorTypeDuplicate))
+	})
+
+	It("rejects microservice import excluding roles or superusers", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						Database: "app",
+						Owner:    "app",
+						Import: &Import{
+							Type:              MicroserviceSnapshotType,
+							Databases:         []string{"foo"},
+							ExcludedRoles:     []string{"dba"},
+							ExcludeSuperusers: true,
+						},
+					},
+				},
+			},
+		}
+
+		result := cluster.validateImport()
+		Expect(result).To(HaveLen(2))
+	})
+})
+
+var _ = Describe("validation of replication slots configuration", func() {
+	It("prevents using replication slots on PostgreSQL 10 and older", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:10.5",
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled: true,
+					},
+					UpdateInterval: 0,
+				},
+			},
+		}
+		cluster.Default()
+
+		result := cluster.validateReplicationSlots()
+		Expect(result).To(HaveLen(1))
+	})
+
+	It("can be enabled on the default PostgreSQL image", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled: true,
+					},
+					UpdateInterval: 0,
+				},
+			},
+		}
+		cluster.Default()
+
+		result := cluster.validateReplicationSlots()
+		Expect(result).To(BeEmpty())
+	})
+
+	It("prevents using a prefix generating slot names that are too long", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: strings.Repeat("a", 50),
+			},
+			Spec: ClusterSpec{
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled: true,
+					},
+				},
+			},
+		}
+		cluster.Default()
+		Expect(cluster.validateReplicationSlots()).To(BeEmpty())
+
+		cluster.Spec.ReplicationSlots.HighAvailability.SlotPrefix = "_my_long_prefix_"
+		result := cluster.validateReplicationSlots()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.replicationSlots.highAvailability.slotPrefix"))
+	})
+
+	It("computes the length of the slot names from the highest instance serial", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: strings.Repeat("a", 55),
+			},
+			Spec: ClusterSpec{
+				ImageName: versions.DefaultImageName,
+				Instances: 3,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled: true,
+					},
+				},
+			},
+		}
+		cluster.Default()
+		Expect(cluster.validateReplicationSlots()).To(BeEmpty())
+
+		cluster.Status.LatestGeneratedNode = 10
+		result := cluster.validateReplicationSlots()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.replicationSlots.highAvailability.slotPrefix"))
+	})
+
+	It("allows enabling replication slots on the fly", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: versions.DefaultImageName,
+			},
+		}
+		oldCluster.Default()
+
+		newCluster := oldCluster.DeepCopy()
+		newCluster.Spec.ReplicationSlots = &ReplicationSlotsConfiguration{
+			HighAvailability: &ReplicationSlotsHAConfiguration{
+				Enabled:    true,
+				SlotPrefix: "_test_",
+			},
+		}
+
+		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(BeEmpty())
+	})
+
+	It("prevents changing the slot prefix while replication slots are enabled", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled:    true,
+						SlotPrefix: "_test_",
+					},
+				},
+			},
+		}
+		oldCluster.Default()
+
+		newCluster := oldCluster.DeepCopy()
+		newCluster.Spec.ReplicationSlots.HighAvailability.SlotPrefix = "_toast_"
+		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(HaveLen(1))
+	})
+
+	It("prevents removing the replication slot section when replication slots are enabled", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled:    true,
+						SlotPrefix: "_test_",
+					},
+				},
+			},
+		}
+		oldCluster.Default()
+
+		newCluster := oldCluster.DeepCopy()
+		newCluster.Spec.ReplicationSlots = nil
+		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(HaveLen(1))
+	})
+
+	It("allows disabling the replication slots", func() {
+		oldCluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: versions.DefaultImageName,
+				ReplicationSlots: &ReplicationSlotsConfiguration{
+					HighAvailability: &ReplicationSlotsHAConfiguration{
+						Enabled:    true,
+						SlotPrefix: "_test_",
+					},
+				},
+			},
+		}
+		oldCluster.Default()
+
+		newCluster := oldCluster.DeepCopy()
+		newCluster.Spec.ReplicationSlots.HighAvailability.Enabled = false
+		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Managed roles validation", func() {
+	It("allows a cluster without managed roles", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateManagedRoles()).To(BeEmpty())
+	})
+
+	It("allows a list of distinct roles", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{Name: "app_reader", Login: true},
+						{Name: "app_writer", Login: true, CreateDB: true},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedRoles()).To(BeEmpty())
+	})
+
+	It("complains about duplicate role names", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{Name: "app_reader"},
+						{Name: "app_writer"},
+						{Name: "app_reader", Ensure: EnsureAbsent},
+					},
+				},
+			},
+		}
+		errs := cluster.validateManagedRoles()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.managed.roles[2].name"))
+	})
+
+	It("complains about roles reserved for the operator or PostgreSQL", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{Name: "postgres"},
+						{Name: "streaming_replica"},
+						{Name: "pg_monitor"},
+					},
+				},
+			},
+		}
+		Expect(cluster.validateManagedRoles()).To(HaveLen(3))
+	})
+})
+
+var _ = Describe("cluster_name parameter validation", func() {
+	It("is valid when not specified", func() {
+		cluster := &Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"}}
+		Expect(cluster.validatePostgresClusterName()).To(BeEmpty())
+		Expect(cluster.GetPostgresClusterName()).To(Equal("cluster-example"))
+	})
+
+	It("accepts a custom value", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					ClusterName: "eu-west/billing",
+				},
+			},
+		}
+		Expect(cluster.validatePostgresClusterName()).To(BeEmpty())
+		Expect(cluster.GetPostgresClusterName()).To(Equal("eu-west/billing"))
+	})
+
+	It("complains about non printable ASCII characters", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					ClusterName: "billing-è",
+				},
+			},
+		}
+		Expect(cluster.validatePostgresClusterName()).To(HaveLen(1))
+	})
+
+	It("complains when using the name of an instance", func() {
+		cluster := &Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					ClusterName: "cluster-example-2",
+				},
+			},
+		}
+		Expect(cluster.validatePostgresClusterName()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("validation of image pull secrets", func() {
+	It("accepts a cluster without pull secrets", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateImagePullSecrets()).To(BeEmpty())
+	})
+
+	It("accepts a list of valid pull secrets", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImagePullSecrets: []LocalObjectReference{
+					{Name: "registry-credentials"},
+					{Name: "mirror.credentials"},
+				},
+			},
+		}
+		Expect(cluster.validateImagePullSecrets()).To(BeEmpty())
+	})
+
+	It("rejects empty, invalid and duplicate pull secret names", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImagePullSecrets: []LocalObjectReference{
+					{Name: ""},
+					{Name: "Registry_Credentials"},
+					{Name: "registry-credentials"},
+					{Name: "registry-credentials"},
+				},
+			},
+		}
+		result := cluster.validateImagePullSecrets()
+		Expect(result).To(HaveLen(3))
+		Expect(result[0].Type).To(Equal(field.ErrorTypeRequired))
+		Expect(result[1].Type).To(Equal(field.ErrorTypeInvalid))
+		Expect(result[2].Type).To(Equal(field.ErrorTypeDuplicate))
+		Expect(result[2].Field).To(Equal("spec.imagePullSecrets[3].name"))
+	})
+})
+
+var _ = Describe("environment variables validation", func() {
+	It("accepts a cluster without environment variables", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateEnv()).To(BeEmpty())
+	})
+
+	It("accepts custom environment variables", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Env: []v1.EnvVar{
+					{Name: "HTTPS_PROXY", Value: "http://proxy:3128"},
+					{Name: "NO_PROXY", Value: "10.0.0.0/8"},
+				},
+			},
+		}
+		Expect(cluster.validateEnv()).To(BeEmpty())
+	})
+
+	It("rejects the environment variables reserved to the operator", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Env: []v1.EnvVar{
+					{Name: "HTTPS_PROXY", Value: "http://proxy:3128"},
+					{Name: "PGDATA", Value: "/tmp"},
 				},
+			},
+		}
+		errs := cluster.validateEnv()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.env[1].name"))
+	})
+
+	It("rejects environment variables without a name", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Env: []v1.EnvVar{{Value: "value"}},
+			},
+		}
+		errs := cluster.validateEnv()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeRequired))
+	})
+})
+
+var _ = Describe("post-init analyze validation", func() {
+	It("accepts a bootstrap without analyze", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{InitDB: &BootstrapInitDB{}},
+			},
+		}
+		Expect(cluster.validatePostInitAnalyze()).To(BeEmpty())
+	})
+
+	It("accepts analyze when data is loaded in the application database", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
 				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{},
+					InitDB: &BootstrapInitDB{
+						PostInitApplicationSQL: []string{"CREATE TABLE numbers AS SELECT generate_series(1, 100) AS n"},
+						PostInitAnalyze:        PostInitAnalyzeModeVacuumAnalyze,
+					},
 				},
-				ExternalClusters: []ExternalCluster{},
 			},
 		}
+		Expect(cluster.validatePostInitAnalyze()).To(BeEmpty())
 
-		cluster.Spec.Bootstrap.PgBaseBackup = nil
-		result := cluster.validateReplicaMode()
-		Expect(result).ToNot(BeEmpty())
+		cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQL = nil
+		cluster.Spec.Bootstrap.InitDB.Import = &Import{
+			Type:      MicroserviceSnapshotType,
+			Databases: []string{"app"},
+		}
+		Expect(cluster.validatePostInitAnalyze()).To(BeEmpty())
 	})
 
-	It("complains when enabled on an existing cluster with no replica mode configured", func() {
-		oldCluster := &Cluster{
-			Spec: ClusterSpec{},
+	It("rejects analyze when no data is loaded", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{PostInitAnalyze: PostInitAnalyzeModeAnalyze},
+				},
+			},
 		}
+		errs := cluster.validatePostInitAnalyze()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.bootstrap.initdb.postInitAnalyze"))
+	})
+
+	It("rejects an unknown mode", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{
+						PostInitApplicationSQL: []string{"SELECT 1"},
+						PostInitAnalyze:        "vacuumFull",
+					},
+				},
+			},
+		}
+		errs := cluster.validatePostInitAnalyze()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeNotSupported))
+	})
+})
+
+var _ = Describe("listen scope validation", func() {
+	It("accepts the supported scopes", func() {
+		for _, scope := range []ListenScope{"", ListenOnAllInterfaces, ListenOnPodIPOnly} {
+			cluster := &Cluster{
+				Spec: ClusterSpec{
+					PostgresConfiguration: PostgresConfiguration{ListenScope: scope},
+				},
+			}
+			Expect(cluster.validateListenScope()).To(BeEmpty(), string(scope))
+		}
+	})
+
+	It("rejects an unknown scope", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{ListenScope: "Localhost"},
+			},
+		}
+		errs := cluster.validateListenScope()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeNotSupported))
+	})
+
+	It("keeps listen_addresses among the fixed parameters", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:15",
+				PostgresConfiguration: PostgresConfiguration{
+					ListenScope: ListenOnPodIPOnly,
+					Parameters:  map[string]string{"listen_addresses": "*"},
+				},
+			},
+		}
+		Expect(cluster.validateConfiguration()).ToNot(BeEmpty())
+	})
+
+	It("rejects the fixed parameters of images referenced only by their digest", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres@sha256:cff94de382ca538861622bbe84cfe03f44f307a9846a5c5eda672cf4dc692866",
+				PostgresConfiguration: PostgresConfiguration{
+					Parameters: map[string]string{"port": "5433"},
+				},
+			},
+		}
+		Expect(cluster.validateConfiguration()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("data checksums verification validation", func() {
+	It("accepts a cluster not verifying the data checksums", func() {
+		cluster := &Cluster{}
+		Expect(cluster.validateChecksumsVerification()).To(BeEmpty())
+	})
+
+	It("accepts the verification when data checksums are enabled", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:15",
+				PostgresConfiguration: PostgresConfiguration{
+					VerifyChecksumsOnStart: true,
 				},
 				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{},
+					InitDB: &BootstrapInitDB{DataChecksums: pointer.Bool(true)},
 				},
-				ExternalClusters: []ExternalCluster{
-					{Name: "test"},
+			},
+		}
+		Expect(cluster.validateChecksumsVerification()).To(BeEmpty())
+	})
+
+	It("rejects the verification when data checksums are disabled", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:15",
+				PostgresConfiguration: PostgresConfiguration{
+					VerifyChecksumsOnStart: true,
+				},
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{},
+				},
+			},
+		}
+		errs := cluster.validateChecksumsVerification()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.postgresql.verifyChecksumsOnStart"))
+	})
+
+	It("accepts the verification for clusters bootstrapped from a backup", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:15",
+				PostgresConfiguration: PostgresConfiguration{
+					VerifyChecksumsOnStart: true,
+				},
+				Bootstrap: &BootstrapConfiguration{
+					Recovery: &BootstrapRecovery{Source: "origin"},
+				},
+			},
+		}
+		Expect(cluster.validateChecksumsVerification()).To(BeEmpty())
+	})
+
+	It("rejects the verification with PostgreSQL 11", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				ImageName: "postgres:11",
+				PostgresConfiguration: PostgresConfiguration{
+					VerifyChecksumsOnStart: true,
+				},
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{DataChecksums: pointer.Bool(true)},
+				},
+			},
+		}
+		Expect(cluster.validateChecksumsVerification()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("application certificate authentication validation", func() {
+	It("accepts the names that can be used in pg_hba.conf", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					ApplicationCertificateAuthentication: true,
+				},
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{Database: "app", Owner: "app"},
+				},
+			},
+		}
+		Expect(cluster.validateApplicationCertificateAuthentication()).To(BeEmpty())
+	})
+
+	It("complains about the names containing a double quote", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					ApplicationCertificateAuthentication: true,
+				},
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{Database: `my"db`, Owner: "app"},
+				},
+			},
+		}
+		result := cluster.validateApplicationCertificateAuthentication()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.postgresql.applicationCertificateAuthentication"))
+
+		cluster.Spec.PostgresConfiguration.ApplicationCertificateAuthentication = false
+		Expect(cluster.validateApplicationCertificateAuthentication()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("inherited metadata validation", func() {
+	It("accepts a cluster without inherited metadata", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateMetadata(nil)).To(BeEmpty())
+	})
+
+	It("accepts labels and annotations not using the reserved prefix", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				InheritedMetadata: &EmbeddedObjectMetadata{
+					Labels:      map[string]string{"app": "accounting", "example.com/team": "dba"},
+					Annotations: map[string]string{"example.com/owner": "accounting"},
+				},
+			},
+		}
+		Expect(cluster.validateMetadata(nil)).To(BeEmpty())
+	})
+
+	It("rejects labels and annotations using the reserved prefix", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				InheritedMetadata: &EmbeddedObjectMetadata{
+					Labels: map[string]string{
+						"cnpg.io/podRole":         "instance",
+						"cnpg.io/instanceName":    "cluster-example-1",
+						"postgresql.cnpg.io/test": "test",
+					},
+					Annotations: map[string]string{"cnpg.io/operatorVersion": "1.0.0"},
+				},
+			},
+		}
+		result := cluster.validateMetadata(nil)
+		Expect(result).To(HaveLen(4))
+		Expect(result[0].Field).To(Equal("spec.inheritedMetadata.labels[cnpg.io/instanceName]"))
+		Expect(result[3].Field).To(Equal("spec.inheritedMetadata.annotations[cnpg.io/operatorVersion]"))
+	})
+
+	It("only rejects the newly added keys using the reserved prefix when updating a cluster", func() {
+		oldCluster := Cluster{
+			Spec: ClusterSpec{
+				InheritedMetadata: &EmbeddedObjectMetadata{
+					Labels: map[string]string{"cnpg.io/team": "dba"},
 				},
 			},
 		}
-		Expect(cluster.validateReplicaMode()).To(BeEmpty())
-		Expect(cluster.validateReplicaModeChange(oldCluster)).ToNot(BeEmpty())
+		cluster := oldCluster.DeepCopy()
+		cluster.Spec.InheritedMetadata.Labels["cnpg.io/team"] = "accounting"
+		Expect(cluster.validateMetadata(&oldCluster)).To(BeEmpty())
+
+		cluster.Spec.InheritedMetadata.Annotations = map[string]string{"cnpg.io/team": "dba"}
+		result := cluster.validateMetadata(&oldCluster)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.inheritedMetadata.annotations[cnpg.io/team]"))
 	})
 
-	It("complains when enabled on an existing cluster with replica mode disabled", func() {
-		oldCluster := &Cluster{
+	It("checks the metadata of the service account template", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: false,
-					Source:  "test",
-				},
-				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{},
-				},
-				ExternalClusters: []ExternalCluster{
-					{Name: "test"},
+				ServiceAccountTemplate: &ServiceAccountTemplate{
+					Metadata: Metadata{
+						Annotations: map[string]string{
+							"eks.amazonaws.com/role-arn": "arn:aws:iam::123456789012:role/backup",
+						},
+					},
 				},
 			},
 		}
-		cluster := &Cluster{
+		Expect(cluster.validateMetadata(nil)).To(BeEmpty())
+
+		cluster.Spec.ServiceAccountTemplate.Metadata.Annotations["cnpg.io/managedSecrets"] = "[]"
+		result := cluster.validateMetadata(nil)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.serviceAccountTemplate.metadata.annotations[cnpg.io/managedSecrets]"))
+	})
+
+	It("accepts the reserved keys already defined in the service account template of an existing cluster", func() {
+		oldCluster := Cluster{
 			Spec: ClusterSpec{
-				ReplicaCluster: &ReplicaClusterConfiguration{
-					Enabled: true,
-					Source:  "test",
-				},
-				Bootstrap: &BootstrapConfiguration{
-					PgBaseBackup: &BootstrapPgBaseBackup{},
-				},
-				ExternalClusters: []ExternalCluster{
-					{Name: "test"},
+				ServiceAccountTemplate: &ServiceAccountTemplate{
+					Metadata: Metadata{
+						Annotations: map[string]string{"cnpg.io/managedSecrets": "[]"},
+					},
 				},
 			},
 		}
-		Expect(cluster.validateReplicaMode()).To(BeEmpty())
-		Expect(cluster.validateReplicaModeChange(oldCluster)).ToNot(BeEmpty())
+		cluster := oldCluster.DeepCopy()
+		cluster.Spec.ServiceAccountTemplate.Metadata.Labels = map[string]string{"app": "accounting"}
+		Expect(cluster.ValidateChanges(&oldCluster)).To(BeEmpty())
+
+		cluster.Spec.ServiceAccountTemplate.Metadata.Labels["cnpg.io/podRole"] = "instance"
+		result := cluster.ValidateChanges(&oldCluster)
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.serviceAccountTemplate.metadata.labels[cnpg.io/podRole]"))
 	})
 })
 
-var _ = Describe("Validation changes", func() {
-	It("doesn't complain if given old cluster is nil", func() {
-		newCluster := &Cluster{}
-		err := newCluster.ValidateChanges(nil)
-		Expect(err).To(BeNil())
+var _ = Describe("dangling PVC policy validation", func() {
+	It("accepts the supported policies", func() {
+		for _, policy := range []DanglingPVCPolicy{"", DanglingPVCPolicyDelete, DanglingPVCPolicyRetain} {
+			cluster := &Cluster{Spec: ClusterSpec{DanglingPVCPolicy: policy}}
+			Expect(cluster.validateDanglingPVCPolicy()).To(BeEmpty())
+		}
+	})
+
+	It("rejects an unknown policy", func() {
+		cluster := &Cluster{Spec: ClusterSpec{DanglingPVCPolicy: "Archive"}}
+		errs := cluster.validateDanglingPVCPolicy()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Type).To(Equal(field.ErrorTypeNotSupported))
+		Expect(errs[0].Field).To(Equal("spec.danglingPVCPolicy"))
+	})
+
+	It("deletes the unneeded PVCs by default", func() {
+		cluster := &Cluster{}
+		Expect(cluster.GetDanglingPVCPolicy()).To(Equal(DanglingPVCPolicyDelete))
+		cluster.Spec.DanglingPVCPolicy = DanglingPVCPolicyRetain
+		Expect(cluster.GetDanglingPVCPolicy()).To(Equal(DanglingPVCPolicyRetain))
 	})
 })
 
-var _ = Describe("Backup validation", func() {
-	It("complain if there's no credentials", func() {
+var _ = Describe("WAL and data storage collision", func() {
+	newCluster := func(dataTemplate, walTemplate *v1.PersistentVolumeClaimSpec) *Cluster {
+		return &Cluster{
+			Spec: ClusterSpec{
+				StorageConfiguration: StorageConfiguration{
+					Size:                          "1Gi",
+					PersistentVolumeClaimTemplate: dataTemplate,
+				},
+				WalStorage: &StorageConfiguration{
+					Size:                          "1Gi",
+					PersistentVolumeClaimTemplate: walTemplate,
+				},
+			},
+		}
+	}
+
+	It("doesn't complain without a dedicated WAL storage", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
-				Backup: &BackupConfiguration{
-					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+				StorageConfiguration: StorageConfiguration{
+					PersistentVolumeClaimTemplate: &v1.PersistentVolumeClaimSpec{VolumeName: "pv-data"},
 				},
 			},
 		}
-		err := cluster.validateBackupConfiguration()
-		Expect(len(err)).To(Equal(1))
+		Expect(cluster.validateStorageConfiguration()).To(BeEmpty())
 	})
 
-	It("doesn't complain if given policy is not provided", func() {
-		cluster := &Cluster{
+	It("accepts distinct PVC templates", func() {
+		cluster := newCluster(
+			&v1.PersistentVolumeClaimSpec{
+				VolumeName: "pv-data",
+				DataSource: &v1.TypedLocalObjectReference{Kind: "VolumeSnapshot", Name: "pgdata"},
+			},
+			&v1.PersistentVolumeClaimSpec{
+				VolumeName: "pv-wal",
+				DataSource: &v1.TypedLocalObjectReference{Kind: "VolumeSnapshot", Name: "pgwal"},
+			},
+		)
+		Expect(cluster.validateStorageConfiguration()).To(BeEmpty())
+
+		cluster = newCluster(&v1.PersistentVolumeClaimSpec{}, &v1.PersistentVolumeClaimSpec{})
+		Expect(cluster.validateStorageConfiguration()).To(BeEmpty())
+	})
+
+	It("rejects WAL and data storage bound to the same persistent volume", func() {
+		cluster := newCluster(
+			&v1.PersistentVolumeClaimSpec{VolumeName: "pv-shared"},
+			&v1.PersistentVolumeClaimSpec{VolumeName: "pv-shared"},
+		)
+		errs := cluster.validateStorageConfiguration()
+		Expect(errs).To(HaveLen(1))
+		Expect(errs[0].Field).To(Equal("spec.walStorage.pvcTemplate.volumeName"))
+	})
+
+	It("rejects WAL and data storage populated from the same data source", func() {
+		snapshot := v1.TypedLocalObjectReference{Kind: "VolumeSnapshot", Name: "pgdata"}
+		cluster := newCluster(
+			&v1.PersistentVolumeClaimSpec{DataSource: snapshot.DeepCopy(), DataSourceRef: snapshot.DeepCopy()},
+			&v1.PersistentVolumeClaimSpec{DataSource: snapshot.DeepCopy(), DataSourceRef: snapshot.DeepCopy()},
+		)
+		errs := cluster.validateStorageConfiguration()
+		Expect(errs).To(HaveLen(2))
+		Expect(errs[0].Field).To(Equal("spec.walStorage.pvcTemplate.dataSource"))
+		Expect(errs[1].Field).To(Equal("spec.walStorage.pvcTemplate.dataSourceRef"))
+	})
+})
+
+var _ = Describe("backup before upgrade validation", func() {
+	It("accepts a cluster not requiring a backup before the upgrade", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
 				Backup: &BackupConfiguration{},
 			},
 		}
-		err := cluster.validateBackupConfiguration()
-		Expect(err).To(BeNil())
+		Expect(cluster.validateBackupBeforeUpgrade()).To(BeEmpty())
 	})
 
-	It("doesn't complain if given policy is valid", func() {
-		cluster := &Cluster{
+	It("accepts a backup before the upgrade when the object store is configured", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
 				Backup: &BackupConfiguration{
-					RetentionPolicy: "90d",
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+					BeforeUpgrade:     true,
 				},
 			},
 		}
-		err := cluster.validateBackupConfiguration()
-		Expect(err).To(BeNil())
+		Expect(cluster.validateBackupBeforeUpgrade()).To(BeEmpty())
 	})
 
-	It("complain if a given policy is not valid", func() {
-		cluster := &Cluster{
+	It("complains when the backup before the upgrade can't be taken", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
 				Backup: &BackupConfiguration{
-					BarmanObjectStore: &BarmanObjectStoreConfiguration{},
-					RetentionPolicy:   "09",
+					CustomWalArchive: &CustomWalArchiveConfiguration{
+						ArchiveCommand: []string{"archive", "%p"},
+					},
+					BeforeUpgrade: true,
 				},
 			},
 		}
-		err := cluster.validateBackupConfiguration()
-		Expect(len(err)).To(Equal(2))
+		result := cluster.validateBackupBeforeUpgrade()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.backup.beforeUpgrade"))
 	})
 })
 
-var _ = Describe("Default monitoring queries", func() {
-	It("correctly set the default monitoring queries configmap and secret when none is already specified", func() {
-		cluster := &Cluster{}
-		cluster.defaultMonitoringQueries(&configuration.Data{
-			MonitoringQueriesSecret:    "test-secret",
-			MonitoringQueriesConfigmap: "test-configmap",
-		})
-		Expect(cluster.Spec.Monitoring).NotTo(BeNil())
-		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
-		Expect(cluster.Spec.Monitoring.CustomQueriesConfigMap).
-			To(ContainElement(ConfigMapKeySelector{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
-				Key:                  DefaultMonitoringKey,
-			}))
-		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
-		Expect(cluster.Spec.Monitoring.CustomQueriesSecret).
-			To(ContainElement(SecretKeySelector{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringSecretName},
-				Key:                  DefaultMonitoringKey,
-			}))
-	})
-	testCluster := &Cluster{Spec: ClusterSpec{Monitoring: &MonitoringConfiguration{
-		CustomQueriesConfigMap: []ConfigMapKeySelector{
-			{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
-				Key:                  "test2",
-			},
-		},
-		CustomQueriesSecret: []SecretKeySelector{
-			{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
-				Key:                  "test3",
+var _ = Describe("backup encryption validation", func() {
+	It("accepts the KMS key when the WAL files are encrypted with aws:kms", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						BarmanCredentials: BarmanCredentials{AWS: &S3Credentials{}},
+						Wal:               &WalBackupConfiguration{Encryption: EncryptionTypeNoneAWSKMS},
+						Data:              &DataBackupConfiguration{Encryption: EncryptionTypeAES256},
+						SSEKMSKeyID:       "alias/backups",
+					},
+				},
 			},
-		},
-	}}}
-	It("correctly set the default monitoring queries configmap when other metrics are already specified", func() {
-		modifiedCluster := testCluster.DeepCopy()
-		modifiedCluster.defaultMonitoringQueries(&configuration.Data{
-			MonitoringQueriesConfigmap: "test-configmap",
-		})
-
-		Expect(modifiedCluster.Spec.Monitoring).NotTo(BeNil())
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).
-			To(ContainElement(ConfigMapKeySelector{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringConfigMapName},
-				Key:                  "test2",
-			}))
-
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).
-			To(BeEquivalentTo(testCluster.Spec.Monitoring.CustomQueriesSecret))
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).
-			To(ContainElements(testCluster.Spec.Monitoring.CustomQueriesConfigMap))
+		}
+		Expect(cluster.validateBackupEncryption()).To(BeEmpty())
 	})
-	It("correctly set the default monitoring queries secret when other metrics are already specified", func() {
-		modifiedCluster := testCluster.DeepCopy()
-		modifiedCluster.defaultMonitoringQueries(&configuration.Data{
-			MonitoringQueriesSecret: "test-secret",
-		})
 
-		Expect(modifiedCluster.Spec.Monitoring).NotTo(BeNil())
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).NotTo(BeEmpty())
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).NotTo(BeEmpty())
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).
-			To(ContainElement(SecretKeySelector{
-				LocalObjectReference: LocalObjectReference{Name: DefaultMonitoringSecretName},
-				Key:                  "test3",
-			}))
-
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesConfigMap).
-			To(BeEquivalentTo(testCluster.Spec.Monitoring.CustomQueriesConfigMap))
-		Expect(modifiedCluster.Spec.Monitoring.CustomQueriesSecret).
-			To(ContainElements(testCluster.Spec.Monitoring.CustomQueriesSecret))
+	It("complains about a KMS key not used by any encryption", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						BarmanCredentials: BarmanCredentials{AWS: &S3Credentials{}},
+						Wal:               &WalBackupConfiguration{Encryption: EncryptionTypeAES256},
+						SSEKMSKeyID:       "alias/backups",
+					},
+				},
+			},
+		}
+		result := cluster.validateBackupEncryption()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.backup.barmanObjectStore.sseKmsKeyId"))
 	})
-})
 
-var _ = Describe("validation of imports", func() {
-	It("rejects unrecognized import type", func() {
-		cluster := &Cluster{
+	It("complains about a KMS key used outside of S3", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type: "fooBar",
-						},
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						BarmanCredentials: BarmanCredentials{Google: &GoogleCredentials{}},
+						Data:              &DataBackupConfiguration{Encryption: EncryptionTypeNoneAWSKMS},
+						SSEKMSKeyID:       "alias/backups",
 					},
 				},
 			},
 		}
-
-		result := cluster.validateImport()
+		result := cluster.validateBackupEncryption()
 		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.backup.barmanObjectStore.sseKmsKeyId"))
 	})
+})
 
-	It("rejects microservice import with roles", func() {
-		cluster := &Cluster{
+var _ = Describe("barman endpoint CA validation", func() {
+	It("accepts a CA bundle for an https endpoint", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MicroserviceSnapshotType,
-							Databases: []string{"foo"},
-							Roles:     []string{"bar"},
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						EndpointURL: "https://minio:9000",
+						EndpointCA: &SecretKeySelector{
+							LocalObjectReference: LocalObjectReference{Name: "minio-ca"},
+							Key:                  "ca.crt",
 						},
 					},
 				},
 			},
 		}
-
-		result := cluster.validateImport()
-		Expect(result).To(HaveLen(1))
+		Expect(cluster.validateBarmanEndpointCA()).To(BeEmpty())
 	})
 
-	It("rejects microservice import without exactly one database", func() {
-		cluster := &Cluster{
+	It("complains about an incomplete reference to the CA bundle", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MicroserviceSnapshotType,
-							Databases: []string{"foo", "bar"},
+				ExternalClusters: []ExternalCluster{
+					{
+						Name: "origin",
+						BarmanObjectStore: &BarmanObjectStoreConfiguration{
+							EndpointURL: "https://minio:9000",
+							EndpointCA: &SecretKeySelector{
+								LocalObjectReference: LocalObjectReference{Name: "minio-ca"},
+							},
 						},
 					},
 				},
 			},
 		}
-
-		result := cluster.validateImport()
+		result := cluster.validateBarmanEndpointCA()
 		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.externalClusters[0].barmanObjectStore.endpointCA.key"))
 	})
 
-	It("rejects microservice import with a wildcard on the database name", func() {
-		cluster := &Cluster{
+	It("complains about a CA bundle for a plain http endpoint", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MicroserviceSnapshotType,
-							Databases: []string{"*foo"},
+				Backup: &BackupConfiguration{
+					BarmanObjectStore: &BarmanObjectStoreConfiguration{
+						EndpointURL: "http://minio:9000",
+						EndpointCA: &SecretKeySelector{
+							LocalObjectReference: LocalObjectReference{Name: "minio-ca"},
+							Key:                  "ca.crt",
 						},
 					},
 				},
 			},
 		}
-
-		result := cluster.validateImport()
+		result := cluster.validateBarmanEndpointCA()
 		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.backup.barmanObjectStore.endpointCA"))
 	})
+})
 
-	It("accepts microservice import when well specified", func() {
-		cluster := &Cluster{
+var _ = Describe("pg_hba validation", func() {
+	It("accepts well-formed rules", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MicroserviceSnapshotType,
-							Databases: []string{"foo"},
-						},
+				PostgresConfiguration: PostgresConfiguration{
+					PgHBA: []string{
+						"host all all 10.0.0.0/8 md5",
+						"host all all 10.0.0.0 255.0.0.0 md5",
+						"hostssl app app 10.244.0.0/16 scram-sha-256 # application network",
+						"local all postgres peer map=local",
+						`host "my db" all all ldap ldapserver=ldap.example.com ldapbinddn="cn=a user"`,
+						"# just a comment",
+						"",
+					},
+				},
+			},
+		}
+		Expect(cluster.validatePgHBA()).To(BeEmpty())
+	})
+
+	It("complains about rules with too few fields", func() {
+		cluster := Cluster{
+			Spec: ClusterSpec{
+				PostgresConfiguration: PostgresConfiguration{
+					PgHBA: []string{
+						"host all all md5",
+						"local all peer",
+						"hostssl all all 10.0.0.0/8 # md5",
 					},
 				},
 			},
 		}
-
-		result := cluster.validateImport()
-		Expect(result).To(BeEmpty())
+		Expect(cluster.validatePgHBA()).To(HaveLen(3))
 	})
 
-	It("rejects monolith import with no databases", func() {
-		cluster := &Cluster{
+	It("complains about unknown connection types", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MonolithSnapshotType,
-							Databases: []string{},
-						},
+				PostgresConfiguration: PostgresConfiguration{
+					PgHBA: []string{
+						"hots all all 10.0.0.0/8 md5",
 					},
 				},
 			},
 		}
-
-		result := cluster.validateImport()
-		Expect(result).To(HaveLen(1))
+		Expect(cluster.validatePgHBA()).To(HaveLen(1))
 	})
+})
 
-	It("rejects monolith import with PostImport Application SQL", func() {
-		cluster := &Cluster{
+var _ = Describe("pg_ident validation", func() {
+	It("accepts well-formed user name maps", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:                     MonolithSnapshotType,
-							Databases:                []string{"foo"},
-							PostImportApplicationSQL: []string{"select * from bar"},
-						},
+				PostgresConfiguration: PostgresConfiguration{
+					PgIdent: []string{
+						"certmap app.example.com app",
+						`certmap "/^(.*)@example\.com$" \1 # e-mail addresses`,
+						"# just a comment",
+						"",
 					},
 				},
 			},
 		}
-
-		result := cluster.validateImport()
-		Expect(result).To(HaveLen(1))
+		Expect(cluster.validatePgIdent()).To(BeEmpty())
 	})
 
-	It("rejects monolith import with wildcards alongside specific values", func() {
-		cluster := &Cluster{
+	It("complains about user name maps with the wrong number of fields", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MonolithSnapshotType,
-							Databases: []string{"bar", "*"},
-						},
+				PostgresConfiguration: PostgresConfiguration{
+					PgIdent: []string{
+						"certmap app.example.com",
+						"certmap app.example.com app other",
 					},
 				},
 			},
 		}
+		Expect(cluster.validatePgIdent()).To(HaveLen(2))
+	})
 
-		result := cluster.validateImport()
-		Expect(result).To(HaveLen(1))
-
-		cluster = &Cluster{
+	It("complains about user name maps using the reserved map", func() {
+		cluster := Cluster{
 			Spec: ClusterSpec{
-				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MonolithSnapshotType,
-							Databases: []string{"foo"},
-							Roles:     []string{"baz", "*"},
-						},
+				PostgresConfiguration: PostgresConfiguration{
+					PgIdent: []string{
+						"local root postgres",
 					},
 				},
 			},
 		}
+		Expect(cluster.validatePgIdent()).To(HaveLen(1))
+	})
+})
 
-		result = cluster.validateImport()
-		Expect(result).To(HaveLen(1))
+var _ = Describe("instances validation", func() {
+	It("rejects clusters without instances", func() {
+		cluster := Cluster{Spec: ClusterSpec{Instances: 0}}
+		Expect(cluster.validateInstances()).To(HaveLen(1))
+
+		cluster.Spec.Instances = -1
+		Expect(cluster.validateInstances()).To(HaveLen(1))
 	})
 
-	It("accepts monolith import with proper values", func() {
-		cluster := &Cluster{
+	It("accepts clusters with at least one instance", func() {
+		cluster := Cluster{Spec: ClusterSpec{Instances: 1}}
+		Expect(cluster.validateInstances()).To(BeEmpty())
+
+		cluster.Spec.Instances = 3
+		Expect(cluster.validateInstances()).To(BeEmpty())
+	})
+
+	It("warns about clusters with too many instances", func() {
+		cluster := Cluster{Spec: ClusterSpec{Instances: maxRecommendedInstances}}
+		Expect(cluster.getManyInstancesWarnings()).To(BeEmpty())
+
+		cluster.Spec.Instances = maxRecommendedInstances + 1
+		Expect(cluster.getManyInstancesWarnings()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("Managed databases validation", func() {
+	newCluster := func(databases ...DatabaseConfiguration) Cluster {
+		return Cluster{
 			Spec: ClusterSpec{
 				Bootstrap: &BootstrapConfiguration{
-					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
-						Import: &Import{
-							Type:      MonolithSnapshotType,
-							Databases: []string{"foo"},
-						},
+					InitDB: &BootstrapInitDB{Database: "app", Owner: "app"},
+				},
+				Managed: &ManagedConfiguration{
+					Roles: []RoleConfiguration{
+						{Name: "reporter", Login: true},
+						{Name: "former", Ensure: EnsureAbsent},
 					},
+					Databases: databases,
 				},
 			},
 		}
+	}
 
-		result := cluster.validateImport()
-		Expect(result).To(BeEmpty())
+	It("is valid without managed databases", func() {
+		cluster := Cluster{}
+		Expect(cluster.validateManagedDatabases()).To(BeEmpty())
+
+		cluster = newCluster()
+		Expect(cluster.validateManagedDatabases()).To(BeEmpty())
 	})
 
-	It("accepts monolith import with wildcards", func() {
-		cluster := &Cluster{
+	It("accepts databases owned by managed roles or by the application owner", func() {
+		cluster := newCluster(
+			DatabaseConfiguration{Name: "reporting", Owner: "reporter"},
+			DatabaseConfiguration{Name: "other", Owner: "app", Encoding: "LATIN1"},
+			DatabaseConfiguration{Name: "legacy", Ensure: EnsureAbsent},
+		)
+		Expect(cluster.validateManagedDatabases()).To(BeEmpty())
+	})
+
+	It("complains about duplicate names", func() {
+		cluster := newCluster(
+			DatabaseConfiguration{Name: "reporting", Owner: "reporter"},
+			DatabaseConfiguration{Name: "reporting", Owner: "app"},
+		)
+		Expect(cluster.validateManagedDatabases()).To(HaveLen(1))
+	})
+
+	It("complains about owners which are not managed", func() {
+		cluster := newCluster(
+			DatabaseConfiguration{Name: "reporting", Owner: "unknown"},
+			DatabaseConfiguration{Name: "history", Owner: "former"},
+		)
+		Expect(cluster.validateManagedDatabases()).To(HaveLen(2))
+	})
+
+	It("requires the owner only for the databases which should be present", func() {
+		cluster := newCluster(
+			DatabaseConfiguration{Name: "reporting"},
+			DatabaseConfiguration{Name: "legacy", Ensure: EnsureAbsent},
+		)
+		result := cluster.validateManagedDatabases()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Type).To(Equal(field.ErrorTypeRequired))
+		Expect(result[0].Field).To(Equal("spec.managed.databases[0].owner"))
+	})
+
+	It("complains about reserved databases", func() {
+		cluster := newCluster(
+			DatabaseConfiguration{Name: "postgres", Owner: "app"},
+			DatabaseConfiguration{Name: "template1", Owner: "app"},
+			DatabaseConfiguration{Name: "", Owner: "app"},
+		)
+		Expect(cluster.validateManagedDatabases()).To(HaveLen(3))
+	})
+
+	It("doesn't allow dropping the application database", func() {
+		cluster := newCluster(
+			DatabaseConfiguration{Name: "app", Ensure: EnsureAbsent},
+		)
+		Expect(cluster.validateManagedDatabases()).To(HaveLen(1))
+	})
+
+	It("complains about empty or duplicate extensions", func() {
+		cluster := newCluster(
+			DatabaseConfiguration{Name: "reporting", Owner: "reporter", Extensions: []string{"pgcrypto", "uuid-ossp"}},
+		)
+		Expect(cluster.validateManagedDatabases()).To(BeEmpty())
+
+		cluster = newCluster(
+			DatabaseConfiguration{Name: "reporting", Owner: "reporter", Extensions: []string{"pgcrypto", "", "pgcrypto"}},
+		)
+		Expect(cluster.validateManagedDatabases()).To(HaveLen(2))
+	})
+
+	It("doesn't allow extensions in the databases which should be absent", func() {
+		cluster := newCluster(
+			DatabaseConfiguration{Name: "legacy", Ensure: EnsureAbsent, Extensions: []string{"pgcrypto"}},
+		)
+		result := cluster.validateManagedDatabases()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.managed.databases[0].extensions"))
+	})
+})
+
+var _ = Describe("bootstrap import source validation", func() {
+	newImportCluster := func(externalClusters ...ExternalCluster) *Cluster {
+		return &Cluster{
 			Spec: ClusterSpec{
 				Bootstrap: &BootstrapConfiguration{
 					InitDB: &BootstrapInitDB{
-						Database: "app",
-						Owner:    "app",
 						Import: &Import{
-							Type:      MonolithSnapshotType,
-							Databases: []string{"*"},
-							Roles:     []string{"*"},
+							Type:      MicroserviceSnapshotType,
+							Databases: []string{"app"},
+							Source: ImportSource{
+								ExternalCluster: "source",
+							},
 						},
 					},
 				},
+				ExternalClusters: externalClusters,
 			},
 		}
+	}
 
-		result := cluster.validateImport()
-		Expect(result).To(BeEmpty())
-	})
-})
-
-var _ = Describe("validation of replication slots configuration", func() {
-	It("prevents using replication slots on PostgreSQL 10 and older", func() {
+	It("doesn't complain if we are not importing databases", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
-				ImageName: "ghcr.io/cloudnative-pg/postgresql:10.5",
-				ReplicationSlots: &ReplicationSlotsConfiguration{
-					HighAvailability: &ReplicationSlotsHAConfiguration{
-						Enabled: true,
-					},
-					UpdateInterval: 0,
+				Bootstrap: &BootstrapConfiguration{
+					InitDB: &BootstrapInitDB{},
 				},
 			},
 		}
-		cluster.Default()
+		Expect(cluster.validateBootstrapImportSource()).To(BeEmpty())
+	})
 
-		result := cluster.validateReplicationSlots()
+	It("doesn't complain when the source can be reached via streaming", func() {
+		cluster := newImportCluster(ExternalCluster{
+			Name: "source",
+			ConnectionParameters: map[string]string{
+				"host": "source-rw",
+			},
+		})
+		Expect(cluster.validateBootstrapImportSource()).To(BeEmpty())
+	})
+
+	It("complains when the source cluster doesn't exist", func() {
+		cluster := newImportCluster(ExternalCluster{
+			Name: "another-source",
+			ConnectionParameters: map[string]string{
+				"host": "source-rw",
+			},
+		})
+		result := cluster.validateBootstrapImportSource()
 		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.bootstrap.initdb.import.source.externalCluster"))
 	})
 
-	It("can be enabled on the default PostgreSQL image", func() {
+	It("complains when the source has no connection parameters", func() {
+		cluster := newImportCluster(ExternalCluster{
+			Name:              "source",
+			BarmanObjectStore: &BarmanObjectStoreConfiguration{},
+		})
+		Expect(cluster.validateBootstrapImportSource()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("security context validation", func() {
+	It("doesn't complain by default", func() {
+		Expect((&Cluster{}).validateSecurityContext()).To(BeEmpty())
+	})
+
+	It("complains about negative UID and GID", func() {
 		cluster := &Cluster{
 			Spec: ClusterSpec{
-				ImageName: versions.DefaultImageName,
-				ReplicationSlots: &ReplicationSlotsConfiguration{
-					HighAvailability: &ReplicationSlotsHAConfiguration{
-						Enabled: true,
-					},
-					UpdateInterval: 0,
-				},
+				PostgresUID: -1,
+				PostgresGID: -1,
 			},
 		}
-		cluster.Default()
-
-		result := cluster.validateReplicationSlots()
-		Expect(result).To(BeEmpty())
+		Expect(cluster.validateSecurityContext()).To(HaveLen(2))
 	})
 
-	It("allows enabling replication slots on the fly", func() {
-		oldCluster := &Cluster{
+	It("accepts a custom fsGroup", func() {
+		fsGroup := int64(1000)
+		cluster := &Cluster{
 			Spec: ClusterSpec{
-				ImageName: versions.DefaultImageName,
+				FSGroup: &fsGroup,
 			},
 		}
-		oldCluster.Default()
+		Expect(cluster.validateSecurityContext()).To(BeEmpty())
+	})
 
-		newCluster := oldCluster.DeepCopy()
-		newCluster.Spec.ReplicationSlots = &ReplicationSlotsConfiguration{
-			HighAvailability: &ReplicationSlotsHAConfiguration{
-				Enabled:    true,
-				SlotPrefix: "_test_",
+	It("requires the profile of a Localhost seccomp profile", func() {
+		cluster := &Cluster{
+			Spec: ClusterSpec{
+				SeccompProfile: &v1.SeccompProfile{
+					Type: v1.SeccompProfileTypeLocalhost,
+				},
 			},
 		}
+		result := cluster.validateSecurityContext()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.seccompProfile.localhostProfile"))
 
-		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(BeEmpty())
+		localhostProfile := "profiles/postgres.json"
+		cluster.Spec.SeccompProfile.LocalhostProfile = &localhostProfile
+		Expect(cluster.validateSecurityContext()).To(BeEmpty())
 	})
+})
 
-	It("prevents changing the slot prefix while replication slots are enabled", func() {
-		oldCluster := &Cluster{
-			Spec: ClusterSpec{
-				ImageName: versions.DefaultImageName,
-				ReplicationSlots: &ReplicationSlotsConfiguration{
-					HighAvailability: &ReplicationSlotsHAConfiguration{
-						Enabled:    true,
-						SlotPrefix: "_test_",
-					},
+var _ = Describe("fenced instances validation", func() {
+	newFencedCluster := func(annotation string) *Cluster {
+		return &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example",
+				Annotations: map[string]string{
+					utils.FencedInstanceAnnotation: annotation,
 				},
 			},
 		}
-		oldCluster.Default()
+	}
 
-		newCluster := oldCluster.DeepCopy()
-		newCluster.Spec.ReplicationSlots.HighAvailability.SlotPrefix = "_toast_"
-		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(HaveLen(1))
+	It("doesn't complain when no instance is fenced", func() {
+		Expect((&Cluster{}).validateFencedInstances()).To(BeEmpty())
+		Expect(newFencedCluster(`[]`).validateFencedInstances()).To(BeEmpty())
 	})
 
-	It("prevents removing the replication slot section when replication slots are enabled", func() {
-		oldCluster := &Cluster{
+	It("accepts the instances of the cluster and the wildcard", func() {
+		Expect(newFencedCluster(`["cluster-example-1","cluster-example-3"]`).validateFencedInstances()).
+			To(BeEmpty())
+		Expect(newFencedCluster(`["*"]`).validateFencedInstances()).To(BeEmpty())
+	})
+
+	It("complains about an invalid syntax", func() {
+		result := newFencedCluster(`cluster-example-1`).validateFencedInstances()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("metadata.annotations." + utils.FencedInstanceAnnotation))
+	})
+
+	It("complains about instances of other clusters", func() {
+		Expect(newFencedCluster(`["another-cluster-1"]`).validateFencedInstances()).To(HaveLen(1))
+		Expect(newFencedCluster(`["cluster-example-one"]`).validateFencedInstances()).To(HaveLen(1))
+		Expect(newFencedCluster(`["cluster-example--0"]`).validateFencedInstances()).To(HaveLen(1))
+		Expect(newFencedCluster(`["cluster-example-01"]`).validateFencedInstances()).To(HaveLen(1))
+	})
+})
+
+var _ = Describe("liveness isolation check validation", func() {
+	newCluster := func(timeoutSeconds *int32, requestTimeout int) *Cluster {
+		return &Cluster{
 			Spec: ClusterSpec{
-				ImageName: versions.DefaultImageName,
-				ReplicationSlots: &ReplicationSlotsConfiguration{
-					HighAvailability: &ReplicationSlotsHAConfiguration{
-						Enabled:    true,
-						SlotPrefix: "_test_",
+				Probes: &ProbesConfiguration{
+					Liveness: &LivenessProbe{
+						Probe: Probe{TimeoutSeconds: timeoutSeconds},
+						IsolationCheck: &IsolationCheckConfiguration{
+							Enabled:        true,
+							RequestTimeout: requestTimeout,
+						},
 					},
 				},
 			},
 		}
-		oldCluster.Default()
+	}
 
-		newCluster := oldCluster.DeepCopy()
-		newCluster.Spec.ReplicationSlots = nil
-		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(HaveLen(1))
+	It("doesn't complain when the isolation check is not configured", func() {
+		Expect((&Cluster{}).validateLivenessIsolationCheck()).To(BeEmpty())
+		Expect((&Cluster{Spec: ClusterSpec{Probes: &ProbesConfiguration{}}}).validateLivenessIsolationCheck()).
+			To(BeEmpty())
 	})
 
-	It("allows disabling the replication slots", func() {
-		oldCluster := &Cluster{
+	It("doesn't complain when the request completes before the liveness probe times out", func() {
+		Expect(newCluster(nil, 1000).validateLivenessIsolationCheck()).To(BeEmpty())
+		Expect(newCluster(pointer.Int32(2), 1500).validateLivenessIsolationCheck()).To(BeEmpty())
+	})
+
+	It("complains when the request outlasts the liveness probe", func() {
+		result := newCluster(pointer.Int32(1), 0).validateLivenessIsolationCheck()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.probes.liveness.isolationCheck.requestTimeout"))
+		Expect(newCluster(pointer.Int32(2), 3000).validateLivenessIsolationCheck()).To(HaveLen(1))
+	})
+
+	It("compares the request timeout with the default timeout of the liveness probe", func() {
+		Expect(newCluster(nil, 4000).validateLivenessIsolationCheck()).To(BeEmpty())
+		Expect(newCluster(nil, 6000).validateLivenessIsolationCheck()).To(HaveLen(1))
+		Expect(newCluster(pointer.Int32(0), 500).validateLivenessIsolationCheck()).To(BeEmpty())
+		Expect(newCluster(pointer.Int32(0), 1500).validateLivenessIsolationCheck()).To(HaveLen(1))
+	})
+
+	It("doesn't complain when the isolation check is disabled", func() {
+		cluster := newCluster(pointer.Int32(1), 3000)
+		cluster.Spec.Probes.Liveness.IsolationCheck.Enabled = false
+		Expect(cluster.validateLivenessIsolationCheck()).To(BeEmpty())
+	})
+})
+
+var _ = Describe("additional volumes validation", func() {
+	newCluster := func(volumeName, mountName, mountPath string) *Cluster {
+		return &Cluster{
 			Spec: ClusterSpec{
-				ImageName: versions.DefaultImageName,
-				ReplicationSlots: &ReplicationSlotsConfiguration{
-					HighAvailability: &ReplicationSlotsHAConfiguration{
-						Enabled:    true,
-						SlotPrefix: "_test_",
+				AdditionalVolumes: []v1.Volume{
+					{
+						Name: volumeName,
+						VolumeSource: v1.VolumeSource{
+							EmptyDir: &v1.EmptyDirVolumeSource{},
+						},
+					},
+				},
+				AdditionalVolumeMounts: []v1.VolumeMount{
+					{
+						Name:      mountName,
+						MountPath: mountPath,
 					},
 				},
 			},
 		}
-		oldCluster.Default()
+	}
 
-		newCluster := oldCluster.DeepCopy()
-		newCluster.Spec.ReplicationSlots.HighAvailability.Enabled = false
-		Expect(newCluster.validateReplicationSlotsChange(oldCluster)).To(BeEmpty())
+	It("doesn't complain when no additional volume is requested", func() {
+		Expect((&Cluster{}).validateAdditionalVolumes()).To(BeEmpty())
+	})
+
+	It("accepts a volume mounted onto a free path", func() {
+		Expect(newCluster("audit", "audit", "/var/log/audit").validateAdditionalVolumes()).To(BeEmpty())
+		Expect(newCluster("audit", "audit", "/var/lib/postgresql/audit").validateAdditionalVolumes()).To(BeEmpty())
+	})
+
+	It("complains about the volumes managed by the operator", func() {
+		result := newCluster("pgdata", "pgdata", "/audit").validateAdditionalVolumes()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.additionalVolumes[0].name"))
+
+		result = newCluster("0-post-init-application-sql", "0-post-init-application-sql", "/audit").
+			validateAdditionalVolumes()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.additionalVolumes[0].name"))
+	})
+
+	It("complains about duplicated and unnamed volumes", func() {
+		cluster := newCluster("audit", "audit", "/audit")
+		cluster.Spec.AdditionalVolumes = append(cluster.Spec.AdditionalVolumes,
+			cluster.Spec.AdditionalVolumes[0], v1.Volume{})
+		result := cluster.validateAdditionalVolumes()
+		Expect(result).To(HaveLen(2))
+		Expect(result[0].Field).To(Equal("spec.additionalVolumes[1].name"))
+		Expect(result[1].Field).To(Equal("spec.additionalVolumes[2].name"))
+	})
+
+	It("complains about mounts not referring to an additional volume", func() {
+		result := newCluster("audit", "scratch-data", "/audit").validateAdditionalVolumes()
+		Expect(result).To(HaveLen(1))
+		Expect(result[0].Field).To(Equal("spec.additionalVolumeMounts[0].name"))
+	})
+
+	It("complains about mounts onto the paths reserved to the operator", func() {
+		for _, mountPath := range []string{
+			"/var/lib/postgresql/data",
+			"/var/lib/postgresql/data/",
+			"/var/lib/postgresql/data/pgdata",
+			"/var/lib/postgresql",
+			"/controller/certificates",
+			"/",
+			"audit",
+		} {
+			result := newCluster("audit", "audit", mountPath).validateAdditionalVolumes()
+			Expect(result).To(HaveLen(1), mountPath)
+			Expect(result[0].Field).To(Equal("spec.additionalVolumeMounts[0].mountPath"))
+		}
 	})
 })
@@ -17,13 +17,18 @@ limitations under the License.
 package v1
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/robfig/cron"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 )
@@ -35,6 +40,7 @@ var scheduledBackupLog = log.WithName("scheduledbackup-resource").WithValues("ve
 func (r *ScheduledBackup) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithValidator(&scheduledBackupValidator{client: mgr.GetClient()}).
 		Complete()
 }
 
@@ -59,6 +65,11 @@ func (r *ScheduledBackup) ValidateCreate() error {
 
 	allErrs = append(allErrs, r.validateSchedule()...)
 
+	return r.toInvalidError(allErrs)
+}
+
+// toInvalidError wraps the passed validation errors, if any, in an API error
+func (r *ScheduledBackup) toInvalidError(allErrs field.ErrorList) error {
 	if len(allErrs) == 0 {
 		return nil
 	}
@@ -92,3 +103,88 @@ func (r *ScheduledBackup) validateSchedule() field.ErrorList {
 
 	return result
 }
+
+// scheduledBackupValidator extends the validation of the ScheduledBackup
+// resources with the checks involving the target cluster
+type scheduledBackupValidator struct {
+	client client.Reader
+}
+
+var _ admission.CustomValidator = &scheduledBackupValidator{}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *scheduledBackupValidator) ValidateCreate(ctx context.Context, obj runtime.Object) error {
+	scheduledBackup, ok := obj.(*ScheduledBackup)
+	if !ok {
+		return fmt.Errorf("expected a ScheduledBackup but got a %T", obj)
+	}
+
+	if err := scheduledBackup.ValidateCreate(); err != nil {
+		return err
+	}
+
+	return scheduledBackup.toInvalidError(v.validateClusterBackupConfiguration(ctx, scheduledBackup))
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *scheduledBackupValidator) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) error {
+	scheduledBackup, ok := newObj.(*ScheduledBackup)
+	if !ok {
+		return fmt.Errorf("expected a ScheduledBackup but got a %T", newObj)
+	}
+
+	return scheduledBackup.ValidateUpdate(oldObj)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *scheduledBackupValidator) ValidateDelete(_ context.Context, obj runtime.Object) error {
+	scheduledBackup, ok := obj.(*ScheduledBackup)
+	if !ok {
+		return fmt.Errorf("expected a ScheduledBackup but got a %T", obj)
+	}
+
+	return scheduledBackup.ValidateDelete()
+}
+
+// validateClusterBackupConfiguration checks that the target cluster has
+// a backup section with an object store, as otherwise every backup would
+// fail. A cluster not existing yet is accepted, as it may be created at
+// the same time as the ScheduledBackup
+func (v *scheduledBackupValidator) validateClusterBackupConfiguration(
+	ctx context.Context,
+	scheduledBackup *ScheduledBackup,
+) field.ErrorList {
+	var cluster Cluster
+	err := v.client.Get(ctx, client.ObjectKey{
+		Namespace: scheduledBackup.Namespace,
+		Name:      scheduledBackup.Spec.Cluster.Name,
+	}, &cluster)
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+	if err != nil {
+		scheduledBackupLog.Warning("Cannot get the target cluster, skipping the backup configuration check",
+			"name", scheduledBackup.Name, "namespace", scheduledBackup.Namespace, "err", err)
+		return nil
+	}
+
+	if cluster.Spec.Backup == nil {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "cluster", "name"),
+				scheduledBackup.Spec.Cluster.Name,
+				"cannot schedule backups, as the cluster has no backup section"),
+		}
+	}
+
+	if cluster.Spec.Backup.BarmanObjectStore == nil {
+		return field.ErrorList{
+			field.Invalid(
+				field.NewPath("spec", "cluster", "name"),
+				scheduledBackup.Spec.Cluster.Name,
+				"cannot schedule backups, as the backup section of the cluster has no object store"),
+		}
+	}
+
+	return nil
+}
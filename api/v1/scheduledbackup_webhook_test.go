@@ -17,6 +17,12 @@ limitations under the License.
 package v1
 
 import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -44,3 +50,73 @@ var _ = Describe("Validate schedule", func() {
 		Expect(len(result)).To(Equal(1))
 	})
 })
+
+var _ = Describe("Validate the backup configuration of the target cluster", func() {
+	newValidator := func(objects ...runtime.Object) *scheduledBackupValidator {
+		scheme := runtime.NewScheme()
+		Expect(AddToScheme(scheme)).To(Succeed())
+		return &scheduledBackupValidator{
+			client: fake.NewClientBuilder().WithScheme(scheme).WithRuntimeObjects(objects...).Build(),
+		}
+	}
+
+	scheduledBackup := &ScheduledBackup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "scheduled-backup",
+			Namespace: "default",
+		},
+		Spec: ScheduledBackupSpec{
+			Schedule: "0 0 0 * * *",
+			Cluster: LocalObjectReference{
+				Name: "cluster-example",
+			},
+		},
+	}
+
+	newCluster := func(backup *BackupConfiguration) *Cluster {
+		return &Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: ClusterSpec{
+				Backup: backup,
+			},
+		}
+	}
+
+	backupConfiguration := &BackupConfiguration{
+		BarmanObjectStore: &BarmanObjectStoreConfiguration{DestinationPath: "s3://backups/"},
+	}
+
+	It("accepts a cluster with a backup section", func() {
+		validator := newValidator(newCluster(backupConfiguration))
+		Expect(validator.ValidateCreate(context.TODO(), scheduledBackup)).To(Succeed())
+	})
+
+	It("accepts a cluster that doesn't exist yet", func() {
+		validator := newValidator()
+		Expect(validator.ValidateCreate(context.TODO(), scheduledBackup)).To(Succeed())
+	})
+
+	It("rejects a cluster without a backup section", func() {
+		validator := newValidator(newCluster(nil))
+		err := validator.ValidateCreate(context.TODO(), scheduledBackup)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("the cluster has no backup section"))
+	})
+
+	It("rejects a cluster without an object store", func() {
+		validator := newValidator(newCluster(&BackupConfiguration{}))
+		err := validator.ValidateCreate(context.TODO(), scheduledBackup)
+		Expect(err).To(HaveOccurred())
+		Expect(err.Error()).To(ContainSubstring("has no object store"))
+	})
+
+	It("still validates the schedule", func() {
+		validator := newValidator(newCluster(backupConfiguration))
+		wrongSchedule := scheduledBackup.DeepCopy()
+		wrongSchedule.Spec.Schedule = "0 0 0 * * * 1996"
+		Expect(validator.ValidateCreate(context.TODO(), wrongSchedule)).ToNot(Succeed())
+	})
+})
@@ -142,6 +142,11 @@ func (in *BackupConfiguration) DeepCopyInto(out *BackupConfiguration) {
 		*out = new(BarmanObjectStoreConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CustomWalArchive != nil {
+		in, out := &in.CustomWalArchive, &out.CustomWalArchive
+		*out = new(CustomWalArchiveConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BackupConfiguration.
@@ -569,6 +574,21 @@ func (in *ClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterMonitoringTLSConfiguration) DeepCopyInto(out *ClusterMonitoringTLSConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterMonitoringTLSConfiguration.
+func (in *ClusterMonitoringTLSConfiguration) DeepCopy() *ClusterMonitoringTLSConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterMonitoringTLSConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	*out = *in
@@ -577,12 +597,51 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(EmbeddedObjectMetadata)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.CommandOverride != nil {
+		in, out := &in.CommandOverride, &out.CommandOverride
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ArgsOverride != nil {
+		in, out := &in.ArgsOverride, &out.ArgsOverride
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]corev1.EnvVar, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]corev1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.FSGroup != nil {
+		in, out := &in.FSGroup, &out.FSGroup
+		*out = new(int64)
+		**out = **in
+	}
+	if in.SeccompProfile != nil {
+		in, out := &in.SeccompProfile, &out.SeccompProfile
+		*out = new(corev1.SeccompProfile)
+		(*in).DeepCopyInto(*out)
+	}
 	in.PostgresConfiguration.DeepCopyInto(&out.PostgresConfiguration)
 	if in.ReplicationSlots != nil {
 		in, out := &in.ReplicationSlots, &out.ReplicationSlots
 		*out = new(ReplicationSlotsConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.DelayedReplicas != nil {
+		in, out := &in.DelayedReplicas, &out.DelayedReplicas
+		*out = new(DelayedReplicasConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Bootstrap != nil {
 		in, out := &in.Bootstrap, &out.Bootstrap
 		*out = new(BootstrapConfiguration)
@@ -624,8 +683,56 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(StorageConfiguration)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AdditionalVolumes != nil {
+		in, out := &in.AdditionalVolumes, &out.AdditionalVolumes
+		*out = make([]corev1.Volume, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalVolumeMounts != nil {
+		in, out := &in.AdditionalVolumeMounts, &out.AdditionalVolumeMounts
+		*out = make([]corev1.VolumeMount, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Probes != nil {
+		in, out := &in.Probes, &out.Probes
+		*out = new(ProbesConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	in.Affinity.DeepCopyInto(&out.Affinity)
+	if in.TopologySpreadConstraints != nil {
+		in, out := &in.TopologySpreadConstraints, &out.TopologySpreadConstraints
+		*out = make([]corev1.TopologySpreadConstraint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	in.Resources.DeepCopyInto(&out.Resources)
+	if in.ResourcesOverrides != nil {
+		in, out := &in.ResourcesOverrides, &out.ResourcesOverrides
+		*out = make(map[string]corev1.ResourceRequirements, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+	if in.BootstrapResources != nil {
+		in, out := &in.BootstrapResources, &out.BootstrapResources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.EnableAutomaticFailover != nil {
+		in, out := &in.EnableAutomaticFailover, &out.EnableAutomaticFailover
+		*out = new(bool)
+		**out = **in
+	}
+	if in.PrimaryHealthCheck != nil {
+		in, out := &in.PrimaryHealthCheck, &out.PrimaryHealthCheck
+		*out = new(PrimaryHealthCheckConfiguration)
+		**out = **in
+	}
 	if in.Backup != nil {
 		in, out := &in.Backup, &out.Backup
 		*out = new(BackupConfiguration)
@@ -636,6 +743,16 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		*out = new(NodeMaintenanceWindow)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.EnablePDB != nil {
+		in, out := &in.EnablePDB, &out.EnablePDB
+		*out = new(bool)
+		**out = **in
+	}
+	if in.NetworkPolicy != nil {
+		in, out := &in.NetworkPolicy, &out.NetworkPolicy
+		*out = new(NetworkPolicyConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Monitoring != nil {
 		in, out := &in.Monitoring, &out.Monitoring
 		*out = new(MonitoringConfiguration)
@@ -648,6 +765,11 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Managed != nil {
+		in, out := &in.Managed, &out.Managed
+		*out = new(ManagedConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterSpec.
@@ -682,9 +804,14 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		in, out := &in.InstancesReportedState, &out.InstancesReportedState
 		*out = make(map[PodName]InstanceReportedState, len(*in))
 		for key, val := range *in {
-			(*out)[key] = val
+			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.TimelineHistory != nil {
+		in, out := &in.TimelineHistory, &out.TimelineHistory
+		*out = make([]TimelineHistoryEntry, len(*in))
+		copy(*out, *in)
+	}
 	in.Topology.DeepCopyInto(&out.Topology)
 	if in.DanglingPVC != nil {
 		in, out := &in.DanglingPVC, &out.DanglingPVC
@@ -731,6 +858,13 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ManagedDatabasesErrors != nil {
+		in, out := &in.ManagedDatabasesErrors, &out.ManagedDatabasesErrors
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -781,6 +915,31 @@ func (in *ConfigMapResourceVersion) DeepCopy() *ConfigMapResourceVersion {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CustomWalArchiveConfiguration) DeepCopyInto(out *CustomWalArchiveConfiguration) {
+	*out = *in
+	if in.ArchiveCommand != nil {
+		in, out := &in.ArchiveCommand, &out.ArchiveCommand
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RestoreCommand != nil {
+		in, out := &in.RestoreCommand, &out.RestoreCommand
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CustomWalArchiveConfiguration.
+func (in *CustomWalArchiveConfiguration) DeepCopy() *CustomWalArchiveConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(CustomWalArchiveConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DataBackupConfiguration) DeepCopyInto(out *DataBackupConfiguration) {
 	*out = *in
@@ -801,6 +960,46 @@ func (in *DataBackupConfiguration) DeepCopy() *DataBackupConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DatabaseConfiguration) DeepCopyInto(out *DatabaseConfiguration) {
+	*out = *in
+	if in.Extensions != nil {
+		in, out := &in.Extensions, &out.Extensions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DatabaseConfiguration.
+func (in *DatabaseConfiguration) DeepCopy() *DatabaseConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DatabaseConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DelayedReplicasConfiguration) DeepCopyInto(out *DelayedReplicasConfiguration) {
+	*out = *in
+	if in.Instances != nil {
+		in, out := &in.Instances, &out.Instances
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DelayedReplicasConfiguration.
+func (in *DelayedReplicasConfiguration) DeepCopy() *DelayedReplicasConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(DelayedReplicasConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EmbeddedObjectMetadata) DeepCopyInto(out *EmbeddedObjectMetadata) {
 	*out = *in
@@ -911,6 +1110,11 @@ func (in *Import) DeepCopyInto(out *Import) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.ExcludedRoles != nil {
+		in, out := &in.ExcludedRoles, &out.ExcludedRoles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.PostImportApplicationSQL != nil {
 		in, out := &in.PostImportApplicationSQL, &out.PostImportApplicationSQL
 		*out = make([]string, len(*in))
@@ -961,6 +1165,11 @@ func (in *InstanceID) DeepCopy() *InstanceID {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InstanceReportedState) DeepCopyInto(out *InstanceReportedState) {
 	*out = *in
+	if in.PendingRestartSettings != nil {
+		in, out := &in.PendingRestartSettings, &out.PendingRestartSettings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstanceReportedState.
@@ -973,6 +1182,21 @@ func (in *InstanceReportedState) DeepCopy() *InstanceReportedState {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IsolationCheckConfiguration) DeepCopyInto(out *IsolationCheckConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IsolationCheckConfiguration.
+func (in *IsolationCheckConfiguration) DeepCopy() *IsolationCheckConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(IsolationCheckConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LDAPBindAsAuth) DeepCopyInto(out *LDAPBindAsAuth) {
 	*out = *in
@@ -1033,6 +1257,27 @@ func (in *LDAPConfig) DeepCopy() *LDAPConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LivenessProbe) DeepCopyInto(out *LivenessProbe) {
+	*out = *in
+	in.Probe.DeepCopyInto(&out.Probe)
+	if in.IsolationCheck != nil {
+		in, out := &in.IsolationCheck, &out.IsolationCheck
+		*out = new(IsolationCheckConfiguration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LivenessProbe.
+func (in *LivenessProbe) DeepCopy() *LivenessProbe {
+	if in == nil {
+		return nil
+	}
+	out := new(LivenessProbe)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LocalObjectReference) DeepCopyInto(out *LocalObjectReference) {
 	*out = *in
@@ -1048,6 +1293,70 @@ func (in *LocalObjectReference) DeepCopy() *LocalObjectReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedConfiguration) DeepCopyInto(out *ManagedConfiguration) {
+	*out = *in
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]RoleConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Databases != nil {
+		in, out := &in.Databases, &out.Databases
+		*out = make([]DatabaseConfiguration, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Services != nil {
+		in, out := &in.Services, &out.Services
+		*out = new(ManagedServices)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedConfiguration.
+func (in *ManagedConfiguration) DeepCopy() *ManagedConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManagedServices) DeepCopyInto(out *ManagedServices) {
+	*out = *in
+	if in.ReadWrite != nil {
+		in, out := &in.ReadWrite, &out.ReadWrite
+		*out = new(ServiceTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ReadOnly != nil {
+		in, out := &in.ReadOnly, &out.ReadOnly
+		*out = new(ServiceTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Read != nil {
+		in, out := &in.Read, &out.Read
+		*out = new(ServiceTemplate)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManagedServices.
+func (in *ManagedServices) DeepCopy() *ManagedServices {
+	if in == nil {
+		return nil
+	}
+	out := new(ManagedServices)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Metadata) DeepCopyInto(out *Metadata) {
 	*out = *in
@@ -1095,6 +1404,11 @@ func (in *MonitoringConfiguration) DeepCopyInto(out *MonitoringConfiguration) {
 		*out = make([]SecretKeySelector, len(*in))
 		copy(*out, *in)
 	}
+	if in.TLSConfig != nil {
+		in, out := &in.TLSConfig, &out.TLSConfig
+		*out = new(ClusterMonitoringTLSConfiguration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonitoringConfiguration.
@@ -1107,6 +1421,53 @@ func (in *MonitoringConfiguration) DeepCopy() *MonitoringConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyClient) DeepCopyInto(out *NetworkPolicyClient) {
+	*out = *in
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.PodSelector != nil {
+		in, out := &in.PodSelector, &out.PodSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyClient.
+func (in *NetworkPolicyClient) DeepCopy() *NetworkPolicyClient {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyClient)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NetworkPolicyConfiguration) DeepCopyInto(out *NetworkPolicyConfiguration) {
+	*out = *in
+	if in.AllowedClients != nil {
+		in, out := &in.AllowedClients, &out.AllowedClients
+		*out = make([]NetworkPolicyClient, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NetworkPolicyConfiguration.
+func (in *NetworkPolicyConfiguration) DeepCopy() *NetworkPolicyConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(NetworkPolicyConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NodeMaintenanceWindow) DeepCopyInto(out *NodeMaintenanceWindow) {
 	*out = *in
@@ -1417,7 +1778,17 @@ func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PgIdent != nil {
+		in, out := &in.PgIdent, &out.PgIdent
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	in.SyncReplicaElectionConstraint.DeepCopyInto(&out.SyncReplicaElectionConstraint)
+	if in.Synchronous != nil {
+		in, out := &in.Synchronous, &out.Synchronous
+		*out = new(SynchronousReplicaConfiguration)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AdditionalLibraries != nil {
 		in, out := &in.AdditionalLibraries, &out.AdditionalLibraries
 		*out = make([]string, len(*in))
@@ -1428,6 +1799,31 @@ func (in *PostgresConfiguration) DeepCopyInto(out *PostgresConfiguration) {
 		*out = new(LDAPConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.HotStandbyFeedback != nil {
+		in, out := &in.HotStandbyFeedback, &out.HotStandbyFeedback
+		*out = new(bool)
+		**out = **in
+	}
+	if in.WalSenderTimeout != nil {
+		in, out := &in.WalSenderTimeout, &out.WalSenderTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.WalReceiverTimeout != nil {
+		in, out := &in.WalReceiverTimeout, &out.WalReceiverTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.StatementTimeout != nil {
+		in, out := &in.StatementTimeout, &out.StatementTimeout
+		*out = new(int32)
+		**out = **in
+	}
+	if in.IdleInTransactionSessionTimeout != nil {
+		in, out := &in.IdleInTransactionSessionTimeout, &out.IdleInTransactionSessionTimeout
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostgresConfiguration.
@@ -1440,6 +1836,86 @@ func (in *PostgresConfiguration) DeepCopy() *PostgresConfiguration {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrimaryHealthCheckConfiguration) DeepCopyInto(out *PrimaryHealthCheckConfiguration) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrimaryHealthCheckConfiguration.
+func (in *PrimaryHealthCheckConfiguration) DeepCopy() *PrimaryHealthCheckConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(PrimaryHealthCheckConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Probe) DeepCopyInto(out *Probe) {
+	*out = *in
+	if in.InitialDelaySeconds != nil {
+		in, out := &in.InitialDelaySeconds, &out.InitialDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.TimeoutSeconds != nil {
+		in, out := &in.TimeoutSeconds, &out.TimeoutSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PeriodSeconds != nil {
+		in, out := &in.PeriodSeconds, &out.PeriodSeconds
+		*out = new(int32)
+		**out = **in
+	}
+	if in.FailureThreshold != nil {
+		in, out := &in.FailureThreshold, &out.FailureThreshold
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Probe.
+func (in *Probe) DeepCopy() *Probe {
+	if in == nil {
+		return nil
+	}
+	out := new(Probe)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ProbesConfiguration) DeepCopyInto(out *ProbesConfiguration) {
+	*out = *in
+	if in.Startup != nil {
+		in, out := &in.Startup, &out.Startup
+		*out = new(Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Liveness != nil {
+		in, out := &in.Liveness, &out.Liveness
+		*out = new(LivenessProbe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Readiness != nil {
+		in, out := &in.Readiness, &out.Readiness
+		*out = new(Probe)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ProbesConfiguration.
+func (in *ProbesConfiguration) DeepCopy() *ProbesConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(ProbesConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RecoveryTarget) DeepCopyInto(out *RecoveryTarget) {
 	*out = *in
@@ -1515,6 +1991,31 @@ func (in *ReplicationSlotsHAConfiguration) DeepCopy() *ReplicationSlotsHAConfigu
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RoleConfiguration) DeepCopyInto(out *RoleConfiguration) {
+	*out = *in
+	if in.PasswordSecret != nil {
+		in, out := &in.PasswordSecret, &out.PasswordSecret
+		*out = new(LocalObjectReference)
+		**out = **in
+	}
+	if in.ConnectionLimit != nil {
+		in, out := &in.ConnectionLimit, &out.ConnectionLimit
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RoleConfiguration.
+func (in *RoleConfiguration) DeepCopy() *RoleConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(RoleConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RollingUpdateStatus) DeepCopyInto(out *RollingUpdateStatus) {
 	*out = *in
@@ -1719,6 +2220,13 @@ func (in *SecretsResourceVersion) DeepCopyInto(out *SecretsResourceVersion) {
 			(*out)[key] = val
 		}
 	}
+	if in.ManagedRoleSecretVersions != nil {
+		in, out := &in.ManagedRoleSecretVersions, &out.ManagedRoleSecretVersions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SecretsResourceVersion.
@@ -1747,6 +2255,22 @@ func (in *ServiceAccountTemplate) DeepCopy() *ServiceAccountTemplate {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceTemplate) DeepCopyInto(out *ServiceTemplate) {
+	*out = *in
+	in.Metadata.DeepCopyInto(&out.Metadata)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceTemplate.
+func (in *ServiceTemplate) DeepCopy() *ServiceTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageConfiguration) DeepCopyInto(out *StorageConfiguration) {
 	*out = *in
@@ -1797,6 +2321,41 @@ func (in *SyncReplicaElectionConstraints) DeepCopy() *SyncReplicaElectionConstra
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SynchronousReplicaConfiguration) DeepCopyInto(out *SynchronousReplicaConfiguration) {
+	*out = *in
+	if in.StandbyNames != nil {
+		in, out := &in.StandbyNames, &out.StandbyNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SynchronousReplicaConfiguration.
+func (in *SynchronousReplicaConfiguration) DeepCopy() *SynchronousReplicaConfiguration {
+	if in == nil {
+		return nil
+	}
+	out := new(SynchronousReplicaConfiguration)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TimelineHistoryEntry) DeepCopyInto(out *TimelineHistoryEntry) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TimelineHistoryEntry.
+func (in *TimelineHistoryEntry) DeepCopy() *TimelineHistoryEntry {
+	if in == nil {
+		return nil
+	}
+	out := new(TimelineHistoryEntry)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Topology) DeepCopyInto(out *Topology) {
 	*out = *in
@@ -18,7 +18,9 @@ package controllers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
@@ -62,7 +64,7 @@ type BackupReconciler struct {
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters,verbs=get
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups="",resources=pods/exec,verbs=get;list;delete;patch;create;watch
-// +kubebuilder:rbac:groups="",resources=pods,verbs=get
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list
 
 // Reconcile is the main reconciliation loop
 func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -109,12 +111,16 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 
 	contextLogger.Debug("Found cluster for backup", "cluster", clusterName)
 
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+		const message = "cannot proceed with the backup as the cluster has no backup section"
+		contextLogger.Warning(message)
+		r.Recorder.Event(&backup, "Warning", "ClusterHasNoBackupConfig", message)
+		backup.Status.SetAsFailed(errors.New(message))
+		return ctrl.Result{}, r.Status().Update(ctx, &backup)
+	}
+
 	// Detect the pod where a backup will be executed
-	var pod corev1.Pod
-	err := r.Get(ctx, client.ObjectKey{
-		Namespace: backup.Namespace,
-		Name:      cluster.Status.TargetPrimary,
-	}, &pod)
+	pod, err := r.getBackupTargetPod(ctx, &cluster)
 	if err != nil {
 		if apierrs.IsNotFound(err) {
 			r.Recorder.Eventf(&backup, "Warning", "FindingPod",
@@ -131,34 +137,34 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	}
 	contextLogger.Debug("Found pod for backup", "pod", pod.Name)
 
-	if !utils.IsPodReady(pod) {
+	if !utils.IsPodReady(*pod) {
 		contextLogger.Info("Not ready backup target, will retry in 30 seconds", "target", pod.Name)
 		backup.Status.Phase = apiv1.BackupPhasePending
 		r.Recorder.Eventf(&backup, "Warning", "BackupPending", "Backup target pod not ready: %s",
-			cluster.Status.TargetPrimary)
+			pod.Name)
 		return ctrl.Result{RequeueAfter: 30 * time.Second}, r.Status().Update(ctx, &backup)
 	}
 
 	if backup.Status.Phase != "" && backup.Status.InstanceID != nil {
 		// Detect the pod where a backup will be executed
-		var pod corev1.Pod
+		var runningPod corev1.Pod
 		err = r.Get(ctx, client.ObjectKey{
 			Namespace: backup.Namespace,
 			Name:      backup.Status.InstanceID.PodName,
-		}, &pod)
+		}, &runningPod)
 		// we found the pod
 		if err == nil &&
-			// the pod is actually the target primary,
-			// we don't care whether it's the current one as running the backup on the new primary would
+			// the pod is actually the selected target,
+			// we don't care whether it's the current primary as running the backup on the new primary would
 			// still be the correct thing to do
-			backup.Status.InstanceID.PodName == cluster.Status.TargetPrimary &&
+			backup.Status.InstanceID.PodName == pod.Name &&
 			// the pod was not restarted since when we started the backup
-			backup.Status.InstanceID.ContainerID == pod.Status.ContainerStatuses[0].ContainerID &&
+			backup.Status.InstanceID.ContainerID == runningPod.Status.ContainerStatuses[0].ContainerID &&
 			// the pod is active
-			utils.IsPodActive(pod) {
+			utils.IsPodActive(runningPod) {
 			contextLogger.Info("Backup is already running on",
 				"cluster", cluster.Name,
-				"pod", pod.Name,
+				"pod", runningPod.Name,
 				"started at", backup.Status.StartedAt)
 
 			// Nothing to do here
@@ -177,7 +183,7 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 		"pod", pod.Name)
 
 	// This backup has been started
-	err = StartBackup(ctx, r.Client, &backup, pod, &cluster)
+	err = StartBackup(ctx, r.Client, &backup, *pod, &cluster)
 	if err != nil {
 		r.Recorder.Eventf(&backup, "Warning", "Error", "Backup exit with error %v", err)
 	}
@@ -185,6 +191,60 @@ func (r *BackupReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctr
 	return ctrl.Result{}, err
 }
 
+// getBackupTargetPod returns the pod where the backup will be executed, which
+// is a ready standby instance if available and preferred by the backup
+// configuration of the cluster, and the target primary otherwise
+func (r *BackupReconciler) getBackupTargetPod(ctx context.Context, cluster *apiv1.Cluster) (*corev1.Pod, error) {
+	if cluster.GetBackupTarget() == apiv1.BackupTargetStandby {
+		var pods corev1.PodList
+		if err := r.List(
+			ctx,
+			&pods,
+			client.InNamespace(cluster.Namespace),
+			client.MatchingLabels{
+				utils.ClusterLabelName:     cluster.Name,
+				specs.ClusterRoleLabelName: specs.ClusterRoleLabelReplica,
+			},
+		); err != nil {
+			return nil, err
+		}
+
+		if standby := selectBackupStandby(cluster, pods.Items); standby != nil {
+			return standby, nil
+		}
+	}
+
+	var pod corev1.Pod
+	err := r.Get(ctx, client.ObjectKey{
+		Namespace: cluster.Namespace,
+		Name:      cluster.Status.TargetPrimary,
+	}, &pod)
+	if err != nil {
+		return nil, err
+	}
+	return &pod, nil
+}
+
+// selectBackupStandby chooses the ready standby instance where the backup
+// should be taken, returning nil if none is available
+func selectBackupStandby(cluster *apiv1.Cluster, pods []corev1.Pod) *corev1.Pod {
+	sort.Slice(pods, func(i, j int) bool {
+		return pods[i].Name < pods[j].Name
+	})
+
+	for idx := range pods {
+		pod := &pods[idx]
+		if pod.Name == cluster.Status.CurrentPrimary || pod.Name == cluster.Status.TargetPrimary {
+			continue
+		}
+		if utils.IsPodReady(*pod) && utils.IsPodActive(*pod) {
+			return pod
+		}
+	}
+
+	return nil
+}
+
 // StartBackup request a backup in a Pod and marks the backup started
 // or failed if needed
 func StartBackup(
@@ -0,0 +1,75 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backup target selection", func() {
+	cluster := &apiv1.Cluster{
+		Status: apiv1.ClusterStatus{
+			CurrentPrimary: "cluster-example-1",
+			TargetPrimary:  "cluster-example-1",
+		},
+	}
+
+	newPod := func(name string, ready bool) corev1.Pod {
+		readyStatus := corev1.ConditionFalse
+		if ready {
+			readyStatus = corev1.ConditionTrue
+		}
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodRunning,
+				Conditions: []corev1.PodCondition{
+					{Type: corev1.ContainersReady, Status: readyStatus},
+				},
+			},
+		}
+	}
+
+	It("selects the first ready standby", func() {
+		pods := []corev1.Pod{
+			newPod("cluster-example-3", true),
+			newPod("cluster-example-2", false),
+			newPod("cluster-example-1", true),
+		}
+		pod := selectBackupStandby(cluster, pods)
+		Expect(pod).ToNot(BeNil())
+		Expect(pod.Name).To(Equal("cluster-example-3"))
+	})
+
+	It("returns nil when no standby is ready", func() {
+		pods := []corev1.Pod{
+			newPod("cluster-example-1", true),
+			newPod("cluster-example-2", false),
+		}
+		Expect(selectBackupStandby(cluster, pods)).To(BeNil())
+	})
+
+	It("defaults to prefer the standby instances", func() {
+		Expect(cluster.GetBackupTarget()).To(Equal(apiv1.BackupTargetStandby))
+	})
+})
@@ -29,6 +29,7 @@ import (
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
@@ -111,6 +112,7 @@ var ErrNextLoop = errors.New("stop this loop and return the associated Result ob
 // +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;delete;patch;create;watch
 // +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update
 // +kubebuilder:rbac:groups=monitoring.coreos.com,resources=podmonitors,verbs=get;create;list;watch;delete;patch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=create;delete;get;list;watch;patch
 // +kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=create;delete;get;list;watch;update;patch
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=postgresql.cnpg.io,resources=clusters/finalizers,verbs=update
@@ -160,8 +162,15 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 	// Run the inner reconcile loop. Translate any ErrNextLoop to an errorless return
 	result, err := r.reconcile(ctx, cluster)
 	if errors.Is(err, ErrNextLoop) {
-		return result, nil
+		err = nil
 	}
+
+	// Record the outcome of the loop, unless the user asked
+	// the operator not to manage this cluster
+	if !utils.IsReconciliationDisabled(&cluster.ObjectMeta) {
+		r.updateReconcileStatus(ctx, cluster, err)
+	}
+
 	return result, err
 }
 
@@ -170,7 +179,15 @@ func (r *ClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ct
 func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluster) (ctrl.Result, error) {
 	contextLogger := log.FromContext(ctx)
 
-	if utils.IsReconciliationDisabled(&cluster.ObjectMeta) {
+	reconciliationDisabled := utils.IsReconciliationDisabled(&cluster.ObjectMeta)
+	if err := r.updateReconciliationDisabledCondition(ctx, cluster, reconciliationDisabled); err != nil {
+		if apierrs.IsConflict(err) {
+			return ctrl.Result{Requeue: true}, nil
+		}
+		return ctrl.Result{}, fmt.Errorf("cannot update the reconciliation disabled condition: %w", err)
+	}
+
+	if reconciliationDisabled {
 		contextLogger.Warning("Disable reconciliation loop annotation set, skipping the reconciliation.")
 		return ctrl.Result{}, nil
 	}
@@ -234,6 +251,19 @@ func (r *ClusterReconciler) reconcile(ctx context.Context, cluster *apiv1.Cluste
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, nil
 	}
 
+	// The instances of a cluster being hibernated must neither be
+	// recreated nor failed over
+	if result, err := r.reconcileHibernation(ctx, cluster, resources); result != nil || err != nil {
+		if err != nil {
+			if apierrs.IsConflict(err) {
+				contextLogger.Debug("Conflict error while reconciling hibernation", "error", err)
+				return ctrl.Result{Requeue: true}, nil
+			}
+			return ctrl.Result{}, fmt.Errorf("cannot reconcile hibernation: %w", err)
+		}
+		return *result, nil
+	}
+
 	// Get the replication status
 	instancesStatus := r.getStatusFromInstances(ctx, resources.instances)
 
@@ -345,6 +375,17 @@ func (r *ClusterReconciler) handleSwitchover(
 		return nil, nil
 	}
 
+	// Honor the switchover requested by the user with the annotation
+	requestedPrimary, err := r.reconcileTargetPrimaryAnnotation(ctx, cluster, instancesStatus)
+	if err != nil {
+		return nil, err
+	}
+	if requestedPrimary != "" {
+		contextLogger.Info("Waiting for the requested primary to notice the promotion request",
+			"newPrimary", requestedPrimary)
+		return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+
 	// Update the target primary name from the Pods status.
 	// This means issuing a failover or switchover when needed.
 	selectedPrimary, err := r.updateTargetPrimaryFromPods(ctx, cluster, instancesStatus, resources)
@@ -461,6 +502,10 @@ func (r *ClusterReconciler) reconcileResources(
 		return ctrl.Result{}, fmt.Errorf("cannot update annotations on pvcs: %w", err)
 	}
 
+	if exceeded, err := r.registerExceededBootstrapTimeout(ctx, cluster, resources); exceeded || err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Act on Pods and PVCs only if there is nothing that is currently being created or deleted
 	if runningJobs := resources.countRunningJobs(); runningJobs > 0 {
 		contextLogger.Debug("A job is currently running. Waiting", "count", runningJobs)
@@ -491,6 +536,16 @@ func (r *ClusterReconciler) reconcileResources(
 		return ctrl.Result{}, err
 	}
 
+	// Once the first primary is running, we may need to wait for the first
+	// WAL file to be archived before creating the replicas and declaring
+	// the cluster healthy
+	if isBootstrapPhase(cluster.Status.Phase) && cluster.ShouldWaitForFirstArchive() &&
+		instancesStatus.IsPodReporting(cluster.Status.CurrentPrimary) {
+		contextLogger.Info("Waiting for the first WAL file to be archived")
+		return ctrl.Result{RequeueAfter: 5 * time.Second},
+			r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForFirstArchive, "")
+	}
+
 	// Reconcile Pods
 	if res, err := r.ReconcilePods(ctx, cluster, resources, instancesStatus); err != nil {
 		return res, err
@@ -506,6 +561,12 @@ func (r *ClusterReconciler) reconcileResources(
 		return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
 	}
 
+	// The cluster is not ready until every replica is streaming from the primary
+	if !instancesStatus.AreReplicasStreaming(cluster.Status.CurrentPrimary) {
+		contextLogger.Debug("Waiting for the replicas to start streaming")
+		return ctrl.Result{RequeueAfter: 1 * time.Second}, ErrNextLoop
+	}
+
 	// When everything is reconciled, update the status
 	if err = r.RegisterPhase(ctx, cluster, apiv1.PhaseHealthy, ""); err != nil {
 		return ctrl.Result{}, err
@@ -516,6 +577,38 @@ func (r *ClusterReconciler) reconcileResources(
 	return ctrl.Result{}, nil
 }
 
+// isBootstrapPhase checks whether the passed phase is one of the phases
+// the cluster passes through while setting up its first primary. Joining
+// replicas isn't included, as it happens after every scale up
+func isBootstrapPhase(phase string) bool {
+	switch phase {
+	case apiv1.PhaseFirstPrimary, apiv1.PhaseWaitingForFirstArchive:
+		return true
+	}
+	return false
+}
+
+// registerExceededBootstrapTimeout moves the cluster to the unrecoverable
+// phase when one of its jobs exceeded the bootstrap timeout, as the job
+// won't be retried. It returns true when this happens
+func (r *ClusterReconciler) registerExceededBootstrapTimeout(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (bool, error) {
+	for _, job := range resources.jobs.Items {
+		if !utils.IsJobDeadlineExceeded(job) {
+			continue
+		}
+		log.FromContext(ctx).Info("A job exceeded the bootstrap timeout", "job", job.Name,
+			"bootstrapTimeout", cluster.Spec.BootstrapTimeout)
+		return true, r.RegisterPhase(ctx, cluster, apiv1.PhaseUnrecoverable,
+			fmt.Sprintf("Job %s exceeded the bootstrap timeout", job.Name))
+	}
+
+	return false, nil
+}
+
 // deleteEvictedPods will delete the Pods that the Kubelet has evicted
 func (r *ClusterReconciler) deleteEvictedPods(ctx context.Context, cluster *apiv1.Cluster,
 	resources *managedResources,
@@ -757,6 +850,15 @@ func (r *ClusterReconciler) handleRollingUpdate(
 ) (ctrl.Result, error) {
 	contextLogger := log.FromContext(ctx)
 
+	// Take the backup requested before upgrading the instances to a new image
+	upgradeBackupResult, err := r.reconcileUpgradeBackup(ctx, cluster, instancesStatus)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if upgradeBackupResult != nil {
+		return *upgradeBackupResult, ErrNextLoop
+	}
+
 	// If we need to roll out a restart of any instance, this is the right moment
 	// Do I have to roll out a new image?
 	done, err := r.rolloutDueToCondition(ctx, cluster, &instancesStatus, IsPodNeedingRollout)
@@ -792,6 +894,10 @@ func (r *ClusterReconciler) handleRollingUpdate(
 		}
 	}
 
+	if err := r.updateLastCompletedRestart(ctx, cluster, instancesStatus); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	return ctrl.Result{}, nil
 }
 
@@ -809,6 +915,7 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 		Owns(&corev1.Service{}).
 		Owns(&corev1.PersistentVolumeClaim{}).
 		Owns(&policyv1.PodDisruptionBudget{}).
+		Owns(&networkingv1.NetworkPolicy{}).
 		Watches(
 			&source.Kind{Type: &corev1.ConfigMap{}},
 			handler.EnqueueRequestsFromMapFunc(r.mapConfigMapsToClusters(ctx)),
@@ -17,9 +17,15 @@ limitations under the License.
 package controllers
 
 import (
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	controllerScheme "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -56,3 +62,69 @@ var _ = Describe("Filtering cluster", func() {
 		Expect(req).ToNot(BeNil())
 	})
 })
+
+var _ = Describe("Bootstrap phases", func() {
+	It("recognizes the phases setting up the first primary", func() {
+		Expect(isBootstrapPhase(apiv1.PhaseFirstPrimary)).To(BeTrue())
+		Expect(isBootstrapPhase(apiv1.PhaseCreatingReplica)).To(BeFalse())
+		Expect(isBootstrapPhase(apiv1.PhaseWaitingForFirstArchive)).To(BeTrue())
+		Expect(isBootstrapPhase(apiv1.PhaseHealthy)).To(BeFalse())
+		Expect(isBootstrapPhase(apiv1.PhaseSwitchover)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Bootstrap timeout", func() {
+	var (
+		cluster    *apiv1.Cluster
+		reconciler *ClusterReconciler
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec:       apiv1.ClusterSpec{BootstrapTimeout: 7200},
+		}
+		reconciler = &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(controllerScheme.BuildWithAllKnownScheme()).
+				WithObjects(cluster).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	newJob := func(reason string) batchv1.Job {
+		return batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1-full-recovery", Namespace: "default"},
+			Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{
+					{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: reason},
+				},
+			},
+		}
+	}
+
+	It("moves the cluster to the unrecoverable phase when a job exceeded its deadline", func(ctx SpecContext) {
+		resources := &managedResources{
+			jobs: batchv1.JobList{Items: []batchv1.Job{newJob("DeadlineExceeded")}},
+		}
+		exceeded, err := reconciler.registerExceededBootstrapTimeout(ctx, cluster, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exceeded).To(BeTrue())
+
+		var storedCluster apiv1.Cluster
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(cluster), &storedCluster)).To(Succeed())
+		Expect(storedCluster.Status.Phase).To(Equal(apiv1.PhaseUnrecoverable))
+		Expect(storedCluster.Status.PhaseReason).To(ContainSubstring("cluster-example-1-full-recovery"))
+	})
+
+	It("ignores the jobs failed for other reasons", func(ctx SpecContext) {
+		resources := &managedResources{
+			jobs: batchv1.JobList{Items: []batchv1.Job{newJob("BackoffLimitExceeded")}},
+		}
+		exceeded, err := reconciler.registerExceededBootstrapTimeout(ctx, cluster, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(exceeded).To(BeFalse())
+		Expect(cluster.Status.Phase).To(BeEmpty())
+	})
+})
@@ -20,12 +20,14 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"time"
 
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	"github.com/sethvargo/go-password/password"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
 	policyv1 "k8s.io/api/policy/v1"
 	rbacv1 "k8s.io/api/rbac/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
@@ -41,6 +43,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/resources"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs/pgbouncer"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 )
@@ -67,6 +70,11 @@ func (r *ClusterReconciler) createPostgresClusterObjects(ctx context.Context, cl
 		return err
 	}
 
+	err = r.reconcileNetworkPolicy(ctx, cluster)
+	if err != nil {
+		return err
+	}
+
 	err = r.createOrPatchServiceAccount(ctx, cluster)
 	if err != nil {
 		return err
@@ -104,6 +112,15 @@ func (r *ClusterReconciler) createPostgresClusterObjects(ctx context.Context, cl
 }
 
 func (r *ClusterReconciler) reconcilePodDisruptionBudget(ctx context.Context, cluster *apiv1.Cluster) error {
+	// The user asked us to not manage any PDB, so let's remove
+	// the ones we may have created before
+	if !cluster.IsPodDisruptionBudgetEnabled() {
+		if err := r.deleteReplicasPodDisruptionBudget(ctx, cluster); err != nil {
+			return err
+		}
+		return r.deletePrimaryPodDisruptionBudget(ctx, cluster)
+	}
+
 	// The PDB should not be enforced if we are inside a maintenance
 	// window, and we chose to avoid allocating more storage space.
 	if cluster.IsNodeMaintenanceWindowInProgress() && cluster.IsReusePVCEnabled() {
@@ -280,33 +297,85 @@ func (r *ClusterReconciler) createPostgresServices(ctx context.Context, cluster
 		}
 	}
 
-	readService := specs.CreateClusterReadService(*cluster)
-	SetClusterOwnerAnnotationsAndLabels(&readService.ObjectMeta, cluster)
-
-	if err := resources.CreateIfNotFound(ctx, r.Client, readService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
+	// The other services can be customized by the user, so we need
+	// to keep them aligned with the cluster specification
+	managedServices := cluster.GetManagedServices()
+	for _, item := range []struct {
+		service  *corev1.Service
+		template *apiv1.ServiceTemplate
+	}{
+		{specs.CreateClusterReadService(*cluster), managedServices.Read},
+		{specs.CreateClusterReadOnlyService(*cluster), managedServices.ReadOnly},
+		{specs.CreateClusterReadWriteService(*cluster), managedServices.ReadWrite},
+	} {
+		SetClusterOwnerAnnotationsAndLabels(&item.service.ObjectMeta, cluster)
+		if err := r.createOrPatchService(ctx, cluster, item.service, item.template); err != nil {
 			return err
 		}
 	}
 
-	readOnlyService := specs.CreateClusterReadOnlyService(*cluster)
-	SetClusterOwnerAnnotationsAndLabels(&readOnlyService.ObjectMeta, cluster)
+	return nil
+}
 
-	if err := resources.CreateIfNotFound(ctx, r.Client, readOnlyService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
+// createOrPatchService ensures that a service generated by the operator
+// exists and has the required labels, annotations and selector. The selector
+// is always enforced, so that the traffic never reaches the wrong instances.
+// The service type is only enforced when requested by the user with the
+// service template, so that any other change made to the service is left
+// in place
+func (r *ClusterReconciler) createOrPatchService(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	proposed *corev1.Service,
+	template *apiv1.ServiceTemplate,
+) error {
+	var livingService corev1.Service
+	err := r.Get(ctx, client.ObjectKeyFromObject(proposed), &livingService)
+	if apierrs.IsNotFound(err) {
+		if err := resources.CreateIfNotFound(ctx, r.Client, proposed); err != nil && !apierrs.IsAlreadyExists(err) {
 			return err
 		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("while getting service %s: %w", proposed.Name, err)
 	}
 
-	readWriteService := specs.CreateClusterReadWriteService(*cluster)
-	SetClusterOwnerAnnotationsAndLabels(&readWriteService.ObjectMeta, cluster)
+	patchedService := livingService.DeepCopy()
+	if patchedService.Labels == nil && len(proposed.Labels) > 0 {
+		patchedService.Labels = map[string]string{}
+	}
+	if patchedService.Annotations == nil && len(proposed.Annotations) > 0 {
+		patchedService.Annotations = map[string]string{}
+	}
+	utils.MergeMap(patchedService.Labels, proposed.Labels)
+	utils.MergeMap(patchedService.Annotations, proposed.Annotations)
 
-	if err := resources.CreateIfNotFound(ctx, r.Client, readWriteService); err != nil {
-		if !apierrs.IsAlreadyExists(err) {
-			return err
+	if !reflect.DeepEqual(patchedService.Spec.Selector, proposed.Spec.Selector) {
+		patchedService.Spec.Selector = proposed.Spec.Selector
+	}
+
+	if template != nil && template.Type != "" && patchedService.Spec.Type != proposed.Spec.Type {
+		patchedService.Spec.Type = proposed.Spec.Type
+		// Node ports are only allowed for NodePort and LoadBalancer services
+		if proposed.Spec.Type == corev1.ServiceTypeClusterIP {
+			for idx := range patchedService.Spec.Ports {
+				patchedService.Spec.Ports[idx].NodePort = 0
+			}
 		}
 	}
 
+	if reflect.DeepEqual(livingService.ObjectMeta, patchedService.ObjectMeta) &&
+		reflect.DeepEqual(livingService.Spec, patchedService.Spec) {
+		return nil
+	}
+
+	r.Recorder.Event(cluster, "Normal", "UpdatingService",
+		fmt.Sprintf("Updating service %s", proposed.Name))
+	if err := r.Patch(ctx, patchedService, client.MergeFrom(&livingService)); err != nil {
+		return fmt.Errorf("while patching service %s: %w", proposed.Name, err)
+	}
+
 	return nil
 }
 
@@ -398,6 +467,69 @@ func (r *ClusterReconciler) deletePodDisruptionBudget(
 	return nil
 }
 
+// reconcileNetworkPolicy creates, updates or deletes the NetworkPolicy
+// restricting the access to the instances, as requested by the user
+func (r *ClusterReconciler) reconcileNetworkPolicy(ctx context.Context, cluster *apiv1.Cluster) error {
+	var poolers apiv1.PoolerList
+	if cluster.IsNetworkPolicyEnabled() {
+		err := r.List(ctx, &poolers,
+			client.InNamespace(cluster.Namespace),
+			client.MatchingFields{poolerClusterKey: cluster.Name})
+		if err != nil {
+			return fmt.Errorf("while getting poolers for cluster %s: %w", cluster.Name, err)
+		}
+	}
+
+	networkPolicy := specs.BuildNetworkPolicy(
+		cluster,
+		pgbouncer.PodSelector(poolers.Items),
+		configuration.Current.OperatorNamespace)
+
+	var oldNetworkPolicy networkingv1.NetworkPolicy
+	err := r.Get(ctx, client.ObjectKey{Name: cluster.Name, Namespace: cluster.Namespace}, &oldNetworkPolicy)
+	if err != nil && !apierrs.IsNotFound(err) {
+		return fmt.Errorf("while getting NetworkPolicy: %w", err)
+	}
+	exists := err == nil
+
+	switch {
+	case networkPolicy == nil && !exists:
+		return nil
+
+	case networkPolicy == nil:
+		r.Recorder.Event(cluster, "Normal", "DeletingNetworkPolicy",
+			fmt.Sprintf("Deleting NetworkPolicy %s", oldNetworkPolicy.Name))
+		if err := r.Delete(ctx, &oldNetworkPolicy); err != nil && !apierrs.IsNotFound(err) {
+			return fmt.Errorf("while deleting NetworkPolicy: %w", err)
+		}
+		return nil
+
+	case !exists:
+		SetClusterOwnerAnnotationsAndLabels(&networkPolicy.ObjectMeta, cluster)
+		r.Recorder.Event(cluster, "Normal", "CreatingNetworkPolicy",
+			fmt.Sprintf("Creating NetworkPolicy %s", networkPolicy.Name))
+		if err := r.Create(ctx, networkPolicy); err != nil {
+			return fmt.Errorf("while creating NetworkPolicy: %w", err)
+		}
+		return nil
+	}
+
+	if reflect.DeepEqual(networkPolicy.Spec, oldNetworkPolicy.Spec) {
+		return nil
+	}
+
+	r.Recorder.Event(cluster, "Normal", "UpdatingNetworkPolicy",
+		fmt.Sprintf("Updating NetworkPolicy %s", networkPolicy.Name))
+
+	patchedNetworkPolicy := oldNetworkPolicy
+	patchedNetworkPolicy.Spec = networkPolicy.Spec
+	if err := r.Patch(ctx, &patchedNetworkPolicy, client.MergeFrom(&oldNetworkPolicy)); err != nil {
+		return fmt.Errorf("while patching NetworkPolicy: %w", err)
+	}
+
+	return nil
+}
+
 // createOrPatchServiceAccount creates or synchronizes the ServiceAccount used by the
 // cluster with the latest cluster specification
 func (r *ClusterReconciler) createOrPatchServiceAccount(ctx context.Context, cluster *apiv1.Cluster) error {
@@ -851,9 +983,23 @@ func (r *ClusterReconciler) createRoleBinding(ctx context.Context, cluster *apiv
 	return nil
 }
 
-// generateNodeSerial extracts the first free node serial in this pods
+// generateNodeSerial extracts the first free node serial in this pods,
+// skipping the serials whose PVCs have been released by a previous
+// cluster with the same name
 func (r *ClusterReconciler) generateNodeSerial(ctx context.Context, cluster *apiv1.Cluster) (int, error) {
-	cluster.Status.LatestGeneratedNode++
+	for {
+		cluster.Status.LatestGeneratedNode++
+		released, err := r.hasReleasedPVCs(ctx, cluster, cluster.Status.LatestGeneratedNode)
+		if err != nil {
+			return 0, err
+		}
+		if !released {
+			break
+		}
+		log.FromContext(ctx).Info("skipping the node serial used by a released PVC",
+			"nodeSerial", cluster.Status.LatestGeneratedNode)
+	}
+
 	if err := r.Status().Update(ctx, cluster); err != nil {
 		return 0, err
 	}
@@ -861,6 +1007,73 @@ func (r *ClusterReconciler) generateNodeSerial(ctx context.Context, cluster *api
 	return cluster.Status.LatestGeneratedNode, nil
 }
 
+// hasReleasedPVCs checks if the PVCs of the instance with the passed
+// serial already exist without belonging to the cluster, as they have
+// been released according to the dangling PVC policy
+func (r *ClusterReconciler) hasReleasedPVCs(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	nodeSerial int,
+) (bool, error) {
+	instanceName := specs.GetInstanceName(cluster.Name, nodeSerial)
+	for _, role := range []utils.PVCRole{utils.PVCRolePgData, utils.PVCRolePgWal} {
+		var pvc corev1.PersistentVolumeClaim
+		err := r.Get(ctx, types.NamespacedName{
+			Name:      specs.GetPVCName(*cluster, instanceName, role),
+			Namespace: cluster.Namespace,
+		}, &pvc)
+		if apierrs.IsNotFound(err) {
+			continue
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if pvc.Labels[utils.ClusterLabelName] != cluster.Name {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// getMissingPostInitApplicationSQLRefs gets the list of the
+// secrets and config maps keys referenced by the post-init application
+// SQL refs that are not available in the cluster namespace
+func (r *ClusterReconciler) getMissingPostInitApplicationSQLRefs(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+) ([]string, error) {
+	refs := cluster.Spec.Bootstrap.InitDB.PostInitApplicationSQLRefs
+	var missingRefs []string
+
+	for _, ref := range refs.SecretRefs {
+		var secret corev1.Secret
+		err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: ref.Name}, &secret)
+		if err != nil && !apierrs.IsNotFound(err) {
+			return nil, err
+		}
+		if _, ok := secret.Data[ref.Key]; !ok {
+			missingRefs = append(missingRefs, fmt.Sprintf("secret %s/%s", ref.Name, ref.Key))
+		}
+	}
+
+	for _, ref := range refs.ConfigMapRefs {
+		var configMap corev1.ConfigMap
+		err := r.Get(ctx, client.ObjectKey{Namespace: cluster.Namespace, Name: ref.Name}, &configMap)
+		if err != nil && !apierrs.IsNotFound(err) {
+			return nil, err
+		}
+		_, inData := configMap.Data[ref.Key]
+		_, inBinaryData := configMap.BinaryData[ref.Key]
+		if !inData && !inBinaryData {
+			missingRefs = append(missingRefs, fmt.Sprintf("configmap %s/%s", ref.Name, ref.Key))
+		}
+	}
+
+	return missingRefs, nil
+}
+
 func (r *ClusterReconciler) createPrimaryInstance(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -887,6 +1100,25 @@ func (r *ClusterReconciler) createPrimaryInstance(
 		return ctrl.Result{}, nil
 	}
 
+	// The initdb job would be stuck waiting for the volumes holding
+	// the post-init application SQL refs, so we check them in advance
+	if cluster.ShouldInitDBRunPostInitApplicationSQLRefs() {
+		missingRefs, err := r.getMissingPostInitApplicationSQLRefs(ctx, cluster)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if len(missingRefs) > 0 {
+			contextLogger.Info("Missing post-init application SQL references, can't continue initdb",
+				"missingRefs", missingRefs)
+			r.Recorder.Eventf(cluster, "Warning", "MissingPostInitApplicationSQLRefs",
+				"Missing post-init application SQL references: %v", strings.Join(missingRefs, ", "))
+			return ctrl.Result{
+				Requeue:      true,
+				RequeueAfter: time.Minute,
+			}, nil
+		}
+	}
+
 	// Generate a new node serial
 	nodeSerial, err := r.generateNodeSerial(ctx, cluster)
 	if err != nil {
@@ -967,13 +1199,9 @@ func (r *ClusterReconciler) createPrimaryInstance(
 		"name", job.Name,
 		"primary", true)
 
-	utils.SetOperatorVersion(&job.ObjectMeta, versions.Version)
-	utils.InheritAnnotations(&job.ObjectMeta, cluster.Annotations,
-		cluster.GetFixedInheritedAnnotations(), configuration.Current)
+	setInheritedMetadata(&job.ObjectMeta, cluster)
 	utils.InheritAnnotations(&job.Spec.Template.ObjectMeta, cluster.Annotations,
 		cluster.GetFixedInheritedAnnotations(), configuration.Current)
-	utils.InheritLabels(&job.ObjectMeta, cluster.Labels,
-		cluster.GetFixedInheritedLabels(), configuration.Current)
 	utils.InheritLabels(&job.Spec.Template.ObjectMeta, cluster.Labels,
 		cluster.GetFixedInheritedLabels(), configuration.Current)
 
@@ -990,6 +1218,17 @@ func (r *ClusterReconciler) createPrimaryInstance(
 	return ctrl.Result{RequeueAfter: 30 * time.Second}, ErrNextLoop
 }
 
+// setInheritedMetadata applies the annotations and labels inherited from the
+// cluster to an object created for an instance. The operator version is set
+// afterward, as it must not be overridden by the inherited metadata
+func setInheritedMetadata(obj *metav1.ObjectMeta, cluster *apiv1.Cluster) {
+	utils.InheritAnnotations(obj, cluster.Annotations,
+		cluster.GetFixedInheritedAnnotations(), configuration.Current)
+	utils.InheritLabels(obj, cluster.Labels,
+		cluster.GetFixedInheritedLabels(), configuration.Current)
+	utils.SetOperatorVersion(obj, versions.Version)
+}
+
 // getOriginBackup gets the backup that is used to bootstrap a new PostgreSQL cluster
 func (r *ClusterReconciler) getOriginBackup(ctx context.Context, cluster *apiv1.Cluster) (*apiv1.Backup, error) {
 	if cluster.Spec.Bootstrap == nil ||
@@ -1049,13 +1288,9 @@ func (r *ClusterReconciler) joinReplicaInstance(
 		return ctrl.Result{}, err
 	}
 
-	utils.SetOperatorVersion(&job.ObjectMeta, versions.Version)
-	utils.InheritAnnotations(&job.ObjectMeta, cluster.Annotations,
-		cluster.GetFixedInheritedAnnotations(), configuration.Current)
+	setInheritedMetadata(&job.ObjectMeta, cluster)
 	utils.InheritAnnotations(&job.Spec.Template.ObjectMeta, cluster.Annotations,
 		cluster.GetFixedInheritedAnnotations(), configuration.Current)
-	utils.InheritLabels(&job.ObjectMeta, cluster.Labels,
-		cluster.GetFixedInheritedLabels(), configuration.Current)
 	utils.InheritLabels(&job.Spec.Template.ObjectMeta, cluster.Labels,
 		cluster.GetFixedInheritedLabels(), configuration.Current)
 
@@ -1122,7 +1357,11 @@ func (r *ClusterReconciler) reconcilePVCs(
 	}
 
 	if len(cluster.Status.DanglingPVC) > 0 {
-		if (cluster.IsNodeMaintenanceWindowInProgress() && !cluster.IsReusePVCEnabled()) ||
+		// When there are no instances left, the dangling PVCs hold
+		// the only copy of the data, and we must never drop them,
+		// not even in a maintenance window without PVC reuse
+		if (cluster.IsNodeMaintenanceWindowInProgress() && !cluster.IsReusePVCEnabled() &&
+			cluster.Status.Instances > 0) ||
 			cluster.Spec.Instances <= cluster.Status.Instances {
 			contextLogger.Info(
 				"Detected unneeded PVCs, removing them",
@@ -1185,11 +1424,7 @@ func (r *ClusterReconciler) reconcilePVCs(
 		return ctrl.Result{}, fmt.Errorf("unable to set the owner reference for the Pod: %w", err)
 	}
 
-	utils.SetOperatorVersion(&pod.ObjectMeta, versions.Version)
-	utils.InheritAnnotations(&pod.ObjectMeta, cluster.Annotations,
-		cluster.GetFixedInheritedAnnotations(), configuration.Current)
-	utils.InheritLabels(&pod.ObjectMeta, cluster.Labels,
-		cluster.GetFixedInheritedLabels(), configuration.Current)
+	setInheritedMetadata(&pod.ObjectMeta, cluster)
 
 	if err := r.Create(ctx, pod); err != nil {
 		if apierrs.IsAlreadyExists(err) {
@@ -1226,8 +1461,10 @@ func electPvcToReattach(cluster *apiv1.Cluster) string {
 	return pvcs[0]
 }
 
-// removeDanglingPVCs will remove dangling PVCs
+// removeDanglingPVCs will remove dangling PVCs, deleting or releasing
+// them according to the policy of the cluster
 func (r *ClusterReconciler) removeDanglingPVCs(ctx context.Context, cluster *apiv1.Cluster) error {
+	policy := cluster.GetDanglingPVCPolicy()
 	for _, pvcName := range cluster.Status.DanglingPVC {
 		var pvc corev1.PersistentVolumeClaim
 
@@ -1235,16 +1472,29 @@ func (r *ClusterReconciler) removeDanglingPVCs(ctx context.Context, cluster *api
 		if err != nil {
 			// Ignore if NotFound, otherwise report the error
 			if apierrs.IsNotFound(err) {
-				return nil
+				continue
 			}
-			return fmt.Errorf("removing unneeded PVC %v: %v", pvc.Name, err)
+			return fmt.Errorf("removing unneeded PVC %v: %v", pvcName, err)
+		}
+
+		if policy == apiv1.DanglingPVCPolicyRetain {
+			origPVC := pvc.DeepCopy()
+			releasePVC(&pvc, cluster)
+			if err := r.Patch(ctx, &pvc, client.MergeFrom(origPVC)); err != nil {
+				if apierrs.IsNotFound(err) {
+					continue
+				}
+				return fmt.Errorf("releasing unneeded PVC %v: %v", pvc.Name, err)
+			}
+			r.Recorder.Eventf(cluster, "Normal", "ReleasedPVC", "Released unneeded PVC %v", pvc.Name)
+			continue
 		}
 
 		err = r.Delete(ctx, &pvc)
 		if err != nil {
 			// Ignore if NotFound, otherwise report the error
 			if apierrs.IsNotFound(err) {
-				return nil
+				continue
 			}
 			return fmt.Errorf("removing unneeded PVC %v: %v", pvc.Name, err)
 		}
@@ -1253,6 +1503,22 @@ func (r *ClusterReconciler) removeDanglingPVCs(ctx context.Context, cluster *api
 	return nil
 }
 
+// releasePVC detaches a PVC from the cluster, removing the owner
+// reference and the cluster label, so that the operator will neither
+// manage it anymore nor adopt it again when the cluster is recreated
+func releasePVC(pvc *corev1.PersistentVolumeClaim, cluster *apiv1.Cluster) {
+	ownerReferences := make([]metav1.OwnerReference, 0, len(pvc.OwnerReferences))
+	for _, ownerReference := range pvc.OwnerReferences {
+		if ownerReference.UID == cluster.UID {
+			continue
+		}
+		ownerReferences = append(ownerReferences, ownerReference)
+	}
+	pvc.OwnerReferences = ownerReferences
+
+	delete(pvc.Labels, utils.ClusterLabelName)
+}
+
 func (r *ClusterReconciler) createPVC(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -19,12 +19,22 @@ package controllers
 import (
 	"context"
 
+	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	policyv1 "k8s.io/api/policy/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	controllerScheme "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -101,6 +111,74 @@ var _ = Describe("cluster_create unit tests", func() {
 		})
 	})
 
+	It("should apply the services customizations to existing services", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+
+		By("creating the services without customizations", func() {
+			err := clusterReconciler.createPostgresServices(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("customizing the read-write service", func() {
+			cluster.Spec.Managed = &apiv1.ManagedConfiguration{
+				Services: &apiv1.ManagedServices{
+					ReadWrite: &apiv1.ServiceTemplate{
+						Metadata: apiv1.Metadata{
+							Annotations: map[string]string{"test": "annotation"},
+						},
+						Type: corev1.ServiceTypeLoadBalancer,
+					},
+				},
+			}
+			err := clusterReconciler.createPostgresServices(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("making sure that the read-write service has been updated", func() {
+			service := &corev1.Service{}
+			expectResourceExistsWithDefaultClient(cluster.GetServiceReadWriteName(), namespace, service)
+			Expect(service.Annotations).To(HaveKeyWithValue("test", "annotation"))
+			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+		})
+
+		By("making sure that the read-only service is unchanged", func() {
+			service := &corev1.Service{}
+			expectResourceExistsWithDefaultClient(cluster.GetServiceReadOnlyName(), namespace, service)
+			Expect(service.Annotations).ToNot(HaveKey("test"))
+			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+		})
+	})
+
+	It("should restore the selector of the read-only service", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+
+		By("creating the services", func() {
+			err := clusterReconciler.createPostgresServices(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("selecting every instance in the read-only service", func() {
+			service := &corev1.Service{}
+			expectResourceExistsWithDefaultClient(cluster.GetServiceReadOnlyName(), namespace, service)
+			service.Spec.Selector = map[string]string{specs.ClusterLabelName: cluster.Name}
+			Expect(k8sClient.Update(ctx, service)).To(Succeed())
+		})
+
+		By("making sure that the selector is restored", func() {
+			err := clusterReconciler.createPostgresServices(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+
+			service := &corev1.Service{}
+			expectResourceExistsWithDefaultClient(cluster.GetServiceReadOnlyName(), namespace, service)
+			Expect(service.Spec.Selector).To(
+				HaveKeyWithValue(specs.ClusterRoleLabelName, specs.ClusterRoleLabelReplica))
+		})
+	})
+
 	It("should make sure that createOrPatchServiceAccount works correctly", func() {
 		ctx := context.Background()
 		namespace := newFakeNamespace()
@@ -235,6 +313,33 @@ var _ = Describe("cluster_create unit tests", func() {
 			)
 		})
 	})
+
+	It("should delete the PDBs when they are disabled", func() {
+		ctx := context.Background()
+		namespace := newFakeNamespace()
+		cluster := newFakeCNPGCluster(namespace)
+		pdbReplicaName := specs.BuildReplicasPodDisruptionBudget(cluster).Name
+		pdbPrimaryName := specs.BuildPrimaryPodDisruptionBudget(cluster).Name
+
+		By("creating the primary and replica PDB", func() {
+			err := clusterReconciler.reconcilePodDisruptionBudget(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+			expectResourceExistsWithDefaultClient(pdbPrimaryName, namespace, &policyv1.PodDisruptionBudget{})
+			expectResourceExistsWithDefaultClient(pdbReplicaName, namespace, &policyv1.PodDisruptionBudget{})
+		})
+
+		By("disabling the PDBs", func() {
+			enablePDB := false
+			cluster.Spec.EnablePDB = &enablePDB
+			err := clusterReconciler.reconcilePodDisruptionBudget(ctx, cluster)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		By("making sure that both the replicas and main PDB are deleted", func() {
+			expectResourceDoesntExistWithDefaultClient(pdbPrimaryName, namespace, &policyv1.PodDisruptionBudget{})
+			expectResourceDoesntExistWithDefaultClient(pdbReplicaName, namespace, &policyv1.PodDisruptionBudget{})
+		})
+	})
 })
 
 var _ = Describe("Set cluster metadata of service account", func() {
@@ -273,3 +378,260 @@ var _ = Describe("Set cluster metadata of service account", func() {
 		Expect(sa.Labels).To(BeEquivalentTo(cluster.Spec.ServiceAccountTemplate.Metadata.Labels))
 	})
 })
+
+var _ = Describe("Releasing unneeded PVCs", func() {
+	It("removes the owner reference and the label of the cluster", func() {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", UID: "cluster-uid"},
+		}
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster-example-3",
+				Labels: map[string]string{
+					utils.ClusterLabelName: "cluster-example",
+					"example.com/team":     "dba",
+				},
+				Annotations: map[string]string{specs.ClusterSerialAnnotationName: "3"},
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: apiv1.ClusterKind, Name: "cluster-example", UID: "cluster-uid"},
+					{Kind: "ConfigMap", Name: "other", UID: "other-uid"},
+				},
+			},
+		}
+
+		releasePVC(pvc, cluster)
+		Expect(pvc.OwnerReferences).To(HaveLen(1))
+		Expect(pvc.OwnerReferences[0].UID).To(BeEquivalentTo("other-uid"))
+		Expect(pvc.Labels).To(Equal(map[string]string{"example.com/team": "dba"}))
+		Expect(pvc.Annotations).To(HaveKey(specs.ClusterSerialAnnotationName))
+	})
+
+	It("skips the node serials of the PVCs released by a previous cluster", func(ctx SpecContext) {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		releasedPVC := func(name string) *corev1.PersistentVolumeClaim {
+			return &corev1.PersistentVolumeClaim{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			}
+		}
+		reconciler := &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(controllerScheme.BuildWithAllKnownScheme()).
+				WithObjects(cluster, releasedPVC("cluster-example-1"), releasedPVC("cluster-example-2-wal")).
+				Build(),
+		}
+
+		serial, err := reconciler.generateNodeSerial(ctx, cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(serial).To(Equal(3))
+		Expect(cluster.Status.LatestGeneratedNode).To(Equal(3))
+	})
+
+	It("doesn't skip the node serials of the PVCs of the cluster", func(ctx SpecContext) {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example-1",
+				Namespace: "default",
+				Labels:    map[string]string{utils.ClusterLabelName: "cluster-example"},
+			},
+		}
+		reconciler := &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(controllerScheme.BuildWithAllKnownScheme()).
+				WithObjects(cluster, pvc).
+				Build(),
+		}
+
+		Expect(reconciler.generateNodeSerial(ctx, cluster)).To(Equal(1))
+	})
+})
+
+var _ = Describe("Inherited metadata of the instances", func() {
+	It("doesn't let the inherited metadata override the operator version", func(ctx SpecContext) {
+		cluster := &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances: 1,
+				InheritedMetadata: &apiv1.EmbeddedObjectMetadata{
+					Labels: map[string]string{"policy": "required"},
+					Annotations: map[string]string{
+						"owner":                             "team",
+						utils.OperatorVersionAnnotationName: "0.0.1",
+					},
+				},
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{},
+				},
+				StorageConfiguration: apiv1.StorageConfiguration{Size: "1Gi"},
+			},
+		}
+		scheme := controllerScheme.BuildWithAllKnownScheme()
+		reconciler := &ClusterReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build(),
+			Scheme:   scheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+
+		_, err := reconciler.createPrimaryInstance(ctx, cluster)
+		Expect(err).To(MatchError(ErrNextLoop))
+
+		var job batchv1.Job
+		Expect(reconciler.Get(ctx, client.ObjectKey{
+			Namespace: cluster.Namespace,
+			Name:      specs.CreatePrimaryJobViaInitdb(*cluster, 1).Name,
+		}, &job)).To(Succeed())
+		Expect(job.Labels).To(HaveKeyWithValue("policy", "required"))
+		Expect(job.Annotations).To(HaveKeyWithValue("owner", "team"))
+		Expect(job.Annotations).To(HaveKeyWithValue(utils.OperatorVersionAnnotationName, versions.Version))
+		Expect(job.Spec.Template.Labels).To(HaveKeyWithValue("policy", "required"))
+	})
+})
+
+var _ = Describe("Dangling PVCs during a node maintenance window", func() {
+	var (
+		cluster    *apiv1.Cluster
+		pvc        *corev1.PersistentVolumeClaim
+		reconciler *ClusterReconciler
+	)
+
+	BeforeEach(func() {
+		reusePVC := false
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances: 1,
+				NodeMaintenanceWindow: &apiv1.NodeMaintenanceWindow{
+					InProgress: true,
+					ReusePVC:   &reusePVC,
+				},
+				StorageConfiguration: apiv1.StorageConfiguration{Size: "1Gi"},
+			},
+			Status: apiv1.ClusterStatus{
+				DanglingPVC: []string{"cluster-example-1"},
+			},
+		}
+		pvc = &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example-1",
+				Namespace: "default",
+				Labels:    map[string]string{utils.ClusterLabelName: "cluster-example"},
+				Annotations: map[string]string{
+					specs.ClusterSerialAnnotationName: "1",
+					specs.PVCStatusAnnotationName:     specs.PVCStatusReady,
+				},
+			},
+		}
+		scheme := controllerScheme.BuildWithAllKnownScheme()
+		reconciler = &ClusterReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster, pvc).Build(),
+			Scheme:   scheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	It("keeps the PVC of the last instance and reattaches it", func(ctx SpecContext) {
+		resources := &managedResources{
+			pvcs: corev1.PersistentVolumeClaimList{Items: []corev1.PersistentVolumeClaim{*pvc}},
+		}
+		_, err := reconciler.reconcilePVCs(ctx, cluster, resources, postgres.PostgresqlStatusList{})
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(pvc), &corev1.PersistentVolumeClaim{})).To(Succeed())
+		Expect(reconciler.Get(ctx, client.ObjectKey{Namespace: "default", Name: "cluster-example-1"},
+			&corev1.Pod{})).To(Succeed())
+	})
+
+	It("removes the PVC when other instances are still running", func(ctx SpecContext) {
+		cluster.Spec.Instances = 3
+		cluster.Status.Instances = 2
+		resources := &managedResources{
+			pvcs: corev1.PersistentVolumeClaimList{Items: []corev1.PersistentVolumeClaim{*pvc}},
+		}
+		_, err := reconciler.reconcilePVCs(ctx, cluster, resources, postgres.PostgresqlStatusList{})
+		Expect(err).ToNot(HaveOccurred())
+
+		err = reconciler.Get(ctx, client.ObjectKeyFromObject(pvc), &corev1.PersistentVolumeClaim{})
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+})
+
+var _ = Describe("Missing post-init application SQL refs", func() {
+	var (
+		cluster    *apiv1.Cluster
+		reconciler *ClusterReconciler
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						PostInitApplicationSQLRefs: &apiv1.PostInitApplicationSQLRefs{
+							SecretRefs: []apiv1.SecretKeySelector{
+								{LocalObjectReference: apiv1.LocalObjectReference{Name: "secret-sql"}, Key: "schema.sql"},
+							},
+							ConfigMapRefs: []apiv1.ConfigMapKeySelector{
+								{LocalObjectReference: apiv1.LocalObjectReference{Name: "configmap-sql"}, Key: "data.sql"},
+							},
+						},
+					},
+				},
+			},
+		}
+		scheme := controllerScheme.BuildWithAllKnownScheme()
+		reconciler = &ClusterReconciler{
+			Client:   fake.NewClientBuilder().WithScheme(scheme).Build(),
+			Scheme:   scheme,
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	It("reports the references to missing objects", func(ctx SpecContext) {
+		Expect(reconciler.getMissingPostInitApplicationSQLRefs(ctx, cluster)).To(ConsistOf(
+			"secret secret-sql/schema.sql",
+			"configmap configmap-sql/data.sql",
+		))
+	})
+
+	It("reports the references to missing keys", func(ctx SpecContext) {
+		Expect(reconciler.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "secret-sql", Namespace: "default"},
+			Data:       map[string][]byte{"other.sql": []byte("SELECT 1")},
+		})).To(Succeed())
+		Expect(reconciler.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "configmap-sql", Namespace: "default"},
+			BinaryData: map[string][]byte{"data.sql": []byte("SELECT 1")},
+		})).To(Succeed())
+
+		Expect(reconciler.getMissingPostInitApplicationSQLRefs(ctx, cluster)).To(ConsistOf(
+			"secret secret-sql/schema.sql",
+		))
+	})
+
+	It("doesn't report anything when every reference is available", func(ctx SpecContext) {
+		Expect(reconciler.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "secret-sql", Namespace: "default"},
+			Data:       map[string][]byte{"schema.sql": []byte("CREATE TABLE test()")},
+		})).To(Succeed())
+		Expect(reconciler.Create(ctx, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "configmap-sql", Namespace: "default"},
+			Data:       map[string]string{"data.sql": "INSERT INTO test VALUES ()"},
+		})).To(Succeed())
+
+		Expect(reconciler.getMissingPostInitApplicationSQLRefs(ctx, cluster)).To(BeEmpty())
+	})
+})
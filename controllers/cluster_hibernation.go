@@ -0,0 +1,152 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// reconcileHibernation shuts down the instances of a cluster having the
+// hibernation annotation, preserving its PVCs. When the annotation is
+// removed, the instances are recreated by the usual reconciliation loop
+// starting from the dangling PVCs. A non-nil result means that the
+// reconciliation loop must stop here
+func (r *ClusterReconciler) reconcileHibernation(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	resources *managedResources,
+) (*ctrl.Result, error) {
+	contextLogger := log.FromContext(ctx)
+
+	if !utils.IsHibernationRequested(&cluster.ObjectMeta) {
+		if cluster.Status.Phase == apiv1.PhaseHibernated || cluster.Status.Phase == apiv1.PhaseHibernating {
+			contextLogger.Info("Resuming the cluster from hibernation")
+			r.Recorder.Event(cluster, "Normal", "Hibernation", "Resuming the cluster from hibernation")
+			return nil, r.RegisterPhase(ctx, cluster, apiv1.PhaseResumingFromHibernation, "")
+		}
+		return nil, nil
+	}
+
+	if len(resources.instances.Items) == 0 {
+		if cluster.Status.Phase != apiv1.PhaseHibernated {
+			contextLogger.Info("Cluster hibernated")
+			r.Recorder.Event(cluster, "Normal", "Hibernation", "Cluster hibernated")
+		}
+		return &ctrl.Result{}, r.RegisterPhase(ctx, cluster, apiv1.PhaseHibernated, "")
+	}
+
+	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseHibernating, ""); err != nil {
+		return nil, err
+	}
+
+	// A replica may be joining the cluster, and we need to wait
+	// for it before shutting down the instances
+	if runningJobs := resources.countRunningJobs(); runningJobs > 0 {
+		contextLogger.Debug("Waiting for the running jobs before hibernating", "count", runningJobs)
+		return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+	}
+
+	podsToHibernate := getPodsToHibernate(cluster, resources.instances.Items)
+	if len(podsToHibernate) > 0 && podsToHibernate[0].Name != cluster.Status.CurrentPrimary {
+		// The primary is gone: the replicas will be shut down as soon as
+		// they have replayed the WAL they received from it
+		statuses := r.getStatusFromInstances(ctx, resources.instances)
+		podsToHibernate = getReplicasReadyToHibernate(podsToHibernate, statuses)
+	}
+
+	for _, pod := range podsToHibernate {
+		contextLogger.Info("Shutting down instance for hibernation", "pod", pod.Name)
+		if err := r.Delete(ctx, pod); err != nil && !apierrs.IsNotFound(err) {
+			return nil, err
+		}
+	}
+
+	return &ctrl.Result{RequeueAfter: 1 * time.Second}, nil
+}
+
+// getPodsToHibernate gets the instances to be shut down in this
+// reconciliation loop. The primary comes first: its clean shutdown
+// checkpoints the data and sends the remaining WAL to the replicas, that
+// are shut down only when the primary is gone. This way every instance is
+// aligned with the last state of the primary, minimizing the recovery work
+// needed when the cluster is resumed
+func getPodsToHibernate(cluster *apiv1.Cluster, instances []corev1.Pod) []*corev1.Pod {
+	var replicas []*corev1.Pod
+	for idx := range instances {
+		pod := &instances[idx]
+		switch {
+		case pod.Name == cluster.Status.CurrentPrimary:
+			if !pod.DeletionTimestamp.IsZero() {
+				return nil
+			}
+			return []*corev1.Pod{pod}
+		case pod.DeletionTimestamp.IsZero():
+			replicas = append(replicas, pod)
+		}
+	}
+
+	return replicas
+}
+
+// getReplicasReadyToHibernate filters the passed replicas, keeping the
+// ones that have replayed all the WAL they received. The replicas which
+// are not reporting their status, or that are not expected to catch up
+// because their replay is paused or delayed, are shut down anyway
+func getReplicasReadyToHibernate(
+	replicas []*corev1.Pod,
+	statuses postgres.PostgresqlStatusList,
+) []*corev1.Pod {
+	statusByName := make(map[string]postgres.PostgresqlStatus, len(statuses.Items))
+	for _, item := range statuses.Items {
+		statusByName[item.Pod.Name] = item
+	}
+
+	result := make([]*corev1.Pod, 0, len(replicas))
+	for _, pod := range replicas {
+		status, ok := statusByName[pod.Name]
+		if ok && isReplicaReplayingWAL(status) {
+			continue
+		}
+		result = append(result, pod)
+	}
+
+	return result
+}
+
+// isReplicaReplayingWAL checks if a replica is still replaying
+// the WAL it received
+func isReplicaReplayingWAL(status postgres.PostgresqlStatus) bool {
+	if status.Error != nil || status.IsPrimary || status.ReplayPaused {
+		return false
+	}
+
+	if status.RecoveryMinApplyDelay != "" && status.RecoveryMinApplyDelay != "0" {
+		return false
+	}
+
+	return status.ReplayLsn.Less(status.ReceivedLsn)
+}
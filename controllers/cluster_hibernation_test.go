@@ -0,0 +1,206 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"errors"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	controllerScheme "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("hibernation", func() {
+	cluster := &apiv1.Cluster{
+		Status: apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+	}
+
+	newPod := func(name string, terminating bool) corev1.Pod {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if terminating {
+			now := metav1.Now()
+			pod.DeletionTimestamp = &now
+		}
+		return pod
+	}
+
+	podNames := func(pods []*corev1.Pod) []string {
+		names := make([]string, len(pods))
+		for idx, pod := range pods {
+			names[idx] = pod.Name
+		}
+		return names
+	}
+
+	It("shuts down the primary first", func() {
+		instances := []corev1.Pod{
+			newPod("cluster-example-1", false),
+			newPod("cluster-example-2", false),
+			newPod("cluster-example-3", true),
+		}
+		Expect(podNames(getPodsToHibernate(cluster, instances))).To(Equal([]string{"cluster-example-1"}))
+	})
+
+	It("waits for the primary to be gone before shutting down the replicas", func() {
+		instances := []corev1.Pod{
+			newPod("cluster-example-1", true),
+			newPod("cluster-example-2", false),
+		}
+		Expect(getPodsToHibernate(cluster, instances)).To(BeEmpty())
+	})
+
+	It("shuts down the replicas when the primary is gone", func() {
+		instances := []corev1.Pod{
+			newPod("cluster-example-2", false),
+			newPod("cluster-example-3", true),
+		}
+		Expect(podNames(getPodsToHibernate(cluster, instances))).To(Equal([]string{"cluster-example-2"}))
+	})
+
+	It("waits for the replicas to replay the WAL they received", func() {
+		replicas := []*corev1.Pod{
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-3"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-4"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-5"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-6"}},
+		}
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{Pod: *replicas[0], ReceivedLsn: "0/5000060", ReplayLsn: "0/5000060"},
+				{Pod: *replicas[1], ReceivedLsn: "0/5000060", ReplayLsn: "0/4000028"},
+				{Pod: *replicas[2], ReceivedLsn: "0/5000060", ReplayLsn: "0/4000028", ReplayPaused: true},
+				{Pod: *replicas[3], ReceivedLsn: "0/5000060", ReplayLsn: "0/4000028", RecoveryMinApplyDelay: "1h"},
+				{Pod: *replicas[4], Error: errors.New("connection refused")},
+			},
+		}
+		Expect(podNames(getReplicasReadyToHibernate(replicas, statuses))).To(Equal([]string{
+			"cluster-example-2", "cluster-example-4", "cluster-example-5", "cluster-example-6",
+		}))
+	})
+})
+
+var _ = Describe("hibernation reconciliation", func() {
+	var (
+		cluster    *apiv1.Cluster
+		primary    *corev1.Pod
+		replica    *corev1.Pod
+		reconciler *ClusterReconciler
+	)
+
+	newInstance := func(name string) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	}
+
+	podExists := func(ctx SpecContext, pod *corev1.Pod) bool {
+		err := reconciler.Get(ctx, client.ObjectKeyFromObject(pod), &corev1.Pod{})
+		if apierrs.IsNotFound(err) {
+			return false
+		}
+		Expect(err).ToNot(HaveOccurred())
+		return true
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+				Annotations: map[string]string{
+					utils.HibernationAnnotationName: string(utils.HibernationAnnotationValueOn),
+				},
+			},
+			Status: apiv1.ClusterStatus{CurrentPrimary: "cluster-example-1"},
+		}
+		primary = newInstance("cluster-example-1")
+		replica = newInstance("cluster-example-2")
+		reconciler = &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(controllerScheme.BuildWithAllKnownScheme()).
+				WithObjects(cluster, primary, replica).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	It("does nothing when the hibernation is not requested", func(ctx SpecContext) {
+		cluster.Annotations = nil
+		cluster.Status.Phase = apiv1.PhaseHealthy
+		resources := &managedResources{instances: corev1.PodList{Items: []corev1.Pod{*primary, *replica}}}
+
+		result, err := reconciler.reconcileHibernation(ctx, cluster, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeNil())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseHealthy))
+		Expect(podExists(ctx, primary)).To(BeTrue())
+	})
+
+	It("shuts down the primary, then waits for it to be gone", func(ctx SpecContext) {
+		resources := &managedResources{instances: corev1.PodList{Items: []corev1.Pod{*primary, *replica}}}
+
+		result, err := reconciler.reconcileHibernation(ctx, cluster, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).ToNot(BeNil())
+		Expect(result.RequeueAfter).ToNot(BeZero())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseHibernating))
+		Expect(podExists(ctx, primary)).To(BeFalse())
+		Expect(podExists(ctx, replica)).To(BeTrue())
+	})
+
+	It("waits for the running jobs before shutting down the instances", func(ctx SpecContext) {
+		resources := &managedResources{
+			instances: corev1.PodList{Items: []corev1.Pod{*primary, *replica}},
+			jobs:      batchv1.JobList{Items: []batchv1.Job{{ObjectMeta: metav1.ObjectMeta{Name: "join"}}}},
+		}
+
+		result, err := reconciler.reconcileHibernation(ctx, cluster, resources)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).ToNot(BeNil())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseHibernating))
+		Expect(podExists(ctx, primary)).To(BeTrue())
+	})
+
+	It("marks the cluster as hibernated when every instance is gone", func(ctx SpecContext) {
+		result, err := reconciler.reconcileHibernation(ctx, cluster, &managedResources{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).ToNot(BeNil())
+		Expect(result.RequeueAfter).To(BeZero())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseHibernated))
+	})
+
+	It("resumes the cluster when the hibernation is not requested anymore", func(ctx SpecContext) {
+		cluster.Annotations = nil
+		cluster.Status.Phase = apiv1.PhaseHibernated
+
+		result, err := reconciler.reconcileHibernation(ctx, cluster, &managedResources{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(result).To(BeNil())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseResumingFromHibernation))
+	})
+})
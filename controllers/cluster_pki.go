@@ -58,7 +58,7 @@ func (r *ClusterReconciler) setupPostgresPKI(ctx context.Context, cluster *apiv1
 	if err != nil {
 		if apierrors.IsNotFound(err) {
 			return fmt.Errorf("missing specified server TLS secret %s: %w",
-				cluster.Status.Certificates.ServerTLSSecret, err)
+				cluster.GetServerTLSSecretName(), err)
 		}
 		return fmt.Errorf("generating server certificate: %w", err)
 	}
@@ -264,10 +264,18 @@ func (r *ClusterReconciler) ensureServerLeafCertificate(
 	var serverSecret v1.Secret
 	err := r.Get(ctx, secretName, &serverSecret)
 	if err != nil {
+		r.Recorder.Event(cluster, "Warning", "SecretNotFound",
+			"Getting secret "+secretName.Name)
 		return err
 	}
 
-	return validateLeafCertificate(caSecret, &serverSecret, opts)
+	if err := validateLeafCertificate(caSecret, &serverSecret, opts); err != nil {
+		r.Recorder.Event(cluster, "Warning", "InvalidServerTLSSecret",
+			fmt.Sprintf("Validating server secret %s: %s", serverSecret.Name, err.Error()))
+		return err
+	}
+
+	return nil
 }
 
 func validateLeafCertificate(caSecret *v1.Secret, serverSecret *v1.Secret, opts *x509.VerifyOptions) error {
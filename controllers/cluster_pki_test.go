@@ -0,0 +1,71 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"crypto/x509"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("User-provided server certificates", func() {
+	It("accepts a server certificate signed by the provided CA", func() {
+		opts := &x509.VerifyOptions{KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}
+		ca, err := certs.CreateRootCA("company-ca", "default")
+		Expect(err).ToNot(HaveOccurred())
+		server, err := ca.CreateAndSignPair("cluster-example-rw", certs.CertTypeServer, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(validateLeafCertificate(
+			ca.GenerateCASecret("default", "company-ca"),
+			server.GenerateCertificateSecret("default", "company-server"),
+			opts)).To(Succeed())
+	})
+
+	It("rejects a server certificate signed by another CA", func() {
+		opts := &x509.VerifyOptions{KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}
+		ca, err := certs.CreateRootCA("company-ca", "default")
+		Expect(err).ToNot(HaveOccurred())
+		otherCA, err := certs.CreateRootCA("other-ca", "default")
+		Expect(err).ToNot(HaveOccurred())
+		server, err := otherCA.CreateAndSignPair("cluster-example-rw", certs.CertTypeServer, nil)
+		Expect(err).ToNot(HaveOccurred())
+
+		Expect(validateLeafCertificate(
+			ca.GenerateCASecret("default", "company-ca"),
+			server.GenerateCertificateSecret("default", "company-server"),
+			opts)).ToNot(Succeed())
+	})
+
+	It("rejects a server secret without the private key", func() {
+		opts := &x509.VerifyOptions{KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}
+		ca, err := certs.CreateRootCA("company-ca", "default")
+		Expect(err).ToNot(HaveOccurred())
+		server, err := ca.CreateAndSignPair("cluster-example-rw", certs.CertTypeServer, nil)
+		Expect(err).ToNot(HaveOccurred())
+		serverSecret := server.GenerateCertificateSecret("default", "company-server")
+		delete(serverSecret.Data, certs.TLSPrivateKeyKey)
+
+		Expect(validateLeafCertificate(
+			ca.GenerateCASecret("default", "company-ca"),
+			serverSecret,
+			opts)).To(MatchError(ContainSubstring(certs.TLSPrivateKeyKey)))
+	})
+})
@@ -41,7 +41,10 @@ func (r *ClusterReconciler) scaleDownCluster(
 ) error {
 	contextLogger := log.FromContext(ctx)
 
-	if cluster.Spec.MaxSyncReplicas > 0 && cluster.Spec.Instances < (cluster.Spec.MaxSyncReplicas+1) {
+	// Scaling down to the primary alone is allowed, as the synchronous
+	// replication is relaxed until the replicas are scaled back up
+	if cluster.Spec.Instances > 1 && cluster.Spec.MaxSyncReplicas > 0 &&
+		cluster.Spec.Instances < (cluster.Spec.MaxSyncReplicas+1) {
 		cluster.Spec.Instances = cluster.Status.Instances
 		if err := r.Update(ctx, cluster); err != nil {
 			return err
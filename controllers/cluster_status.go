@@ -26,6 +26,7 @@ import (
 	"net/http"
 	"reflect"
 	"sort"
+	"strings"
 	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
@@ -59,6 +60,10 @@ var StatusRequestRetry = wait.Backoff{
 	Jitter:   0.1,
 }
 
+// maxTimelineHistoryEntries is the number of timeline switches reported
+// in the cluster status
+const maxTimelineHistoryEntries = 10
+
 // managedResources contains the resources that are created a cluster
 // and need to be managed by the controller
 type managedResources struct {
@@ -300,6 +305,7 @@ func (r *ClusterReconciler) updateResourceStatus(
 	// Services
 	cluster.Status.WriteService = cluster.GetServiceReadWriteName()
 	cluster.Status.ReadService = cluster.GetServiceReadName()
+	cluster.Status.ReadOnlyService = cluster.GetServiceReadOnlyName()
 
 	// If we are switching, check if the target primary is still active
 	// Ignore this check if current primary is empty (it happens during the bootstrap)
@@ -417,6 +423,44 @@ func (r *ClusterReconciler) updateOnlineUpdateEnabled(
 	return r.Status().Update(ctx, cluster)
 }
 
+// updateLastCompletedRestart records in the cluster status the restart
+// request that every instance has completed
+func (r *ClusterReconciler) updateLastCompletedRestart(
+	ctx context.Context, cluster *apiv1.Cluster, statuses postgres.PostgresqlStatusList,
+) error {
+	clusterRestart, ok := cluster.Annotations[specs.ClusterRestartAnnotationName]
+	if !ok || cluster.Status.LastCompletedRestart == clusterRestart {
+		return nil
+	}
+
+	if !isClusterRestartCompleted(clusterRestart, statuses) {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Restart of the cluster completed", "restartedAt", clusterRestart)
+	cluster.Status.LastCompletedRestart = clusterRestart
+	return r.Status().Update(ctx, cluster)
+}
+
+// isClusterRestartCompleted checks if every instance has been restarted
+// after the passed restart request, and has no restart pending
+func isClusterRestartCompleted(clusterRestart string, statuses postgres.PostgresqlStatusList) bool {
+	if len(statuses.Items) == 0 {
+		return false
+	}
+
+	for _, item := range statuses.Items {
+		if item.Pod.Annotations[specs.ClusterRestartAnnotationName] != clusterRestart {
+			return false
+		}
+		if item.Error != nil || item.PendingRestart {
+			return false
+		}
+	}
+
+	return true
+}
+
 // SetClusterOwnerAnnotationsAndLabels sets the cluster as owner of the passed object and then
 // sets all the needed annotations and labels
 func SetClusterOwnerAnnotationsAndLabels(obj *metav1.ObjectMeta, cluster *apiv1.Cluster) {
@@ -533,35 +577,42 @@ func (r *ClusterReconciler) refreshCertsExpirations(ctx context.Context, cluster
 	namespace := cluster.GetNamespace()
 
 	cluster.Status.Certificates.Expirations = make(map[string]string, 4)
+	cluster.Status.Certificates.NextRenewal = ""
 	certificates := cluster.Status.Certificates
 
-	err := r.setCertExpiration(ctx, cluster, certificates.ServerCASecret, namespace, certs.CACertKey)
-	if err != nil {
-		return err
-	}
-
-	err = r.setCertExpiration(ctx, cluster, certificates.ServerTLSSecret, namespace, certs.TLSCertKey)
-	if err != nil {
-		return err
+	secrets := []struct {
+		name    string
+		certKey string
+	}{
+		{name: certificates.ServerCASecret, certKey: certs.CACertKey},
+		{name: certificates.ServerTLSSecret, certKey: certs.TLSCertKey},
+		{name: certificates.ClientCASecret, certKey: certs.CACertKey},
+		{name: certificates.ReplicationTLSSecret, certKey: certs.TLSCertKey},
 	}
 
-	err = r.setCertExpiration(ctx, cluster, certificates.ClientCASecret, namespace, certs.CACertKey)
-	if err != nil {
-		return err
+	var nextRenewal *time.Time
+	for _, secret := range secrets {
+		renewal, err := r.setCertExpiration(ctx, cluster, secret.name, namespace, secret.certKey)
+		if err != nil {
+			return err
+		}
+		if renewal != nil && (nextRenewal == nil || renewal.Before(*nextRenewal)) {
+			nextRenewal = renewal
+		}
 	}
 
-	err = r.setCertExpiration(ctx, cluster, certificates.ReplicationTLSSecret, namespace, certs.TLSCertKey)
-	if err != nil {
-		return err
+	if nextRenewal != nil {
+		cluster.Status.Certificates.NextRenewal = nextRenewal.Format(certs.ExpirationDateLayout)
 	}
 
 	return nil
 }
 
-// setCertExpiration check the expiration date of a certificates used by the cluster
+// setCertExpiration check the expiration date of a certificates used by the cluster,
+// returning when it will be renewed if it is managed by the operator
 func (r *ClusterReconciler) setCertExpiration(ctx context.Context, cluster *apiv1.Cluster, secretName string,
 	namespace string, certKey string,
-) error {
+) (*time.Time, error) {
 	var secret corev1.Secret
 	err := r.Get(ctx, client.ObjectKey{
 		Namespace: namespace,
@@ -569,25 +620,31 @@ func (r *ClusterReconciler) setCertExpiration(ctx context.Context, cluster *apiv
 	}, &secret)
 	if err != nil {
 		if apierrs.IsNotFound(err) {
-			return nil
+			return nil, nil
 		}
-		return err
+		return nil, err
 	}
 	cert, ok := secret.Data[certKey]
 
 	if !ok {
-		return err
+		return nil, err
 	}
 
 	keyPair := certs.KeyPair{Certificate: cert}
 	_, expDate, err := keyPair.IsExpiring()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	cluster.Status.Certificates.Expirations[secretName] = expDate.String()
+	cluster.Status.Certificates.Expirations[secretName] = expDate.Format(certs.ExpirationDateLayout)
 
-	return nil
+	// User-provided certificates are not renewed by the operator
+	if owner, ok := IsOwnedByCluster(&secret); !ok || owner != cluster.Name {
+		return nil, nil
+	}
+
+	renewal := certs.GetRenewalTime(*expDate)
+	return &renewal, nil
 }
 
 // refreshConfigMapResourceVersions set the resource version of the secrets
@@ -678,6 +735,22 @@ func (r *ClusterReconciler) refreshSecretResourceVersions(ctx context.Context, c
 		}
 	}
 
+	if cluster.Spec.Managed != nil {
+		for _, role := range cluster.Spec.Managed.Roles {
+			if role.PasswordSecret == nil {
+				continue
+			}
+			if versions.ManagedRoleSecretVersions == nil {
+				versions.ManagedRoleSecretVersions = make(map[string]string)
+			}
+			version, err = r.getSecretResourceVersion(ctx, cluster, role.PasswordSecret.Name)
+			if err != nil {
+				return err
+			}
+			versions.ManagedRoleSecretVersions[role.PasswordSecret.Name] = version
+		}
+	}
+
 	cluster.Status.SecretsResourceVersion = versions
 
 	return nil
@@ -731,6 +804,68 @@ func (r *ClusterReconciler) setPrimaryInstance(
 	return r.Status().Update(ctx, cluster)
 }
 
+// updateReconcileStatus records in the status of the cluster the outcome
+// of the reconciliation loop. Errors are only logged, as they must not
+// hide the outcome of the loop itself
+func (r *ClusterReconciler) updateReconcileStatus(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	reconcileErr error,
+) {
+	origCluster := cluster.DeepCopy()
+	if !setReconcileStatus(&cluster.Status, reconcileErr, time.Now()) {
+		return
+	}
+
+	if err := r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil && !apierrs.IsNotFound(err) {
+		log.FromContext(ctx).Warning("Cannot update the reconciliation status", "error", err.Error())
+	}
+}
+
+// setReconcileStatus sets the reconciliation timestamps and error in the
+// passed status, returning true when it needs to be updated. The status
+// is only updated when the outcome of the loop changes, as updating it at
+// every loop would trigger a new one
+func setReconcileStatus(status *apiv1.ClusterStatus, reconcileErr error, now time.Time) bool {
+	timestamp := now.Format(metav1.RFC3339Micro)
+	failing := isReconcileFailing(status)
+
+	if reconcileErr != nil {
+		if failing && reconcileErr.Error() == status.LastReconcileError {
+			return false
+		}
+
+		status.LastReconcileOutcomeChangeTime = timestamp
+		status.LastFailedReconcileTime = timestamp
+		status.LastReconcileError = reconcileErr.Error()
+		return true
+	}
+
+	if !failing && status.LastSuccessfulReconcileTime != "" {
+		return false
+	}
+
+	status.LastReconcileOutcomeChangeTime = timestamp
+	status.LastSuccessfulReconcileTime = timestamp
+	return true
+}
+
+// isReconcileFailing checks if the last reconciliation loop recorded in
+// the passed status failed. Invalid timestamps are considered missing
+func isReconcileFailing(status *apiv1.ClusterStatus) bool {
+	lastFailure, err := time.Parse(metav1.RFC3339Micro, status.LastFailedReconcileTime)
+	if err != nil {
+		return false
+	}
+
+	lastSuccess, err := time.Parse(metav1.RFC3339Micro, status.LastSuccessfulReconcileTime)
+	if err != nil {
+		return true
+	}
+
+	return !lastFailure.Before(lastSuccess)
+}
+
 // RegisterPhase update phase in the status cluster with the
 // proper reason
 func (r *ClusterReconciler) RegisterPhase(ctx context.Context,
@@ -780,14 +915,18 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 	cluster *apiv1.Cluster,
 	statuses postgres.PostgresqlStatusList,
 ) error {
-	existingClusterStatus := cluster.Status
+	existingClusterStatus := *cluster.Status.DeepCopy()
 	cluster.Status.InstancesReportedState = make(map[apiv1.PodName]apiv1.InstanceReportedState, len(statuses.Items))
 
 	// we extract the instances reported state
 	for _, item := range statuses.Items {
 		cluster.Status.InstancesReportedState[apiv1.PodName(item.Pod.Name)] = apiv1.InstanceReportedState{
-			IsPrimary:  item.IsPrimary,
-			TimeLineID: item.TimeLineID,
+			IsPrimary:              item.IsPrimary,
+			TimeLineID:             item.TimeLineID,
+			PendingRestart:         item.PendingRestart,
+			PendingRestartSettings: item.PendingRestartSettings,
+			RecoveryMinApplyDelay:  item.RecoveryMinApplyDelay,
+			Phase:                  utils.GetPodStatus(item.Pod),
 		}
 	}
 
@@ -797,15 +936,156 @@ func (r *ClusterReconciler) updateClusterStatusThatRequiresInstancesState(
 		// This avoids to have a zero timeline id in case that no primary instance is up during reconciliation.
 		if item.IsPrimary && item.TimeLineID != 0 {
 			cluster.Status.TimelineID = item.TimeLineID
+			cluster.Status.TimelineHistory = buildTimelineHistory(item.TimeLineID, item.TimelineHistory)
 		}
 	}
 
+	r.setTimelineDivergenceCondition(cluster, statuses)
+
 	if !reflect.DeepEqual(existingClusterStatus, cluster.Status) {
 		return r.Status().Update(ctx, cluster)
 	}
 	return nil
 }
 
+// updateReconciliationDisabledCondition updates the condition telling if
+// the reconciliation loop of the cluster has been disabled by the user.
+// The condition is only added to the status if the loop gets disabled
+func (r *ClusterReconciler) updateReconciliationDisabledCondition(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	disabled bool,
+) error {
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionReconciliationDisabled),
+		Status:  metav1.ConditionFalse,
+		Reason:  string(apiv1.ConditionReasonReconciliationLoopEnabled),
+		Message: "The operator is reconciling the cluster",
+	}
+
+	if disabled {
+		condition = metav1.Condition{
+			Type:   string(apiv1.ConditionReconciliationDisabled),
+			Status: metav1.ConditionTrue,
+			Reason: string(apiv1.ConditionReasonReconciliationLoopDisabled),
+			Message: fmt.Sprintf("The reconciliation loop has been disabled through the %s annotation",
+				utils.ReconciliationLoopAnnotationName),
+		}
+	}
+
+	existingCondition := meta.FindStatusCondition(cluster.Status.Conditions, condition.Type)
+	if existingCondition == nil && !disabled {
+		return nil
+	}
+	if existingCondition != nil && existingCondition.Status == condition.Status {
+		return nil
+	}
+
+	if disabled {
+		r.Recorder.Event(cluster, "Warning", "ReconciliationDisabled", condition.Message)
+	} else {
+		r.Recorder.Event(cluster, "Normal", "ReconciliationEnabled", "The reconciliation loop has been enabled")
+	}
+
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+	return r.Status().Update(ctx, cluster)
+}
+
+// setTimelineDivergenceCondition sets the condition telling if any replica
+// is on a timeline that diverged from the one of the primary. The condition
+// is only added to the status once a divergence is detected
+func (r *ClusterReconciler) setTimelineDivergenceCondition(
+	cluster *apiv1.Cluster,
+	statuses postgres.PostgresqlStatusList,
+) {
+	conditionType := string(apiv1.ConditionTimelineDivergence)
+
+	// The timeline of the primary is not known in replica clusters
+	if cluster.IsReplica() {
+		meta.RemoveStatusCondition(&cluster.Status.Conditions, conditionType)
+		return
+	}
+
+	// The timeline in the cluster status may be outdated after a failover,
+	// so we compare the replicas with the timeline the primary is reporting.
+	// When the primary is not reporting, there's nothing to compare with
+	primaryTimelineID := 0
+	for _, item := range statuses.Items {
+		if item.IsPrimary && item.Error == nil {
+			primaryTimelineID = item.TimeLineID
+		}
+	}
+	if primaryTimelineID == 0 {
+		return
+	}
+
+	var divergedInstances []string
+	for _, item := range statuses.Items {
+		if !item.IsPrimary && item.Error == nil && cluster.IsTimelineDiverged(primaryTimelineID, item.TimeLineID) {
+			divergedInstances = append(divergedInstances, item.Pod.Name)
+		}
+	}
+
+	if len(divergedInstances) == 0 {
+		if meta.FindStatusCondition(cluster.Status.Conditions, conditionType) != nil {
+			meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+				Type:    conditionType,
+				Status:  metav1.ConditionFalse,
+				Reason:  string(apiv1.ConditionReasonTimelineAligned),
+				Message: "Every replica is on a timeline reached by the primary",
+			})
+		}
+		return
+	}
+
+	condition := metav1.Condition{
+		Type:   conditionType,
+		Status: metav1.ConditionTrue,
+		Reason: string(apiv1.ConditionReasonTimelineDiverged),
+		Message: fmt.Sprintf("The timeline of these replicas diverged from the one of the primary (%d): %s",
+			primaryTimelineID, strings.Join(divergedInstances, ", ")),
+	}
+	if !meta.IsStatusConditionTrue(cluster.Status.Conditions, conditionType) {
+		r.Recorder.Event(cluster, "Warning", "TimelineDivergence", condition.Message)
+	}
+	meta.SetStatusCondition(&cluster.Status.Conditions, condition)
+}
+
+// buildTimelineHistory builds the timeline history to be reported in the
+// cluster status from the history file of the current timeline. Only the
+// most recent switches are kept, to prevent the status from growing forever
+func buildTimelineHistory(
+	currentTimelineID int,
+	history []postgres.TimelineHistoryEntry,
+) []apiv1.TimelineHistoryEntry {
+	if len(history) == 0 {
+		return nil
+	}
+
+	result := make([]apiv1.TimelineHistoryEntry, len(history))
+	for idx, entry := range history {
+		// Every switch creates the timeline that is left by the following
+		// one, while the last one creates the current timeline
+		timelineID := currentTimelineID
+		if idx+1 < len(history) {
+			timelineID = history[idx+1].ParentTimelineID
+		}
+
+		result[idx] = apiv1.TimelineHistoryEntry{
+			TimelineID:       timelineID,
+			ParentTimelineID: entry.ParentTimelineID,
+			SwitchPoint:      string(entry.SwitchPoint),
+			Reason:           entry.Reason,
+		}
+	}
+
+	if len(result) > maxTimelineHistoryEntries {
+		result = result[len(result)-maxTimelineHistoryEntries:]
+	}
+
+	return result
+}
+
 // extractInstancesStatus extracts the status of the underlying PostgreSQL instance from
 // the requested Pod, via the instance manager. In case of failure, errors are passed
 // in the result list
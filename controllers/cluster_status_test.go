@@ -18,15 +18,24 @@ package controllers
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	controllerScheme "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -52,7 +61,7 @@ var _ = Describe("cluster_status unit tests", func() {
 		})
 		By("making sure that sets the status of the secret correctly", func() {
 			cluster.Status.Certificates.Expirations = map[string]string{}
-			err := clusterReconciler.setCertExpiration(ctx, cluster, secretName, namespace, certs.CACertKey)
+			_, err := clusterReconciler.setCertExpiration(ctx, cluster, secretName, namespace, certs.CACertKey)
 			Expect(err).To(BeNil())
 			Expect(cluster.Status.Certificates.Expirations[secretName]).To(Equal(certExpirationDate))
 		})
@@ -162,3 +171,360 @@ var _ = Describe("cluster_status unit tests", func() {
 		})
 	})
 })
+
+var _ = Describe("timeline history", func() {
+	It("is empty when there's no history", func() {
+		Expect(buildTimelineHistory(1, nil)).To(BeNil())
+	})
+
+	It("reports the timeline created by every switch", func() {
+		history := []postgres.TimelineHistoryEntry{
+			{ParentTimelineID: 1, SwitchPoint: "0/5000000", Reason: "no recovery target specified"},
+			{ParentTimelineID: 2, SwitchPoint: "0/7000148", Reason: "no recovery target specified"},
+		}
+		Expect(buildTimelineHistory(3, history)).To(Equal([]v1.TimelineHistoryEntry{
+			{
+				TimelineID:       2,
+				ParentTimelineID: 1,
+				SwitchPoint:      "0/5000000",
+				Reason:           "no recovery target specified",
+			},
+			{
+				TimelineID:       3,
+				ParentTimelineID: 2,
+				SwitchPoint:      "0/7000148",
+				Reason:           "no recovery target specified",
+			},
+		}))
+	})
+
+	It("keeps only the most recent switches", func() {
+		history := make([]postgres.TimelineHistoryEntry, maxTimelineHistoryEntries+5)
+		for idx := range history {
+			history[idx] = postgres.TimelineHistoryEntry{ParentTimelineID: idx + 1, SwitchPoint: "0/5000000"}
+		}
+		result := buildTimelineHistory(len(history)+1, history)
+		Expect(result).To(HaveLen(maxTimelineHistoryEntries))
+		Expect(result[0].ParentTimelineID).To(Equal(6))
+		Expect(result[len(result)-1].TimelineID).To(Equal(len(history) + 1))
+	})
+})
+
+var _ = Describe("timeline divergence condition", func() {
+	newStatus := func(name string, isPrimary bool, timelineID int) postgres.PostgresqlStatus {
+		return postgres.PostgresqlStatus{
+			Pod:        corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			IsPrimary:  isPrimary,
+			TimeLineID: timelineID,
+		}
+	}
+
+	It("reports the replicas on a diverged timeline", func() {
+		recorder := record.NewFakeRecorder(10)
+		reconciler := &ClusterReconciler{Recorder: recorder}
+		cluster := &v1.Cluster{Status: v1.ClusterStatus{TimelineID: 2}}
+
+		reconciler.setTimelineDivergenceCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", true, 2),
+				newStatus("cluster-example-2", false, 2),
+				newStatus("cluster-example-3", false, 3),
+			},
+		})
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionTimelineDivergence))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonTimelineDiverged)))
+		Expect(condition.Message).To(ContainSubstring("cluster-example-3"))
+		Expect(condition.Message).ToNot(ContainSubstring("cluster-example-2"))
+		Expect(recorder.Events).To(HaveLen(1))
+	})
+
+	It("clears the condition when the replicas are aligned", func() {
+		reconciler := &ClusterReconciler{Recorder: record.NewFakeRecorder(10)}
+		cluster := &v1.Cluster{}
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:   string(v1.ConditionTimelineDivergence),
+			Status: metav1.ConditionTrue,
+			Reason: string(v1.ConditionReasonTimelineDiverged),
+		})
+
+		reconciler.setTimelineDivergenceCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-2", false, 3),
+				newStatus("cluster-example-3", true, 3),
+			},
+		})
+
+		Expect(meta.IsStatusConditionFalse(cluster.Status.Conditions,
+			string(v1.ConditionTimelineDivergence))).To(BeTrue())
+	})
+
+	It("doesn't add the condition when no replica ever diverged", func() {
+		reconciler := &ClusterReconciler{Recorder: record.NewFakeRecorder(10)}
+		cluster := &v1.Cluster{}
+
+		reconciler.setTimelineDivergenceCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", true, 3),
+				newStatus("cluster-example-2", false, 3),
+			},
+		})
+
+		Expect(cluster.Status.Conditions).To(BeEmpty())
+	})
+
+	It("compares the replicas with the timeline the primary is reporting", func() {
+		recorder := record.NewFakeRecorder(10)
+		reconciler := &ClusterReconciler{Recorder: recorder}
+		cluster := &v1.Cluster{Status: v1.ClusterStatus{TimelineID: 2}}
+
+		// The primary has just been promoted, and the cluster status
+		// still reports the previous timeline
+		reconciler.setTimelineDivergenceCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-2", true, 3),
+				newStatus("cluster-example-3", false, 3),
+			},
+		})
+		Expect(cluster.Status.Conditions).To(BeEmpty())
+
+		// Without a primary reporting its timeline there's nothing to compare with
+		reconciler.setTimelineDivergenceCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-3", false, 3),
+			},
+		})
+		Expect(cluster.Status.Conditions).To(BeEmpty())
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("removes the condition in a replica cluster", func() {
+		reconciler := &ClusterReconciler{Recorder: record.NewFakeRecorder(10)}
+		cluster := &v1.Cluster{
+			Spec: v1.ClusterSpec{
+				ReplicaCluster: &v1.ReplicaClusterConfiguration{Enabled: true, Source: "origin"},
+			},
+		}
+		meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+			Type:   string(v1.ConditionTimelineDivergence),
+			Status: metav1.ConditionTrue,
+			Reason: string(v1.ConditionReasonTimelineDiverged),
+		})
+
+		reconciler.setTimelineDivergenceCondition(cluster, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", true, 2),
+				newStatus("cluster-example-2", false, 3),
+			},
+		})
+
+		Expect(cluster.Status.Conditions).To(BeEmpty())
+	})
+})
+
+var _ = Describe("reconciliation status", func() {
+	now := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	nowTimestamp := now.Format(metav1.RFC3339Micro)
+	recentTimestamp := now.Add(-10 * time.Second).Format(metav1.RFC3339Micro)
+	oldTimestamp := now.Add(-2 * time.Hour).Format(metav1.RFC3339Micro)
+
+	It("records the first successful reconciliation", func() {
+		status := v1.ClusterStatus{}
+		Expect(setReconcileStatus(&status, nil, now)).To(BeTrue())
+		Expect(status.LastReconcileOutcomeChangeTime).To(Equal(nowTimestamp))
+		Expect(status.LastSuccessfulReconcileTime).To(Equal(nowTimestamp))
+		Expect(status.LastFailedReconcileTime).To(BeEmpty())
+		Expect(status.LastReconcileError).To(BeEmpty())
+	})
+
+	It("doesn't update the status while the reconciliations keep succeeding", func() {
+		status := v1.ClusterStatus{
+			LastReconcileOutcomeChangeTime: oldTimestamp,
+			LastSuccessfulReconcileTime:    oldTimestamp,
+			LastFailedReconcileTime:        now.Add(-3 * time.Hour).Format(metav1.RFC3339Micro),
+			LastReconcileError:             "boom",
+		}
+		Expect(setReconcileStatus(&status, nil, now)).To(BeFalse())
+		Expect(status.LastReconcileOutcomeChangeTime).To(Equal(oldTimestamp))
+	})
+
+	It("records a failure keeping the last successful reconciliation", func() {
+		status := v1.ClusterStatus{
+			LastReconcileOutcomeChangeTime: recentTimestamp,
+			LastSuccessfulReconcileTime:    recentTimestamp,
+		}
+		Expect(setReconcileStatus(&status, errors.New("boom"), now)).To(BeTrue())
+		Expect(status.LastReconcileOutcomeChangeTime).To(Equal(nowTimestamp))
+		Expect(status.LastFailedReconcileTime).To(Equal(nowTimestamp))
+		Expect(status.LastSuccessfulReconcileTime).To(Equal(recentTimestamp))
+		Expect(status.LastReconcileError).To(Equal("boom"))
+	})
+
+	It("doesn't update the status while the reconciliations keep failing with the same error", func() {
+		status := v1.ClusterStatus{
+			LastReconcileOutcomeChangeTime: oldTimestamp,
+			LastFailedReconcileTime:        oldTimestamp,
+			LastReconcileError:             "boom",
+		}
+		Expect(setReconcileStatus(&status, errors.New("boom"), now)).To(BeFalse())
+		Expect(setReconcileStatus(&status, errors.New("another"), now)).To(BeTrue())
+		Expect(status.LastReconcileError).To(Equal("another"))
+	})
+
+	It("records the same error again after a successful reconciliation", func() {
+		status := v1.ClusterStatus{
+			LastReconcileOutcomeChangeTime: recentTimestamp,
+			LastSuccessfulReconcileTime:    recentTimestamp,
+			LastFailedReconcileTime:        oldTimestamp,
+			LastReconcileError:             "boom",
+		}
+		Expect(setReconcileStatus(&status, errors.New("boom"), now)).To(BeTrue())
+		Expect(status.LastFailedReconcileTime).To(Equal(nowTimestamp))
+	})
+
+	It("records a success right after a failure", func() {
+		status := v1.ClusterStatus{
+			LastReconcileOutcomeChangeTime: recentTimestamp,
+			LastSuccessfulReconcileTime:    oldTimestamp,
+			LastFailedReconcileTime:        recentTimestamp,
+			LastReconcileError:             "boom",
+		}
+		Expect(setReconcileStatus(&status, nil, now)).To(BeTrue())
+		Expect(status.LastSuccessfulReconcileTime).To(Equal(nowTimestamp))
+		Expect(status.LastReconcileError).To(Equal("boom"))
+	})
+
+	It("compares the timestamps as times rather than as strings", func() {
+		// The failure happened after the success, but its timestamp
+		// sorts before the other one as a string
+		status := v1.ClusterStatus{
+			LastSuccessfulReconcileTime: "2023-01-01T10:00:00.000000+02:00",
+			LastFailedReconcileTime:     "2023-01-01T09:00:00.000000Z",
+			LastReconcileError:          "boom",
+		}
+		Expect(setReconcileStatus(&status, nil, now)).To(BeTrue())
+		Expect(status.LastSuccessfulReconcileTime).To(Equal(nowTimestamp))
+	})
+})
+
+var _ = Describe("reconciliation disabled condition", func() {
+	var reconciler *ClusterReconciler
+	var cluster *v1.Cluster
+
+	BeforeEach(func() {
+		cluster = &v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		reconciler = &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(controllerScheme.BuildWithAllKnownScheme()).
+				WithObjects(cluster).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	It("doesn't add the condition when the reconciliation loop is enabled", func(ctx SpecContext) {
+		Expect(reconciler.updateReconciliationDisabledCondition(ctx, cluster, false)).To(Succeed())
+		Expect(cluster.Status.Conditions).To(BeEmpty())
+	})
+
+	It("reports when the reconciliation loop is disabled and enabled again", func(ctx SpecContext) {
+		Expect(reconciler.updateReconciliationDisabledCondition(ctx, cluster, true)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(cluster.Status.Conditions,
+			string(v1.ConditionReconciliationDisabled))).To(BeTrue())
+
+		var storedCluster v1.Cluster
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "cluster-example", Namespace: "default"},
+			&storedCluster)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(storedCluster.Status.Conditions,
+			string(v1.ConditionReconciliationDisabled))).To(BeTrue())
+
+		Expect(reconciler.updateReconciliationDisabledCondition(ctx, cluster, false)).To(Succeed())
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(v1.ConditionReconciliationDisabled))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(v1.ConditionReasonReconciliationLoopEnabled)))
+	})
+})
+
+var _ = Describe("cluster restart completion", func() {
+	const restartedAt = "2023-03-01T10:00:00Z"
+
+	newStatus := func(name string, podRestart string) postgres.PostgresqlStatus {
+		pod := corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}}
+		if podRestart != "" {
+			pod.Annotations = map[string]string{specs.ClusterRestartAnnotationName: podRestart}
+		}
+		return postgres.PostgresqlStatus{Pod: pod}
+	}
+
+	It("is completed when every instance has been restarted", func() {
+		Expect(isClusterRestartCompleted(restartedAt, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", restartedAt),
+				newStatus("cluster-example-2", restartedAt),
+			},
+		})).To(BeTrue())
+	})
+
+	It("is not completed when an instance has not been restarted yet", func() {
+		Expect(isClusterRestartCompleted(restartedAt, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", restartedAt),
+				newStatus("cluster-example-2", "2023-02-01T10:00:00Z"),
+				newStatus("cluster-example-3", ""),
+			},
+		})).To(BeFalse())
+	})
+
+	It("is not completed when an instance is not reporting its status", func() {
+		unreachable := newStatus("cluster-example-2", restartedAt)
+		unreachable.Error = errors.New("connection refused")
+		Expect(isClusterRestartCompleted(restartedAt, postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newStatus("cluster-example-1", restartedAt),
+				unreachable,
+			},
+		})).To(BeFalse())
+	})
+
+	It("is not completed without instances", func() {
+		Expect(isClusterRestartCompleted(restartedAt, postgres.PostgresqlStatusList{})).To(BeFalse())
+	})
+})
+
+var _ = Describe("instances reported state", func() {
+	It("reports the settings waiting for a restart of each instance", func(ctx SpecContext) {
+		cluster := &v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+		}
+		reconciler := &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(controllerScheme.BuildWithAllKnownScheme()).
+				WithObjects(cluster).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+		statuses := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				{
+					Pod:       corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+					IsPrimary: true,
+				},
+				{
+					Pod:                    corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+					PendingRestart:         true,
+					PendingRestartSettings: []string{"max_connections", "shared_buffers"},
+				},
+			},
+		}
+
+		Expect(reconciler.updateClusterStatusThatRequiresInstancesState(ctx, cluster, statuses)).To(Succeed())
+		Expect(cluster.Status.InstancesReportedState["cluster-example-1"].PendingRestartSettings).To(BeEmpty())
+		Expect(cluster.Status.InstancesReportedState["cluster-example-2"].PendingRestartSettings).
+			To(Equal([]string{"max_connections", "shared_buffers"}))
+	})
+})
@@ -23,9 +23,11 @@ import (
 	"io"
 	"net/http"
 	neturl "net/url"
+	"reflect"
 
 	v1 "k8s.io/api/core/v1"
 	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/utils/strings/slices"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -116,26 +118,31 @@ func (r *ClusterReconciler) updatePrimaryPod(
 	// we need to check whether a manual switchover is required
 	contextLogger = contextLogger.WithValues("primaryPod", primaryPod.Name)
 	if cluster.GetPrimaryUpdateStrategy() == apiv1.PrimaryUpdateStrategySupervised {
-		contextLogger.Info("Waiting for the user to request a switchover to complete the rolling update",
-			"reason", reason)
-		err := r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForUser, "User must issue a supervised switchover")
-		if err != nil {
-			return false, err
+		if !isPrimaryUpdateApproved(cluster, primaryPod.Name) {
+			contextLogger.Info("Waiting for the user to request a switchover to complete the rolling update",
+				"reason", reason)
+			err := r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForUser,
+				apiv1.GetPrimaryRestartRequiredReason(primaryPod.Name, reason))
+			if err != nil {
+				return false, err
+			}
+
+			return true, nil
 		}
 
-		return true, nil
+		// The approval is valid only once, we remove it before
+		// proceeding so that it can't be used for the following updates
+		contextLogger.Info("The user approved the update of the primary instance", "reason", reason)
+		origCluster := cluster.DeepCopy()
+		delete(cluster.Annotations, utils.ProceedAnnotationName)
+		if err := r.Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+			return false, err
+		}
 	}
 
 	if cluster.GetPrimaryUpdateMethod() == apiv1.PrimaryUpdateMethodRestart {
 		if inPlacePossible {
-			// In-place restart is possible
-			if err := r.updateRestartAnnotation(ctx, cluster, primaryPod); err != nil {
-				return false, err
-			}
-			contextLogger.Info("Restarting primary instance in-place",
-				"reason", reason)
-			err := r.RegisterPhase(ctx, cluster, apiv1.PhaseInplacePrimaryRestart, reason)
-			return err == nil, err
+			return r.restartPrimaryInplace(ctx, cluster, primaryPod, reason)
 		}
 		// The pod needs to be deleted and recreated for the change to be applied
 		contextLogger.Info("Restarting primary instance without a switchover first",
@@ -173,7 +180,13 @@ func (r *ClusterReconciler) updatePrimaryPod(
 		return true, r.setPrimaryInstance(ctx, cluster, targetPrimary)
 	}
 
-	// if there is only one instance in the cluster, we should upgrade it even if it's a primary
+	// if there is only one instance in the cluster, there's no replica to switch
+	// over to, so we restart the primary in-place whenever that is enough
+	if inPlacePossible {
+		return r.restartPrimaryInplace(ctx, cluster, primaryPod, reason)
+	}
+
+	// otherwise we should upgrade it even if it's a primary
 	if err := r.RegisterPhase(ctx, cluster, apiv1.PhaseUpgrade,
 		fmt.Sprintf("The primary instance needs to be restarted: %s, reason: %s",
 			primaryPod.Name, reason),
@@ -184,6 +197,24 @@ func (r *ClusterReconciler) updatePrimaryPod(
 	return true, r.upgradePod(ctx, cluster, &primaryPod)
 }
 
+// restartPrimaryInplace asks the instance manager to restart the primary
+// instance without recreating its Pod
+func (r *ClusterReconciler) restartPrimaryInplace(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	primaryPod v1.Pod,
+	reason string,
+) (bool, error) {
+	if err := r.updateRestartAnnotation(ctx, cluster, primaryPod); err != nil {
+		return false, err
+	}
+	log.FromContext(ctx).Info("Restarting primary instance in-place",
+		"primaryPod", primaryPod.Name,
+		"reason", reason)
+	err := r.RegisterPhase(ctx, cluster, apiv1.PhaseInplacePrimaryRestart, reason)
+	return err == nil, err
+}
+
 func (r *ClusterReconciler) updateRestartAnnotation(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -270,6 +301,39 @@ func IsPodNeedingRollout(status postgres.PostgresqlStatus, cluster *apiv1.Cluste
 		}
 	}
 
+	// Check if the user changed the priority class of the instances. When
+	// it is not set, the pods may get the default one from Kubernetes
+	if cluster.Spec.PriorityClassName != "" &&
+		status.Pod.Spec.PriorityClassName != cluster.Spec.PriorityClassName {
+		return true, false, fmt.Sprintf("priority class changed, old: %q, new: %q",
+			status.Pod.Spec.PriorityClassName,
+			cluster.Spec.PriorityClassName)
+	}
+
+	// Check if the user changed the stop delay, as the kubelet would
+	// otherwise kill PostgreSQL before the instance manager shuts it down
+	if gracePeriod := status.Pod.Spec.TerminationGracePeriodSeconds; gracePeriod != nil &&
+		*gracePeriod != int64(cluster.GetMaxStopDelay()) {
+		return true, false, fmt.Sprintf("stop delay changed, old: %d, new: %d",
+			*gracePeriod,
+			cluster.GetMaxStopDelay())
+	}
+
+	// Check if the user changed the fsGroup or the seccomp profile
+	if isPodNeedingUpdatedSecurityContext(cluster, status.Pod) {
+		return true, false, "the security context changed"
+	}
+
+	// Check if the user changed the topology spread constraints
+	if isPodNeedingUpdatedTopologySpreadConstraints(cluster, status.Pod) {
+		return true, false, "the topology spread constraints changed"
+	}
+
+	// Check if the user added or removed any additional volume
+	if isPodNeedingUpdatedAdditionalVolumes(cluster, status.Pod) {
+		return true, false, "the additional volumes changed"
+	}
+
 	// Detect changes in the postgres container configuration
 	for _, container := range status.Pod.Spec.Containers {
 		// we go to the next array element if it isn't the postgres container
@@ -278,11 +342,44 @@ func IsPodNeedingRollout(status postgres.PostgresqlStatus, cluster *apiv1.Cluste
 		}
 
 		// Check if there is a change in the resource requirements
-		if !utils.IsResourceSubset(container.Resources, cluster.Spec.Resources) {
+		resources := cluster.GetInstanceResources(status.Pod.Name)
+		if !utils.IsResourceSubset(container.Resources, resources) {
 			return true, false, fmt.Sprintf("resources changed, old: %+v, new: %+v",
-				cluster.Spec.Resources,
+				resources,
 				container.Resources)
 		}
+
+		// Check if the user changed the command or the arguments. A nil and
+		// an empty list are equivalent, as the API server doesn't keep the latter
+		if command := specs.GetPostgresContainerCommand(*cluster); !slices.Equal(container.Command, command) ||
+			!slices.Equal(container.Args, cluster.Spec.ArgsOverride) {
+			return true, false, fmt.Sprintf("command changed, old: %v %v, new: %v %v",
+				container.Command, container.Args,
+				command, cluster.Spec.ArgsOverride)
+		}
+
+		// Check if the user changed the probes configuration
+		if isContainerNeedingUpdatedProbes(cluster, container) {
+			return true, false, "the probes configuration changed"
+		}
+
+		// Check if the user changed the environment variables
+		if isContainerNeedingUpdatedEnv(cluster, container) {
+			return true, false, "the environment variables changed"
+		}
+
+		// Check if the user changed the additional volume mounts
+		if isContainerNeedingUpdatedVolumeMounts(cluster, status.Pod, container) {
+			return true, false, "the additional volume mounts changed"
+		}
+
+		// Check if the instance manager can know the IP address PostgreSQL
+		// needs to listen on. Pods created by older versions of the operator
+		// don't receive it
+		if cluster.Spec.PostgresConfiguration.ListenScope == apiv1.ListenOnPodIPOnly &&
+			!slices.Contains(getEnvVarNames(container), "POD_IP") {
+			return true, false, "the IP address of the pod is required to listen only on it"
+		}
 	}
 
 	// check if pod needs to be restarted because of some config requiring it
@@ -290,6 +387,183 @@ func IsPodNeedingRollout(status postgres.PostgresqlStatus, cluster *apiv1.Cluste
 		true, "configuration needs a restart to apply some configuration changes"
 }
 
+// isContainerNeedingUpdatedProbes checks whether the probes of the PostgreSQL
+// container are different from the ones the operator would generate now.
+// This covers the tunings added, changed or removed in the probes
+// configuration of the cluster
+func isContainerNeedingUpdatedProbes(cluster *apiv1.Cluster, container v1.Container) bool {
+	startup, liveness, readiness := specs.GetPostgresContainerProbes(*cluster)
+
+	return !isProbeTimingEqual(container.StartupProbe, startup) ||
+		!isProbeTimingEqual(container.LivenessProbe, liveness) ||
+		!isProbeTimingEqual(container.ReadinessProbe, readiness)
+}
+
+// isProbeTimingEqual checks whether two probes are run with the same
+// timing. The fields left unset are filled in with the defaults of the
+// API server, as they are in the probes of the existing pods
+func isProbeTimingEqual(current, expected *v1.Probe) bool {
+	if current == nil || expected == nil {
+		return current == nil && expected == nil
+	}
+
+	defaultValue := func(value, defaultValue int32) int32 {
+		if value == 0 {
+			return defaultValue
+		}
+		return value
+	}
+
+	return current.InitialDelaySeconds == expected.InitialDelaySeconds &&
+		defaultValue(current.TimeoutSeconds, 1) == defaultValue(expected.TimeoutSeconds, 1) &&
+		defaultValue(current.PeriodSeconds, 10) == defaultValue(expected.PeriodSeconds, 10) &&
+		defaultValue(current.SuccessThreshold, 1) == defaultValue(expected.SuccessThreshold, 1) &&
+		defaultValue(current.FailureThreshold, 3) == defaultValue(expected.FailureThreshold, 3)
+}
+
+// isPodNeedingUpdatedSecurityContext checks whether the fsGroup or the seccomp
+// profile of the pod don't reflect the ones requested in the cluster. The
+// other fields may be set by the security context constraints of OpenShift
+func isPodNeedingUpdatedSecurityContext(cluster *apiv1.Cluster, pod v1.Pod) bool {
+	securityContext := specs.CreatePostgresPodSecurityContext(*cluster)
+	if securityContext == nil {
+		return false
+	}
+
+	podSecurityContext := pod.Spec.SecurityContext
+	if podSecurityContext == nil {
+		podSecurityContext = &v1.PodSecurityContext{}
+	}
+
+	return !reflect.DeepEqual(podSecurityContext.FSGroup, securityContext.FSGroup) ||
+		!reflect.DeepEqual(podSecurityContext.SeccompProfile, securityContext.SeccompProfile)
+}
+
+// isPodNeedingUpdatedTopologySpreadConstraints checks whether the topology
+// spread constraints of the pod don't reflect the ones requested in the cluster
+func isPodNeedingUpdatedTopologySpreadConstraints(cluster *apiv1.Cluster, pod v1.Pod) bool {
+	constraints := specs.CreateTopologySpreadConstraints(*cluster)
+	if len(constraints) == 0 && len(pod.Spec.TopologySpreadConstraints) == 0 {
+		return false
+	}
+
+	return !reflect.DeepEqual(pod.Spec.TopologySpreadConstraints, constraints)
+}
+
+// isPodNeedingUpdatedAdditionalVolumes checks whether the additional volumes
+// of the pod, which are recorded in its annotations, differ from the ones
+// requested in the cluster. Volumes are compared by name, as the Kubernetes
+// API server sets defaults in their sources, and the pod can contain volumes
+// injected by Kubernetes, like the one of the service account token
+func isPodNeedingUpdatedAdditionalVolumes(cluster *apiv1.Cluster, pod v1.Pod) bool {
+	podVolumeNames := make([]string, len(pod.Spec.Volumes))
+	for idx, volume := range pod.Spec.Volumes {
+		podVolumeNames[idx] = volume.Name
+	}
+
+	desiredVolumeNames := specs.GetAdditionalVolumeNames(*cluster)
+	for _, name := range desiredVolumeNames {
+		if !slices.Contains(podVolumeNames, name) {
+			return true
+		}
+	}
+
+	for _, name := range specs.GetAppliedAdditionalVolumeNames(pod) {
+		if !slices.Contains(desiredVolumeNames, name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isContainerNeedingUpdatedVolumeMounts checks whether the PostgreSQL
+// container lacks any of the additional volume mounts requested in the
+// cluster, or still contains one which is not requested anymore
+func isContainerNeedingUpdatedVolumeMounts(cluster *apiv1.Cluster, pod v1.Pod, container v1.Container) bool {
+	containsVolumeMount := func(volumeMounts []v1.VolumeMount, volumeMount v1.VolumeMount) bool {
+		for _, candidate := range volumeMounts {
+			if reflect.DeepEqual(candidate, volumeMount) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, desired := range cluster.Spec.AdditionalVolumeMounts {
+		if !containsVolumeMount(container.VolumeMounts, desired) {
+			return true
+		}
+	}
+
+	// The additional volume mounts can only refer to the additional volumes
+	appliedVolumeNames := specs.GetAppliedAdditionalVolumeNames(pod)
+	for _, volumeMount := range container.VolumeMounts {
+		if slices.Contains(appliedVolumeNames, volumeMount.Name) &&
+			!containsVolumeMount(cluster.Spec.AdditionalVolumeMounts, volumeMount) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isContainerNeedingUpdatedEnv checks whether the environment of the PostgreSQL
+// container doesn't reflect the environment variables requested in the cluster
+func isContainerNeedingUpdatedEnv(cluster *apiv1.Cluster, container v1.Container) bool {
+	var userEnv []v1.EnvVar
+	for _, envVar := range container.Env {
+		if !slices.Contains(apiv1.ReservedEnvironmentVariables, envVar.Name) {
+			userEnv = append(userEnv, envVar)
+		}
+	}
+
+	if len(userEnv) != len(cluster.Spec.Env) {
+		return true
+	}
+	for idx := range userEnv {
+		if !isEnvVarEqual(userEnv[idx], cluster.Spec.Env[idx]) {
+			return true
+		}
+	}
+
+	if len(container.EnvFrom) != len(cluster.Spec.EnvFrom) {
+		return true
+	}
+	for idx := range container.EnvFrom {
+		if !reflect.DeepEqual(container.EnvFrom[idx], cluster.Spec.EnvFrom[idx]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getEnvVarNames gets the names of the environment variables of a container
+func getEnvVarNames(container v1.Container) []string {
+	names := make([]string, len(container.Env))
+	for idx, envVar := range container.Env {
+		names[idx] = envVar.Name
+	}
+	return names
+}
+
+// isEnvVarEqual compares two environment variables, taking into account
+// the API version the Kubernetes API server sets by default in the
+// field references
+func isEnvVarEqual(current, desired v1.EnvVar) bool {
+	normalize := func(envVar v1.EnvVar) v1.EnvVar {
+		result := *envVar.DeepCopy()
+		if result.ValueFrom != nil && result.ValueFrom.FieldRef != nil &&
+			result.ValueFrom.FieldRef.APIVersion == "" {
+			result.ValueFrom.FieldRef.APIVersion = "v1"
+		}
+		return result
+	}
+
+	return reflect.DeepEqual(normalize(current), normalize(desired))
+}
+
 // isPodNeedingUpgradedImage checks whether an image in a pod has to be changed
 func isPodNeedingUpgradedImage(
 	cluster *apiv1.Cluster,
@@ -497,3 +771,9 @@ func upgradeInstanceManagerOnPod(ctx context.Context, pod v1.Pod) error {
 
 	return fmt.Errorf(string(body))
 }
+
+// isPrimaryUpdateApproved checks if the user approved the update of
+// the passed primary instance, with the supervised primary update strategy
+func isPrimaryUpdateApproved(cluster *apiv1.Cluster, primaryPodName string) bool {
+	return cluster.Annotations[utils.ProceedAnnotationName] == primaryPodName
+}
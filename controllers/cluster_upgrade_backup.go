@@ -0,0 +1,145 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils/hash"
+)
+
+// upgradeBackupCheckInterval is how often the operator checks the
+// status of the backup taken before upgrading the instances
+const upgradeBackupCheckInterval = 10 * time.Second
+
+// reconcileUpgradeBackup takes a backup of the cluster before its instances
+// are upgraded to a new PostgreSQL image, when requested by the user.
+// A nil result means the upgrade can proceed
+func (r *ClusterReconciler) reconcileUpgradeBackup(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	instancesStatus postgres.PostgresqlStatusList,
+) (*ctrl.Result, error) {
+	if !cluster.Spec.Backup.ShouldBackupBeforeUpgrade() || !isClusterNeedingUpgradedImage(cluster, instancesStatus) {
+		return nil, nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+
+	backupName, err := getUpgradeBackupName(cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	var backup apiv1.Backup
+	err = r.Get(ctx, types.NamespacedName{Name: backupName, Namespace: cluster.Namespace}, &backup)
+	if apierrs.IsNotFound(err) {
+		backup = apiv1.Backup{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      backupName,
+				Namespace: cluster.Namespace,
+			},
+			Spec: apiv1.BackupSpec{
+				Cluster: apiv1.LocalObjectReference{Name: cluster.Name},
+			},
+		}
+		SetClusterOwnerAnnotationsAndLabels(&backup.ObjectMeta, cluster)
+
+		contextLogger.Info("Taking a backup before upgrading the instances",
+			"backupName", backupName,
+			"targetImage", cluster.GetImageName())
+		if err := r.Create(ctx, &backup); err != nil {
+			return nil, fmt.Errorf("while creating the backup preceding the upgrade: %w", err)
+		}
+		r.Recorder.Eventf(cluster, "Normal", "UpgradeBackup",
+			"Taking backup %s before upgrading to %s", backupName, cluster.GetImageName())
+
+		return &ctrl.Result{RequeueAfter: upgradeBackupCheckInterval},
+			r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForUpgradeBackup,
+				fmt.Sprintf("Waiting for backup %s to complete", backupName))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch backup.Status.Phase {
+	case apiv1.BackupPhaseCompleted:
+		return nil, nil
+
+	case apiv1.BackupPhaseFailed:
+		contextLogger.Warning("The backup preceding the upgrade failed, not upgrading the instances",
+			"backupName", backupName,
+			"error", backup.Status.Error)
+		return &ctrl.Result{RequeueAfter: upgradeBackupCheckInterval},
+			r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForUser,
+				fmt.Sprintf("Backup %s preceding the upgrade failed, delete it to retry", backupName))
+
+	default:
+		contextLogger.Debug("Waiting for the backup preceding the upgrade to complete",
+			"backupName", backupName,
+			"phase", backup.Status.Phase)
+		return &ctrl.Result{RequeueAfter: upgradeBackupCheckInterval},
+			r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForUpgradeBackup,
+				fmt.Sprintf("Waiting for backup %s to complete", backupName))
+	}
+}
+
+// isClusterNeedingUpgradedImage checks whether any instance of the
+// cluster has to be upgraded to a different PostgreSQL image
+func isClusterNeedingUpgradedImage(cluster *apiv1.Cluster, instancesStatus postgres.PostgresqlStatusList) bool {
+	for _, status := range instancesStatus.Items {
+		_, newImage, err := isPodNeedingUpgradedImage(cluster, status.Pod)
+		if err != nil {
+			log.Error(err, "while checking if image could be upgraded", "podName", status.Pod.Name)
+			continue
+		}
+		if newImage != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getUpgradeBackupName gets the name of the backup taken before upgrading
+// the instances, which is unique for every target image and generation of
+// the cluster. The generation avoids reusing the backup taken before a
+// previous upgrade to the same image, like after a rollback
+func getUpgradeBackupName(cluster *apiv1.Cluster) (string, error) {
+	upgradeHash, err := hash.ComputeHash(struct {
+		Image      string
+		Generation int64
+	}{
+		Image:      cluster.GetImageName(),
+		Generation: cluster.Generation,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s-upgrade-%s", cluster.Name, upgradeHash), nil
+}
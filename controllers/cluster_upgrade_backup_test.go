@@ -0,0 +1,75 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Backup before the upgrade", func() {
+	cluster := apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			ImageName: "postgres:13.0",
+		},
+	}
+	cluster.Name = "cluster-example"
+
+	It("detects when an instance is running a different image", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		instancesStatus := postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{{Pod: *pod}},
+		}
+		Expect(isClusterNeedingUpgradedImage(&cluster, instancesStatus)).To(BeFalse())
+
+		pod.Spec.Containers[0].Image = "postgres:12.9"
+		instancesStatus.Items = append(instancesStatus.Items, postgres.PostgresqlStatus{Pod: *pod})
+		Expect(isClusterNeedingUpgradedImage(&cluster, instancesStatus)).To(BeTrue())
+	})
+
+	It("uses a different backup for every target image", func() {
+		name, err := getUpgradeBackupName(&cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(name).To(HavePrefix("cluster-example-upgrade-"))
+
+		sameName, err := getUpgradeBackupName(&cluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(sameName).To(Equal(name))
+
+		upgradedCluster := cluster
+		upgradedCluster.Spec.ImageName = "postgres:13.1"
+		otherName, err := getUpgradeBackupName(&upgradedCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(otherName).ToNot(Equal(name))
+	})
+
+	It("uses a different backup when upgrading again to the same image", func() {
+		name, err := getUpgradeBackupName(&cluster)
+		Expect(err).ToNot(HaveOccurred())
+
+		// The cluster was rolled back and upgraded again to the same image
+		upgradedAgainCluster := cluster
+		upgradedAgainCluster.Generation = cluster.Generation + 2
+		otherName, err := getUpgradeBackupName(&upgradedAgainCluster)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(otherName).ToNot(Equal(name))
+	})
+})
@@ -17,9 +17,19 @@ limitations under the License.
 package controllers
 
 import (
+	v1 "k8s.io/api/core/v1"
+	apierrs "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/pointer"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	controllerScheme "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -90,4 +100,363 @@ var _ = Describe("Pod upgrade", func() {
 		Expect(inplacePossible).To(BeTrue())
 		Expect(reason).To(BeEquivalentTo("configuration needs a restart to apply some configuration changes"))
 	})
+
+	It("requires a rollout when the command of the postgres container changes", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsPodReady: true, ExecutableHash: "test_hash"}
+		needRollout, _, _ := IsPodNeedingRollout(status, &cluster)
+		Expect(needRollout).To(BeFalse())
+
+		overriddenCluster := cluster.DeepCopy()
+		overriddenCluster.Spec.CommandOverride = []string{"/usr/bin/tini", "--", "/controller/manager", "instance", "run"}
+		needRollout, inplacePossible, reason := IsPodNeedingRollout(status, overriddenCluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(inplacePossible).To(BeFalse())
+		Expect(reason).To(ContainSubstring("command changed"))
+	})
+
+	It("doesn't require a rollout when the arguments override is empty", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsPodReady: true, ExecutableHash: "test_hash"}
+
+		emptyArgsCluster := cluster.DeepCopy()
+		emptyArgsCluster.Spec.ArgsOverride = []string{}
+		needRollout, _, _ := IsPodNeedingRollout(status, emptyArgsCluster)
+		Expect(needRollout).To(BeFalse())
+
+		status.Pod.Spec.Containers[0].Args = []string{}
+		needRollout, _, _ = IsPodNeedingRollout(status, &cluster)
+		Expect(needRollout).To(BeFalse())
+	})
+
+	It("requires a rollout when the probes configuration changes", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsPodReady: true, ExecutableHash: "test_hash"}
+
+		tunedCluster := cluster.DeepCopy()
+		tunedCluster.Spec.Probes = &apiv1.ProbesConfiguration{
+			Startup: &apiv1.Probe{FailureThreshold: pointer.Int32(30)},
+		}
+		needRollout, inplacePossible, reason := IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(inplacePossible).To(BeFalse())
+		Expect(reason).To(Equal("the probes configuration changed"))
+
+		tunedPod := specs.PodWithExistingStorage(*tunedCluster, 1)
+		status.Pod = *tunedPod
+		needRollout, _, _ = IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeFalse())
+
+		tunedCluster.Spec.Probes.Readiness = &apiv1.Probe{TimeoutSeconds: pointer.Int32(15)}
+		needRollout, _, _ = IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeTrue())
+	})
+
+	It("requires a rollout when a probe tuning is removed", func() {
+		tunedCluster := cluster.DeepCopy()
+		tunedCluster.Spec.Probes = &apiv1.ProbesConfiguration{
+			Readiness: &apiv1.Probe{TimeoutSeconds: pointer.Int32(15)},
+		}
+		pod := specs.PodWithExistingStorage(*tunedCluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsPodReady: true, ExecutableHash: "test_hash"}
+
+		needRollout, _, _ := IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeFalse())
+
+		needRollout, _, reason := IsPodNeedingRollout(status, &cluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(reason).To(Equal("the probes configuration changed"))
+	})
+
+	It("doesn't require a rollout because of the defaults of the API server", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		pod.Spec.Containers[0].LivenessProbe.PeriodSeconds = 10
+		pod.Spec.Containers[0].LivenessProbe.SuccessThreshold = 1
+		pod.Spec.Containers[0].LivenessProbe.FailureThreshold = 3
+		status := postgres.PostgresqlStatus{Pod: *pod, IsPodReady: true, ExecutableHash: "test_hash"}
+
+		needRollout, _, _ := IsPodNeedingRollout(status, &cluster)
+		Expect(needRollout).To(BeFalse())
+	})
+
+	It("requires a rollout when the additional volumes change", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsPodReady: true, ExecutableHash: "test_hash"}
+
+		tunedCluster := cluster.DeepCopy()
+		tunedCluster.Spec.AdditionalVolumes = []v1.Volume{
+			{
+				Name:         "audit",
+				VolumeSource: v1.VolumeSource{EmptyDir: &v1.EmptyDirVolumeSource{}},
+			},
+		}
+		needRollout, inplacePossible, reason := IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(inplacePossible).To(BeFalse())
+		Expect(reason).To(Equal("the additional volumes changed"))
+
+		status.Pod = *specs.PodWithExistingStorage(*tunedCluster, 1)
+		needRollout, _, _ = IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeFalse())
+
+		tunedCluster.Spec.AdditionalVolumeMounts = []v1.VolumeMount{
+			{Name: "audit", MountPath: "/var/log/audit"},
+		}
+		needRollout, _, reason = IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(reason).To(Equal("the additional volume mounts changed"))
+
+		status.Pod = *specs.PodWithExistingStorage(*tunedCluster, 1)
+		needRollout, _, _ = IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeFalse())
+
+		tunedCluster.Spec.AdditionalVolumeMounts = nil
+		needRollout, _, reason = IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(reason).To(Equal("the additional volume mounts changed"))
+
+		status.Pod = *specs.PodWithExistingStorage(*tunedCluster, 1)
+		needRollout, _, _ = IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeFalse())
+
+		tunedCluster.Spec.AdditionalVolumes = nil
+		needRollout, _, reason = IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(reason).To(Equal("the additional volumes changed"))
+
+		status.Pod = *specs.PodWithExistingStorage(*tunedCluster, 1)
+		needRollout, _, _ = IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeFalse())
+	})
+
+	It("requires a rollout when the priority class changes", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsPodReady: true, ExecutableHash: "test_hash"}
+
+		tunedCluster := cluster.DeepCopy()
+		tunedCluster.Spec.PriorityClassName = "business-critical"
+		needRollout, inplacePossible, reason := IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(inplacePossible).To(BeFalse())
+		Expect(reason).To(ContainSubstring("priority class changed"))
+
+		status.Pod = *specs.PodWithExistingStorage(*tunedCluster, 1)
+		needRollout, _, _ = IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeFalse())
+	})
+
+	It("doesn't require a rollout for the default priority class assigned by Kubernetes", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		pod.Spec.PriorityClassName = "cluster-default"
+		status := postgres.PostgresqlStatus{Pod: *pod, IsPodReady: true, ExecutableHash: "test_hash"}
+
+		needRollout, _, _ := IsPodNeedingRollout(status, &cluster)
+		Expect(needRollout).To(BeFalse())
+	})
+
+	It("requires a rollout when the stop delay changes", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsPodReady: true, ExecutableHash: "test_hash"}
+
+		tunedCluster := cluster.DeepCopy()
+		tunedCluster.Spec.MaxStopDelay = 1800
+		needRollout, inplacePossible, reason := IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(inplacePossible).To(BeFalse())
+		Expect(reason).To(ContainSubstring("stop delay changed"))
+
+		status.Pod = *specs.PodWithExistingStorage(*tunedCluster, 1)
+		needRollout, _, _ = IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeFalse())
+	})
+
+	It("requires a rollout when the topology spread constraints change", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsPodReady: true, ExecutableHash: "test_hash"}
+
+		tunedCluster := cluster.DeepCopy()
+		tunedCluster.Spec.TopologySpreadConstraints = []v1.TopologySpreadConstraint{
+			{
+				MaxSkew:           1,
+				TopologyKey:       "topology.kubernetes.io/zone",
+				WhenUnsatisfiable: v1.DoNotSchedule,
+			},
+		}
+		needRollout, inplacePossible, reason := IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(inplacePossible).To(BeFalse())
+		Expect(reason).To(Equal("the topology spread constraints changed"))
+
+		status.Pod = *specs.PodWithExistingStorage(*tunedCluster, 1)
+		needRollout, _, _ = IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeFalse())
+	})
+
+	It("requires a rollout when the pod doesn't know its IP address", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		pod.Spec.Containers[0].Env = nil
+		status := postgres.PostgresqlStatus{Pod: *pod, IsPodReady: true, ExecutableHash: "test_hash"}
+		needRollout, _, _ := IsPodNeedingRollout(status, &cluster)
+		Expect(needRollout).To(BeFalse())
+
+		tunedCluster := cluster.DeepCopy()
+		tunedCluster.Spec.PostgresConfiguration.ListenScope = apiv1.ListenOnPodIPOnly
+		needRollout, inplacePossible, reason := IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(inplacePossible).To(BeFalse())
+		Expect(reason).To(ContainSubstring("IP address of the pod"))
+
+		status.Pod = *specs.PodWithExistingStorage(*tunedCluster, 1)
+		needRollout, _, _ = IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeFalse())
+	})
+
+	It("requires a rollout when the environment variables change", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsPodReady: true, ExecutableHash: "test_hash"}
+
+		tunedCluster := cluster.DeepCopy()
+		tunedCluster.Spec.Env = []v1.EnvVar{
+			{Name: "HTTPS_PROXY", Value: "http://proxy:3128"},
+			{
+				Name: "NODE_NAME",
+				ValueFrom: &v1.EnvVarSource{
+					FieldRef: &v1.ObjectFieldSelector{FieldPath: "spec.nodeName"},
+				},
+			},
+		}
+		needRollout, inplacePossible, reason := IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(inplacePossible).To(BeFalse())
+		Expect(reason).To(Equal("the environment variables changed"))
+
+		// the API server sets the API version of the field references
+		tunedPod := specs.PodWithExistingStorage(*tunedCluster, 1)
+		for idx := range tunedPod.Spec.Containers[0].Env {
+			envVar := &tunedPod.Spec.Containers[0].Env[idx]
+			if envVar.ValueFrom != nil && envVar.ValueFrom.FieldRef != nil {
+				envVar.ValueFrom.FieldRef.APIVersion = "v1"
+			}
+		}
+		status.Pod = *tunedPod
+		needRollout, _, _ = IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeFalse())
+
+		tunedCluster.Spec.EnvFrom = []v1.EnvFromSource{
+			{ConfigMapRef: &v1.ConfigMapEnvSource{LocalObjectReference: v1.LocalObjectReference{Name: "proxy"}}},
+		}
+		needRollout, _, reason = IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(reason).To(Equal("the environment variables changed"))
+	})
+
+	It("requires a rollout when the security context changes", func() {
+		pod := specs.PodWithExistingStorage(cluster, 1)
+		status := postgres.PostgresqlStatus{Pod: *pod, IsPodReady: true, ExecutableHash: "test_hash"}
+
+		fsGroup := int64(1000)
+		tunedCluster := cluster.DeepCopy()
+		tunedCluster.Spec.FSGroup = &fsGroup
+		needRollout, inplacePossible, reason := IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(inplacePossible).To(BeFalse())
+		Expect(reason).To(Equal("the security context changed"))
+
+		tunedCluster.Spec.FSGroup = nil
+		tunedCluster.Spec.SeccompProfile = &v1.SeccompProfile{Type: v1.SeccompProfileTypeUnconfined}
+		needRollout, _, reason = IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeTrue())
+		Expect(reason).To(Equal("the security context changed"))
+
+		status.Pod = *specs.PodWithExistingStorage(*tunedCluster, 1)
+		needRollout, _, _ = IsPodNeedingRollout(status, tunedCluster)
+		Expect(needRollout).To(BeFalse())
+	})
+})
+
+var _ = Describe("Supervised primary update approval", func() {
+	It("is approved only for the annotated primary", func() {
+		cluster := apiv1.Cluster{}
+		Expect(isPrimaryUpdateApproved(&cluster, "cluster-example-1")).To(BeFalse())
+
+		cluster.Annotations = map[string]string{
+			utils.ProceedAnnotationName: "cluster-example-1",
+		}
+		Expect(isPrimaryUpdateApproved(&cluster, "cluster-example-1")).To(BeTrue())
+		Expect(isPrimaryUpdateApproved(&cluster, "cluster-example-2")).To(BeFalse())
+	})
+})
+
+var _ = Describe("Update of a single instance primary", func() {
+	var (
+		cluster    *apiv1.Cluster
+		pod        *v1.Pod
+		podList    *postgres.PostgresqlStatusList
+		reconciler *ClusterReconciler
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+				Annotations: map[string]string{
+					specs.ClusterRestartAnnotationName: "2023-01-01T00:00:00Z",
+				},
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances:             1,
+				PrimaryUpdateStrategy: apiv1.PrimaryUpdateStrategyUnsupervised,
+			},
+			Status: apiv1.ClusterStatus{
+				Instances:      1,
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		pod = specs.PodWithExistingStorage(*cluster, 1)
+		podList = &postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{{Pod: *pod, IsPrimary: true, IsPodReady: true}},
+		}
+		reconciler = &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(controllerScheme.BuildWithAllKnownScheme()).
+				WithObjects(cluster, pod).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	It("restarts the primary in-place when possible", func(ctx SpecContext) {
+		done, err := reconciler.updatePrimaryPod(ctx, cluster, podList, *pod, true, "a restart is needed")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(done).To(BeTrue())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseInplacePrimaryRestart))
+
+		var storedPod v1.Pod
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(pod), &storedPod)).To(Succeed())
+		Expect(storedPod.Annotations).To(HaveKeyWithValue(
+			specs.ClusterRestartAnnotationName, cluster.Annotations[specs.ClusterRestartAnnotationName]))
+	})
+
+	It("recreates the primary when an in-place restart is not enough", func(ctx SpecContext) {
+		done, err := reconciler.updatePrimaryPod(ctx, cluster, podList, *pod, false, "the image changed")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(done).To(BeTrue())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseUpgrade))
+
+		var storedPod v1.Pod
+		err = reconciler.Get(ctx, client.ObjectKeyFromObject(pod), &storedPod)
+		Expect(apierrs.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("waits for the user with the supervised strategy", func(ctx SpecContext) {
+		cluster.Spec.PrimaryUpdateStrategy = apiv1.PrimaryUpdateStrategySupervised
+		done, err := reconciler.updatePrimaryPod(ctx, cluster, podList, *pod, false, "the image changed")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(done).To(BeTrue())
+		Expect(cluster.Status.Phase).To(Equal(apiv1.PhaseWaitingForUser))
+		Expect(cluster.Status.PhaseReason).To(Equal(
+			apiv1.GetPrimaryRestartRequiredReason("cluster-example-1", "the image changed")))
+	})
 })
@@ -23,6 +23,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs/pgbouncer"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -205,4 +206,27 @@ var _ = Describe("pooler_resources unit tests", func() {
 			assertResourceIsCorrect(pooler.ObjectMeta, result.ObjectMeta, err)
 		})
 	})
+
+	It("should select only the pods of the passed poolers", func() {
+		Expect(pgbouncer.PodSelector(nil)).To(BeNil())
+
+		poolers := []apiv1.Pooler{
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-rw"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-ro"}},
+		}
+		selector := pgbouncer.PodSelector(poolers)
+		Expect(selector.MatchLabels).To(BeEmpty())
+		Expect(selector.MatchExpressions).To(ConsistOf(metav1.LabelSelectorRequirement{
+			Key:      pgbouncer.PgbouncerNameLabel,
+			Operator: metav1.LabelSelectorOpIn,
+			Values:   []string{"cluster-example-ro", "cluster-example-rw"},
+		}))
+	})
+
+	It("should build the same selector regardless of the order of the poolers", func() {
+		poolerA := apiv1.Pooler{ObjectMeta: metav1.ObjectMeta{Name: "pooler-a"}}
+		poolerB := apiv1.Pooler{ObjectMeta: metav1.ObjectMeta{Name: "pooler-b"}}
+		Expect(pgbouncer.PodSelector([]apiv1.Pooler{poolerB, poolerA})).
+			To(Equal(pgbouncer.PodSelector([]apiv1.Pooler{poolerA, poolerB})))
+	})
 })
@@ -36,6 +36,10 @@ import (
 // because there is a WAL receiver running in our Pod list
 var ErrWalReceiversRunning = fmt.Errorf("wal receivers are still running")
 
+// maxSwitchoverTargetLag is the maximum amount of WAL, in bytes, that the
+// target of a requested switchover can lag behind the primary
+const maxSwitchoverTargetLag = 64 * 1024 * 1024
+
 // updateTargetPrimaryFromPods sets the name of the target primary from the Pods status if needed
 // this function will return the name of the new primary selected for promotion
 func (r *ClusterReconciler) updateTargetPrimaryFromPods(
@@ -105,6 +109,10 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
 	// (if is still alive) to shut down by setting the apiv1.PendingFailoverMarker as
 	// target primary.
 	if cluster.Status.TargetPrimary == cluster.Status.CurrentPrimary {
+		if !cluster.IsAutomaticFailoverEnabled() {
+			return "", r.waitForExternalFailover(ctx, cluster)
+		}
+
 		contextLogger.Info("Current primary isn't healthy, initiating a failover")
 		status.LogStatus(ctx)
 		contextLogger.Debug("Cluster status before initiating the failover", "instances", resources.instances)
@@ -157,6 +165,25 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsPrimaryCluster(
 	return status.Items[0].Pod.Name, r.setPrimaryInstance(ctx, cluster, status.Items[0].Pod.Name)
 }
 
+// waitForExternalFailover reports that the primary isn't healthy, leaving
+// the promotion of a new one to an external orchestrator since the
+// automatic failover is disabled
+func (r *ClusterReconciler) waitForExternalFailover(ctx context.Context, cluster *apiv1.Cluster) error {
+	contextLogger := log.FromContext(ctx)
+
+	if cluster.Status.Phase == apiv1.PhaseWaitingForExternalFailover {
+		return nil
+	}
+
+	contextLogger.Info("Current primary isn't healthy, but the automatic failover is disabled",
+		"currentPrimary", cluster.Status.CurrentPrimary)
+	r.Recorder.Eventf(cluster, "Warning", "FailoverDisabled",
+		"Current primary isn't healthy, waiting for an external failover from %v", cluster.Status.CurrentPrimary)
+	return r.RegisterPhase(ctx, cluster, apiv1.PhaseWaitingForExternalFailover,
+		fmt.Sprintf("Current primary %v isn't healthy and the automatic failover is disabled",
+			cluster.Status.CurrentPrimary))
+}
+
 // isNodeUnschedulable checks whether a node is set to unschedulable
 func (r *ClusterReconciler) isNodeUnschedulable(ctx context.Context, nodeName string) (bool, error) {
 	var node corev1.Node
@@ -243,6 +270,119 @@ func (r *ClusterReconciler) setPrimaryOnSchedulableNode(
 	return "", nil
 }
 
+// reconcileTargetPrimaryAnnotation triggers the switchover requested by the
+// user with the target primary annotation, when the requested instance is
+// eligible. The annotation is removed once processed, and the name of the
+// new target primary is returned when a switchover has been triggered
+func (r *ClusterReconciler) reconcileTargetPrimaryAnnotation(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+) (string, error) {
+	requestedPrimary, ok := cluster.Annotations[utils.TargetPrimaryAnnotationName]
+	if !ok {
+		return "", nil
+	}
+
+	// The request is processed once the running switchover or failover completes
+	if cluster.Status.TargetPrimary != cluster.Status.CurrentPrimary {
+		return "", nil
+	}
+
+	contextLogger := log.FromContext(ctx).WithValues("requestedPrimary", requestedPrimary)
+
+	// The request is valid only once, we remove it before proceeding
+	origCluster := cluster.DeepCopy()
+	delete(cluster.Annotations, utils.TargetPrimaryAnnotationName)
+	if err := r.Patch(ctx, cluster, client.MergeFrom(origCluster)); err != nil {
+		return "", err
+	}
+
+	if requestedPrimary == cluster.Status.CurrentPrimary {
+		contextLogger.Info("The requested instance is already the primary, nothing to do")
+		return "", nil
+	}
+
+	if err := checkTargetPrimaryEligibility(cluster, status, requestedPrimary); err != nil {
+		contextLogger.Info("Ignoring the requested switchover", "reason", err.Error())
+		r.Recorder.Eventf(cluster, "Warning", "SwitchoverRejected",
+			"Cannot switch over to %v: %v", requestedPrimary, err)
+		return "", nil
+	}
+
+	contextLogger.Info("Switching over to the requested instance",
+		"currentPrimary", cluster.Status.CurrentPrimary)
+	r.Recorder.Eventf(cluster, "Normal", "SwitchingOver",
+		"Switching over from %v to %v, as requested by the user",
+		cluster.Status.CurrentPrimary, requestedPrimary)
+	if err := r.setPrimaryInstance(ctx, cluster, requestedPrimary); err != nil {
+		return "", err
+	}
+	return requestedPrimary, r.RegisterPhase(ctx, cluster, apiv1.PhaseSwitchover,
+		fmt.Sprintf("Switching over to %v", requestedPrimary))
+}
+
+// checkTargetPrimaryEligibility checks whether an instance is healthy and
+// caught up with the primary, so that it can be promoted by a switchover
+func checkTargetPrimaryEligibility(
+	cluster *apiv1.Cluster,
+	status postgres.PostgresqlStatusList,
+	instanceName string,
+) error {
+	var instanceStatus, primaryStatus *postgres.PostgresqlStatus
+	for idx := range status.Items {
+		switch {
+		case status.Items[idx].Pod.Name == instanceName:
+			instanceStatus = &status.Items[idx]
+		case status.Items[idx].IsPrimary:
+			primaryStatus = &status.Items[idx]
+		}
+	}
+
+	switch {
+	case instanceStatus == nil:
+		return fmt.Errorf("instance not found")
+	case instanceStatus.Error != nil:
+		return fmt.Errorf("cannot get the status of the instance: %w", instanceStatus.Error)
+	case !instanceStatus.IsPodReady:
+		return fmt.Errorf("the instance is not ready")
+	case cluster.IsInstanceFenced(instanceName):
+		return fmt.Errorf("the instance is fenced")
+	case instanceStatus.ReplayPaused || cluster.IsInstanceReplayPaused(instanceName):
+		return fmt.Errorf("the WAL replay of the instance is paused")
+	case instanceStatus.TimelineDiverged:
+		return fmt.Errorf("the instance is on a diverged timeline")
+	case instanceStatus.RecoveryMinApplyDelay != "" || cluster.GetInstanceMinApplyDelay(instanceName) > 0:
+		return fmt.Errorf("the instance is a delayed replica")
+	case !instanceStatus.IsWalReceiverActive:
+		return fmt.Errorf("the instance is not streaming from the primary")
+	case primaryStatus == nil:
+		return fmt.Errorf("cannot find the status of the current primary")
+	}
+
+	return checkTargetPrimaryLag(primaryStatus, instanceStatus)
+}
+
+// checkTargetPrimaryLag checks whether the WAL replayed by an instance is
+// within maxSwitchoverTargetLag bytes from the current LSN of the primary
+func checkTargetPrimaryLag(primaryStatus, instanceStatus *postgres.PostgresqlStatus) error {
+	primaryLsn, err := primaryStatus.CurrentLsn.Parse()
+	if err != nil {
+		return fmt.Errorf("cannot get the current LSN of the primary: %w", err)
+	}
+
+	replayLsn, err := instanceStatus.ReplayLsn.Parse()
+	if err != nil {
+		return fmt.Errorf("cannot get the replay LSN of the instance: %w", err)
+	}
+
+	if lag := primaryLsn - replayLsn; lag > maxSwitchoverTargetLag {
+		return fmt.Errorf("the instance is lagging %v bytes behind the primary", lag)
+	}
+
+	return nil
+}
+
 // updateTargetPrimaryFromPodsReplicaCluster sets the name of the target designated
 // primary from the Pods status if needed this function will return the name of the
 // new primary selected for promotion
@@ -264,6 +404,10 @@ func (r *ClusterReconciler) updateTargetPrimaryFromPodsReplicaCluster(
 		}
 	}
 
+	if !cluster.IsAutomaticFailoverEnabled() {
+		return "", r.waitForExternalFailover(ctx, cluster)
+	}
+
 	// The designated primary is not correctly working, and we need to elect a new one
 	// but before doing that we need to wait for all the WAL receivers to be
 	// terminated. This is needed to avoid losing the WAL data that is being received
@@ -495,7 +639,10 @@ func (r *ClusterReconciler) updateClusterLabelsOnPVCs(
 	return nil
 }
 
-// Make sure that only the currentPrimary has the label forward write traffic to him
+// updateRoleLabelsOnPods ensures that the role labels of the instances, used
+// by the selectors of the services, reflect the current primary. The primary
+// label is removed from every other instance before being set on the current
+// primary, so that the -rw service never points to more than one instance
 func (r *ClusterReconciler) updateRoleLabelsOnPods(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
@@ -508,7 +655,7 @@ func (r *ClusterReconciler) updateRoleLabelsOnPods(
 		return nil
 	}
 
-	primaryFound := false
+	var primaryPod *corev1.Pod
 	for idx := range pods.Items {
 		pod := &pods.Items[idx]
 
@@ -522,12 +669,17 @@ func (r *ClusterReconciler) updateRoleLabelsOnPods(
 
 		switch {
 		case pod.Name == cluster.Status.CurrentPrimary:
-			primaryFound = true
-
-			if !hasRole || podRole != specs.ClusterRoleLabelPrimary {
-				contextLogger.Info("Setting primary label", "pod", pod.Name)
+			primaryPod = pod
+
+		case pod.Name == cluster.Status.TargetPrimary,
+			cluster.IsInstanceReplayPaused(pod.Name):
+			// The instance is being promoted, or its WAL replay is paused
+			// and the data it serves is not updated: remove it from the
+			// read-only service until that's over
+			if hasRole {
+				contextLogger.Info("Removing role label", "pod", pod.Name)
 				patch := client.MergeFrom(pod.DeepCopy())
-				pod.Labels[specs.ClusterRoleLabelName] = specs.ClusterRoleLabelPrimary
+				delete(pod.Labels, specs.ClusterRoleLabelName)
 				if err := r.Patch(ctx, pod, patch); err != nil {
 					return err
 				}
@@ -545,11 +697,44 @@ func (r *ClusterReconciler) updateRoleLabelsOnPods(
 		}
 	}
 
-	if !primaryFound {
+	if primaryPod == nil {
 		contextLogger.Info("No primary instance found for this cluster")
+		return nil
 	}
 
-	return nil
+	if primaryPod.Labels[specs.ClusterRoleLabelName] != specs.ClusterRoleLabelPrimary {
+		contextLogger.Info("Setting primary label", "pod", primaryPod.Name)
+		patch := client.MergeFrom(primaryPod.DeepCopy())
+		primaryPod.Labels[specs.ClusterRoleLabelName] = specs.ClusterRoleLabelPrimary
+		if err := r.Patch(ctx, primaryPod, patch); err != nil {
+			return err
+		}
+	}
+
+	// The timestamp is checked independently of the label, so that it is
+	// recorded even if the status couldn't be patched after the label
+	if !isReadWriteServiceTimestampOutdated(cluster) {
+		return nil
+	}
+
+	origCluster := cluster.DeepCopy()
+	cluster.Status.ReadWriteServiceTimestamp = utils.GetCurrentTimestamp()
+	return r.Status().Patch(ctx, cluster, client.MergeFrom(origCluster))
+}
+
+// isReadWriteServiceTimestampOutdated checks whether the switch of the
+// read-write service to the current primary still needs to be recorded,
+// which is true when it has never been recorded or when it precedes the
+// last promotion
+func isReadWriteServiceTimestampOutdated(cluster *apiv1.Cluster) bool {
+	if cluster.Status.ReadWriteServiceTimestamp == "" {
+		return true
+	}
+
+	difference, err := utils.DifferenceBetweenTimestamps(
+		cluster.Status.ReadWriteServiceTimestamp,
+		cluster.Status.CurrentPrimaryTimestamp)
+	return err == nil && difference < 0
 }
 
 // updateOperatorLabelsOnInstances ensures that the instances have the correct labels
@@ -19,9 +19,15 @@ package controllers
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	controllerScheme "github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -150,3 +156,211 @@ var _ = Describe("Check pods not on primary node", func() {
 		Expect(GetPodsNotOnPrimaryNode(statusList2, &statusList2.Items[0]).Items).ToNot(BeEmpty())
 	})
 })
+
+var _ = Describe("Role labels of the instances", func() {
+	var (
+		cluster    *apiv1.Cluster
+		pods       corev1.PodList
+		reconciler *ClusterReconciler
+	)
+
+	newInstance := func(name, role string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "default",
+				Labels: map[string]string{
+					specs.ClusterLabelName:     "cluster-example",
+					specs.ClusterRoleLabelName: role,
+				},
+			},
+		}
+	}
+
+	getRole := func(ctx SpecContext, pod *corev1.Pod) string {
+		var livePod corev1.Pod
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(pod), &livePod)).To(Succeed())
+		*pod = livePod
+		return livePod.Labels[specs.ClusterRoleLabelName]
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary:            "cluster-example-1",
+				TargetPrimary:             "cluster-example-2",
+				CurrentPrimaryTimestamp:   "2023-01-10T10:00:00.000000Z",
+				ReadWriteServiceTimestamp: "2023-01-10T10:00:01.000000Z",
+			},
+		}
+		pods = corev1.PodList{
+			Items: []corev1.Pod{
+				newInstance("cluster-example-1", specs.ClusterRoleLabelPrimary),
+				newInstance("cluster-example-2", specs.ClusterRoleLabelReplica),
+				newInstance("cluster-example-3", specs.ClusterRoleLabelReplica),
+			},
+		}
+		reconciler = &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(controllerScheme.BuildWithAllKnownScheme()).
+				WithObjects(cluster, &pods.Items[0], &pods.Items[1], &pods.Items[2]).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	It("excludes the target primary from the read-only service", func(ctx SpecContext) {
+		Expect(reconciler.updateRoleLabelsOnPods(ctx, cluster, pods)).To(Succeed())
+		Expect(getRole(ctx, &pods.Items[0])).To(Equal(specs.ClusterRoleLabelPrimary))
+		Expect(getRole(ctx, &pods.Items[1])).To(BeEmpty())
+		Expect(getRole(ctx, &pods.Items[2])).To(Equal(specs.ClusterRoleLabelReplica))
+		Expect(cluster.Status.ReadWriteServiceTimestamp).To(Equal("2023-01-10T10:00:01.000000Z"))
+	})
+
+	It("excludes the replicas whose WAL replay is paused from the read-only service", func(ctx SpecContext) {
+		cluster.Annotations = map[string]string{
+			utils.ReplayPausedInstancesAnnotation: `["cluster-example-3"]`,
+		}
+		Expect(reconciler.updateRoleLabelsOnPods(ctx, cluster, pods)).To(Succeed())
+		Expect(getRole(ctx, &pods.Items[2])).To(BeEmpty())
+
+		cluster.Annotations = nil
+		Expect(reconciler.updateRoleLabelsOnPods(ctx, cluster, pods)).To(Succeed())
+		Expect(getRole(ctx, &pods.Items[2])).To(Equal(specs.ClusterRoleLabelReplica))
+	})
+
+	It("switches the read-write service to the new primary", func(ctx SpecContext) {
+		Expect(reconciler.updateRoleLabelsOnPods(ctx, cluster, pods)).To(Succeed())
+		Expect(getRole(ctx, &pods.Items[1])).To(BeEmpty())
+
+		cluster.Status.CurrentPrimary = "cluster-example-2"
+		cluster.Status.CurrentPrimaryTimestamp = utils.GetCurrentTimestamp()
+		Expect(reconciler.updateRoleLabelsOnPods(ctx, cluster, pods)).To(Succeed())
+		Expect(getRole(ctx, &pods.Items[0])).To(Equal(specs.ClusterRoleLabelReplica))
+		Expect(getRole(ctx, &pods.Items[1])).To(Equal(specs.ClusterRoleLabelPrimary))
+		Expect(isReadWriteServiceTimestampOutdated(cluster)).To(BeFalse())
+
+		var storedCluster apiv1.Cluster
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(cluster), &storedCluster)).To(Succeed())
+		Expect(storedCluster.Status.ReadWriteServiceTimestamp).To(Equal(cluster.Status.ReadWriteServiceTimestamp))
+	})
+
+	It("records the switch of the read-write service when the primary is already labelled", func(ctx SpecContext) {
+		// The label has been set, but the status couldn't be patched
+		cluster.Status.CurrentPrimaryTimestamp = "2023-01-10T11:00:00.000000Z"
+		Expect(reconciler.updateRoleLabelsOnPods(ctx, cluster, pods)).To(Succeed())
+		Expect(getRole(ctx, &pods.Items[0])).To(Equal(specs.ClusterRoleLabelPrimary))
+		Expect(isReadWriteServiceTimestampOutdated(cluster)).To(BeFalse())
+	})
+})
+
+var _ = Describe("Switchover requested with the annotation", func() {
+	var (
+		cluster    *apiv1.Cluster
+		status     postgres.PostgresqlStatusList
+		reconciler *ClusterReconciler
+	)
+
+	newInstanceStatus := func(name string, isPrimary bool) postgres.PostgresqlStatus {
+		instanceStatus := postgres.PostgresqlStatus{
+			Pod:                 corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}},
+			IsPrimary:           isPrimary,
+			IsPodReady:          true,
+			IsWalReceiverActive: !isPrimary,
+		}
+		if isPrimary {
+			instanceStatus.CurrentLsn = "0/6000060"
+		} else {
+			instanceStatus.ReceivedLsn = "0/6000060"
+			instanceStatus.ReplayLsn = "0/6000060"
+		}
+		return instanceStatus
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+				Annotations: map[string]string{
+					utils.TargetPrimaryAnnotationName: "cluster-example-2",
+				},
+			},
+			Spec: apiv1.ClusterSpec{Instances: 3},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				TargetPrimary:  "cluster-example-1",
+			},
+		}
+		status = postgres.PostgresqlStatusList{
+			Items: []postgres.PostgresqlStatus{
+				newInstanceStatus("cluster-example-1", true),
+				newInstanceStatus("cluster-example-2", false),
+				newInstanceStatus("cluster-example-3", false),
+			},
+		}
+		reconciler = &ClusterReconciler{
+			Client: fake.NewClientBuilder().
+				WithScheme(controllerScheme.BuildWithAllKnownScheme()).
+				WithObjects(cluster).
+				Build(),
+			Recorder: record.NewFakeRecorder(10),
+		}
+	})
+
+	It("switches over to the requested instance", func(ctx SpecContext) {
+		requestedPrimary, err := reconciler.reconcileTargetPrimaryAnnotation(ctx, cluster, status)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requestedPrimary).To(Equal("cluster-example-2"))
+
+		var storedCluster apiv1.Cluster
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(cluster), &storedCluster)).To(Succeed())
+		Expect(storedCluster.Annotations).ToNot(HaveKey(utils.TargetPrimaryAnnotationName))
+		Expect(storedCluster.Status.TargetPrimary).To(Equal("cluster-example-2"))
+		Expect(storedCluster.Status.Phase).To(Equal(apiv1.PhaseSwitchover))
+	})
+
+	It("waits for the running switchover to complete", func(ctx SpecContext) {
+		cluster.Status.TargetPrimary = "cluster-example-3"
+		requestedPrimary, err := reconciler.reconcileTargetPrimaryAnnotation(ctx, cluster, status)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requestedPrimary).To(BeEmpty())
+		Expect(cluster.Annotations).To(HaveKey(utils.TargetPrimaryAnnotationName))
+	})
+
+	It("discards the request when the instance isn't eligible", func(ctx SpecContext) {
+		status.Items[1].IsWalReceiverActive = false
+		requestedPrimary, err := reconciler.reconcileTargetPrimaryAnnotation(ctx, cluster, status)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(requestedPrimary).To(BeEmpty())
+
+		var storedCluster apiv1.Cluster
+		Expect(reconciler.Get(ctx, client.ObjectKeyFromObject(cluster), &storedCluster)).To(Succeed())
+		Expect(storedCluster.Annotations).ToNot(HaveKey(utils.TargetPrimaryAnnotationName))
+		Expect(storedCluster.Status.TargetPrimary).To(Equal("cluster-example-1"))
+	})
+
+	It("only promotes healthy replicas that are caught up", func() {
+		Expect(checkTargetPrimaryEligibility(cluster, status, "cluster-example-2")).To(Succeed())
+		Expect(checkTargetPrimaryEligibility(cluster, status, "cluster-example-4")).ToNot(Succeed())
+
+		status.Items[1].IsPodReady = false
+		Expect(checkTargetPrimaryEligibility(cluster, status, "cluster-example-2")).ToNot(Succeed())
+
+		status.Items[2].ReplayPaused = true
+		Expect(checkTargetPrimaryEligibility(cluster, status, "cluster-example-3")).ToNot(Succeed())
+	})
+
+	It("only promotes replicas whose lag is within the allowed bound", func() {
+		status.Items[0].CurrentLsn = "1/6000060"
+		status.Items[1].ReplayLsn = "1/2000060"
+		Expect(checkTargetPrimaryEligibility(cluster, status, "cluster-example-2")).To(Succeed())
+
+		status.Items[2].ReplayLsn = "0/6000060"
+		Expect(checkTargetPrimaryEligibility(cluster, status, "cluster-example-3")).ToNot(Succeed())
+
+		status.Items[1].ReplayLsn = ""
+		Expect(checkTargetPrimaryEligibility(cluster, status, "cluster-example-2")).ToNot(Succeed())
+	})
+})
@@ -36,6 +36,7 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/manager/instance/run/lifecycle"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/primaryhealth"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/runner"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/concurrency"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -58,8 +59,10 @@ func init() {
 func NewCmd() *cobra.Command {
 	var pgData string
 	var podName string
+	var podIP string
 	var clusterName string
 	var namespace string
+	var metricsPortTLS bool
 
 	cmd := &cobra.Command{
 		Use: "run [flags]",
@@ -70,7 +73,9 @@ func NewCmd() *cobra.Command {
 			instance.PgData = pgData
 			instance.Namespace = namespace
 			instance.PodName = podName
+			instance.PodIP = podIP
 			instance.ClusterName = clusterName
+			instance.MetricsPortTLS = metricsPortTLS
 
 			return retry.OnError(retry.DefaultRetry, isRunSubCommandRetryable, func() error {
 				return runSubCommand(ctx, instance)
@@ -81,10 +86,14 @@ func NewCmd() *cobra.Command {
 	cmd.Flags().StringVar(&pgData, "pg-data", os.Getenv("PGDATA"), "The PGDATA to be started up")
 	cmd.Flags().StringVar(&podName, "pod-name", os.Getenv("POD_NAME"), "The name of this pod, to "+
 		"be checked against the cluster state")
+	cmd.Flags().StringVar(&podIP, "pod-ip", os.Getenv("POD_IP"), "The IP address of this pod, used "+
+		"when PostgreSQL must listen only on it")
 	cmd.Flags().StringVar(&clusterName, "cluster-name", os.Getenv("CLUSTER_NAME"), "The name of the "+
 		"current cluster in k8s, used to coordinate switchover and failover")
 	cmd.Flags().StringVar(&namespace, "namespace", os.Getenv("NAMESPACE"), "The namespace of "+
 		"the cluster and of the Pod in k8s")
+	cmd.Flags().BoolVar(&metricsPortTLS, "metrics-port-tls", false, "Serve the metrics endpoint "+
+		"over TLS, using the server certificate of the cluster")
 
 	return cmd
 }
@@ -185,6 +194,11 @@ func runSubCommand(ctx context.Context, instance *postgres.Instance) error {
 		return err
 	}
 
+	if err = mgr.Add(primaryhealth.NewChecker(instance)); err != nil {
+		setupLog.Error(err, "unable to create primary health checker")
+		return err
+	}
+
 	// onlineUpgradeCtx is a child context of the postgres context.
 	// onlineUpgradeCtx will be the context passed to all the manager handled Runnables via Start(ctx),
 	// its deletion will imply all Runnables to stop, but will be handled
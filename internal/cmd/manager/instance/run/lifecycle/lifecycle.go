@@ -114,7 +114,7 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 					return nil
 				}
 				log.Info("Context has been cancelled, shutting down and exiting")
-				if err := tryShuttingDownSmartFast(i.instance.MaxStopDelay, i.instance); err != nil {
+				if err := tryShuttingDownSmartFast(i.smartShutdownTimeout(i.instance.MaxStopDelay), i.instance); err != nil {
 					log.Error(err, "error shutting down instance, proceeding")
 				}
 				return nil
@@ -126,9 +126,10 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 				// resulting in a data corruption.
 				//
 				// This is why we are trying a smart shutdown for half-time
-				// of our stop delay, and then we proceed.
+				// of our stop delay, unless a different smart shutdown timeout
+				// has been set, and then we proceed.
 				log.Info("Received termination signal", "signal", sig)
-				if err := tryShuttingDownSmartFast(i.instance.MaxStopDelay/2, i.instance); err != nil {
+				if err := tryShuttingDownSmartFast(i.smartShutdownTimeout(i.instance.MaxStopDelay/2), i.instance); err != nil {
 					log.Error(err, "error while shutting down instance, proceeding")
 				}
 				return nil
@@ -156,6 +157,15 @@ func (i *PostgresLifecycle) Start(ctx context.Context) error {
 	}
 }
 
+// smartShutdownTimeout returns the time reserved to the smart shutdown of
+// PostgreSQL, falling back to the passed one when not set in the cluster
+func (i *PostgresLifecycle) smartShutdownTimeout(defaultTimeout int32) int32 {
+	if i.instance.SmartShutdownTimeout > 0 {
+		return i.instance.SmartShutdownTimeout
+	}
+	return defaultTimeout
+}
+
 // handleInstanceCommandRequests execute a command requested by the reconciliation
 // loop.
 func (i *PostgresLifecycle) handleInstanceCommandRequests(
@@ -176,13 +186,13 @@ func (i *PostgresLifecycle) handleInstanceCommandRequests(
 	case postgres.FenceOn:
 		log.Info("Fencing request received, will proceed shutting down the instance")
 		i.instance.SetFencing(true)
-		err := tryShuttingDownSmartFast(i.instance.MaxStopDelay, i.instance)
+		err := tryShuttingDownSmartFast(i.smartShutdownTimeout(i.instance.MaxStopDelay), i.instance)
 		if err != nil {
 			err = fmt.Errorf("while shutting down the instance to fence it: %w", err)
 		}
 		return false, err
 	case postgres.RestartSmartFast:
-		return true, tryShuttingDownSmartFast(i.instance.MaxStopDelay, i.instance)
+		return true, tryShuttingDownSmartFast(i.smartShutdownTimeout(i.instance.MaxStopDelay), i.instance)
 	case postgres.ShutDownFastImmediate:
 		if err := tryShuttingDownFastImmediate(i.instance.MaxSwitchoverDelay, i.instance); err != nil {
 			log.Error(err, "error shutting down instance, proceeding")
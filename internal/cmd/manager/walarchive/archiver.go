@@ -0,0 +1,50 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchive
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// Archiver is implemented by every method the instance manager can use
+// to archive the WAL files of a cluster
+type Archiver interface {
+	// Archive archives the WAL file requested by PostgreSQL, reporting
+	// the outcome in the continuous archiving condition of the cluster
+	Archive(ctx context.Context, walName string) error
+}
+
+// newArchiver creates the Archiver configured in the backup section of
+// the cluster: the custom commands when they are set, barman-cloud otherwise
+func newArchiver(cluster *apiv1.Cluster, pgData string, client client.WithWatch) Archiver {
+	if cluster.Spec.Backup.CustomWalArchive != nil {
+		return &customArchiver{
+			cluster: cluster,
+			client:  client,
+		}
+	}
+
+	return &barmanArchiver{
+		cluster: cluster,
+		pgData:  pgData,
+		client:  client,
+	}
+}
@@ -95,7 +95,6 @@ func NewCmd() *cobra.Command {
 }
 
 func run(ctx context.Context, podName, pgData string, args []string, client client.WithWatch) error {
-	startTime := time.Now()
 	contextLog := log.FromContext(ctx)
 	walName := args[0]
 
@@ -106,7 +105,7 @@ func run(ctx context.Context, podName, pgData string, args []string, client clie
 		return fmt.Errorf("failed to get cluster: %w", err)
 	}
 
-	if cluster.Spec.Backup == nil || cluster.Spec.Backup.BarmanObjectStore == nil {
+	if !cluster.Spec.Backup.IsWalArchivingConfigured() {
 		// Backup not configured, skipping WAL
 		contextLog.Info("Backup not configured, skip WAL archiving",
 			"walName", walName,
@@ -129,6 +128,26 @@ func run(ctx context.Context, podName, pgData string, args []string, client clie
 		}
 	}
 
+	return newArchiver(cluster, pgData, client).Archive(ctx, walName)
+}
+
+// barmanArchiver archives the WAL files in the object store of the
+// cluster with barman-cloud, and is used by default
+type barmanArchiver struct {
+	cluster *apiv1.Cluster
+	pgData  string
+	client  client.WithWatch
+}
+
+// Archive implements the Archiver interface
+func (ba *barmanArchiver) Archive(ctx context.Context, walName string) error {
+	startTime := time.Now()
+	contextLog := log.FromContext(ctx)
+	cluster := ba.cluster
+	pgData := ba.pgData
+	client := ba.client
+
+	var err error
 	maxParallel := 1
 	if cluster.Spec.Backup.BarmanObjectStore.Wal != nil {
 		maxParallel = cluster.Spec.Backup.BarmanObjectStore.Wal.MaxParallel
@@ -299,22 +318,9 @@ func barmanCloudWalArchiveOptions(
 	}
 	configuration := cluster.Spec.Backup.BarmanObjectStore
 
-	var options []string
-	if configuration.Wal != nil {
-		if configuration.Wal.Compression == apiv1.CompressionTypeSnappy && !capabilities.HasSnappy {
-			return nil, fmt.Errorf("snappy compression is not supported in Barman %v", capabilities.Version)
-		}
-		if len(configuration.Wal.Compression) != 0 {
-			options = append(
-				options,
-				fmt.Sprintf("--%v", configuration.Wal.Compression))
-		}
-		if len(configuration.Wal.Encryption) != 0 {
-			options = append(
-				options,
-				"-e",
-				string(configuration.Wal.Encryption))
-		}
+	options, err := getWalConfiguration(nil, configuration, capabilities)
+	if err != nil {
+		return nil, err
 	}
 	if len(configuration.EndpointURL) > 0 {
 		options = append(
@@ -355,6 +361,43 @@ func barmanCloudWalArchiveOptions(
 	return options, nil
 }
 
+// getWalConfiguration gets the configuration in the `Wal` object of the Barman configuration
+func getWalConfiguration(
+	options []string,
+	configuration *apiv1.BarmanObjectStoreConfiguration,
+	capabilities *barmanCapabilities.Capabilities,
+) ([]string, error) {
+	if configuration.Wal == nil {
+		return options, nil
+	}
+
+	if configuration.Wal.Compression == apiv1.CompressionTypeSnappy && !capabilities.HasSnappy {
+		return nil, fmt.Errorf("snappy compression is not supported in Barman %v", capabilities.Version)
+	}
+	if len(configuration.Wal.Compression) != 0 {
+		options = append(
+			options,
+			fmt.Sprintf("--%v", configuration.Wal.Compression))
+	}
+	if len(configuration.Wal.Encryption) != 0 {
+		options = append(
+			options,
+			"-e",
+			string(configuration.Wal.Encryption))
+	}
+	if configuration.Wal.Encryption == apiv1.EncryptionTypeNoneAWSKMS && len(configuration.SSEKMSKeyID) != 0 {
+		if !capabilities.HasSSEKMSKeyID {
+			return nil, fmt.Errorf("the KMS key is not supported in Barman %v", capabilities.Version)
+		}
+		options = append(
+			options,
+			"--sse-kms-key-id",
+			configuration.SSEKMSKeyID)
+	}
+
+	return options, nil
+}
+
 func checkWalArchive(ctx context.Context,
 	cluster *apiv1.Cluster,
 	walArchiver *archiver.WALArchiver,
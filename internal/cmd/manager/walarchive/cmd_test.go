@@ -0,0 +1,65 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchive
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	barmanCapabilities "github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/capabilities"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("barman-cloud-wal-archive WAL options", func() {
+	capabilities := &barmanCapabilities.Capabilities{HasSnappy: true, HasSSEKMSKeyID: true}
+
+	It("passes the KMS key when the WAL files are encrypted with aws:kms", func() {
+		options, err := getWalConfiguration(nil, &apiv1.BarmanObjectStoreConfiguration{
+			Wal: &apiv1.WalBackupConfiguration{
+				Encryption: apiv1.EncryptionTypeNoneAWSKMS,
+			},
+			SSEKMSKeyID: "alias/backups",
+		}, capabilities)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options).To(Equal([]string{
+			"-e", "aws:kms",
+			"--sse-kms-key-id", "alias/backups",
+		}))
+	})
+
+	It("ignores the KMS key when the WAL files aren't encrypted with aws:kms", func() {
+		options, err := getWalConfiguration(nil, &apiv1.BarmanObjectStoreConfiguration{
+			Wal: &apiv1.WalBackupConfiguration{
+				Compression: apiv1.CompressionTypeGzip,
+				Encryption:  apiv1.EncryptionTypeAES256,
+			},
+			SSEKMSKeyID: "alias/backups",
+		}, capabilities)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(options).To(Equal([]string{"--gzip", "-e", "AES256"}))
+	})
+
+	It("complains when Barman doesn't support the KMS key", func() {
+		_, err := getWalConfiguration(nil, &apiv1.BarmanObjectStoreConfiguration{
+			Wal: &apiv1.WalBackupConfiguration{
+				Encryption: apiv1.EncryptionTypeNoneAWSKMS,
+			},
+			SSEKMSKeyID: "alias/backups",
+		}, &barmanCapabilities.Capabilities{})
+		Expect(err).To(HaveOccurred())
+	})
+})
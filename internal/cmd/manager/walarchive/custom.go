@@ -0,0 +1,78 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walarchive
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/walcommand"
+)
+
+// customArchiver archives the WAL files with the custom archive command
+// of the cluster
+type customArchiver struct {
+	cluster *apiv1.Cluster
+	client  client.WithWatch
+}
+
+// Archive implements the Archiver interface
+func (ca *customArchiver) Archive(ctx context.Context, walPath string) error {
+	cluster := ca.cluster
+	client := ca.client
+	contextLog := log.FromContext(ctx)
+	startTime := time.Now()
+	walName := filepath.Base(walPath)
+
+	err := walcommand.Run(ctx, cluster.Spec.Backup.CustomWalArchive.ArchiveCommand, walPath, walName)
+	if err != nil {
+		condition := metav1.Condition{
+			Type:    string(apiv1.ConditionContinuousArchiving),
+			Status:  metav1.ConditionFalse,
+			Reason:  string(apiv1.ConditionReasonContinuousArchivingFailing),
+			Message: err.Error(),
+		}
+		if errCond := conditions.Update(ctx, client, cluster, &condition); errCond != nil {
+			log.Error(errCond, "Error updating wal archiving condition (wal archiving failed)")
+		}
+		return err
+	}
+
+	contextLog.Info("Archived WAL file (custom command)",
+		"walName", walName,
+		"startTime", startTime,
+		"totalTime", time.Since(startTime))
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionContinuousArchiving),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonContinuousArchivingSuccess),
+		Message: "Continuous archiving is working",
+	}
+	if errCond := conditions.Update(ctx, client, cluster, &condition); errCond != nil {
+		log.Error(errCond, "Error while updating wal archiving condition (wal archiving succeeded)")
+	}
+
+	return nil
+}
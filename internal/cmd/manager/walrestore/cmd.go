@@ -98,19 +98,32 @@ func NewCmd() *cobra.Command {
 }
 
 func run(ctx context.Context, podName string, args []string) error {
-	contextLog := log.FromContext(ctx)
-	startTime := time.Now()
 	walName := args[0]
 	destinationPath := args[1]
 
-	var cluster *apiv1.Cluster
-	var err error
-
-	cluster, err = cacheClient.GetCluster()
+	cluster, err := cacheClient.GetCluster()
 	if err != nil {
 		return fmt.Errorf("failed to get cluster: %w", err)
 	}
 
+	return newRestorer(cluster, podName).Restore(ctx, walName, destinationPath)
+}
+
+// barmanRestorer restores the WAL files from the object store of the
+// cluster, or from the one of the source of a replica cluster, with
+// barman-cloud, and is used by default
+type barmanRestorer struct {
+	cluster *apiv1.Cluster
+	podName string
+}
+
+// Restore implements the Restorer interface
+func (br *barmanRestorer) Restore(ctx context.Context, walName, destinationPath string) error {
+	contextLog := log.FromContext(ctx)
+	startTime := time.Now()
+	cluster := br.cluster
+	podName := br.podName
+
 	recoverClusterName, recoverEnv, barmanConfiguration, err := GetRecoverConfiguration(cluster, podName)
 	if errors.Is(err, ErrNoBackupConfigured) {
 		// Backup not configured, skipping WAL
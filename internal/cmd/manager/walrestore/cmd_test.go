@@ -108,3 +108,87 @@ var _ = Describe("Function isStreamingAvailable", func() {
 		Expect(isStreamingAvailable(&cluster, "primaryPod")).To(BeTrue())
 	})
 })
+
+var _ = Describe("Function getCustomRestoreCommand", func() {
+	restoreCommand := []string{"/usr/bin/restore", "%f", "%p"}
+
+	It("returns false when the custom WAL archive is not configured", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{},
+			},
+		}
+		_, isCustom := getCustomRestoreCommand(&cluster, "replicaPod")
+		Expect(isCustom).To(BeFalse())
+	})
+
+	It("returns the custom restore command", func() {
+		cluster := apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "primaryPod",
+			},
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					CustomWalArchive: &apiv1.CustomWalArchiveConfiguration{
+						ArchiveCommand: []string{"/usr/bin/archive", "%p"},
+						RestoreCommand: restoreCommand,
+					},
+				},
+			},
+		}
+		command, isCustom := getCustomRestoreCommand(&cluster, "replicaPod")
+		Expect(isCustom).To(BeTrue())
+		Expect(command).To(Equal(restoreCommand))
+	})
+
+	It("lets the designated primary of a replica cluster restore from the source", func() {
+		cluster := apiv1.Cluster{
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "primaryPod",
+			},
+			Spec: apiv1.ClusterSpec{
+				ReplicaCluster: &apiv1.ReplicaClusterConfiguration{
+					Enabled: true,
+					Source:  "clusterSource",
+				},
+				Backup: &apiv1.BackupConfiguration{
+					CustomWalArchive: &apiv1.CustomWalArchiveConfiguration{
+						ArchiveCommand: []string{"/usr/bin/archive", "%p"},
+						RestoreCommand: restoreCommand,
+					},
+				},
+			},
+		}
+		_, isCustom := getCustomRestoreCommand(&cluster, "primaryPod")
+		Expect(isCustom).To(BeFalse())
+	})
+})
+
+var _ = Describe("Function newRestorer", func() {
+	It("uses barman-cloud by default", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					BarmanObjectStore: &apiv1.BarmanObjectStoreConfiguration{},
+				},
+			},
+		}
+		Expect(newRestorer(&cluster, "replicaPod")).To(BeAssignableToTypeOf(&barmanRestorer{}))
+	})
+
+	It("uses the custom restore command when configured", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Backup: &apiv1.BackupConfiguration{
+					CustomWalArchive: &apiv1.CustomWalArchiveConfiguration{
+						ArchiveCommand: []string{"/usr/bin/archive", "%p"},
+						RestoreCommand: []string{"/usr/bin/restore", "%f", "%p"},
+					},
+				},
+			},
+		}
+		Expect(newRestorer(&cluster, "replicaPod")).To(Equal(&customRestorer{
+			restoreCommand: []string{"/usr/bin/restore", "%f", "%p"},
+		}))
+	})
+})
@@ -0,0 +1,71 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walrestore
+
+import (
+	"context"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/walcommand"
+)
+
+// getCustomRestoreCommand returns the custom command restoring the WAL files
+// of the cluster, and whether the WAL files are archived with the custom
+// commands. The designated primary of a replica cluster always restores the
+// WAL files from the source of the replica cluster instead
+func getCustomRestoreCommand(cluster *apiv1.Cluster, podName string) ([]string, bool) {
+	if cluster.IsReplica() && cluster.Status.CurrentPrimary == podName {
+		return nil, false
+	}
+
+	if cluster.Spec.Backup == nil || cluster.Spec.Backup.CustomWalArchive == nil {
+		return nil, false
+	}
+
+	return cluster.Spec.Backup.CustomWalArchive.RestoreCommand, true
+}
+
+// customRestorer restores the WAL files with the custom restore command
+// of the cluster
+type customRestorer struct {
+	restoreCommand []string
+}
+
+// Restore implements the Restorer interface
+func (cr *customRestorer) Restore(ctx context.Context, walName, destinationPath string) error {
+	contextLog := log.FromContext(ctx)
+
+	if len(cr.restoreCommand) == 0 {
+		contextLog.Trace("Skipping WAL restore, there is no custom restore command",
+			"walName", walName)
+		return ErrNoBackupConfigured
+	}
+
+	startTime := time.Now()
+	if err := walcommand.Run(ctx, cr.restoreCommand, destinationPath, walName); err != nil {
+		return err
+	}
+
+	contextLog.Info("Restored WAL file (custom command)",
+		"walName", walName,
+		"startTime", startTime,
+		"totalTime", time.Since(startTime))
+
+	return nil
+}
@@ -0,0 +1,46 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walrestore
+
+import (
+	"context"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+)
+
+// Restorer is implemented by every method the instance manager can use
+// to restore the WAL files of a cluster
+type Restorer interface {
+	// Restore restores the WAL file requested by PostgreSQL into the
+	// passed destination path, returning ErrNoBackupConfigured when
+	// there is nothing to restore it from
+	Restore(ctx context.Context, walName, destinationPath string) error
+}
+
+// newRestorer creates the Restorer configured in the cluster for the
+// passed instance: the custom commands when they are set, barman-cloud
+// otherwise
+func newRestorer(cluster *apiv1.Cluster, podName string) Restorer {
+	if restoreCommand, isCustom := getCustomRestoreCommand(cluster, podName); isCustom {
+		return &customRestorer{restoreCommand: restoreCommand}
+	}
+
+	return &barmanRestorer{
+		cluster: cluster,
+		podName: podName,
+	}
+}
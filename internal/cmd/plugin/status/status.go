@@ -36,6 +36,7 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/cmd/plugin"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/plugin/resources"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
@@ -539,7 +540,10 @@ func (fullStatus *PostgresqlStatus) printInstancesStatus() {
 			continue
 		}
 		statusMsg := "OK"
-		if instance.PendingRestart {
+		switch {
+		case instance.PendingRestart && len(instance.PendingRestartSettings) > 0:
+			statusMsg += fmt.Sprintf(" (pending restart: %s)", strings.Join(instance.PendingRestartSettings, ", "))
+		case instance.PendingRestart:
 			statusMsg += " (pending restart)"
 		}
 
@@ -577,7 +581,7 @@ func (fullStatus *PostgresqlStatus) printCertificatesStatus() {
 
 	for _, certName := range certNames {
 		expirationDate := certExpirations[certName]
-		expirationTime, err := time.Parse("2006-01-02 15:04:05.999999999 -0700 MST", expirationDate)
+		expirationTime, err := time.Parse(certs.ExpirationDateLayout, expirationDate)
 		if err != nil {
 			fmt.Printf("\n error while parsing the following certificate: %s, date: %s",
 				certName, expirationDate)
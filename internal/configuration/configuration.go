@@ -21,6 +21,7 @@ package configuration
 import (
 	"path"
 	"strings"
+	"time"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/configparser"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -32,6 +33,16 @@ var configurationLog = log.WithName("configuration")
 // DefaultOperatorPullSecretName is implicitly copied into newly created clusters.
 const DefaultOperatorPullSecretName = "cnpg-pull-secret" // #nosec
 
+const (
+	// DefaultCertificateDuration is the default lifetime, in days, of the
+	// certificates generated by the operator
+	DefaultCertificateDuration = 90
+
+	// DefaultExpiringCheckThreshold is the default number of days before
+	// the expiration of a certificate when it is renewed
+	DefaultExpiringCheckThreshold = 7
+)
+
 // Data is the struct containing the configuration of the operator.
 // Usually the operator code will use the "Current" configuration.
 type Data struct {
@@ -84,6 +95,14 @@ type Data struct {
 	// MonitoringQueriesSecret is the name of the secret in the operator namespace which contain
 	// the monitoring queries. The queries will be read from the data key: "queries".
 	MonitoringQueriesSecret string `json:"monitoringQueriesSecret" env:"MONITORING_QUERIES_SECRET"`
+
+	// CertificateDuration is the lifetime, in days, of the certificates
+	// generated by the operator
+	CertificateDuration int `json:"certificateDuration" env:"CERTIFICATE_DURATION"`
+
+	// ExpiringCheckThreshold is the number of days before the expiration
+	// of a certificate generated by the operator when it is renewed
+	ExpiringCheckThreshold int `json:"expiringCheckThreshold" env:"EXPIRING_CHECK_THRESHOLD"`
 }
 
 // Current is the configuration used by the operator
@@ -95,6 +114,8 @@ func newDefaultConfig() *Data {
 		OperatorPullSecretName: DefaultOperatorPullSecretName,
 		OperatorImageName:      versions.DefaultOperatorImageName,
 		PostgresImageName:      versions.DefaultImageName,
+		CertificateDuration:    DefaultCertificateDuration,
+		ExpiringCheckThreshold: DefaultExpiringCheckThreshold,
 	}
 }
 
@@ -123,6 +144,37 @@ func (config *Data) IsLabelInherited(name string) bool {
 	return evaluateGlobPatterns(config.InheritedLabels, name)
 }
 
+// GetCertificateDuration gets the lifetime of the certificates generated
+// by the operator, falling back to the default when the configured one is
+// not positive
+func (config *Data) GetCertificateDuration() time.Duration {
+	duration := config.CertificateDuration
+	if duration <= 0 {
+		duration = DefaultCertificateDuration
+	}
+
+	return time.Duration(duration) * 24 * time.Hour
+}
+
+// GetExpiringCheckThreshold gets how long before their expiration the
+// certificates generated by the operator are renewed. The default is used
+// when the configured threshold is not positive or not lower than the
+// lifetime of the certificates, and it is further lowered to half of the
+// lifetime of short-lived certificates
+func (config *Data) GetExpiringCheckThreshold() time.Duration {
+	duration := config.GetCertificateDuration()
+
+	threshold := time.Duration(config.ExpiringCheckThreshold) * 24 * time.Hour
+	if threshold <= 0 || threshold >= duration {
+		threshold = DefaultExpiringCheckThreshold * 24 * time.Hour
+	}
+	if threshold >= duration {
+		threshold = duration / 2
+	}
+
+	return threshold
+}
+
 // WatchedNamespaces get the list of additional watched namespaces.
 // The result is a list of namespaces specified in the WATCHED_NAMESPACE where
 // each namespace is separated by comma
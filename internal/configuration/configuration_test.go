@@ -17,6 +17,8 @@ limitations under the License.
 package configuration
 
 import (
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 )
@@ -128,3 +130,37 @@ var _ = Describe("Annotation and label inheritance", func() {
 		})
 	})
 })
+
+var _ = Describe("Certificates validity", func() {
+	const day = 24 * time.Hour
+
+	It("uses the defaults when nothing is configured", func() {
+		config := Data{}
+		Expect(config.GetCertificateDuration()).To(Equal(DefaultCertificateDuration * day))
+		Expect(config.GetExpiringCheckThreshold()).To(Equal(DefaultExpiringCheckThreshold * day))
+	})
+
+	It("uses the configured values", func() {
+		config := Data{
+			CertificateDuration:    30,
+			ExpiringCheckThreshold: 3,
+		}
+		Expect(config.GetCertificateDuration()).To(Equal(30 * day))
+		Expect(config.GetExpiringCheckThreshold()).To(Equal(3 * day))
+	})
+
+	It("ignores a threshold which is not lower than the duration", func() {
+		config := Data{
+			CertificateDuration:    10,
+			ExpiringCheckThreshold: 10,
+		}
+		Expect(config.GetExpiringCheckThreshold()).To(Equal(DefaultExpiringCheckThreshold * day))
+	})
+
+	It("halves the duration of short-lived certificates", func() {
+		config := Data{
+			CertificateDuration: 4,
+		}
+		Expect(config.GetExpiringCheckThreshold()).To(Equal(2 * day))
+	})
+})
@@ -0,0 +1,50 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package databases
+
+import (
+	"context"
+)
+
+// DatabaseInfo represents a database in the PostgreSQL instance, with the
+// attributes that can be managed declaratively
+type DatabaseInfo struct {
+	// Name of the database
+	Name string
+
+	// The role owning the database
+	Owner string
+
+	// The character set encoding of the database, an empty
+	// value means the one of the template database
+	Encoding string
+}
+
+// DatabaseManager abstracts the operations that need to be sent to
+// the database instance for the management of databases
+type DatabaseManager interface {
+	// List the databases, excluding the templates
+	List(ctx context.Context) ([]DatabaseInfo, error)
+	// Create the database
+	Create(ctx context.Context, database DatabaseInfo) error
+	// Update the owner of the database
+	UpdateOwner(ctx context.Context, database DatabaseInfo) error
+	// Delete the database
+	Delete(ctx context.Context, database DatabaseInfo) error
+	// CreateExtension installs the extension in the database, if missing
+	CreateExtension(ctx context.Context, database DatabaseInfo, extension string) error
+}
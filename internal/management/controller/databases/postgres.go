@@ -0,0 +1,191 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package databases
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// pooler is an internal interface to pass a connection pooler to NewPostgresDatabaseManager
+type pooler interface {
+	Connection(dbname string) (*sql.DB, error)
+	GetDsn(dbname string) string
+}
+
+// PostgresDatabaseManager is a DatabaseManager for a database instance
+type PostgresDatabaseManager struct {
+	pool pooler
+}
+
+// NewPostgresDatabaseManager returns an implementation of DatabaseManager for postgres
+func NewPostgresDatabaseManager(pool pooler) DatabaseManager {
+	return PostgresDatabaseManager{
+		pool: pool,
+	}
+}
+
+func (sm PostgresDatabaseManager) String() string {
+	return sm.pool.GetDsn("postgres")
+}
+
+// List the databases, excluding the templates
+func (sm PostgresDatabaseManager) List(ctx context.Context) ([]DatabaseInfo, error) {
+	db, err := sm.pool.Connection("postgres")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT datname, pg_catalog.pg_get_userbyid(datdba), pg_catalog.pg_encoding_to_char(encoding)
+            FROM pg_catalog.pg_database WHERE NOT datistemplate`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var databases []DatabaseInfo
+	for rows.Next() {
+		var database DatabaseInfo
+		err := rows.Scan(
+			&database.Name,
+			&database.Owner,
+			&database.Encoding,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		databases = append(databases, database)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return databases, nil
+}
+
+// Create the database
+func (sm PostgresDatabaseManager) Create(ctx context.Context, database DatabaseInfo) error {
+	contextLog := log.FromContext(ctx).WithName("createDatabase")
+	contextLog.Trace("Invoked", "database", database.Name)
+
+	db, err := sm.pool.Connection("postgres")
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf("CREATE DATABASE %s%s",
+		pgx.Identifier{database.Name}.Sanitize(), databaseOptions(database)))
+	if err != nil {
+		return fmt.Errorf("while running CREATE DATABASE %s: %w", database.Name, err)
+	}
+	return nil
+}
+
+// UpdateOwner updates the owner of the database
+func (sm PostgresDatabaseManager) UpdateOwner(ctx context.Context, database DatabaseInfo) error {
+	contextLog := log.FromContext(ctx).WithName("updateDatabaseOwner")
+	contextLog.Trace("Invoked", "database", database.Name)
+
+	db, err := sm.pool.Connection("postgres")
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf("ALTER DATABASE %s OWNER TO %s",
+		pgx.Identifier{database.Name}.Sanitize(), pgx.Identifier{database.Owner}.Sanitize()))
+	if err != nil {
+		return fmt.Errorf("while running ALTER DATABASE %s: %w", database.Name, err)
+	}
+	return nil
+}
+
+// Delete the database
+func (sm PostgresDatabaseManager) Delete(ctx context.Context, database DatabaseInfo) error {
+	contextLog := log.FromContext(ctx).WithName("dropDatabase")
+	contextLog.Trace("Invoked", "database", database.Name)
+
+	db, err := sm.pool.Connection("postgres")
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf("DROP DATABASE %s", pgx.Identifier{database.Name}.Sanitize()))
+	if err != nil {
+		return fmt.Errorf("while running DROP DATABASE %s: %w", database.Name, err)
+	}
+	return nil
+}
+
+// CreateExtension installs the extension in the database, if missing
+func (sm PostgresDatabaseManager) CreateExtension(
+	ctx context.Context,
+	database DatabaseInfo,
+	extension string,
+) error {
+	contextLog := log.FromContext(ctx).WithName("createExtension")
+	contextLog.Trace("Invoked", "database", database.Name, "extension", extension)
+
+	db, err := sm.pool.Connection(database.Name)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %s",
+		pgx.Identifier{extension}.Sanitize()))
+	if err != nil {
+		return fmt.Errorf("while running CREATE EXTENSION %s: %w", extension, err)
+	}
+	return nil
+}
+
+// databaseOptions builds the option list of the CREATE DATABASE
+// command for the passed database
+func databaseOptions(database DatabaseInfo) string {
+	var options []string
+
+	if database.Owner != "" {
+		options = append(options, fmt.Sprintf("OWNER %s", pgx.Identifier{database.Owner}.Sanitize()))
+	}
+
+	if database.Encoding != "" {
+		// The encoding of template1 may be different from the requested one,
+		// while template0 can be used with any encoding
+		options = append(options,
+			"TEMPLATE template0",
+			fmt.Sprintf("ENCODING %s", pq.QuoteLiteral(database.Encoding)))
+	}
+
+	if len(options) == 0 {
+		return ""
+	}
+
+	return " " + strings.Join(options, " ")
+}
@@ -0,0 +1,38 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package databases
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Database options", func() {
+	It("uses the defaults when nothing is specified", func() {
+		Expect(databaseOptions(DatabaseInfo{Name: "app"})).To(BeEmpty())
+	})
+
+	It("quotes the owner", func() {
+		Expect(databaseOptions(DatabaseInfo{Name: "app", Owner: "app owner"})).To(Equal(
+			` OWNER "app owner"`))
+	})
+
+	It("uses template0 when the encoding is specified", func() {
+		Expect(databaseOptions(DatabaseInfo{Name: "app", Owner: "app", Encoding: "LATIN1"})).To(Equal(
+			` OWNER "app" TEMPLATE template0 ENCODING 'LATIN1'`))
+	})
+})
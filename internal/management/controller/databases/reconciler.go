@@ -0,0 +1,166 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package databases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// ReconcileManagedDatabases ensures the databases declared in the managed
+// configuration are present in, or absent from, the instance, and that
+// their extensions are installed.
+// Databases not listed in the configuration are left untouched.
+// The errors raised while reconciling a database, or installing its
+// extensions, don't stop the reconciliation of the other ones, and are
+// returned indexed by database name
+func ReconcileManagedDatabases(
+	ctx context.Context,
+	manager DatabaseManager,
+	managed *apiv1.ManagedConfiguration,
+) (map[string]string, error) {
+	if managed == nil || len(managed.Databases) == 0 {
+		return nil, nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+	contextLogger.Debug("Updating managed databases")
+
+	currentDatabases, err := manager.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("while listing databases: %w", err)
+	}
+
+	existingDatabases := make(map[string]DatabaseInfo, len(currentDatabases))
+	for _, database := range currentDatabases {
+		existingDatabases[database.Name] = database
+	}
+
+	var databasesErrors map[string]string
+	addError := func(databaseName string, message string) {
+		if databasesErrors == nil {
+			databasesErrors = make(map[string]string)
+		}
+		databasesErrors[databaseName] = message
+	}
+
+	for _, databaseConfiguration := range managed.Databases {
+		currentDatabase, found := existingDatabases[databaseConfiguration.Name]
+
+		switch databaseConfiguration.GetEnsure() {
+		case apiv1.EnsureAbsent:
+			if !found {
+				continue
+			}
+			contextLogger.Info("Dropping managed database", "database", databaseConfiguration.Name)
+			if err := manager.Delete(ctx, currentDatabase); err != nil {
+				contextLogger.Info("Cannot drop managed database",
+					"database", databaseConfiguration.Name,
+					"error", err.Error())
+				addError(databaseConfiguration.Name, err.Error())
+			}
+
+		case apiv1.EnsurePresent:
+			expectedDatabase := newDatabaseInfo(databaseConfiguration)
+
+			if err := reconcileDatabase(ctx, manager, expectedDatabase, currentDatabase, found); err != nil {
+				contextLogger.Info("Cannot reconcile managed database",
+					"database", databaseConfiguration.Name,
+					"error", err.Error())
+				addError(databaseConfiguration.Name, err.Error())
+				continue
+			}
+
+			if extensionsError := reconcileExtensions(
+				ctx, manager, expectedDatabase, databaseConfiguration.Extensions,
+			); extensionsError != "" {
+				addError(databaseConfiguration.Name, extensionsError)
+			}
+		}
+	}
+
+	return databasesErrors, nil
+}
+
+// reconcileDatabase creates the database when missing, or updates its owner
+func reconcileDatabase(
+	ctx context.Context,
+	manager DatabaseManager,
+	expectedDatabase DatabaseInfo,
+	currentDatabase DatabaseInfo,
+	found bool,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	if !found {
+		contextLogger.Info("Creating managed database", "database", expectedDatabase.Name)
+		return manager.Create(ctx, expectedDatabase)
+	}
+
+	if expectedDatabase.Encoding != "" &&
+		!strings.EqualFold(expectedDatabase.Encoding, currentDatabase.Encoding) {
+		contextLogger.Info("The encoding of an existing database cannot be changed, ignoring it",
+			"database", expectedDatabase.Name,
+			"encoding", currentDatabase.Encoding,
+			"requestedEncoding", expectedDatabase.Encoding)
+	}
+
+	if currentDatabase.Owner == expectedDatabase.Owner {
+		return nil
+	}
+	contextLogger.Info("Updating the owner of managed database",
+		"database", expectedDatabase.Name,
+		"owner", expectedDatabase.Owner)
+	return manager.UpdateOwner(ctx, expectedDatabase)
+}
+
+// reconcileExtensions installs the missing extensions in the database,
+// returning a description of the ones that couldn't be installed
+func reconcileExtensions(
+	ctx context.Context,
+	manager DatabaseManager,
+	database DatabaseInfo,
+	extensions []string,
+) string {
+	contextLogger := log.FromContext(ctx)
+
+	var extensionsErrors []string
+	for _, extension := range extensions {
+		if err := manager.CreateExtension(ctx, database, extension); err != nil {
+			contextLogger.Info("Cannot install the extension of managed database",
+				"database", database.Name,
+				"extension", extension,
+				"error", err.Error())
+			extensionsErrors = append(extensionsErrors, err.Error())
+		}
+	}
+
+	return strings.Join(extensionsErrors, "; ")
+}
+
+// newDatabaseInfo creates the instance representation of a database configuration
+func newDatabaseInfo(databaseConfiguration apiv1.DatabaseConfiguration) DatabaseInfo {
+	return DatabaseInfo{
+		Name:     databaseConfiguration.Name,
+		Owner:    databaseConfiguration.Owner,
+		Encoding: databaseConfiguration.Encoding,
+	}
+}
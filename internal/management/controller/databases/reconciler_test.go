@@ -0,0 +1,192 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package databases
+
+import (
+	"context"
+	"fmt"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakeDatabaseManager struct {
+	databases  map[string]DatabaseInfo
+	updated    []string
+	extensions map[string][]string
+	// the databases whose creation or deletion fails
+	failing map[string]bool
+}
+
+func (fk *fakeDatabaseManager) List(ctx context.Context) ([]DatabaseInfo, error) {
+	result := make([]DatabaseInfo, 0, len(fk.databases))
+	for _, database := range fk.databases {
+		result = append(result, database)
+	}
+	return result, nil
+}
+
+func (fk *fakeDatabaseManager) Create(ctx context.Context, database DatabaseInfo) error {
+	if fk.failing[database.Name] {
+		return fmt.Errorf("cannot create database %q", database.Name)
+	}
+	fk.databases[database.Name] = database
+	return nil
+}
+
+func (fk *fakeDatabaseManager) UpdateOwner(ctx context.Context, database DatabaseInfo) error {
+	current := fk.databases[database.Name]
+	current.Owner = database.Owner
+	fk.databases[database.Name] = current
+	fk.updated = append(fk.updated, database.Name)
+	return nil
+}
+
+func (fk *fakeDatabaseManager) Delete(ctx context.Context, database DatabaseInfo) error {
+	if fk.failing[database.Name] {
+		return fmt.Errorf("database %q is being accessed by other users", database.Name)
+	}
+	delete(fk.databases, database.Name)
+	return nil
+}
+
+func (fk *fakeDatabaseManager) CreateExtension(ctx context.Context, database DatabaseInfo, extension string) error {
+	if extension == "missing" {
+		return fmt.Errorf("extension %q is not available", extension)
+	}
+	fk.extensions[database.Name] = append(fk.extensions[database.Name], extension)
+	return nil
+}
+
+var _ = Describe("Managed databases reconciliation", func() {
+	var manager *fakeDatabaseManager
+
+	BeforeEach(func() {
+		manager = &fakeDatabaseManager{
+			databases: map[string]DatabaseInfo{
+				"postgres": {Name: "postgres", Owner: "postgres", Encoding: "UTF8"},
+				"app":      {Name: "app", Owner: "app", Encoding: "UTF8"},
+			},
+			extensions: map[string][]string{},
+		}
+	})
+
+	It("does nothing without a managed configuration", func() {
+		Expect(ReconcileManagedDatabases(context.TODO(), manager, nil)).Error().ToNot(HaveOccurred())
+		Expect(manager.databases).To(HaveLen(2))
+	})
+
+	It("creates the missing databases", func() {
+		managed := &apiv1.ManagedConfiguration{
+			Databases: []apiv1.DatabaseConfiguration{
+				{Name: "reporting", Owner: "reporter", Encoding: "LATIN1"},
+			},
+		}
+
+		Expect(ReconcileManagedDatabases(context.TODO(), manager, managed)).To(BeEmpty())
+		Expect(manager.databases).To(HaveKey("reporting"))
+		Expect(manager.databases["reporting"].Owner).To(Equal("reporter"))
+		Expect(manager.databases["reporting"].Encoding).To(Equal("LATIN1"))
+	})
+
+	It("updates the owner of the existing databases", func() {
+		managed := &apiv1.ManagedConfiguration{
+			Databases: []apiv1.DatabaseConfiguration{
+				{Name: "app", Owner: "developer"},
+			},
+		}
+
+		Expect(ReconcileManagedDatabases(context.TODO(), manager, managed)).To(BeEmpty())
+		Expect(manager.updated).To(ConsistOf("app"))
+		Expect(manager.databases["app"].Owner).To(Equal("developer"))
+	})
+
+	It("leaves alone the databases which are already in sync", func() {
+		managed := &apiv1.ManagedConfiguration{
+			Databases: []apiv1.DatabaseConfiguration{
+				{Name: "app", Owner: "app", Encoding: "utf8"},
+			},
+		}
+
+		Expect(ReconcileManagedDatabases(context.TODO(), manager, managed)).To(BeEmpty())
+		Expect(manager.updated).To(BeEmpty())
+	})
+
+	It("drops the databases that should be absent", func() {
+		managed := &apiv1.ManagedConfiguration{
+			Databases: []apiv1.DatabaseConfiguration{
+				{Name: "app", Ensure: apiv1.EnsureAbsent},
+				{Name: "missing", Ensure: apiv1.EnsureAbsent},
+			},
+		}
+
+		Expect(ReconcileManagedDatabases(context.TODO(), manager, managed)).To(BeEmpty())
+		Expect(manager.databases).ToNot(HaveKey("app"))
+		Expect(manager.databases).To(HaveKey("postgres"))
+	})
+
+	It("installs the extensions of the databases", func() {
+		managed := &apiv1.ManagedConfiguration{
+			Databases: []apiv1.DatabaseConfiguration{
+				{Name: "app", Owner: "app", Extensions: []string{"pgcrypto", "uuid-ossp"}},
+				{Name: "reporting", Owner: "reporter", Extensions: []string{"pgcrypto"}},
+			},
+		}
+
+		Expect(ReconcileManagedDatabases(context.TODO(), manager, managed)).To(BeEmpty())
+		Expect(manager.extensions["app"]).To(ConsistOf("pgcrypto", "uuid-ossp"))
+		Expect(manager.extensions["reporting"]).To(ConsistOf("pgcrypto"))
+	})
+
+	It("reports the extensions which cannot be installed", func() {
+		managed := &apiv1.ManagedConfiguration{
+			Databases: []apiv1.DatabaseConfiguration{
+				{Name: "app", Owner: "app", Extensions: []string{"missing", "pgcrypto"}},
+				{Name: "reporting", Owner: "reporter", Extensions: []string{"pgcrypto"}},
+			},
+		}
+
+		databasesErrors, err := ReconcileManagedDatabases(context.TODO(), manager, managed)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(databasesErrors).To(HaveLen(1))
+		Expect(databasesErrors["app"]).To(ContainSubstring("missing"))
+		Expect(manager.extensions["app"]).To(ConsistOf("pgcrypto"))
+		Expect(manager.extensions["reporting"]).To(ConsistOf("pgcrypto"))
+	})
+
+	It("reports the databases which cannot be created or dropped, reconciling the other ones", func() {
+		manager.failing = map[string]bool{"app": true, "reporting": true}
+		managed := &apiv1.ManagedConfiguration{
+			Databases: []apiv1.DatabaseConfiguration{
+				{Name: "app", Ensure: apiv1.EnsureAbsent},
+				{Name: "reporting", Owner: "reporter", Extensions: []string{"pgcrypto"}},
+				{Name: "inventory", Owner: "inventory"},
+			},
+		}
+
+		databasesErrors, err := ReconcileManagedDatabases(context.TODO(), manager, managed)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(databasesErrors).To(HaveLen(2))
+		Expect(databasesErrors["app"]).To(ContainSubstring("other users"))
+		Expect(databasesErrors["reporting"]).To(ContainSubstring("cannot create"))
+		Expect(manager.databases).To(HaveKey("app"))
+		Expect(manager.databases).To(HaveKey("inventory"))
+		Expect(manager.extensions).ToNot(HaveKey("reporting"))
+	})
+})
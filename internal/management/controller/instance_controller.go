@@ -24,6 +24,7 @@ import (
 	"math"
 	"path"
 	"path/filepath"
+	"reflect"
 	"strconv"
 	"time"
 
@@ -40,6 +41,8 @@ import (
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/controllers"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/databases"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/roles"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/infrastructure"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/controller/slots/reconciler"
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/utils"
@@ -170,6 +173,9 @@ func (r *InstanceReconciler) Reconcile(
 
 	r.configureSlotReplicator(cluster)
 
+	// the checker itself takes care of skipping the check on the replicas
+	r.instance.ConfigurePrimaryHealthChecker(cluster.Spec.PrimaryHealthCheck)
+
 	if result, err := reconciler.ReconcileReplicationSlots(
 		ctx,
 		r.instance.PodName,
@@ -201,6 +207,14 @@ func (r *InstanceReconciler) Reconcile(
 	}
 	restarted = restarted || restartedInplace
 
+	if err := r.reconcileReplayPause(cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot reconcile the WAL replay pause: %w", err)
+	}
+
+	if err := r.reconcileTimelineDivergence(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot check the timeline divergence: %w", err)
+	}
+
 	// from now on the database can be assumed as running
 
 	if reloadNeeded && !restarted {
@@ -217,10 +231,22 @@ func (r *InstanceReconciler) Reconcile(
 		return reconcile.Result{}, fmt.Errorf("while updating database owner password: %w", err)
 	}
 
+	if err := r.reconcileManagedRoles(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot reconcile managed roles: %w", err)
+	}
+
+	if err := r.reconcileManagedDatabases(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot reconcile managed databases: %w", err)
+	}
+
 	if err := r.reconcileDatabases(ctx, cluster); err != nil {
 		return reconcile.Result{}, fmt.Errorf("cannot reconcile database configurations: %w", err)
 	}
 
+	if err := r.triggerFirstWALArchive(ctx, cluster); err != nil {
+		return reconcile.Result{}, fmt.Errorf("cannot trigger the archiving of the first WAL file: %w", err)
+	}
+
 	// Extremely important.
 	// It could happen that current primary is reconciled before all the topology is extracted by the operator.
 	// We should detect that and schedule the instance manager for another run otherwise we will end up having
@@ -275,6 +301,12 @@ func (r *InstanceReconciler) refreshConfigurationFiles(
 		return false, err
 	}
 
+	reloadIdent, err := r.instance.RefreshPGIdent(cluster)
+	if err != nil {
+		return false, err
+	}
+	reloadNeeded = reloadNeeded || reloadIdent
+
 	// Reconcile PostgreSQL configuration
 	// This doesn't need the PG connection, but it needs to reload it in case of changes
 	reloadConfig, err := r.instance.RefreshConfigurationFilesFromCluster(cluster)
@@ -310,6 +342,91 @@ func (r *InstanceReconciler) reconcileFencing(cluster *apiv1.Cluster) *reconcile
 	return nil
 }
 
+// reconcileTimelineDivergence detects if this instance is a replica on a
+// timeline that the primary has never reached, i.e. its data diverged
+// from the one of the primary
+func (r *InstanceReconciler) reconcileTimelineDivergence(ctx context.Context, cluster *apiv1.Cluster) error {
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+
+	if isPrimary {
+		r.instance.SetTimelineDiverged(false)
+		return nil
+	}
+
+	superUserDB, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	// A replica streaming from the primary is following its timeline. This
+	// prevents us from relying on the timeline stored in the cluster status
+	// when it is not updated yet, as it happens right after a failover
+	var timelineID int
+	var isStreaming bool
+	row := superUserDB.QueryRow(
+		"SELECT timeline_id, " +
+			"EXISTS (SELECT 1 FROM pg_stat_wal_receiver WHERE status = 'streaming') " +
+			"FROM pg_control_checkpoint()")
+	if err := row.Scan(&timelineID, &isStreaming); err != nil {
+		return err
+	}
+
+	diverged := !isStreaming && cluster.IsTimelineDiverged(cluster.Status.TimelineID, timelineID)
+	if diverged && !r.instance.IsTimelineDiverged() {
+		log.FromContext(ctx).Warning("The timeline of this replica diverged from the one of the primary, "+
+			"refusing to serve traffic",
+			"timelineID", timelineID,
+			"primaryTimelineID", cluster.Status.TimelineID)
+	}
+
+	r.instance.SetTimelineDiverged(diverged)
+	return nil
+}
+
+// reconcileReplayPause pauses or resumes the WAL replay of this instance,
+// as requested by the replayPausedInstances annotation of the cluster
+func (r *InstanceReconciler) reconcileReplayPause(cluster *apiv1.Cluster) error {
+	isPrimary, err := r.instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+
+	if isPrimary {
+		// A primary has no WAL to replay, this can happen when a
+		// paused replica has been promoted
+		return nil
+	}
+
+	superUserDB, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	var replayPaused bool
+	row := superUserDB.QueryRow("SELECT pg_is_wal_replay_paused()")
+	if err := row.Scan(&replayPaused); err != nil {
+		return err
+	}
+
+	pauseRequired := cluster.IsInstanceReplayPaused(r.instance.PodName)
+	if pauseRequired == replayPaused {
+		return nil
+	}
+
+	if pauseRequired {
+		log.Info("Pausing the WAL replay as requested")
+		_, err = superUserDB.Exec("SELECT pg_wal_replay_pause()")
+	} else {
+		log.Info("Resuming the WAL replay as requested")
+		_, err = superUserDB.Exec("SELECT pg_wal_replay_resume()")
+	}
+
+	return err
+}
+
 func handleErrNextLoop(err error) (reconcile.Result, error) {
 	if errors.Is(err, controllers.ErrNextLoop) {
 		return reconcile.Result{RequeueAfter: time.Second}, nil
@@ -319,6 +436,13 @@ func handleErrNextLoop(err error) (reconcile.Result, error) {
 
 // initialize will handle initialization tasks
 func (r *InstanceReconciler) initialize(ctx context.Context, cluster *apiv1.Cluster) error {
+	// we refuse to start PostgreSQL on top of a corrupted data directory
+	if cluster.Spec.PostgresConfiguration.VerifyChecksumsOnStart {
+		if err := r.instance.VerifyDataChecksums(); err != nil {
+			return err
+		}
+	}
+
 	// we check there are no parameters that would prevent a follower to start
 	if err := r.verifyParametersForFollower(cluster); err != nil {
 		return err
@@ -425,6 +549,15 @@ func (r *InstanceReconciler) reconcileOldPrimary(
 		}
 	}
 
+	// During a switchover the target primary is still streaming from us:
+	// give it a chance to receive the WAL we have written before being
+	// shut down, without exceeding the time reserved to the switchover
+	switchoverDelay := time.Duration(cluster.GetMaxSwitchoverDelay()) * time.Second
+	err = r.instance.WaitForReplicaCatchUp(ctx, cluster.Status.TargetPrimary, switchoverDelay)
+	if err != nil {
+		contextLogger.Error(err, "Error while waiting for the target primary to catch up")
+	}
+
 	contextLogger.Info("This is an old primary node. Shutting it down to get it demoted to a replica")
 
 	// Here we need to invoke a fast shutdown on the instance, and wait the instance
@@ -870,6 +1003,7 @@ func (r *InstanceReconciler) reconcileInstance(cluster *apiv1.Cluster) {
 	r.instance.PgCtlTimeoutForPromotion = cluster.GetPgCtlTimeoutForPromotion()
 	r.instance.MaxSwitchoverDelay = cluster.GetMaxSwitchoverDelay()
 	r.instance.MaxStopDelay = cluster.GetMaxStopDelay()
+	r.instance.SmartShutdownTimeout = cluster.Spec.SmartShutdownTimeout
 }
 
 func (r *InstanceReconciler) reconcileCheckWalArchiveFile(cluster *apiv1.Cluster) error {
@@ -937,7 +1071,7 @@ func (r *InstanceReconciler) waitForConfigurationReload(ctx context.Context, clu
 			"to complete the rolling update",
 			"cluster", cluster.Name, "primaryPod", status.Pod.Name, "reason", reason)
 		phase = apiv1.PhaseWaitingForUser
-		phaseReason = "User must issue a supervised switchover"
+		phaseReason = apiv1.GetPrimaryRestartRequiredReason(status.Pod.Name, reason)
 	}
 	if phase == apiv1.PhaseApplyingConfiguration &&
 		(cluster.Status.Phase == apiv1.PhaseApplyingConfiguration ||
@@ -1257,6 +1391,193 @@ func (r *InstanceReconciler) reconcileUser(ctx context.Context, username string,
 	return err
 }
 
+// triggerFirstWALArchive switches to a new WAL file in the primary when the
+// operator is waiting for the first WAL file to be archived and no WAL file
+// has been archived yet, so that the bootstrap can be completed
+func (r *InstanceReconciler) triggerFirstWALArchive(ctx context.Context, cluster *apiv1.Cluster) error {
+	if cluster.Status.Phase != apiv1.PhaseWaitingForFirstArchive || !cluster.ShouldWaitForFirstArchive() {
+		return nil
+	}
+
+	primary, err := r.instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+	if !primary {
+		return nil
+	}
+
+	db, err := r.instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	var nothingArchived bool
+	row := db.QueryRowContext(ctx, "SELECT last_archived_wal IS NULL FROM pg_catalog.pg_stat_archiver")
+	if err := row.Scan(&nothingArchived); err != nil {
+		return err
+	}
+	if !nothingArchived {
+		return nil
+	}
+
+	// This has no effect when no WAL has been written since the last switch,
+	// so we don't risk generating empty WAL files in the next reconciliation loops
+	log.FromContext(ctx).Info("Triggering the first WAL file to be archived")
+	_, err = db.ExecContext(ctx, "SELECT pg_catalog.pg_switch_wal()")
+	return err
+}
+
+// reconcileManagedRoles applies the roles declared in the managed section
+// of the cluster to the primary instance
+func (r *InstanceReconciler) reconcileManagedRoles(ctx context.Context, cluster *apiv1.Cluster) error {
+	if cluster.Spec.Managed == nil || len(cluster.Spec.Managed.Roles) == 0 {
+		return nil
+	}
+
+	// In a replica cluster the roles are replicated from the source cluster
+	if cluster.IsReplica() {
+		return nil
+	}
+
+	primary, err := r.instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+	if !primary {
+		return nil
+	}
+
+	passwords, secretVersions, err := r.getManagedRolesPasswords(ctx, cluster.Spec.Managed.Roles)
+	if err != nil {
+		return err
+	}
+
+	err = roles.ReconcileManagedRoles(
+		ctx,
+		roles.NewPostgresRoleManager(r.instance.ConnectionPool()),
+		cluster.Spec.Managed,
+		passwords,
+	)
+	if err != nil {
+		return err
+	}
+
+	// The passwords have been applied, we don't need to set them again
+	// until the secrets change
+	for name, version := range secretVersions {
+		r.secretVersions[name] = version
+	}
+
+	return nil
+}
+
+// reconcileManagedDatabases applies the databases declared in the managed
+// section of the cluster to the primary instance
+func (r *InstanceReconciler) reconcileManagedDatabases(ctx context.Context, cluster *apiv1.Cluster) error {
+	// The errors are reported by the current primary only, otherwise
+	// the other instances would clear them
+	if cluster.Status.CurrentPrimary != r.instance.PodName {
+		return nil
+	}
+
+	// In a replica cluster the databases are replicated from the source cluster.
+	// In both cases, the errors reported for the databases which were
+	// previously managed are not relevant anymore
+	if cluster.Spec.Managed == nil || len(cluster.Spec.Managed.Databases) == 0 || cluster.IsReplica() {
+		return r.updateManagedDatabasesErrors(ctx, cluster, nil)
+	}
+
+	primary, err := r.instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+	if !primary {
+		return nil
+	}
+
+	databasesErrors, err := databases.ReconcileManagedDatabases(
+		ctx,
+		databases.NewPostgresDatabaseManager(r.instance.ConnectionPool()),
+		cluster.Spec.Managed,
+	)
+	if err != nil {
+		return err
+	}
+
+	return r.updateManagedDatabasesErrors(ctx, cluster, databasesErrors)
+}
+
+// updateManagedDatabasesErrors reports the errors raised while reconciling
+// the managed databases in the cluster status, if they changed
+func (r *InstanceReconciler) updateManagedDatabasesErrors(
+	ctx context.Context,
+	cluster *apiv1.Cluster,
+	databasesErrors map[string]string,
+) error {
+	if len(databasesErrors) == 0 && len(cluster.Status.ManagedDatabasesErrors) == 0 {
+		return nil
+	}
+
+	if reflect.DeepEqual(databasesErrors, cluster.Status.ManagedDatabasesErrors) {
+		return nil
+	}
+
+	oldCluster := cluster.DeepCopy()
+	cluster.Status.ManagedDatabasesErrors = databasesErrors
+	return r.client.Status().Patch(ctx, cluster, client.MergeFrom(oldCluster))
+}
+
+// getManagedRolesPasswords reads the passwords of the managed roles from
+// their secrets, returning them together with the secret versions
+func (r *InstanceReconciler) getManagedRolesPasswords(
+	ctx context.Context,
+	roleConfigurations []apiv1.RoleConfiguration,
+) (map[string]roles.RolePassword, map[string]string, error) {
+	contextLogger := log.FromContext(ctx)
+
+	passwords := make(map[string]roles.RolePassword)
+	secretVersions := make(map[string]string)
+	for _, roleConfiguration := range roleConfigurations {
+		if roleConfiguration.PasswordSecret == nil || roleConfiguration.GetEnsure() != apiv1.EnsurePresent {
+			continue
+		}
+
+		var secret corev1.Secret
+		err := r.GetClient().Get(
+			ctx,
+			client.ObjectKey{Namespace: r.instance.Namespace, Name: roleConfiguration.PasswordSecret.Name},
+			&secret)
+		if apierrors.IsNotFound(err) {
+			contextLogger.Info("Password secret for managed role not found, skipping password",
+				"role", roleConfiguration.Name,
+				"secret", roleConfiguration.PasswordSecret.Name)
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		username, password, err := utils.GetUserPasswordFromSecret(&secret)
+		if err != nil {
+			return nil, nil, fmt.Errorf("while reading the password of managed role %v: %w",
+				roleConfiguration.Name, err)
+		}
+		if username != roleConfiguration.Name {
+			return nil, nil, fmt.Errorf("wrong username '%v' in secret, expected '%v'",
+				username, roleConfiguration.Name)
+		}
+
+		passwords[roleConfiguration.Name] = roles.RolePassword{
+			Value:   password,
+			Changed: r.secretVersions[secret.Name] != secret.ResourceVersion,
+		}
+		secretVersions[secret.Name] = secret.ResourceVersion
+	}
+
+	return passwords, secretVersions, nil
+}
+
 func (r *InstanceReconciler) disableSuperuserPassword(tx *sql.Tx) error {
 	_, err := tx.Exec("ALTER ROLE postgres WITH PASSWORD NULL")
 	return err
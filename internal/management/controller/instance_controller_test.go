@@ -0,0 +1,103 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("managed databases errors", func() {
+	var (
+		ctx        context.Context
+		cluster    *apiv1.Cluster
+		fakeClient client.Client
+		reconciler *InstanceReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.TODO()
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Managed: &apiv1.ManagedConfiguration{
+					Databases: []apiv1.DatabaseConfiguration{
+						{Name: "reporting", Owner: "reporter"},
+					},
+				},
+			},
+			Status: apiv1.ClusterStatus{
+				CurrentPrimary: "cluster-example-1",
+				ManagedDatabasesErrors: map[string]string{
+					"reporting": "cannot create database",
+				},
+			},
+		}
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			Build()
+		reconciler = &InstanceReconciler{
+			client:   fakeClient,
+			instance: &postgres.Instance{PodName: "cluster-example-1"},
+		}
+	})
+
+	expectNoErrorsStored := func() {
+		var storedCluster apiv1.Cluster
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &storedCluster)).To(Succeed())
+		Expect(storedCluster.Status.ManagedDatabasesErrors).To(BeEmpty())
+	}
+
+	It("clears the errors when the databases are not managed anymore", func() {
+		cluster.Spec.Managed.Databases = nil
+		Expect(reconciler.reconcileManagedDatabases(ctx, cluster)).To(Succeed())
+		expectNoErrorsStored()
+	})
+
+	It("clears the errors when the cluster becomes a replica", func() {
+		cluster.Spec.ReplicaCluster = &apiv1.ReplicaClusterConfiguration{
+			Enabled: true,
+			Source:  "origin",
+		}
+		Expect(reconciler.reconcileManagedDatabases(ctx, cluster)).To(Succeed())
+		expectNoErrorsStored()
+	})
+
+	It("leaves the errors to the current primary", func() {
+		reconciler.instance.PodName = "cluster-example-2"
+		cluster.Spec.Managed.Databases = nil
+		Expect(reconciler.reconcileManagedDatabases(ctx, cluster)).To(Succeed())
+
+		var storedCluster apiv1.Cluster
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &storedCluster)).To(Succeed())
+		Expect(storedCluster.Status.ManagedDatabasesErrors).To(HaveKey("reporting"))
+	})
+})
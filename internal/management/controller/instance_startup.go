@@ -26,6 +26,7 @@ import (
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/controllers"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	postgresSpec "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	pkgUtils "github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
@@ -210,11 +211,11 @@ func (r *InstanceReconciler) verifyPgDataCoherenceForPrimary(
 			return err
 		}
 
-		tag := pkgUtils.GetImageTag(cluster.GetImageName())
-		pgMajorVersion, err := postgresSpec.GetPostgresMajorVersionFromTag(tag)
+		pgVersion, err := postgres.GetPostgresVersion(cluster)
 		if err != nil {
 			return err
 		}
+		pgMajorVersion := pgVersion / 10000
 
 		// Clean up any stale pid file before executing pg_rewind
 		err = r.instance.CleanUpStalePid()
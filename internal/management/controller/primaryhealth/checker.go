@@ -0,0 +1,162 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package primaryhealth
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+)
+
+// errHealthCheckFailed is returned when the health check query doesn't return true
+var errHealthCheckFailed = errors.New("the health check query didn't return true")
+
+// A Checker is a runner that periodically executes the health check query
+// on the primary, marking it as not healthy after repeated failures
+type Checker struct {
+	instance *postgres.Instance
+
+	// the number of consecutive failures of the health check
+	failures int
+}
+
+// NewChecker creates a new primary health Checker
+func NewChecker(instance *postgres.Instance) *Checker {
+	return &Checker{
+		instance: instance,
+	}
+}
+
+// Start starts running the primary health Checker
+func (c *Checker) Start(ctx context.Context) error {
+	contextLog := log.FromContext(ctx).WithName("PrimaryHealthChecker")
+	go func() {
+		var config *apiv1.PrimaryHealthCheckConfiguration
+		var period time.Duration
+		ticker := time.NewTicker(apiv1.DefaultPrimaryHealthCheckPeriod * time.Second)
+		// the ticker is started once the check is configured
+		ticker.Stop()
+
+		defer func() {
+			ticker.Stop()
+			contextLog.Info("Terminated primary health Checker loop")
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case config = <-c.instance.PrimaryHealthCheckerChan():
+				// If the check is disabled stop the timer, the process
+				// will resume when the check is configured again
+				if config == nil {
+					ticker.Stop()
+					// we set period to 0 to make sure the Ticker will be reset
+					// if the check is enabled again
+					period = 0
+					c.record(contextLog, nil, 0)
+					continue
+				}
+
+				// Update the ticker if the period has changed
+				if newPeriod := config.GetPeriod(); newPeriod != period {
+					ticker.Reset(newPeriod)
+					period = newPeriod
+				}
+				continue
+			case <-ticker.C:
+			}
+
+			if config == nil {
+				continue
+			}
+
+			c.check(ctx, contextLog, config)
+		}
+	}()
+	<-ctx.Done()
+	return nil
+}
+
+// check executes the health check when this instance is a running primary
+func (c *Checker) check(
+	ctx context.Context,
+	contextLog log.Logger,
+	config *apiv1.PrimaryHealthCheckConfiguration,
+) {
+	// The failures of an instance which is expected to be down, or which
+	// isn't the primary, must not trigger a failover
+	if c.instance.MightBeUnavailable() {
+		c.record(contextLog, nil, 0)
+		return
+	}
+	if isPrimary, err := c.instance.IsPrimary(); err != nil || !isPrimary {
+		c.record(contextLog, nil, 0)
+		return
+	}
+
+	err := c.runQuery(ctx, config)
+	if err != nil {
+		contextLog.Warning("primary health check failed", "err", err)
+	}
+	c.record(contextLog, err, config.GetFailureThreshold())
+}
+
+// runQuery executes the health check query, which must return true
+func (c *Checker) runQuery(ctx context.Context, config *apiv1.PrimaryHealthCheckConfiguration) error {
+	db, err := c.instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	queryCtx, cancel := context.WithTimeout(ctx, config.GetTimeout())
+	defer cancel()
+
+	var healthy bool
+	if err := db.QueryRowContext(queryCtx, config.Query).Scan(&healthy); err != nil {
+		return err
+	}
+	if !healthy {
+		return errHealthCheckFailed
+	}
+
+	return nil
+}
+
+// record keeps track of the consecutive failures of the health check,
+// marking the primary as not healthy once they reach the threshold
+func (c *Checker) record(contextLog log.Logger, err error, threshold int) {
+	if err == nil {
+		c.failures = 0
+		if c.instance.IsPrimaryUnhealthy() {
+			contextLog.Info("primary health check not failing anymore")
+			c.instance.SetPrimaryUnhealthy(false)
+		}
+		return
+	}
+
+	c.failures++
+	if c.failures >= threshold && !c.instance.IsPrimaryUnhealthy() {
+		contextLog.Info("primary health check failed repeatedly, marking the primary as not healthy",
+			"failures", c.failures)
+		c.instance.SetPrimaryUnhealthy(true)
+	}
+}
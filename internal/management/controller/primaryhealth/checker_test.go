@@ -0,0 +1,64 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package primaryhealth
+
+import (
+	"errors"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Primary health checker", func() {
+	var (
+		instance *postgres.Instance
+		checker  *Checker
+	)
+	errQuery := errors.New("query failed")
+	contextLog := log.GetLogger()
+
+	BeforeEach(func() {
+		instance = postgres.NewInstance()
+		checker = NewChecker(instance)
+	})
+
+	It("marks the primary as not healthy only after repeated failures", func() {
+		checker.record(contextLog, errQuery, 3)
+		checker.record(contextLog, errQuery, 3)
+		Expect(instance.IsPrimaryUnhealthy()).To(BeFalse())
+
+		checker.record(contextLog, errQuery, 3)
+		Expect(instance.IsPrimaryUnhealthy()).To(BeTrue())
+	})
+
+	It("resets the failures when the check succeeds", func() {
+		checker.record(contextLog, errQuery, 2)
+		checker.record(contextLog, nil, 2)
+		checker.record(contextLog, errQuery, 2)
+		Expect(instance.IsPrimaryUnhealthy()).To(BeFalse())
+
+		checker.record(contextLog, errQuery, 2)
+		Expect(instance.IsPrimaryUnhealthy()).To(BeTrue())
+
+		checker.record(contextLog, nil, 2)
+		Expect(instance.IsPrimaryUnhealthy()).To(BeFalse())
+		Expect(checker.failures).To(BeZero())
+	})
+})
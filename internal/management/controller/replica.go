@@ -60,7 +60,13 @@ func (r *InstanceReconciler) refreshReplicaConfiguration(
 
 func (r *InstanceReconciler) writeReplicaConfigurationForReplica(cluster *apiv1.Cluster) (changed bool, err error) {
 	slotName := cluster.GetSlotNameFromInstanceName(r.instance.PodName)
-	return postgres.UpdateReplicaConfiguration(r.instance.PgData, r.instance.GetPrimaryConnInfo(), slotName)
+	changed, err = postgres.UpdateReplicaConfiguration(r.instance.PgData, r.instance.GetPrimaryConnInfo(), slotName)
+	if err != nil {
+		return changed, err
+	}
+
+	settingsChanged, err := r.writeStandbySettings(cluster)
+	return changed || settingsChanged, err
 }
 
 func (r *InstanceReconciler) writeReplicaConfigurationForDesignatedPrimary(
@@ -84,5 +90,17 @@ func (r *InstanceReconciler) writeReplicaConfigurationForDesignatedPrimary(
 	}
 
 	slotName := cluster.GetSlotNameFromInstanceName(r.instance.PodName)
-	return postgres.UpdateReplicaConfiguration(r.instance.PgData, connectionString, slotName)
+	changed, err = postgres.UpdateReplicaConfiguration(r.instance.PgData, connectionString, slotName)
+	if err != nil {
+		return changed, err
+	}
+
+	settingsChanged, err := r.writeStandbySettings(cluster)
+	return changed || settingsChanged, err
+}
+
+// writeStandbySettings writes the PostgreSQL settings that only apply
+// to this instance while it is running as a standby
+func (r *InstanceReconciler) writeStandbySettings(cluster *apiv1.Cluster) (changed bool, err error) {
+	return postgres.UpdateStandbySettings(r.instance.PgData, cluster.GetStandbySettings(r.instance.PodName))
 }
@@ -0,0 +1,67 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roles
+
+import (
+	"context"
+)
+
+// DatabaseRole represents a role in the PostgreSQL instance, with the
+// attributes that can be managed declaratively
+type DatabaseRole struct {
+	// Name of the role
+	Name string
+
+	// Whether the role is a superuser
+	Superuser bool
+
+	// Whether the role can create databases
+	CreateDB bool
+
+	// Whether the role can log in
+	Login bool
+
+	// How many concurrent connections the role can make, -1 means no limit
+	ConnectionLimit int64
+
+	// The password to be set for the role. This is never read from the
+	// database, and a nil value means the password will not be changed
+	Password *string
+}
+
+// hasSameAttributes checks whether two roles have the same attributes,
+// disregarding the password
+func (role DatabaseRole) hasSameAttributes(other DatabaseRole) bool {
+	return role.Name == other.Name &&
+		role.Superuser == other.Superuser &&
+		role.CreateDB == other.CreateDB &&
+		role.Login == other.Login &&
+		role.ConnectionLimit == other.ConnectionLimit
+}
+
+// RoleManager abstracts the operations that need to be sent to
+// the database instance for the management of roles
+type RoleManager interface {
+	// List the roles that are not reserved to PostgreSQL
+	List(ctx context.Context) ([]DatabaseRole, error)
+	// Update the role attributes and, if specified, its password
+	Update(ctx context.Context, role DatabaseRole) error
+	// Create the role
+	Create(ctx context.Context, role DatabaseRole) error
+	// Delete the role
+	Delete(ctx context.Context, role DatabaseRole) error
+}
@@ -0,0 +1,172 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roles
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/lib/pq"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// pooler is an internal interface to pass a connection pooler to NewPostgresRoleManager
+type pooler interface {
+	Connection(dbname string) (*sql.DB, error)
+	GetDsn(dbname string) string
+}
+
+// PostgresRoleManager is a RoleManager for a database instance
+type PostgresRoleManager struct {
+	pool pooler
+}
+
+// NewPostgresRoleManager returns an implementation of RoleManager for postgres
+func NewPostgresRoleManager(pool pooler) RoleManager {
+	return PostgresRoleManager{
+		pool: pool,
+	}
+}
+
+func (sm PostgresRoleManager) String() string {
+	return sm.pool.GetDsn("postgres")
+}
+
+// List the roles that are not reserved to PostgreSQL
+func (sm PostgresRoleManager) List(ctx context.Context) ([]DatabaseRole, error) {
+	db, err := sm.pool.Connection("postgres")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := db.QueryContext(
+		ctx,
+		`SELECT rolname, rolsuper, rolcreatedb, rolcanlogin, rolconnlimit
+            FROM pg_catalog.pg_roles WHERE rolname NOT LIKE 'pg\_%'`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+
+	var roles []DatabaseRole
+	for rows.Next() {
+		var role DatabaseRole
+		err := rows.Scan(
+			&role.Name,
+			&role.Superuser,
+			&role.CreateDB,
+			&role.Login,
+			&role.ConnectionLimit,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		roles = append(roles, role)
+	}
+
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+
+	return roles, nil
+}
+
+// Update the role attributes and, if specified, its password
+func (sm PostgresRoleManager) Update(ctx context.Context, role DatabaseRole) error {
+	contextLog := log.FromContext(ctx).WithName("updateRole")
+	contextLog.Trace("Invoked", "role", role.Name)
+
+	db, err := sm.pool.Connection("postgres")
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf("ALTER ROLE %s WITH %s",
+		pgx.Identifier{role.Name}.Sanitize(), roleOptions(role)))
+	if err != nil {
+		return fmt.Errorf("while running ALTER ROLE %s: %w", role.Name, err)
+	}
+	return nil
+}
+
+// Create the role
+func (sm PostgresRoleManager) Create(ctx context.Context, role DatabaseRole) error {
+	contextLog := log.FromContext(ctx).WithName("createRole")
+	contextLog.Trace("Invoked", "role", role.Name)
+
+	db, err := sm.pool.Connection("postgres")
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf("CREATE ROLE %s WITH %s",
+		pgx.Identifier{role.Name}.Sanitize(), roleOptions(role)))
+	if err != nil {
+		return fmt.Errorf("while running CREATE ROLE %s: %w", role.Name, err)
+	}
+	return nil
+}
+
+// Delete the role
+func (sm PostgresRoleManager) Delete(ctx context.Context, role DatabaseRole) error {
+	contextLog := log.FromContext(ctx).WithName("dropRole")
+	contextLog.Trace("Invoked", "role", role.Name)
+
+	db, err := sm.pool.Connection("postgres")
+	if err != nil {
+		return err
+	}
+
+	_, err = db.ExecContext(ctx, fmt.Sprintf("DROP ROLE %s", pgx.Identifier{role.Name}.Sanitize()))
+	if err != nil {
+		return fmt.Errorf("while running DROP ROLE %s: %w", role.Name, err)
+	}
+	return nil
+}
+
+// roleOptions builds the option list of the CREATE ROLE and ALTER ROLE
+// commands for the passed role
+func roleOptions(role DatabaseRole) string {
+	options := []string{
+		attributeOption(role.Superuser, "SUPERUSER"),
+		attributeOption(role.CreateDB, "CREATEDB"),
+		attributeOption(role.Login, "LOGIN"),
+		fmt.Sprintf("CONNECTION LIMIT %d", role.ConnectionLimit),
+	}
+
+	if role.Password != nil {
+		options = append(options, fmt.Sprintf("PASSWORD %s", pq.QuoteLiteral(*role.Password)))
+	}
+
+	return strings.Join(options, " ")
+}
+
+// attributeOption returns the role attribute, or its negation
+func attributeOption(enabled bool, attribute string) string {
+	if enabled {
+		return attribute
+	}
+	return "NO" + attribute
+}
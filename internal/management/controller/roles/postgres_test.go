@@ -0,0 +1,45 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roles
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Role options", func() {
+	It("negates the attributes that are not set", func() {
+		Expect(roleOptions(DatabaseRole{Name: "app", ConnectionLimit: -1})).To(Equal(
+			"NOSUPERUSER NOCREATEDB NOLOGIN CONNECTION LIMIT -1"))
+	})
+
+	It("sets the attributes of the role", func() {
+		Expect(roleOptions(DatabaseRole{
+			Name:            "app",
+			Superuser:       true,
+			CreateDB:        true,
+			Login:           true,
+			ConnectionLimit: 10,
+		})).To(Equal("SUPERUSER CREATEDB LOGIN CONNECTION LIMIT 10"))
+	})
+
+	It("quotes the password", func() {
+		password := "it's a secret"
+		Expect(roleOptions(DatabaseRole{Name: "app", Login: true, ConnectionLimit: -1, Password: &password})).To(Equal(
+			"NOSUPERUSER NOCREATEDB LOGIN CONNECTION LIMIT -1 PASSWORD 'it''s a secret'"))
+	})
+})
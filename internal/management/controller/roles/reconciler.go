@@ -0,0 +1,117 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roles
+
+import (
+	"context"
+	"fmt"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// RolePassword is the password of a managed role, as read from the secret
+// referenced in its configuration
+type RolePassword struct {
+	// The password of the role
+	Value string
+
+	// True when the secret has changed since the last time its content
+	// has been applied to the database
+	Changed bool
+}
+
+// ReconcileManagedRoles ensures the roles declared in the managed
+// configuration are present in, or absent from, the database.
+// Roles not listed in the configuration are left untouched
+func ReconcileManagedRoles(
+	ctx context.Context,
+	manager RoleManager,
+	managed *apiv1.ManagedConfiguration,
+	passwords map[string]RolePassword,
+) error {
+	if managed == nil || len(managed.Roles) == 0 {
+		return nil
+	}
+
+	contextLogger := log.FromContext(ctx)
+	contextLogger.Debug("Updating managed roles")
+
+	currentRoles, err := manager.List(ctx)
+	if err != nil {
+		return fmt.Errorf("while listing database roles: %w", err)
+	}
+
+	existingRoles := make(map[string]DatabaseRole, len(currentRoles))
+	for _, role := range currentRoles {
+		existingRoles[role.Name] = role
+	}
+
+	for _, roleConfiguration := range managed.Roles {
+		currentRole, found := existingRoles[roleConfiguration.Name]
+
+		switch roleConfiguration.GetEnsure() {
+		case apiv1.EnsureAbsent:
+			if !found {
+				continue
+			}
+			contextLogger.Info("Dropping managed role", "role", roleConfiguration.Name)
+			if err := manager.Delete(ctx, currentRole); err != nil {
+				return err
+			}
+
+		case apiv1.EnsurePresent:
+			expectedRole := newDatabaseRole(roleConfiguration)
+			password, hasPassword := passwords[roleConfiguration.Name]
+
+			if !found {
+				if hasPassword {
+					expectedRole.Password = &password.Value
+				}
+				contextLogger.Info("Creating managed role", "role", roleConfiguration.Name)
+				if err := manager.Create(ctx, expectedRole); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if hasPassword && password.Changed {
+				expectedRole.Password = &password.Value
+			}
+			if expectedRole.Password == nil && currentRole.hasSameAttributes(expectedRole) {
+				continue
+			}
+			contextLogger.Info("Updating managed role", "role", roleConfiguration.Name)
+			if err := manager.Update(ctx, expectedRole); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// newDatabaseRole creates the database representation of a role configuration
+func newDatabaseRole(roleConfiguration apiv1.RoleConfiguration) DatabaseRole {
+	return DatabaseRole{
+		Name:            roleConfiguration.Name,
+		Superuser:       roleConfiguration.Superuser,
+		CreateDB:        roleConfiguration.CreateDB,
+		Login:           roleConfiguration.Login,
+		ConnectionLimit: roleConfiguration.GetConnectionLimit(),
+	}
+}
@@ -0,0 +1,159 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package roles
+
+import (
+	"context"
+
+	"k8s.io/utils/pointer"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+type fakeRoleManager struct {
+	roles   map[string]DatabaseRole
+	updated []string
+}
+
+func (fk *fakeRoleManager) List(ctx context.Context) ([]DatabaseRole, error) {
+	result := make([]DatabaseRole, 0, len(fk.roles))
+	for _, role := range fk.roles {
+		result = append(result, role)
+	}
+	return result, nil
+}
+
+func (fk *fakeRoleManager) Update(ctx context.Context, role DatabaseRole) error {
+	fk.roles[role.Name] = role
+	fk.updated = append(fk.updated, role.Name)
+	return nil
+}
+
+func (fk *fakeRoleManager) Create(ctx context.Context, role DatabaseRole) error {
+	fk.roles[role.Name] = role
+	return nil
+}
+
+func (fk *fakeRoleManager) Delete(ctx context.Context, role DatabaseRole) error {
+	delete(fk.roles, role.Name)
+	return nil
+}
+
+var _ = Describe("Managed roles reconciliation", func() {
+	var manager *fakeRoleManager
+
+	BeforeEach(func() {
+		manager = &fakeRoleManager{
+			roles: map[string]DatabaseRole{
+				"postgres": {Name: "postgres", Superuser: true, Login: true, ConnectionLimit: -1},
+				"app":      {Name: "app", Login: true, ConnectionLimit: -1},
+			},
+		}
+	})
+
+	It("does nothing without a managed configuration", func() {
+		Expect(ReconcileManagedRoles(context.TODO(), manager, nil, nil)).To(Succeed())
+		Expect(manager.roles).To(HaveLen(2))
+	})
+
+	It("creates the missing roles", func() {
+		managed := &apiv1.ManagedConfiguration{
+			Roles: []apiv1.RoleConfiguration{
+				{Name: "reporting", Login: true, ConnectionLimit: pointer.Int64(5)},
+			},
+		}
+		passwords := map[string]RolePassword{
+			"reporting": {Value: "secret"},
+		}
+
+		Expect(ReconcileManagedRoles(context.TODO(), manager, managed, passwords)).To(Succeed())
+		Expect(manager.roles).To(HaveKey("reporting"))
+		Expect(manager.roles["reporting"].Login).To(BeTrue())
+		Expect(manager.roles["reporting"].ConnectionLimit).To(BeEquivalentTo(5))
+		Expect(manager.roles["reporting"].Password).ToNot(BeNil())
+		Expect(*manager.roles["reporting"].Password).To(Equal("secret"))
+	})
+
+	It("allows preventing a role from connecting with a zero connection limit", func() {
+		managed := &apiv1.ManagedConfiguration{
+			Roles: []apiv1.RoleConfiguration{
+				{Name: "app", Login: true, ConnectionLimit: pointer.Int64(0)},
+			},
+		}
+
+		Expect(ReconcileManagedRoles(context.TODO(), manager, managed, nil)).To(Succeed())
+		Expect(manager.updated).To(ConsistOf("app"))
+		Expect(manager.roles["app"].ConnectionLimit).To(BeZero())
+	})
+
+	It("updates the roles whose attributes are different", func() {
+		managed := &apiv1.ManagedConfiguration{
+			Roles: []apiv1.RoleConfiguration{
+				{Name: "app", Login: true, CreateDB: true},
+			},
+		}
+
+		Expect(ReconcileManagedRoles(context.TODO(), manager, managed, nil)).To(Succeed())
+		Expect(manager.updated).To(ConsistOf("app"))
+		Expect(manager.roles["app"].CreateDB).To(BeTrue())
+	})
+
+	It("leaves alone the roles which are already in sync", func() {
+		managed := &apiv1.ManagedConfiguration{
+			Roles: []apiv1.RoleConfiguration{
+				{Name: "app", Login: true, ConnectionLimit: pointer.Int64(-1)},
+			},
+		}
+		passwords := map[string]RolePassword{
+			"app": {Value: "secret", Changed: false},
+		}
+
+		Expect(ReconcileManagedRoles(context.TODO(), manager, managed, passwords)).To(Succeed())
+		Expect(manager.updated).To(BeEmpty())
+	})
+
+	It("updates the password when the secret changed", func() {
+		managed := &apiv1.ManagedConfiguration{
+			Roles: []apiv1.RoleConfiguration{
+				{Name: "app", Login: true, ConnectionLimit: pointer.Int64(-1)},
+			},
+		}
+		passwords := map[string]RolePassword{
+			"app": {Value: "new-secret", Changed: true},
+		}
+
+		Expect(ReconcileManagedRoles(context.TODO(), manager, managed, passwords)).To(Succeed())
+		Expect(manager.updated).To(ConsistOf("app"))
+		Expect(*manager.roles["app"].Password).To(Equal("new-secret"))
+	})
+
+	It("drops the roles that should be absent", func() {
+		managed := &apiv1.ManagedConfiguration{
+			Roles: []apiv1.RoleConfiguration{
+				{Name: "app", Ensure: apiv1.EnsureAbsent},
+				{Name: "missing", Ensure: apiv1.EnsureAbsent},
+			},
+		}
+
+		Expect(ReconcileManagedRoles(context.TODO(), manager, managed, nil)).To(Succeed())
+		Expect(manager.roles).ToNot(HaveKey("app"))
+		Expect(manager.roles).To(HaveKey("postgres"))
+	})
+})
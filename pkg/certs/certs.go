@@ -33,21 +33,17 @@ import (
 
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 )
 
 const (
-	// This is the lifetime of the generated certificates
-	certificateDuration = 90 * 24 * time.Hour
-
 	// This is the PEM block type of elliptic courves private key
 	ecPrivateKeyPEMBlockType = "EC PRIVATE KEY"
 
 	// This is the PEM block type for certificates
 	certificatePEMBlockType = "CERTIFICATE"
 
-	// Threshold to consider a certificate as expiring
-	expiringCheckThreshold = 7 * 24 * time.Hour
-
 	// CACertKey is the key for certificates in a CA secret
 	CACertKey = "ca.crt"
 
@@ -59,6 +55,10 @@ const (
 
 	// TLSPrivateKeyKey is the key for the private key field in a CA secret
 	TLSPrivateKeyKey = "tls.key"
+
+	// ExpirationDateLayout is the layout of the expiration dates of the
+	// certificates, as reported in the cluster status
+	ExpirationDateLayout = "2006-01-02 15:04:05.999999999 -0700 MST"
 )
 
 // CertType represent a certificate type
@@ -144,7 +144,7 @@ func (pair KeyPair) IsValid(caPair *KeyPair, opts *x509.VerifyOptions) error {
 // CreateAndSignPair given a CA keypair, generate and sign a leaf keypair
 func (pair KeyPair) CreateAndSignPair(host string, usage CertType, altDNSNames []string) (*KeyPair, error) {
 	notBefore := time.Now().Add(time.Minute * -5)
-	notAfter := notBefore.Add(certificateDuration)
+	notAfter := notBefore.Add(configuration.Current.GetCertificateDuration())
 	return pair.createAndSignPairWithValidity(host, notBefore, notAfter, usage, altDNSNames)
 }
 
@@ -268,7 +268,7 @@ func (pair *KeyPair) RenewCertificate(caPrivateKey *ecdsa.PrivateKey, parentCert
 	}
 
 	notBefore := time.Now().Add(time.Minute * -5)
-	notAfter := notBefore.Add(certificateDuration)
+	notAfter := notBefore.Add(configuration.Current.GetCertificateDuration())
 
 	serialNumberLimit := new(big.Int).Lsh(big.NewInt(1), 128)
 	serialNumber, err := rand.Int(rand.Reader, serialNumberLimit)
@@ -314,13 +314,19 @@ func (pair *KeyPair) IsExpiring() (bool, *time.Time, error) {
 	if time.Now().Before(cert.NotBefore) {
 		return true, &cert.NotAfter, nil
 	}
-	if time.Now().Add(expiringCheckThreshold).After(cert.NotAfter) {
+	if time.Now().Add(configuration.Current.GetExpiringCheckThreshold()).After(cert.NotAfter) {
 		return true, &cert.NotAfter, nil
 	}
 
 	return false, &cert.NotAfter, nil
 }
 
+// GetRenewalTime gets the time when a certificate generated by the operator
+// expiring at the passed time will be renewed
+func GetRenewalTime(expiration time.Time) time.Time {
+	return expiration.Add(-configuration.Current.GetExpiringCheckThreshold())
+}
+
 // CreateDerivedCA create a new CA derived from the certificate in the
 // keypair
 func (pair *KeyPair) CreateDerivedCA(commonName string, organizationalUnit string) (*KeyPair, error) {
@@ -335,7 +341,7 @@ func (pair *KeyPair) CreateDerivedCA(commonName string, organizationalUnit strin
 	}
 
 	notBefore := time.Now().Add(time.Minute * -5)
-	notAfter := notBefore.Add(certificateDuration)
+	notAfter := notBefore.Add(configuration.Current.GetCertificateDuration())
 
 	return createCAWithValidity(notBefore, notAfter, certificate, key, commonName, organizationalUnit)
 }
@@ -343,7 +349,7 @@ func (pair *KeyPair) CreateDerivedCA(commonName string, organizationalUnit strin
 // CreateRootCA generates a CA returning its keys
 func CreateRootCA(commonName string, organizationalUnit string) (*KeyPair, error) {
 	notBefore := time.Now().Add(time.Minute * -5)
-	notAfter := notBefore.Add(certificateDuration)
+	notAfter := notBefore.Add(configuration.Current.GetCertificateDuration())
 	return createCAWithValidity(notBefore, notAfter, nil, nil, commonName, organizationalUnit)
 }
 
@@ -24,6 +24,8 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+
+	"github.com/cloudnative-pg/cloudnative-pg/internal/configuration"
 )
 
 var _ = Describe("Keypair generation", func() {
@@ -106,6 +108,24 @@ var _ = Describe("Keypair generation", func() {
 		Expect(isExpiring, err).To(BeFalse())
 	})
 
+	It("reports an expiration date that can be parsed back", func() {
+		ca, err := CreateRootCA("test", "namespace")
+		Expect(err).To(BeNil())
+		_, expirationDate, err := ca.IsExpiring()
+		Expect(err).To(BeNil())
+
+		parsedDate, err := time.Parse(ExpirationDateLayout, expirationDate.Format(ExpirationDateLayout))
+		Expect(err).To(BeNil())
+		Expect(parsedDate.Unix()).To(Equal(expirationDate.Unix()))
+	})
+
+	It("renews the certificates before their expiration", func() {
+		expiration := time.Now().Add(30 * 24 * time.Hour)
+		renewal := GetRenewalTime(expiration)
+		Expect(renewal.Before(expiration)).To(BeTrue())
+		Expect(renewal).To(Equal(expiration.Add(-configuration.Current.GetExpiringCheckThreshold())))
+	})
+
 	When("we have a CA generated", func() {
 		It("should successfully generate a leaf certificate", func() {
 			rootCA, err := CreateRootCA("test", "namespace")
@@ -38,6 +38,9 @@ type FakeData struct {
 
 	// EnablePodDebugging enable debugging mode in new generated pods
 	EnablePodDebugging bool `json:"enablePodDebugging" env:"POD_DEBUG"`
+
+	// CertificateDuration is the lifetime of the generated certificates, in days
+	CertificateDuration int `json:"certificateDuration" env:"CERTIFICATE_DURATION"`
 }
 
 var defaultInheritedAnnotations = []string{
@@ -50,7 +53,10 @@ const oneNamespace = "one-namespace"
 
 // readConfigMap reads the configuration from the environment and the passed in data map
 func (config *FakeData) readConfigMap(data map[string]string, env EnvironmentSource) {
-	ReadConfigMap(config, &FakeData{InheritedAnnotations: defaultInheritedAnnotations}, data, env)
+	ReadConfigMap(config, &FakeData{
+		InheritedAnnotations: defaultInheritedAnnotations,
+		CertificateDuration:  90,
+	}, data, env)
 }
 
 var _ = Describe("Data test suite", func() {
@@ -90,6 +96,25 @@ var _ = Describe("Data test suite", func() {
 		Expect(config.InheritedAnnotations).To(Equal(defaultInheritedAnnotations))
 		Expect(config.InheritedLabels).To(BeNil())
 	})
+
+	It("loads integer values and their defaults", func() {
+		config := &FakeData{}
+		config.readConfigMap(nil, NewFakeEnvironment(nil))
+		Expect(config.CertificateDuration).To(Equal(90))
+
+		config.readConfigMap(map[string]string{
+			"CERTIFICATE_DURATION": "30",
+		}, NewFakeEnvironment(nil))
+		Expect(config.CertificateDuration).To(Equal(30))
+	})
+
+	It("skips invalid integer values", func() {
+		config := &FakeData{}
+		config.readConfigMap(map[string]string{
+			"CERTIFICATE_DURATION": "thirty",
+		}, NewFakeEnvironment(nil))
+		Expect(config.CertificateDuration).To(BeZero())
+	})
 })
 
 // FakeEnvironment is an EnvironmentSource that fetches data from an internal map
@@ -26,6 +26,7 @@ import (
 	"strconv"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -113,6 +114,16 @@ func getDataConfiguration(
 			string(configuration.Data.Encryption))
 	}
 
+	if configuration.Data.Encryption == apiv1.EncryptionTypeNoneAWSKMS && len(configuration.SSEKMSKeyID) != 0 {
+		if !capabilities.HasSSEKMSKeyID {
+			return nil, fmt.Errorf("the KMS key is not supported in Barman %v", capabilities.Version)
+		}
+		options = append(
+			options,
+			"--sse-kms-key-id",
+			configuration.SSEKMSKeyID)
+	}
+
 	if configuration.Data.ImmediateCheckpoint {
 		options = append(
 			options,
@@ -177,6 +188,26 @@ func (b *BackupCommand) getBarmanCloudBackupOptions(
 	return options, nil
 }
 
+// ensureWalArchiveIsWorking checks whether the WAL archiving is working before
+// taking the backup. Standby instances don't archive WAL files themselves,
+// so they rely on the status reported by the primary instance
+func (b *BackupCommand) ensureWalArchiveIsWorking() error {
+	isPrimary, err := b.Instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+
+	if isPrimary {
+		return waitForWalArchiveWorking()
+	}
+
+	if !meta.IsStatusConditionTrue(b.Cluster.Status.Conditions, string(apiv1.ConditionContinuousArchiving)) {
+		return errors.New("wal-archive not working on the primary instance")
+	}
+
+	return nil
+}
+
 // waitForWalArchiveWorking retry until the wal archiving is working or the timeout occur
 func waitForWalArchiveWorking() error {
 	db, err := sql.Open(
@@ -256,7 +287,7 @@ func (b *BackupCommand) Start(ctx context.Context) error {
 		return fmt.Errorf("can't set backup as running: %v", err)
 	}
 
-	err = waitForWalArchiveWorking()
+	err = b.ensureWalArchiveIsWorking()
 	if err != nil {
 		log.Info("WAL archiving is not working")
 		b.Backup.GetStatus().Phase = apiv1.BackupPhaseWalArchivingFailing
@@ -0,0 +1,106 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"k8s.io/utils/strings/slices"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+const (
+	pgChecksumsName = "pg_checksums"
+
+	pgControldataClusterStateKey    = "Database cluster state"
+	pgControldataChecksumVersionKey = "Data page checksum version"
+)
+
+// cleanShutdownStates are the states of the control file in which
+// the data directory can be inspected by pg_checksums
+var cleanShutdownStates = []string{"shut down", "shut down in recovery"}
+
+// parsePgControldataOutput parses the output of pg_controldata, returning
+// the value of every reported field
+func parsePgControldataOutput(data string) map[string]string {
+	result := make(map[string]string)
+	for _, line := range strings.Split(data, "\n") {
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		result[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return result
+}
+
+// VerifyDataChecksums verifies the data checksums of the data directory
+// with pg_checksums, returning an error if a corruption is detected.
+// The verification is skipped when PostgreSQL is already running, like
+// after an online upgrade of the instance manager, when the data directory
+// has not been cleanly shut down, and when data checksums are disabled.
+// A failed verification is not repeated, as it would take a long time
+// to report the same corruption again.
+func (instance *Instance) VerifyDataChecksums() error {
+	if instance.dataChecksumsVerificationErr != nil {
+		return instance.dataChecksumsVerificationErr
+	}
+
+	process, err := instance.CheckForExistingPostmaster(postgresName)
+	if err != nil {
+		return err
+	}
+	if process != nil {
+		log.Info("PostgreSQL is already running, skipping the verification of the data checksums")
+		return nil
+	}
+
+	pgControldataOutput, err := getPgControldataOutput(instance.PgData)
+	if err != nil {
+		return err
+	}
+	controlData := parsePgControldataOutput(pgControldataOutput)
+
+	if version := controlData[pgControldataChecksumVersionKey]; version == "" || version == "0" {
+		log.Warning("Data checksums are disabled, skipping their verification")
+		return nil
+	}
+
+	if state := controlData[pgControldataClusterStateKey]; !slices.Contains(cleanShutdownStates, state) {
+		log.Warning("The data directory has not been cleanly shut down, skipping the verification "+
+			"of the data checksums", "state", state)
+		return nil
+	}
+
+	log.Info("Verifying the data checksums", "pgdata", instance.PgData)
+	pgChecksumsCmd := exec.Command(pgChecksumsName, // #nosec G204
+		"--check",
+		"-D",
+		instance.PgData)
+	if err := execlog.RunStreaming(pgChecksumsCmd, pgChecksumsName); err != nil {
+		instance.dataChecksumsVerificationErr = fmt.Errorf(
+			"data checksums verification failed, the data directory might be corrupted: %w", err)
+		return instance.dataChecksumsVerificationErr
+	}
+
+	log.Info("Data checksums verified successfully")
+	return nil
+}
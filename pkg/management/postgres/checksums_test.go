@@ -0,0 +1,54 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("pg_controldata output parsing", func() {
+	const pgControldataOutput = `pg_control version number:            1300
+Catalog version number:               202209061
+Database system identifier:           7222168461924043100
+Database cluster state:               shut down in recovery
+pg_control last modified:             Wed 15 Mar 2023 10:12:43 AM UTC
+Latest checkpoint location:           0/6000060
+Data page checksum version:           1
+`
+
+	It("extracts the fields of the control file", func() {
+		controlData := parsePgControldataOutput(pgControldataOutput)
+		Expect(controlData).To(HaveKeyWithValue(pgControldataClusterStateKey, "shut down in recovery"))
+		Expect(controlData).To(HaveKeyWithValue(pgControldataChecksumVersionKey, "1"))
+		Expect(controlData).To(HaveKeyWithValue("pg_control last modified", "Wed 15 Mar 2023 10:12:43 AM UTC"))
+	})
+
+	It("ignores the lines without a field", func() {
+		Expect(parsePgControldataOutput("\nnot a field\n")).To(BeEmpty())
+	})
+})
+
+var _ = Describe("data checksums verification", func() {
+	It("doesn't repeat a failed verification", func() {
+		verificationErr := errors.New("data checksums verification failed")
+		instance := &Instance{dataChecksumsVerificationErr: verificationErr}
+		Expect(instance.VerifyDataChecksums()).To(MatchError(verificationErr))
+	})
+})
@@ -57,7 +57,7 @@ func InstallPgDataFileContent(pgdata, contents, destinationFile string) (bool, e
 func (instance *Instance) RefreshConfigurationFilesFromCluster(
 	cluster *apiv1.Cluster,
 ) (bool, error) {
-	postgresConfiguration, sha256, err := createPostgresqlConfiguration(cluster)
+	postgresConfiguration, sha256, err := createPostgresqlConfiguration(cluster, instance.PodIP)
 	if err != nil {
 		return false, err
 	}
@@ -81,7 +81,7 @@ func (instance *Instance) RefreshConfigurationFilesFromCluster(
 
 // GeneratePostgresqlHBA generates the pg_hba.conf content with the LDAP configuration if configured.
 func (instance *Instance) GeneratePostgresqlHBA(cluster *apiv1.Cluster, ldapBindPassword string) (string, error) {
-	version, err := cluster.GetPostgresqlVersion()
+	version, err := GetPostgresVersion(cluster)
 	if err != nil {
 		return "", err
 	}
@@ -98,10 +98,20 @@ func (instance *Instance) GeneratePostgresqlHBA(cluster *apiv1.Cluster, ldapBind
 		defaultAuthenticationMethod = "md5"
 	}
 
+	var applicationCertificateRule string
+	database, owner := cluster.GetApplicationDatabaseName(), cluster.GetApplicationDatabaseOwner()
+	if cluster.Spec.PostgresConfiguration.ApplicationCertificateAuthentication && database != "" && owner != "" {
+		applicationCertificateRule, err = postgres.CreateApplicationCertificateHBARule(database, owner)
+		if err != nil {
+			return "", err
+		}
+	}
+
 	return postgres.CreateHBARules(
 		cluster.Spec.PostgresConfiguration.PgHBA,
 		defaultAuthenticationMethod,
-		buildLDAPConfigString(cluster, ldapBindPassword))
+		buildLDAPConfigString(cluster, ldapBindPassword),
+		applicationCertificateRule)
 }
 
 // RefreshPGHBA generates and writes down the pg_hba.conf file
@@ -264,6 +274,39 @@ func configurePostgresAutoConfFile(pgData, primaryConnInfo, slotName string) (ch
 	return changed, nil
 }
 
+// UpdateStandbySettings sets the PostgreSQL settings that only apply to standby
+// instances in the postgresql.auto.conf file, removing the ones not passed.
+// Standby settings are only supported on PostgreSQL 12 and newer, where they
+// can be changed with a configuration reload
+func UpdateStandbySettings(pgData string, settings map[string]string) (changed bool, err error) {
+	major, err := postgresutils.GetMajorVersion(pgData)
+	if err != nil {
+		return false, err
+	}
+
+	if major < 12 {
+		return false, nil
+	}
+
+	targetFile := path.Join(pgData, "postgresql.auto.conf")
+
+	changed, err = configfile.UpdatePostgresConfigurationFile(
+		targetFile,
+		settings,
+		postgres.StandbySettings...,
+	)
+	if err != nil {
+		return false, err
+	}
+
+	if changed {
+		log.Info("Updated standby settings in postgresql.auto.conf file",
+			"settings", settings)
+	}
+
+	return changed, nil
+}
+
 // createStandbySignal creates a standby.signal file for PostgreSQL 12 and beyond
 func createStandbySignal(pgData string) error {
 	emptyFile, err := os.Create(filepath.Clean(filepath.Join(pgData, "standby.signal")))
@@ -287,10 +330,11 @@ func RemoveArchiveModeFromPostgresAutoConf(pgData string) (changed bool, err err
 }
 
 // createPostgresqlConfiguration creates the PostgreSQL configuration to be
-// used for this cluster and return it and its sha256 checksum
-func createPostgresqlConfiguration(cluster *apiv1.Cluster) (string, string, error) {
+// used for this cluster and return it and its sha256 checksum. The IP
+// address of the pod is used when PostgreSQL must listen only on it
+func createPostgresqlConfiguration(cluster *apiv1.Cluster, podIP string) (string, string, error) {
 	// Extract the PostgreSQL major version
-	fromVersion, err := cluster.GetPostgresqlVersion()
+	fromVersion, err := GetPostgresVersion(cluster)
 	if err != nil {
 		return "", "", err
 	}
@@ -303,18 +347,34 @@ func createPostgresqlConfiguration(cluster *apiv1.Cluster) (string, string, erro
 		IncludingSharedPreloadLibraries:  true,
 		AdditionalSharedPreloadLibraries: cluster.Spec.PostgresConfiguration.AdditionalLibraries,
 		IsReplicaCluster:                 cluster.IsReplica(),
+		WalSenderTimeout:                 cluster.Spec.PostgresConfiguration.WalSenderTimeout,
+		WalReceiverTimeout:               cluster.Spec.PostgresConfiguration.WalReceiverTimeout,
+		StatementTimeout:                 cluster.Spec.PostgresConfiguration.StatementTimeout,
+		IdleInTransactionSessionTimeout:  cluster.Spec.PostgresConfiguration.IdleInTransactionSessionTimeout,
 	}
 
 	// Compute the actual number of sync replicas
 	syncReplicas, electable := cluster.GetSyncReplicasData()
 	info.SyncReplicas = syncReplicas
 	info.SyncReplicasElectable = electable
+	info.SyncReplicasMethod = cluster.GetSynchronousReplicationMethod()
 
-	// Ensure a consistent ordering to avoid spurious configuration changes
-	sort.Strings(info.SyncReplicasElectable)
+	// Ensure a consistent ordering to avoid spurious configuration changes,
+	// unless the user listed the standbys in order of priority
+	if !cluster.HasSynchronousStandbyNames() {
+		sort.Strings(info.SyncReplicasElectable)
+	}
 
 	// Set cluster name
-	info.ClusterName = cluster.Name
+	info.ClusterName = cluster.GetPostgresClusterName()
+
+	// Restrict the network interfaces PostgreSQL listens on
+	if cluster.Spec.PostgresConfiguration.ListenScope == apiv1.ListenOnPodIPOnly {
+		if podIP == "" {
+			return "", "", fmt.Errorf("cannot listen only on the IP address of the pod: the address is unknown")
+		}
+		info.ListenAddresses = podIP
+	}
 
 	conf, sha256 := postgres.CreatePostgresqlConfFile(postgres.CreatePostgresqlConfiguration(info))
 	return conf, sha256, nil
@@ -98,3 +98,87 @@ var _ = Describe("testing the building of the ldap config string", func() {
 			"ldaptls=1 ldapprefix=\"%s\" ldapsuffix=\"%s\"", ldapServer, ldapPort, ldapScheme, ldapPrefix, ldapSuffix)))
 	})
 })
+
+var _ = Describe("listen addresses configuration", func() {
+	cluster := apiv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "configurationTest",
+			Namespace: "default",
+		},
+		Spec: apiv1.ClusterSpec{
+			ImageName: "ghcr.io/cloudnative-pg/postgresql:15.2",
+			PostgresConfiguration: apiv1.PostgresConfiguration{
+				ListenScope: apiv1.ListenOnPodIPOnly,
+			},
+		},
+	}
+
+	It("listens only on the IP address of the pod when requested", func() {
+		conf, _, err := createPostgresqlConfiguration(&cluster, "10.1.2.3")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conf).To(ContainSubstring("listen_addresses = '10.1.2.3'"))
+	})
+
+	It("fails when the IP address of the pod is unknown", func() {
+		_, _, err := createPostgresqlConfiguration(&cluster, "")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("listens on every interface by default", func() {
+		defaultCluster := cluster.DeepCopy()
+		defaultCluster.Spec.PostgresConfiguration.ListenScope = ""
+		conf, _, err := createPostgresqlConfiguration(defaultCluster, "10.1.2.3")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conf).To(ContainSubstring("listen_addresses = '*'"))
+	})
+})
+
+var _ = Describe("replication timeouts configuration", func() {
+	It("applies the timeouts of the cluster over the stored parameters", func() {
+		senderTimeout := int32(30)
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "configurationTest",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:15.2",
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					WalSenderTimeout: &senderTimeout,
+					Parameters: map[string]string{
+						"wal_sender_timeout":   "5s",
+						"wal_receiver_timeout": "5s",
+					},
+				},
+			},
+		}
+
+		conf, _, err := createPostgresqlConfiguration(&cluster, "10.1.2.3")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conf).To(ContainSubstring("wal_sender_timeout = '30s'"))
+		Expect(conf).To(ContainSubstring("wal_receiver_timeout = '5s'"))
+	})
+})
+
+var _ = Describe("session timeouts configuration", func() {
+	It("applies the timeouts of the cluster while generating the configuration", func() {
+		idleTimeout := int32(600)
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "configurationTest",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:15.2",
+				PostgresConfiguration: apiv1.PostgresConfiguration{
+					IdleInTransactionSessionTimeout: &idleTimeout,
+				},
+			},
+		}
+
+		conf, _, err := createPostgresqlConfiguration(&cluster, "10.1.2.3")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(conf).To(ContainSubstring("idle_in_transaction_session_timeout = '600s'"))
+		Expect(conf).ToNot(ContainSubstring("statement_timeout"))
+	})
+})
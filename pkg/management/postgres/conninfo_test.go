@@ -0,0 +1,37 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("primary_conninfo", func() {
+	It("uses the name of the instance as application_name", func() {
+		instance := &Instance{ClusterName: "cluster-example", PodName: "cluster-example-2"}
+		Expect(instance.GetPrimaryConnInfo()).To(And(
+			ContainSubstring("host=cluster-example-rw "),
+			ContainSubstring("application_name=cluster-example-2 "),
+		))
+	})
+
+	It("uses the name of the instance as application_name while restoring", func() {
+		info := InitInfo{ClusterName: "cluster-example", PodName: "cluster-example-3"}
+		Expect(info.GetPrimaryConnInfo()).To(ContainSubstring("application_name=cluster-example-3 "))
+	})
+})
@@ -20,7 +20,9 @@ import (
 	"fmt"
 	"os/user"
 	"path/filepath"
+	"strings"
 
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/constants"
@@ -29,6 +31,19 @@ import (
 // WritePostgresUserMaps creates a pg_ident.conf file containing only one map called "local" that
 // maps the current user to "postgres" user.
 func WritePostgresUserMaps(pgData string) error {
+	_, err := fileutils.WriteStringToFile(filepath.Join(pgData, constants.PostgresqlIdentFile),
+		GeneratePostgresUserMaps(nil))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GeneratePostgresUserMaps generates the content of the pg_ident.conf file:
+// the "local" map, which maps the current user to "postgres" user, followed
+// by the user-defined rules
+func GeneratePostgresUserMaps(pgIdent []string) string {
 	var username string
 
 	currentUser, err := user.Current()
@@ -39,11 +54,30 @@ func WritePostgresUserMaps(pgData string) error {
 		username = currentUser.Username
 	}
 
-	_, err = fileutils.WriteStringToFile(filepath.Join(pgData, constants.PostgresqlIdentFile),
-		fmt.Sprintf("local %s postgres\n", username))
+	var result strings.Builder
+	result.WriteString(fmt.Sprintf("local %s postgres\n", username))
+	for _, rule := range pgIdent {
+		result.WriteString(rule)
+		result.WriteString("\n")
+	}
+
+	return result.String()
+}
+
+// RefreshPGIdent generates and writes down the pg_ident.conf file
+func (instance *Instance) RefreshPGIdent(cluster *apiv1.Cluster) (
+	postgresIdentChanged bool,
+	err error,
+) {
+	postgresIdentChanged, err = InstallPgDataFileContent(
+		instance.PgData,
+		GeneratePostgresUserMaps(cluster.Spec.PostgresConfiguration.PgIdent),
+		constants.PostgresqlIdentFile)
 	if err != nil {
-		return err
+		return postgresIdentChanged, fmt.Errorf(
+			"installing postgresql user name maps: %w",
+			err)
 	}
 
-	return nil
+	return postgresIdentChanged, nil
 }
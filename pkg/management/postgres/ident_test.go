@@ -0,0 +1,44 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("User name maps", func() {
+	It("always contains the local map", func() {
+		lines := strings.Split(strings.TrimSpace(GeneratePostgresUserMaps(nil)), "\n")
+		Expect(lines).To(HaveLen(1))
+		Expect(lines[0]).To(HavePrefix("local "))
+		Expect(lines[0]).To(HaveSuffix(" postgres"))
+	})
+
+	It("appends the user-defined rules after the local map", func() {
+		lines := strings.Split(strings.TrimSpace(GeneratePostgresUserMaps([]string{
+			"certmap app.example.com app",
+			"certmap admin.example.com postgres",
+		})), "\n")
+		Expect(lines).To(HaveLen(3))
+		Expect(lines[0]).To(HavePrefix("local "))
+		Expect(lines[1]).To(Equal("certmap app.example.com app"))
+		Expect(lines[2]).To(Equal("certmap admin.example.com postgres"))
+	})
+})
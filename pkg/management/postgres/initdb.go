@@ -22,6 +22,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -30,9 +31,12 @@ import (
 	"sort"
 
 	"github.com/jackc/pgx/v5"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/strings/slices"
 	ctrl "sigs.k8s.io/controller-runtime/pkg/client"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/conditions"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/barman/archiver"
@@ -202,7 +206,7 @@ func (info InitInfo) ConfigureNewInstance(instance *Instance) error {
 	// Execute the custom set of init queries
 	log.Info("Executing post-init SQL instructions")
 	if err = info.executeQueries(dbSuperUser, info.PostInitSQL); err != nil {
-		return err
+		return fmt.Errorf("could not execute init queries: %w", err)
 	}
 
 	dbTemplate, err := instance.GetTemplateDB()
@@ -259,7 +263,7 @@ func (info InitInfo) ConfigureNewInstance(instance *Instance) error {
 	return nil
 }
 
-func (info InitInfo) executePostInitApplicationSQLRefs(sqlUser *sql.DB) error {
+func (info InitInfo) executePostInitApplicationSQLRefs(sqlUser sqlExecutor) error {
 	if info.PostInitApplicationSQLRefsFolder == "" {
 		return nil
 	}
@@ -281,29 +285,39 @@ func (info InitInfo) executePostInitApplicationSQLRefs(sqlUser *sql.DB) error {
 	for _, file := range files {
 		sql, ioErr := fileutils.ReadFile(path.Join(info.PostInitApplicationSQLRefsFolder, file))
 		if ioErr != nil {
-			return fmt.Errorf("could not read file: %s, err; %w", file, err)
+			return fmt.Errorf("could not read file: %s, err; %w", file, ioErr)
 		}
 
-		if err = info.executeQueries(sqlUser, []string{string(sql)}); err != nil {
-			return fmt.Errorf("could not execute queries: %w", err)
+		// The content of the file is not reported, as it may be
+		// a large script
+		if _, err = sqlUser.Exec(string(sql)); err != nil {
+			return fmt.Errorf("could not execute queries from file %s: %w", file, err)
 		}
 	}
 
 	return nil
 }
 
-// executeQueries run the set of queries in the provided database connection
-func (info InitInfo) executeQueries(sqlUser *sql.DB, queries []string) error {
+// sqlExecutor is the subset of *sql.DB used to run the post-init queries
+type sqlExecutor interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+}
+
+// executeQueries run the set of queries in the provided database connection.
+// Only the index of the failing statement is reported, as the statements
+// may contain sensitive data
+func (info InitInfo) executeQueries(sqlUser sqlExecutor, queries []string) error {
 	if len(queries) == 0 {
 		log.Debug("No queries to execute")
 		return nil
 	}
 
-	for _, sqlQuery := range queries {
-		log.Debug("Executing query", "sqlQuery", sqlQuery)
+	for idx, sqlQuery := range queries {
+		log.Debug("Executing query", "statementIndex", idx)
 		_, err := sqlUser.Exec(sqlQuery)
 		if err != nil {
-			return err
+			log.Error(err, "Error while executing post-init SQL instruction", "statementIndex", idx)
+			return fmt.Errorf("while executing the statement at index %d: %w", idx, err)
 		}
 	}
 
@@ -329,7 +343,7 @@ func (info InitInfo) Bootstrap(ctx context.Context) error {
 
 	instance := info.GetInstance()
 
-	postgresVersion, err := cluster.GetPostgresqlVersion()
+	postgresVersion, err := GetPostgresVersion(cluster)
 	if err != nil {
 		return fmt.Errorf("while reading the PostgreSQL version: %w", err)
 	}
@@ -361,6 +375,10 @@ func (info InitInfo) Bootstrap(ctx context.Context) error {
 	}
 
 	return instance.WithActiveInstance(func() error {
+		// The databases imported by a monolith import, that can be
+		// matched by a wildcard in the cluster specification
+		var importedDatabases []string
+
 		err = info.ConfigureNewInstance(instance)
 		if err != nil {
 			return fmt.Errorf("while configuring new instance: %w", err)
@@ -369,42 +387,145 @@ func (info InitInfo) Bootstrap(ctx context.Context) error {
 		if cluster.Spec.Bootstrap != nil &&
 			cluster.Spec.Bootstrap.InitDB != nil &&
 			cluster.Spec.Bootstrap.InitDB.Import != nil {
-			err = executeLogicalImport(ctx, typedClient, instance, cluster)
-			if err != nil {
+			importedDatabases, err = executeLogicalImport(ctx, typedClient, instance, cluster)
+			var postImportErr *logicalimport.PostImportQueryError
+			if err != nil && !errors.As(err, &postImportErr) {
 				return fmt.Errorf("while executing logical import: %w", err)
 			}
+			// The data has already been imported, and the bootstrap
+			// should not fail only because the outcome can't be reported
+			if errReport := reportPostImportQueries(ctx, typedClient, cluster, postImportErr); errReport != nil {
+				log.Error(errReport, "Error while reporting the outcome of the post import queries")
+			}
+		}
+
+		if cluster.Spec.Bootstrap != nil && cluster.Spec.Bootstrap.InitDB != nil {
+			err = info.analyzeLoadedData(instance, cluster.Spec.Bootstrap.InitDB, importedDatabases)
+			if err != nil {
+				return fmt.Errorf("while refreshing the planner statistics: %w", err)
+			}
 		}
 
 		return nil
 	})
 }
 
+// analyzeLoadedData refreshes the planner statistics of the databases
+// loaded during the bootstrap, as requested by the user
+func (info InitInfo) analyzeLoadedData(
+	instance *Instance,
+	initDB *apiv1.BootstrapInitDB,
+	importedDatabases []string,
+) error {
+	var query string
+	switch initDB.PostInitAnalyze {
+	case "":
+		return nil
+	case apiv1.PostInitAnalyzeModeAnalyze:
+		// The logical import already analyzes the databases at its end
+		if initDB.Import != nil {
+			return nil
+		}
+		query = "ANALYZE"
+	case apiv1.PostInitAnalyzeModeVacuumAnalyze:
+		query = "VACUUM ANALYZE"
+	default:
+		return fmt.Errorf("unknown post-init analyze mode: %s", initDB.PostInitAnalyze)
+	}
+
+	connectionPool := instance.ConnectionPool()
+	defer connectionPool.ShutdownConnections()
+
+	for _, database := range getLoadedDatabases(info.ApplicationDatabase, importedDatabases) {
+		log.Info("Refreshing the planner statistics", "database", database, "query", query)
+		db, err := connectionPool.Connection(database)
+		if err != nil {
+			return err
+		}
+		if _, err := db.Exec(query); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// getLoadedDatabases gets the databases that are loaded with data during
+// the bootstrap: the application database, and the ones imported by a
+// monolith logical import
+func getLoadedDatabases(applicationDatabase string, importedDatabases []string) []string {
+	var databases []string
+	if applicationDatabase != "" {
+		databases = append(databases, applicationDatabase)
+	}
+
+	for _, database := range importedDatabases {
+		if !slices.Contains(databases, database) {
+			databases = append(databases, database)
+		}
+	}
+
+	return databases
+}
+
+// executeLogicalImport imports the data from the source cluster, returning
+// the names of the databases imported by a monolith import
 func executeLogicalImport(
 	ctx context.Context,
 	client ctrl.Client,
 	instance *Instance,
 	cluster *apiv1.Cluster,
-) error {
+) ([]string, error) {
 	destinationPool := instance.ConnectionPool()
 	defer destinationPool.ShutdownConnections()
 
 	originPool, err := getConnectionPoolerForExternalCluster(ctx, cluster, client, cluster.Namespace)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer originPool.ShutdownConnections()
 
 	cloneType := cluster.Spec.Bootstrap.InitDB.Import.Type
 	switch cloneType {
 	case apiv1.MicroserviceSnapshotType:
-		return logicalimport.Microservice(ctx, cluster, destinationPool, originPool)
+		return nil, logicalimport.Microservice(ctx, cluster, destinationPool, originPool)
 	case apiv1.MonolithSnapshotType:
 		return logicalimport.Monolith(ctx, cluster, destinationPool, originPool)
 	default:
-		return fmt.Errorf("unrecognized clone type %s", cloneType)
+		return nil, fmt.Errorf("unrecognized clone type %s", cloneType)
 	}
 }
 
+// reportPostImportQueries sets the condition reporting the outcome of the
+// post import queries in the cluster status. A failure of these queries
+// is not fatal, as the data has already been imported
+func reportPostImportQueries(
+	ctx context.Context,
+	client ctrl.Client,
+	cluster *apiv1.Cluster,
+	postImportErr *logicalimport.PostImportQueryError,
+) error {
+	if len(cluster.Spec.Bootstrap.InitDB.Import.PostImportApplicationSQL) == 0 {
+		return nil
+	}
+
+	condition := metav1.Condition{
+		Type:    string(apiv1.ConditionPostImportQueries),
+		Status:  metav1.ConditionTrue,
+		Reason:  string(apiv1.ConditionReasonPostImportQueriesSucceeded),
+		Message: "Post import queries executed successfully",
+	}
+	if postImportErr != nil {
+		log.Warning("Post import query failed, the imported data is kept",
+			"index", postImportErr.Index, "err", postImportErr.Err)
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = string(apiv1.ConditionReasonPostImportQueriesFailed)
+		condition.Message = postImportErr.Error()
+	}
+
+	return conditions.Update(ctx, client, cluster, &condition)
+}
+
 func getConnectionPoolerForExternalCluster(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
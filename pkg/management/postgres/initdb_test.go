@@ -0,0 +1,171 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/logicalimport"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("databases loaded during the bootstrap", func() {
+	It("includes the application database", func() {
+		Expect(getLoadedDatabases("app", nil)).To(Equal([]string{"app"}))
+	})
+
+	It("includes the databases imported by a monolith import", func() {
+		Expect(getLoadedDatabases("app", []string{"sales", "app", "inventory"})).
+			To(Equal([]string{"app", "sales", "inventory"}))
+	})
+})
+
+var _ = Describe("post import queries report", func() {
+	var (
+		cluster    *apiv1.Cluster
+		fakeClient client.Client
+	)
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "cluster-example",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{
+						Import: &apiv1.Import{
+							Type:                     apiv1.MicroserviceSnapshotType,
+							Databases:                []string{"app"},
+							PostImportApplicationSQL: []string{"ANALYZE"},
+						},
+					},
+				},
+			},
+		}
+		fakeClient = fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			Build()
+	})
+
+	It("reports the successful execution of the queries", func(ctx SpecContext) {
+		Expect(reportPostImportQueries(ctx, fakeClient, cluster, nil)).To(Succeed())
+
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, string(apiv1.ConditionPostImportQueries))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("reports the failed query without failing", func(ctx SpecContext) {
+		postImportErr := &logicalimport.PostImportQueryError{Index: 0, Err: errors.New("syntax error")}
+		Expect(reportPostImportQueries(ctx, fakeClient, cluster, postImportErr)).To(Succeed())
+
+		var storedCluster apiv1.Cluster
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), &storedCluster)).To(Succeed())
+		condition := meta.FindStatusCondition(storedCluster.Status.Conditions, string(apiv1.ConditionPostImportQueries))
+		Expect(condition).ToNot(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal(string(apiv1.ConditionReasonPostImportQueriesFailed)))
+		Expect(condition.Message).To(ContainSubstring("syntax error"))
+	})
+
+	It("doesn't report anything without post import queries", func(ctx SpecContext) {
+		cluster.Spec.Bootstrap.InitDB.Import.PostImportApplicationSQL = nil
+		Expect(reportPostImportQueries(ctx, fakeClient, cluster, nil)).To(Succeed())
+		Expect(cluster.Status.Conditions).To(BeEmpty())
+	})
+})
+
+// fakeSQLExecutor records the executed statements, failing the ones
+// contained in the failing set
+type fakeSQLExecutor struct {
+	executed []string
+	failing  map[string]bool
+}
+
+func (fe *fakeSQLExecutor) Exec(query string, _ ...interface{}) (sql.Result, error) {
+	if fe.failing[query] {
+		return nil, errors.New("syntax error")
+	}
+	fe.executed = append(fe.executed, query)
+	return driver.ResultNoRows, nil
+}
+
+var _ = Describe("post-init queries", func() {
+	var executor *fakeSQLExecutor
+
+	BeforeEach(func() {
+		executor = &fakeSQLExecutor{failing: map[string]bool{}}
+	})
+
+	It("executes every query in order", func() {
+		queries := []string{"CREATE ROLE angus", "CREATE ROLE malcolm"}
+		Expect(InitInfo{}.executeQueries(executor, queries)).To(Succeed())
+		Expect(executor.executed).To(Equal(queries))
+	})
+
+	It("stops at the first failing query, reporting only its index", func() {
+		executor.failing["CREATE ROLE malcolm PASSWORD 'secret'"] = true
+		err := InitInfo{}.executeQueries(executor, []string{
+			"CREATE ROLE angus",
+			"CREATE ROLE malcolm PASSWORD 'secret'",
+			"CREATE ROLE bon",
+		})
+		Expect(err).To(MatchError(ContainSubstring("index 1")))
+		Expect(err.Error()).ToNot(ContainSubstring("secret"))
+		Expect(executor.executed).To(Equal([]string{"CREATE ROLE angus"}))
+	})
+
+	It("executes the SQL refs files sorted by name", func() {
+		folder := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(folder, "1.sql"), []byte("CREATE TABLE second()"), 0o600)).To(Succeed())
+		Expect(os.WriteFile(filepath.Join(folder, "0.sql"), []byte("CREATE TABLE first()"), 0o600)).To(Succeed())
+
+		info := InitInfo{PostInitApplicationSQLRefsFolder: folder}
+		Expect(info.executePostInitApplicationSQLRefs(executor)).To(Succeed())
+		Expect(executor.executed).To(Equal([]string{"CREATE TABLE first()", "CREATE TABLE second()"}))
+	})
+
+	It("reports the file containing the failing SQL refs", func() {
+		folder := GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(folder, "0.sql"), []byte("CREATE TABLE first("), 0o600)).To(Succeed())
+		executor.failing["CREATE TABLE first("] = true
+
+		info := InitInfo{PostInitApplicationSQLRefsFolder: folder}
+		Expect(info.executePostInitApplicationSQLRefs(executor)).To(MatchError(ContainSubstring("0.sql")))
+	})
+
+	It("doesn't execute anything without a SQL refs folder", func() {
+		Expect(InitInfo{}.executePostInitApplicationSQLRefs(executor)).To(Succeed())
+		Expect(executor.executed).To(BeEmpty())
+	})
+})
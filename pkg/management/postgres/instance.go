@@ -139,6 +139,9 @@ type Instance struct {
 	// The name of the Pod where the controller is executing
 	PodName string
 
+	// The IP address of the Pod where the controller is executing
+	PodIP string
+
 	// The name of the cluster of which this Pod is belonging
 	ClusterName string
 
@@ -167,6 +170,13 @@ type Instance struct {
 	// MaxStopDelay is the current MaxStopDelay of the cluster
 	MaxStopDelay int32
 
+	// SmartShutdownTimeout is the current SmartShutdownTimeout of the cluster,
+	// zero when not set
+	SmartShutdownTimeout int32
+
+	// MetricsPortTLS specifies whether the metrics endpoint is served over TLS
+	MetricsPortTLS bool
+
 	// canCheckReadiness specifies whether the instance can start being checked for readiness
 	// Is set to true before the instance is run and to false once it exits,
 	// it's used by the readiness probe to know whether it should be short-circuited
@@ -179,8 +189,24 @@ type Instance struct {
 	// fenced entails mightBeUnavailable ( entails as in logical consequence)
 	fenced atomic.Bool
 
+	// timelineDiverged specifies whether the instance is a replica on a
+	// timeline that the primary has never reached
+	timelineDiverged atomic.Bool
+
+	// dataChecksumsVerificationErr is the error raised by a failed
+	// verification of the data checksums, which is not repeated
+	dataChecksumsVerificationErr error
+
 	// slotsReplicatorChan is used to send replication slot configuration to the slot replicator
 	slotsReplicatorChan chan *apiv1.ReplicationSlotsConfiguration
+
+	// primaryUnhealthy specifies whether the primary repeatedly failed
+	// the additional health check configured in the cluster
+	primaryUnhealthy atomic.Bool
+
+	// primaryHealthCheckerChan is used to send the configuration of the
+	// primary health check to the primary health checker
+	primaryHealthCheckerChan chan *apiv1.PrimaryHealthCheckConfiguration
 }
 
 // IsFenced checks whether the instance is marked as fenced
@@ -206,6 +232,30 @@ func (instance *Instance) SetFencing(enabled bool) {
 	}
 }
 
+// IsTimelineDiverged checks whether the instance is a replica
+// on a timeline that diverged from the one of the primary
+func (instance *Instance) IsTimelineDiverged() bool {
+	return instance.timelineDiverged.Load()
+}
+
+// SetTimelineDiverged marks whether the instance is a replica
+// on a timeline that diverged from the one of the primary
+func (instance *Instance) SetTimelineDiverged(diverged bool) {
+	instance.timelineDiverged.Store(diverged)
+}
+
+// IsPrimaryUnhealthy checks whether the primary repeatedly failed
+// the additional health check configured in the cluster
+func (instance *Instance) IsPrimaryUnhealthy() bool {
+	return instance.primaryUnhealthy.Load()
+}
+
+// SetPrimaryUnhealthy marks whether the primary repeatedly failed
+// the additional health check configured in the cluster
+func (instance *Instance) SetPrimaryUnhealthy(unhealthy bool) {
+	instance.primaryUnhealthy.Store(unhealthy)
+}
+
 // SetCanCheckReadiness marks whether the instance should be checked for readiness
 func (instance *Instance) SetCanCheckReadiness(enabled bool) {
 	instance.canCheckReadiness.Store(enabled)
@@ -228,6 +278,18 @@ func (instance *Instance) SlotReplicatorChan() <-chan *apiv1.ReplicationSlotsCon
 	return instance.slotsReplicatorChan
 }
 
+// ConfigurePrimaryHealthChecker sends the configuration to the primary health checker
+func (instance *Instance) ConfigurePrimaryHealthChecker(config *apiv1.PrimaryHealthCheckConfiguration) {
+	go func() {
+		instance.primaryHealthCheckerChan <- config
+	}()
+}
+
+// PrimaryHealthCheckerChan returns the communication channel to the primary health checker
+func (instance *Instance) PrimaryHealthCheckerChan() <-chan *apiv1.PrimaryHealthCheckConfiguration {
+	return instance.primaryHealthCheckerChan
+}
+
 // InstanceCommand are commands for the goroutine managing postgres
 type InstanceCommand string
 
@@ -255,6 +317,8 @@ func NewInstance() *Instance {
 		SocketDirectory:     postgres.SocketDirectory,
 		instanceCommandChan: make(chan InstanceCommand),
 		slotsReplicatorChan: make(chan *apiv1.ReplicationSlotsConfiguration),
+
+		primaryHealthCheckerChan: make(chan *apiv1.PrimaryHealthCheckConfiguration),
 	}
 }
 
@@ -112,10 +112,14 @@ func (ds *databaseSnapshotter) exportDatabases(
 	return nil
 }
 
+// importDatabases restores the passed databases in the target. When some
+// roles have been excluded from the import, the ownership of the objects
+// is not restored, as it may refer to one of them
 func (ds *databaseSnapshotter) importDatabases(
 	ctx context.Context,
 	target *pool.ConnectionPool,
 	databases []string,
+	excludedRoles []string,
 ) error {
 	contextLogger := log.FromContext(ctx)
 
@@ -133,23 +137,13 @@ func (ds *databaseSnapshotter) importDatabases(
 				return err
 			}
 
-			var options []string
-
 			if !exists {
 				contextLogger.Debug("database not found, creating", "databaseName", database)
-				options = append(options, "--create")
 				// if the database doesn't exist we need to connect to postgres
 				targetDatabase = target.GetDsn(postgresDatabase)
 			}
 
-			alwaysPresentOptions := []string{
-				"-U", "postgres",
-				"-d", targetDatabase,
-				"--section", section,
-				generateFileNameForDatabase(database),
-			}
-
-			options = append(options, alwaysPresentOptions...)
+			options := getImportDatabaseOptions(database, targetDatabase, section, !exists, len(excludedRoles) > 0)
 
 			contextLogger.Info("Running pg_restore",
 				"cmd", pgRestore,
@@ -166,6 +160,29 @@ func (ds *databaseSnapshotter) importDatabases(
 	return nil
 }
 
+// getImportDatabaseOptions returns the pg_restore options needed to
+// restore a section of the dump of the passed database
+func getImportDatabaseOptions(database, targetDatabase, section string, create, noOwner bool) []string {
+	var options []string
+
+	if create {
+		options = append(options, "--create")
+	}
+
+	if noOwner {
+		options = append(options, "--no-owner")
+	}
+
+	alwaysPresentOptions := []string{
+		"-U", "postgres",
+		"-d", targetDatabase,
+		"--section", section,
+		generateFileNameForDatabase(database),
+	}
+
+	return append(options, alwaysPresentOptions...)
+}
+
 func (ds *databaseSnapshotter) importDatabaseContent(
 	ctx context.Context,
 	target *pool.ConnectionPool,
@@ -265,10 +282,10 @@ func (ds *databaseSnapshotter) executePostImportQueries(
 		return err
 	}
 
-	for _, query := range postImportQueries {
+	for idx, query := range postImportQueries {
 		_, err := db.Exec(query)
 		if err != nil {
-			return err
+			return &PostImportQueryError{Index: idx, Err: err}
 		}
 	}
 
@@ -0,0 +1,39 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalimport
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("database import options", func() {
+	It("restores the ownership of the objects by default", func() {
+		Expect(getImportDatabaseOptions("app", "dbname=app", "pre-data", false, false)).To(Equal([]string{
+			"-U", "postgres",
+			"-d", "dbname=app",
+			"--section", "pre-data",
+			generateFileNameForDatabase("app"),
+		}))
+	})
+
+	It("creates the missing database without restoring the ownership", func() {
+		options := getImportDatabaseOptions("app", "dbname=postgres", "pre-data", true, true)
+		Expect(options[:2]).To(Equal([]string{"--create", "--no-owner"}))
+		Expect(options).To(ContainElements("dbname=postgres", generateFileNameForDatabase("app")))
+	})
+})
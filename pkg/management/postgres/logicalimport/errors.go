@@ -0,0 +1,40 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalimport
+
+import "fmt"
+
+// PostImportQueryError is raised when one of the queries listed in
+// `postImportApplicationSQL` fails. The imported data is left in place,
+// so the bootstrap can proceed
+type PostImportQueryError struct {
+	// Index is the position of the failed query in the list
+	Index int
+
+	// Err is the error raised by PostgreSQL
+	Err error
+}
+
+// Error implements the error interface
+func (e *PostImportQueryError) Error() string {
+	return fmt.Sprintf("while executing post import query %d: %v", e.Index, e.Err)
+}
+
+// Unwrap returns the error raised by PostgreSQL
+func (e *PostImportQueryError) Unwrap() error {
+	return e.Err
+}
@@ -18,6 +18,7 @@ package logicalimport
 
 import (
 	"context"
+	"errors"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
@@ -37,7 +38,7 @@ func Microservice(
 	contextLogger.Info("starting microservice clone process")
 
 	if err := createDumpsDirectory(); err != nil {
-		return nil
+		return err
 	}
 
 	if err := ds.exportDatabases(ctx, origin, databases); err != nil {
@@ -62,13 +63,16 @@ func Microservice(
 		return err
 	}
 
-	if err := ds.executePostImportQueries(ctx, destination, cluster.Spec.Bootstrap.InitDB.Database); err != nil {
-		return err
+	// The data has been imported at this point, a failing post import
+	// query is reported to the caller without preventing the analyze
+	postImportErr := ds.executePostImportQueries(ctx, destination, cluster.Spec.Bootstrap.InitDB.Database)
+	if postImportErr != nil && !errors.As(postImportErr, new(*PostImportQueryError)) {
+		return postImportErr
 	}
 
 	if err := ds.analyze(ctx, destination, []string{cluster.Spec.Bootstrap.InitDB.Database}); err != nil {
 		return err
 	}
 
-	return nil
+	return postImportErr
 }
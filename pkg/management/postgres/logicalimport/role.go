@@ -56,23 +56,25 @@ type Role struct {
 	IsCurrentUser  bool    `json:"is_current_user,omitempty"`
 }
 
+// cloneRoles imports the selected roles from the origin, returning
+// the names of the roles that have been excluded from the import
 func cloneRoles(
 	ctx context.Context,
 	cluster *apiv1.Cluster,
 	destination *pool.ConnectionPool,
 	origin *pool.ConnectionPool,
-) error {
+) ([]string, error) {
 	rs := roleManager{origin: origin, destination: destination, cluster: cluster}
-	roles, err := rs.getRoles(ctx)
+	roles, excludedRoles, err := rs.getRoles(ctx)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := rs.importRoles(ctx, roles); err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	return excludedRoles, nil
 }
 
 func (rs *roleManager) importRoles(ctx context.Context, roles []Role) error {
@@ -136,15 +138,15 @@ func (rs *roleManager) createSQLStatement(role Role) string {
 	return query
 }
 
-func (rs *roleManager) getRoles(ctx context.Context) ([]Role, error) {
+func (rs *roleManager) getRoles(ctx context.Context) ([]Role, []string, error) {
 	contextLogger := log.FromContext(ctx)
 	originDatabase, err := rs.origin.Connection(postgresDatabase)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	vers, err := utils.GetPgVersion(originDatabase)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	contextLogger.Info("postgres version extracted", "version", vers.String())
 
@@ -179,7 +181,7 @@ func (rs *roleManager) getRoles(ctx context.Context) ([]Role, error) {
 	contextLogger.Debug("executing role snapshot query", "query", query)
 	rows, err = originDatabase.Query(query)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer func() {
 		closeErr := rows.Close()
@@ -188,7 +190,8 @@ func (rs *roleManager) getRoles(ctx context.Context) ([]Role, error) {
 		}
 	}()
 
-	rolesToImport := rs.cluster.Spec.Bootstrap.InitDB.Import.Roles
+	importSpec := rs.cluster.Spec.Bootstrap.InitDB.Import
+	rolesToImport := importSpec.Roles
 	rolesToSkip := []string{
 		"postgres",
 		apiv1.StreamingReplicationUser,
@@ -197,6 +200,7 @@ func (rs *roleManager) getRoles(ctx context.Context) ([]Role, error) {
 	}
 
 	var roles []Role
+	var excludedRoles []string
 	for rows.Next() {
 		var r Role
 		if err := rows.Scan(
@@ -215,7 +219,7 @@ func (rs *roleManager) getRoles(ctx context.Context) ([]Role, error) {
 			&r.RolComment,
 			&r.IsCurrentUser,
 		); err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		if slices.Contains(rolesToSkip, r.Rolname) {
@@ -236,6 +240,17 @@ func (rs *roleManager) getRoles(ctx context.Context) ([]Role, error) {
 			continue
 		}
 
+		if isRoleExcluded(r, importSpec) {
+			contextLogger.Info(
+				"found a role that has been excluded from the import",
+				"excludedRoles", importSpec.ExcludedRoles,
+				"excludeSuperusers", importSpec.ExcludeSuperusers,
+				"role", r.Rolname,
+			)
+			excludedRoles = append(excludedRoles, r.Rolname)
+			continue
+		}
+
 		if r.Rolsuper {
 			contextLogger.Debug(
 				"found a superUser, downgrading permissions",
@@ -248,10 +263,10 @@ func (rs *roleManager) getRoles(ctx context.Context) ([]Role, error) {
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return roles, nil
+	return roles, excludedRoles, nil
 }
 
 func shouldImportRole(rolname string, rolesToImport []string) bool {
@@ -265,3 +280,12 @@ func shouldImportRole(rolname string, rolesToImport []string) bool {
 
 	return false
 }
+
+// isRoleExcluded checks if the user asked not to import the passed role
+func isRoleExcluded(role Role, importSpec *apiv1.Import) bool {
+	if slices.Contains(importSpec.ExcludedRoles, role.Rolname) {
+		return true
+	}
+
+	return role.Rolsuper && importSpec.ExcludeSuperusers
+}
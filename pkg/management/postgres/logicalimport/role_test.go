@@ -0,0 +1,39 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalimport
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("role exclusion", func() {
+	It("excludes the listed roles", func() {
+		importSpec := &apiv1.Import{ExcludedRoles: []string{"legacy"}}
+		Expect(isRoleExcluded(Role{Rolname: "legacy"}, importSpec)).To(BeTrue())
+		Expect(isRoleExcluded(Role{Rolname: "app"}, importSpec)).To(BeFalse())
+	})
+
+	It("excludes the superusers only when requested", func() {
+		superuser := Role{Rolname: "admin", Rolsuper: true}
+		Expect(isRoleExcluded(superuser, &apiv1.Import{})).To(BeFalse())
+		Expect(isRoleExcluded(superuser, &apiv1.Import{ExcludeSuperusers: true})).To(BeTrue())
+		Expect(isRoleExcluded(Role{Rolname: "app"}, &apiv1.Import{ExcludeSuperusers: true})).To(BeFalse())
+	})
+})
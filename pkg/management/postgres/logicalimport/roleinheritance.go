@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	"github.com/jackc/pgx/v5"
+	"k8s.io/utils/strings/slices"
 
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/pool"
@@ -39,7 +40,14 @@ type RoleInheritance struct {
 	Grantor     *string `json:"grantor,omitempty"`
 }
 
-func cloneRoleInheritance(ctx context.Context, destination *pool.ConnectionPool, origin *pool.ConnectionPool) error {
+// cloneRoleInheritance imports the role memberships from the origin,
+// skipping the ones involving the passed excluded roles
+func cloneRoleInheritance(
+	ctx context.Context,
+	destination *pool.ConnectionPool,
+	origin *pool.ConnectionPool,
+	excludedRoles []string,
+) error {
 	rs := roleInheritanceManager{
 		origin:      origin,
 		destination: destination,
@@ -50,7 +58,29 @@ func cloneRoleInheritance(ctx context.Context, destination *pool.ConnectionPool,
 		return err
 	}
 
-	return rs.importRoleInheritance(ctx, ri)
+	return rs.importRoleInheritance(ctx, filterRoleInheritance(ri, excludedRoles))
+}
+
+// filterRoleInheritance removes the role memberships involving
+// any of the passed roles, including the ones they granted, which
+// couldn't be imported as the grantor doesn't exist
+func filterRoleInheritance(ris []RoleInheritance, excludedRoles []string) []RoleInheritance {
+	if len(excludedRoles) == 0 {
+		return ris
+	}
+
+	result := make([]RoleInheritance, 0, len(ris))
+	for _, ri := range ris {
+		if slices.Contains(excludedRoles, ri.RoleID) || slices.Contains(excludedRoles, ri.Member) {
+			continue
+		}
+		if ri.Grantor != nil && slices.Contains(excludedRoles, *ri.Grantor) {
+			continue
+		}
+		result = append(result, ri)
+	}
+
+	return result
 }
 
 func (rs *roleInheritanceManager) importRoleInheritance(ctx context.Context, ris []RoleInheritance) error {
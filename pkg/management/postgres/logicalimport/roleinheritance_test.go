@@ -0,0 +1,45 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logicalimport
+
+import (
+	"k8s.io/utils/pointer"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("role inheritance filtering", func() {
+	memberships := []RoleInheritance{
+		{RoleID: "readers", Member: "app", Grantor: pointer.String("postgres")},
+		{RoleID: "legacy", Member: "app", Grantor: pointer.String("postgres")},
+		{RoleID: "readers", Member: "legacy", Grantor: pointer.String("postgres")},
+		{RoleID: "writers", Member: "app", Grantor: pointer.String("legacy")},
+		{RoleID: "writers", Member: "reporter"},
+	}
+
+	It("keeps every membership when no role is excluded", func() {
+		Expect(filterRoleInheritance(memberships, nil)).To(Equal(memberships))
+	})
+
+	It("removes the memberships involving or granted by the excluded roles", func() {
+		Expect(filterRoleInheritance(memberships, []string{"legacy"})).To(Equal([]RoleInheritance{
+			{RoleID: "readers", Member: "app", Grantor: pointer.String("postgres")},
+			{RoleID: "writers", Member: "reporter"},
+		}))
+	})
+})
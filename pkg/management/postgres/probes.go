@@ -17,6 +17,7 @@ limitations under the License.
 package postgres
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
@@ -30,11 +31,16 @@ import (
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/executablehash"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/fileutils"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/specs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/versions"
 )
 
+// ErrPrimaryUnhealthy is returned when the primary repeatedly failed
+// the additional health check configured in the cluster
+var ErrPrimaryUnhealthy = errors.New("primary repeatedly failed the health check")
+
 // IsServerHealthy check if the instance is healthy
 func (instance *Instance) IsServerHealthy() error {
 	err := PgIsReady()
@@ -54,6 +60,16 @@ func (instance *Instance) IsServerReady() error {
 	if !instance.CanCheckReadiness() {
 		return fmt.Errorf("instance is not ready yet")
 	}
+	if instance.IsTimelineDiverged() {
+		// the data served by this instance is not consistent with
+		// the one of the primary, so it must not receive traffic
+		return fmt.Errorf("timeline diverged from the primary")
+	}
+	if instance.IsPrimaryUnhealthy() {
+		// the primary is running but not working as expected,
+		// and the operator is going to fail over
+		return ErrPrimaryUnhealthy
+	}
 	superUserDB, err := instance.GetSuperUserDB()
 	if err != nil {
 		return err
@@ -112,7 +128,18 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 		return result, err
 	}
 
+	// Reporting an error makes the operator fail over, like it does
+	// when the primary isn't running
+	if result.IsPrimary && instance.IsPrimaryUnhealthy() {
+		return result, ErrPrimaryUnhealthy
+	}
+
 	if result.PendingRestart {
+		result.PendingRestartSettings, err = getPendingRestartSettings(superUserDB)
+		if err != nil {
+			return result, err
+		}
+
 		err = updateResultForDecrease(instance, superUserDB, result)
 		if err != nil {
 			return result, err
@@ -136,6 +163,30 @@ func (instance *Instance) GetStatus() (result *postgres.PostgresqlStatus, err er
 	return result, nil
 }
 
+// getPendingRestartSettings gets the names of the settings whose
+// changes will be applied only after a restart of the instance
+func getPendingRestartSettings(superUserDB *sql.DB) (settings []string, err error) {
+	rows, err := superUserDB.Query("SELECT name FROM pg_settings WHERE pending_restart ORDER BY name")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil && err == nil {
+			err = closeErr
+		}
+	}()
+
+	for rows.Next() {
+		var name string
+		if err = rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		settings = append(settings, name)
+	}
+
+	return settings, rows.Err()
+}
+
 // updateResultForDecrease updates the given postgres.PostgresqlStatus
 // in case of pending restart, by checking whether the restart is due to hot standby
 // sensible parameters being decreased
@@ -293,8 +344,39 @@ func (instance *Instance) fillStatusFromPrimary(result *postgres.PostgresqlStatu
 		&result.CurrentLsn,
 		&result.TimeLineID,
 	)
+	if err != nil {
+		return err
+	}
 
-	return err
+	instance.fillTimelineHistory(result)
+	return nil
+}
+
+// fillTimelineHistory reads the history of the current timeline from the
+// corresponding history file. A missing or invalid history file is not
+// reported as an error, as it doesn't prevent the instance from working.
+func (instance *Instance) fillTimelineHistory(result *postgres.PostgresqlStatus) {
+	// The first timeline has no history
+	if result.TimeLineID <= 1 {
+		return
+	}
+
+	historyFile := filepath.Join(instance.PgData, "pg_wal", postgres.TimelineHistoryFileName(result.TimeLineID))
+	content, err := fileutils.ReadFile(historyFile)
+	if err != nil {
+		log.Debug("Error while reading the timeline history file",
+			"historyFile", historyFile, "err", err)
+		return
+	}
+
+	history, err := postgres.ParseTimelineHistory(string(content))
+	if err != nil {
+		log.Warning("Error while parsing the timeline history file",
+			"historyFile", historyFile, "err", err)
+		return
+	}
+
+	result.TimelineHistory = history
 }
 
 func (instance *Instance) fillReplicationSlotsStatus(result *postgres.PostgresqlStatus) error {
@@ -445,10 +527,18 @@ func (instance *Instance) fillStatusFromReplica(result *postgres.PostgresqlStatu
 			"(SELECT timeline_id FROM pg_control_checkpoint()), " +
 			"COALESCE(pg_last_wal_receive_lsn()::varchar, ''), " +
 			"COALESCE(pg_last_wal_replay_lsn()::varchar, ''), " +
-			"pg_is_wal_replay_paused()")
-	if err := row.Scan(&result.TimeLineID, &result.ReceivedLsn, &result.ReplayLsn, &result.ReplayPaused); err != nil {
+			"pg_is_wal_replay_paused(), " +
+			"COALESCE(NULLIF(current_setting('recovery_min_apply_delay'), '0'), '')")
+	if err := row.Scan(
+		&result.TimeLineID,
+		&result.ReceivedLsn,
+		&result.ReplayLsn,
+		&result.ReplayPaused,
+		&result.RecoveryMinApplyDelay,
+	); err != nil {
 		return err
 	}
+	result.TimelineDiverged = instance.IsTimelineDiverged()
 
 	// Sometimes pg_last_wal_replay_lsn is getting evaluated after
 	// pg_last_wal_receive_lsn and this, if other WALs are received,
@@ -485,6 +575,37 @@ func (instance *Instance) IsWALReceiverActive() (bool, error) {
 	return result, nil
 }
 
+// HasStreamingReplicas checks if at least one replica of the cluster is
+// streaming from this instance, by looking at the `pg_stat_replication` view
+func (instance *Instance) HasStreamingReplicas(ctx context.Context) (bool, error) {
+	superUserDB, err := instance.GetSuperUserDB()
+	if err != nil {
+		return false, err
+	}
+
+	return hasStreamingReplicas(ctx, superUserDB, instance.ClusterName)
+}
+
+// hasStreamingReplicas checks, using the passed connection, if at least one
+// replica of the cluster is streaming from the instance
+func hasStreamingReplicas(ctx context.Context, db *sql.DB, clusterName string) (bool, error) {
+	var result bool
+
+	row := db.QueryRowContext(ctx,
+		`SELECT count(*) > 0
+		FROM pg_catalog.pg_stat_replication
+		WHERE application_name LIKE $1 AND usename = $2 AND state = 'streaming'`,
+		fmt.Sprintf("%s-%%", clusterName),
+		v1.StreamingReplicationUser,
+	)
+	err := row.Scan(&result)
+	if err != nil {
+		return false, err
+	}
+
+	return result, nil
+}
+
 // PgStatWal is a representation of the pg_stat_wal table
 type PgStatWal struct {
 	WalRecords     int64
@@ -445,9 +445,9 @@ func (info InitInfo) writeRestoreWalConfig(backup *apiv1.Backup, cluster *apiv1.
 		0o600)
 }
 
-// GetEnforcedParametersThroughPgControldata will parse the output of pg_controldata in order to get
-// the values of all the hot standby sensible parameters
-func GetEnforcedParametersThroughPgControldata(pgData string) (map[string]string, error) {
+// getPgControldataOutput runs pg_controldata on the passed data directory,
+// returning its output
+func getPgControldataOutput(pgData string) (string, error) {
 	var stdoutBuffer bytes.Buffer
 	var stderrBuffer bytes.Buffer
 	pgControlDataCmd := exec.Command(pgControlDataName,
@@ -461,13 +461,24 @@ func GetEnforcedParametersThroughPgControldata(pgData string) (map[string]string
 		log.Error(err, "while reading pg_controldata",
 			"stderr", stderrBuffer.String(),
 			"stdout", stdoutBuffer.String())
-		return nil, err
+		return "", err
 	}
 
 	log.Debug("pg_controldata stdout", "stdout", stdoutBuffer.String())
 
+	return stdoutBuffer.String(), nil
+}
+
+// GetEnforcedParametersThroughPgControldata will parse the output of pg_controldata in order to get
+// the values of all the hot standby sensible parameters
+func GetEnforcedParametersThroughPgControldata(pgData string) (map[string]string, error) {
+	pgControldataOutput, err := getPgControldataOutput(pgData)
+	if err != nil {
+		return nil, err
+	}
+
 	enforcedParams := map[string]string{}
-	for _, line := range strings.Split(stdoutBuffer.String(), "\n") {
+	for _, line := range strings.Split(pgControldataOutput, "\n") {
 		matches := enforcedParametersRegex.FindStringSubmatch(line)
 		if len(matches) < 3 {
 			continue
@@ -511,6 +522,9 @@ func (info InitInfo) WriteInitialPostgresqlConf(cluster *apiv1.Cluster) error {
 	temporaryInstance := temporaryInitInfo.GetInstance()
 	temporaryInstance.Namespace = info.Namespace
 	temporaryInstance.ClusterName = info.ClusterName
+	// The configuration is rewritten by the instance manager with the IP
+	// address of the instance before PostgreSQL is started
+	temporaryInstance.PodIP = "127.0.0.1"
 
 	_, err = temporaryInstance.RefreshPGHBA(cluster, "")
 	if err != nil {
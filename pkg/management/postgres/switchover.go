@@ -0,0 +1,141 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"k8s.io/utils/clock"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// replicaCatchUpCheckInterval is the time between two checks of the WAL
+// flushed by the target of a switchover
+const replicaCatchUpCheckInterval = 1 * time.Second
+
+// replicaCatchUpChecker reads the WAL positions needed to know whether
+// the target of a switchover caught up with this primary
+type replicaCatchUpChecker interface {
+	// getCurrentWALLSN gets the location of the WAL written until now
+	getCurrentWALLSN(ctx context.Context) (string, error)
+
+	// isReplicaCaughtUp checks whether the passed replica flushed the WAL
+	// up to the passed location. The replica is reported as not streaming
+	// when it isn't connected to this instance
+	isReplicaCaughtUp(ctx context.Context, replicaName, lsn string) (caughtUp, streaming bool, err error)
+}
+
+// dbReplicaCatchUpChecker reads the WAL positions from PostgreSQL
+type dbReplicaCatchUpChecker struct {
+	db *sql.DB
+}
+
+func (dc dbReplicaCatchUpChecker) getCurrentWALLSN(ctx context.Context) (string, error) {
+	var lsn string
+	err := dc.db.QueryRowContext(ctx, "SELECT pg_catalog.pg_current_wal_lsn()").Scan(&lsn)
+	return lsn, err
+}
+
+func (dc dbReplicaCatchUpChecker) isReplicaCaughtUp(
+	ctx context.Context,
+	replicaName, lsn string,
+) (caughtUp, streaming bool, err error) {
+	row := dc.db.QueryRowContext(ctx,
+		`SELECT coalesce(flush_lsn >= $2::pg_lsn, false)
+		FROM pg_catalog.pg_stat_replication
+		WHERE application_name = $1`,
+		replicaName, lsn)
+	switch err := row.Scan(&caughtUp); {
+	case errors.Is(err, sql.ErrNoRows):
+		return false, false, nil
+	case err != nil:
+		return false, false, err
+	}
+
+	return caughtUp, true, nil
+}
+
+// WaitForReplicaCatchUp waits, up to the passed timeout, for the passed
+// replica to flush the WAL this primary has written until now.
+// This reduces the amount of WAL that the fast shutdown of a former
+// primary needs to send to the new primary during a switchover.
+// The function returns immediately when the replica is not streaming from
+// this instance, and doesn't report an error when the timeout expires, as
+// the switchover needs to proceed anyway
+func (instance *Instance) WaitForReplicaCatchUp(
+	ctx context.Context,
+	replicaName string,
+	timeout time.Duration,
+) error {
+	db, err := instance.GetSuperUserDB()
+	if err != nil {
+		return err
+	}
+
+	return waitForReplicaCatchUp(ctx, dbReplicaCatchUpChecker{db: db}, clock.RealClock{}, replicaName, timeout)
+}
+
+func waitForReplicaCatchUp(
+	ctx context.Context,
+	checker replicaCatchUpChecker,
+	clk clock.Clock,
+	replicaName string,
+	timeout time.Duration,
+) error {
+	contextLogger := log.FromContext(ctx)
+
+	targetLSN, err := checker.getCurrentWALLSN(ctx)
+	if err != nil {
+		return err
+	}
+
+	contextLogger.Info("Waiting for the target primary to catch up",
+		"targetPrimary", replicaName,
+		"lsn", targetLSN,
+		"timeout", timeout)
+
+	deadline := clk.Now().Add(timeout)
+	for {
+		caughtUp, streaming, err := checker.isReplicaCaughtUp(ctx, replicaName, targetLSN)
+		switch {
+		case err != nil:
+			return err
+		case !streaming:
+			contextLogger.Info("The target primary is not streaming from this instance, not waiting for it",
+				"targetPrimary", replicaName)
+			return nil
+		case caughtUp:
+			return nil
+		}
+
+		if !clk.Now().Before(deadline) {
+			contextLogger.Warning("Timeout while waiting for the target primary to catch up, proceeding",
+				"targetPrimary", replicaName,
+				"lsn", targetLSN)
+			return nil
+		}
+
+		clk.Sleep(replicaCatchUpCheckInterval)
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,118 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	clocktesting "k8s.io/utils/clock/testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// fakeReplicaCatchUpChecker reports, for every check, the results
+// contained in the caughtUp list, repeating the last one
+type fakeReplicaCatchUpChecker struct {
+	currentLSN string
+	caughtUp   []bool
+	streaming  bool
+	err        error
+	checks     int
+}
+
+func (fc *fakeReplicaCatchUpChecker) getCurrentWALLSN(context.Context) (string, error) {
+	return fc.currentLSN, nil
+}
+
+func (fc *fakeReplicaCatchUpChecker) isReplicaCaughtUp(
+	_ context.Context,
+	_, lsn string,
+) (bool, bool, error) {
+	Expect(lsn).To(Equal(fc.currentLSN))
+
+	result := fc.caughtUp[len(fc.caughtUp)-1]
+	if fc.checks < len(fc.caughtUp) {
+		result = fc.caughtUp[fc.checks]
+	}
+	fc.checks++
+
+	return result, fc.streaming, fc.err
+}
+
+var _ = Describe("waiting for the target of a switchover to catch up", func() {
+	var fakeClock *clocktesting.FakeClock
+
+	BeforeEach(func() {
+		fakeClock = clocktesting.NewFakeClock(time.Now())
+	})
+
+	It("returns as soon as the replica has flushed the current WAL", func(ctx SpecContext) {
+		checker := &fakeReplicaCatchUpChecker{
+			currentLSN: "0/3000060",
+			caughtUp:   []bool{false, false, true},
+			streaming:  true,
+		}
+		Expect(waitForReplicaCatchUp(ctx, checker, fakeClock, "cluster-example-2", time.Minute)).To(Succeed())
+		Expect(checker.checks).To(Equal(3))
+	})
+
+	It("doesn't wait for a replica that is not streaming from this instance", func(ctx SpecContext) {
+		checker := &fakeReplicaCatchUpChecker{
+			currentLSN: "0/3000060",
+			caughtUp:   []bool{false},
+		}
+		Expect(waitForReplicaCatchUp(ctx, checker, fakeClock, "cluster-example-2", time.Minute)).To(Succeed())
+		Expect(checker.checks).To(Equal(1))
+	})
+
+	It("proceeds without an error when the timeout expires", func(ctx SpecContext) {
+		checker := &fakeReplicaCatchUpChecker{
+			currentLSN: "0/3000060",
+			caughtUp:   []bool{false},
+			streaming:  true,
+		}
+		start := fakeClock.Now()
+		Expect(waitForReplicaCatchUp(ctx, checker, fakeClock, "cluster-example-2", 10*time.Second)).To(Succeed())
+		Expect(fakeClock.Since(start)).To(Equal(10 * replicaCatchUpCheckInterval))
+		Expect(checker.checks).To(Equal(11))
+	})
+
+	It("returns the error of the check", func(ctx SpecContext) {
+		checker := &fakeReplicaCatchUpChecker{
+			currentLSN: "0/3000060",
+			caughtUp:   []bool{false},
+			err:        errors.New("connection refused"),
+		}
+		Expect(waitForReplicaCatchUp(ctx, checker, fakeClock, "cluster-example-2", time.Minute)).
+			To(MatchError("connection refused"))
+	})
+
+	It("stops waiting when the context is cancelled", func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		checker := &fakeReplicaCatchUpChecker{
+			currentLSN: "0/3000060",
+			caughtUp:   []bool{false},
+			streaming:  true,
+		}
+		Expect(waitForReplicaCatchUp(ctx, checker, fakeClock, "cluster-example-2", time.Minute)).
+			To(MatchError(context.Canceled))
+	})
+})
@@ -0,0 +1,55 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+// GetPostgresVersion gets the version of PostgreSQL used by the cluster.
+// It is detected from the tag of the image and, when the image is
+// referenced only by its digest, from the PostgreSQL executable
+func GetPostgresVersion(cluster *apiv1.Cluster) (int, error) {
+	reference := utils.NewReference(cluster.GetImageName())
+	if reference.Tag != "" || reference.Digest == "" {
+		return cluster.GetPostgresqlVersion()
+	}
+
+	output, err := exec.Command(postgresName, "-V").Output() // #nosec G204
+	if err != nil {
+		return 0, fmt.Errorf("while detecting the PostgreSQL version: %w", err)
+	}
+
+	return parsePostgresVersionOutput(string(output))
+}
+
+// parsePostgresVersionOutput parses the output of "postgres -V", like
+// "postgres (PostgreSQL) 15.2 (Debian 15.2-1.pgdg110+1)"
+func parsePostgresVersionOutput(output string) (int, error) {
+	fields := strings.Fields(output)
+	if len(fields) < 3 {
+		return 0, fmt.Errorf("unexpected output of %s -V: %q", postgresName, output)
+	}
+
+	return postgres.GetPostgresVersionFromTag(fields[2])
+}
@@ -0,0 +1,47 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PostgreSQL version detection", func() {
+	It("uses the tag of the image when available", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				ImageName: "ghcr.io/cloudnative-pg/postgresql:15.2@sha256:" +
+					"cff94de382ca538861622bbe84cfe03f44f307a9846a5c5eda672cf4dc692866",
+			},
+		}
+		Expect(GetPostgresVersion(&cluster)).To(Equal(150002))
+	})
+
+	It("parses the output of the PostgreSQL executable", func() {
+		Expect(parsePostgresVersionOutput("postgres (PostgreSQL) 15.2\n")).To(Equal(150002))
+		Expect(parsePostgresVersionOutput("postgres (PostgreSQL) 14.7 (Debian 14.7-1.pgdg110+1)\n")).
+			To(Equal(140007))
+	})
+
+	It("complains about an unexpected output", func() {
+		_, err := parsePostgresVersionOutput("postgres\n")
+		Expect(err).To(HaveOccurred())
+	})
+})
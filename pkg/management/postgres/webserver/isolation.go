@@ -0,0 +1,111 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// errPrimaryIsolated is returned by the isolation check when the primary
+// instance can reach neither the Kubernetes API server nor any replica
+var errPrimaryIsolated = errors.New(
+	"the primary instance can reach neither the Kubernetes API server nor any replica")
+
+// checkPrimaryIsolation verifies, when requested in the cluster, that the
+// primary instance is not isolated from the rest of the cluster. An isolated
+// primary can't be demoted by the operator and would keep accepting writes
+// while another instance gets promoted. Failing the liveness probe makes
+// the kubelet restart it, and PostgreSQL won't be started again until the
+// instance manager can reach the Kubernetes API server
+func (ws *remoteWebserverEndpoints) checkPrimaryIsolation(ctx context.Context) error {
+	cluster, err := cache.LoadCluster()
+	if err != nil {
+		// We haven't received the cluster definition yet
+		return nil
+	}
+
+	if cluster.Spec.Probes == nil {
+		return nil
+	}
+	isolationCheck := cluster.Spec.Probes.Liveness.GetIsolationCheck()
+	if !isolationCheck.IsEnabled() || cluster.Spec.Instances < 2 {
+		return nil
+	}
+
+	isPrimary, err := ws.instance.IsPrimary()
+	if err != nil {
+		return err
+	}
+	if !isPrimary {
+		return nil
+	}
+
+	return checkIsolation(ctx, ws.typedClient, cluster, isolationCheck.GetRequestTimeout(),
+		ws.instance.HasStreamingReplicas)
+}
+
+// checkIsolation checks whether the Kubernetes API server can be reached
+// within the passed timeout or, otherwise, whether at least one replica is
+// streaming from the primary instance
+func checkIsolation(
+	ctx context.Context,
+	cli client.Client,
+	cluster *apiv1.Cluster,
+	requestTimeout time.Duration,
+	hasStreamingReplicas func(ctx context.Context) (bool, error),
+) error {
+	requestCtx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	var currentCluster apiv1.Cluster
+	err := cli.Get(requestCtx, client.ObjectKeyFromObject(cluster), &currentCluster)
+	if isAPIServerReachable(err) {
+		return nil
+	}
+
+	log.Info("Cannot reach the Kubernetes API server, checking the replicas",
+		"err", err.Error())
+	streaming, err := hasStreamingReplicas(ctx)
+	if err != nil {
+		return fmt.Errorf("while checking the streaming replicas: %w", err)
+	}
+	if streaming {
+		return nil
+	}
+
+	return errPrimaryIsolated
+}
+
+// isAPIServerReachable checks whether the result of a request to the
+// Kubernetes API server proves that the server has been reached
+func isAPIServerReachable(err error) bool {
+	if err == nil {
+		return true
+	}
+
+	var statusErr apierrors.APIStatus
+	return errors.As(err, &statusErr)
+}
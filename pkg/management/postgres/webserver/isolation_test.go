@@ -0,0 +1,141 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webserver
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/internal/scheme"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// unreachableClient is a client whose requests never reach the
+// Kubernetes API server
+type unreachableClient struct {
+	client.Client
+}
+
+func (uc unreachableClient) Get(context.Context, client.ObjectKey, client.Object, ...client.GetOption) error {
+	return context.DeadlineExceeded
+}
+
+var _ = Describe("detecting an isolated primary", func() {
+	var cluster *apiv1.Cluster
+
+	streamingReplicas := func(streaming bool, err error) func(context.Context) (bool, error) {
+		return func(context.Context) (bool, error) {
+			return streaming, err
+		}
+	}
+
+	BeforeEach(func() {
+		cluster = &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example", Namespace: "default"},
+			Spec: apiv1.ClusterSpec{
+				Instances: 3,
+				Probes: &apiv1.ProbesConfiguration{
+					Liveness: &apiv1.LivenessProbe{
+						IsolationCheck: &apiv1.IsolationCheckConfiguration{Enabled: true},
+					},
+				},
+			},
+		}
+	})
+
+	It("passes when the Kubernetes API server is reachable", func(ctx SpecContext) {
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			WithObjects(cluster).
+			Build()
+		Expect(checkIsolation(ctx, cli, cluster, time.Second, streamingReplicas(false, nil))).To(Succeed())
+	})
+
+	It("passes when the Kubernetes API server answers with an error", func(ctx SpecContext) {
+		cli := fake.NewClientBuilder().
+			WithScheme(scheme.BuildWithAllKnownScheme()).
+			Build()
+		Expect(checkIsolation(ctx, cli, cluster, time.Second, streamingReplicas(false, nil))).To(Succeed())
+	})
+
+	It("passes when the Kubernetes API server is unreachable but a replica is streaming", func(ctx SpecContext) {
+		Expect(checkIsolation(ctx, unreachableClient{}, cluster, time.Second, streamingReplicas(true, nil))).
+			To(Succeed())
+	})
+
+	It("fails when the primary is isolated", func(ctx SpecContext) {
+		err := checkIsolation(ctx, unreachableClient{}, cluster, time.Second, streamingReplicas(false, nil))
+		Expect(err).To(MatchError(errPrimaryIsolated))
+	})
+
+	It("fails when the streaming replicas can't be checked", func(ctx SpecContext) {
+		err := checkIsolation(ctx, unreachableClient{}, cluster, time.Second,
+			streamingReplicas(false, errors.New("connection refused")))
+		Expect(err).To(HaveOccurred())
+		Expect(err).ToNot(MatchError(errPrimaryIsolated))
+	})
+
+	It("is skipped unless the primary of a cluster with replicas requires it", func(ctx SpecContext) {
+		instance := postgres.NewInstance()
+		instance.PgData = GinkgoT().TempDir()
+		Expect(os.WriteFile(filepath.Join(instance.PgData, "standby.signal"), nil, 0o600)).To(Succeed())
+		ws := &remoteWebserverEndpoints{typedClient: unreachableClient{}, instance: instance}
+		DeferCleanup(cache.Delete, cache.ClusterKey)
+
+		// the instance is a replica
+		cache.Store(cache.ClusterKey, cluster)
+		Expect(ws.checkPrimaryIsolation(ctx)).To(Succeed())
+
+		// the cluster is made of a single instance
+		cluster.Spec.Instances = 1
+		Expect(ws.checkPrimaryIsolation(ctx)).To(Succeed())
+
+		// the isolation check is disabled
+		cluster.Spec.Instances = 3
+		cluster.Spec.Probes.Liveness.IsolationCheck.Enabled = false
+		Expect(ws.checkPrimaryIsolation(ctx)).To(Succeed())
+	})
+})
+
+var _ = Describe("checking whether the Kubernetes API server has been reached", func() {
+	It("considers reached a server that answered the request", func() {
+		Expect(isAPIServerReachable(nil)).To(BeTrue())
+		Expect(isAPIServerReachable(apierrors.NewNotFound(schema.GroupResource{}, "cluster-example"))).
+			To(BeTrue())
+		Expect(isAPIServerReachable(apierrors.NewForbidden(schema.GroupResource{}, "cluster-example", nil))).
+			To(BeTrue())
+	})
+
+	It("considers unreachable a server that didn't answer the request", func() {
+		Expect(isAPIServerReachable(context.DeadlineExceeded)).To(BeFalse())
+		Expect(isAPIServerReachable(errors.New("connection refused"))).To(BeFalse())
+	})
+})
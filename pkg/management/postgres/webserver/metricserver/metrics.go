@@ -17,6 +17,7 @@ limitations under the License.
 package metricserver
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net/http"
 
@@ -27,6 +28,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/webserver"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	pg "github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
 )
 
 // MetricsServer exposes the metrics of the postgres instance
@@ -58,6 +60,23 @@ func New(serverInstance *postgres.Instance) (*MetricsServer, error) {
 		ReadTimeout:       webserver.DefaultReadTimeout,
 		ReadHeaderTimeout: webserver.DefaultReadHeaderTimeout,
 	}
+	if serverInstance.MetricsPortTLS {
+		server.TLSConfig = &tls.Config{
+			MinVersion: tls.VersionTLS12,
+			// The server certificate is rotated by the operator and
+			// refreshed on disk by the instance manager, so we load it
+			// again at every handshake
+			GetCertificate: func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+				certificate, err := tls.LoadX509KeyPair(
+					pg.ServerCertificateLocation,
+					pg.ServerKeyLocation)
+				if err != nil {
+					return nil, fmt.Errorf("while loading the server certificate: %w", err)
+				}
+				return &certificate, nil
+			},
+		}
+	}
 
 	metricServer := &MetricsServer{
 		Webserver: webserver.NewWebServer(serverInstance, server),
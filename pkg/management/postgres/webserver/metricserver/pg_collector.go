@@ -28,6 +28,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/cloudnative-pg/cloudnative-pg/internal/management/cache"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres"
 	m "github.com/cloudnative-pg/cloudnative-pg/pkg/management/postgres/metrics"
@@ -67,6 +68,7 @@ type metrics struct {
 	PgVersion                *prometheus.GaugeVec
 	FirstRecoverabilityPoint prometheus.Gauge
 	FencingOn                prometheus.Gauge
+	CertificateExpiration    *prometheus.GaugeVec
 	PgStatWalMetrics         PgStatWalMetrics
 }
 
@@ -175,6 +177,12 @@ func newMetrics() *metrics {
 			Name:      "fencing_on",
 			Help:      "1 if the instance is fenced, 0 otherwise",
 		}),
+		CertificateExpiration: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: PrometheusNamespace,
+			Subsystem: subsystem,
+			Name:      "certificate_expiration",
+			Help:      "The expiration date of the certificates used by the cluster as a unix timestamp",
+		}, []string{"secret"}),
 		PgStatWalMetrics: PgStatWalMetrics{
 			WalRecords: prometheus.NewGaugeVec(prometheus.GaugeOpts{
 				Namespace: PrometheusNamespace,
@@ -250,6 +258,7 @@ func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
 	e.Metrics.PgVersion.Describe(ch)
 	e.Metrics.FirstRecoverabilityPoint.Describe(ch)
 	e.Metrics.FencingOn.Describe(ch)
+	e.Metrics.CertificateExpiration.Describe(ch)
 
 	if e.queries != nil {
 		e.queries.Describe(ch)
@@ -284,6 +293,7 @@ func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
 	e.Metrics.PgWALDirectory.Collect(ch)
 	e.Metrics.PgVersion.Collect(ch)
 	e.Metrics.FirstRecoverabilityPoint.Collect(ch)
+	e.Metrics.CertificateExpiration.Collect(ch)
 
 	if version, _ := e.instance.GetPgVersion(); version.Major >= 14 {
 		e.Metrics.PgStatWalMetrics.WalSync.Collect(ch)
@@ -357,6 +367,9 @@ func (e *Exporter) collectPgMetrics(ch chan<- prometheus.Metric) {
 
 		// getting the first point of recoverability
 		e.collectFromPrimaryFirstPointOnTimeRecovery()
+
+		// getting the expiration dates of the certificates
+		e.collectFromPrimaryCertificateExpiration()
 	}
 
 	if err := collectPGWalArchiveMetric(e); err != nil {
@@ -432,6 +445,40 @@ func (e *Exporter) collectFromPrimaryFirstPointOnTimeRecovery() {
 	e.Metrics.FirstRecoverabilityPoint.Set(float64(parsedTS.Unix()))
 }
 
+func (e *Exporter) collectFromPrimaryCertificateExpiration() {
+	const errorLabel = "Collect.CertificateExpiration"
+
+	cluster, err := cache.LoadCluster()
+	// there isn't a cached object yet
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return
+	}
+	// programmatic error, we should report that
+	if err != nil {
+		log.Error(err, "error while retrieving cluster cache object")
+		e.Metrics.Error.Set(1)
+		e.Metrics.PgCollectionErrors.WithLabelValues(errorLabel).Inc()
+		e.Metrics.CertificateExpiration.Reset()
+		return
+	}
+
+	// the certificates may have been replaced since the last collection
+	e.Metrics.CertificateExpiration.Reset()
+	for secretName, expirationDate := range cluster.Status.Certificates.Expirations {
+		expirationTime, err := time.Parse(certs.ExpirationDateLayout, expirationDate)
+		if err != nil {
+			log.Error(err, "while parsing the certificate expiration date",
+				"secretName", secretName,
+				"expirationDate", expirationDate)
+			e.Metrics.Error.Set(1)
+			e.Metrics.PgCollectionErrors.WithLabelValues(errorLabel).Inc()
+			continue
+		}
+
+		e.Metrics.CertificateExpiration.WithLabelValues(secretName).Set(float64(expirationTime.Unix()))
+	}
+}
+
 func (e *Exporter) collectFromPrimarySynchronousStandbysNumber(db *sql.DB) {
 	nStandbys, err := getSynchronousStandbysNumber(db)
 	if err != nil {
@@ -76,6 +76,7 @@ func (ws *remoteWebserverEndpoints) isServerHealthy(w http.ResponseWriter, r *ht
 	if ws.instance.PgRewindIsRunning || ws.instance.MightBeUnavailable() {
 		log.Trace("Liveness probe skipped")
 		_, _ = fmt.Fprint(w, "Skipped")
+		return
 	}
 
 	err := ws.instance.IsServerHealthy()
@@ -85,6 +86,13 @@ func (ws *remoteWebserverEndpoints) isServerHealthy(w http.ResponseWriter, r *ht
 		return
 	}
 
+	err = ws.checkPrimaryIsolation(r.Context())
+	if err != nil {
+		log.Info("Liveness probe failing", "err", err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	log.Trace("Liveness probe succeeding")
 	_, _ = fmt.Fprint(w, "OK")
 }
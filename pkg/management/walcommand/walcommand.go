@@ -0,0 +1,86 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package walcommand runs the custom commands archiving and restoring
+// the WAL files, as configured in the cluster
+package walcommand
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/execlog"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/log"
+)
+
+// Expand replaces, in every argument of the command, `%p` with the path of
+// the WAL file, `%f` with its name and `%%` with a single `%` character,
+// like PostgreSQL does with `archive_command` and `restore_command`
+func Expand(command []string, walPath, walName string) []string {
+	result := make([]string, len(command))
+	for idx, argument := range command {
+		var builder strings.Builder
+		for i := 0; i < len(argument); i++ {
+			if argument[i] != '%' || i+1 == len(argument) {
+				builder.WriteByte(argument[i])
+				continue
+			}
+
+			switch argument[i+1] {
+			case 'p':
+				builder.WriteString(walPath)
+			case 'f':
+				builder.WriteString(walName)
+			case '%':
+				builder.WriteByte('%')
+			default:
+				builder.WriteByte(argument[i])
+				continue
+			}
+			i++
+		}
+		result[idx] = builder.String()
+	}
+
+	return result
+}
+
+// Run executes the command, after having expanded its placeholders, and
+// returns an error if it doesn't exit with a zero status
+func Run(ctx context.Context, command []string, walPath, walName string) error {
+	contextLog := log.FromContext(ctx)
+
+	if len(command) == 0 {
+		return fmt.Errorf("empty command")
+	}
+
+	arguments := Expand(command, walPath, walName)
+	contextLog.Trace("Executing custom WAL command",
+		"walName", walName,
+		"command", arguments)
+
+	cmd := exec.CommandContext(ctx, arguments[0], arguments[1:]...) // #nosec G204
+	cmd.Env = os.Environ()
+	if err := execlog.RunStreaming(cmd, filepath.Base(arguments[0])); err != nil {
+		return fmt.Errorf("unexpected failure invoking %s: %w", arguments[0], err)
+	}
+
+	return nil
+}
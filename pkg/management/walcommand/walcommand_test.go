@@ -0,0 +1,59 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package walcommand
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Custom WAL commands", func() {
+	It("replaces the placeholders in every argument", func() {
+		Expect(Expand(
+			[]string{"/usr/bin/archive", "--file=%f", "%p", "s3://bucket/%f"},
+			"pg_wal/000000010000000000000001",
+			"000000010000000000000001",
+		)).To(Equal([]string{
+			"/usr/bin/archive",
+			"--file=000000010000000000000001",
+			"pg_wal/000000010000000000000001",
+			"s3://bucket/000000010000000000000001",
+		}))
+	})
+
+	It("handles the escaped and the unknown sequences", func() {
+		Expect(Expand(
+			[]string{"100%%", "%%p", "%x", "50%"},
+			"pg_wal/000000010000000000000001",
+			"000000010000000000000001",
+		)).To(Equal([]string{"100%", "%p", "%x", "50%"}))
+	})
+
+	It("doesn't modify the command", func() {
+		command := []string{"/usr/bin/archive", "%p"}
+		Expand(command, "pg_wal/000000010000000000000001", "000000010000000000000001")
+		Expect(command).To(Equal([]string{"/usr/bin/archive", "%p"}))
+	})
+
+	It("reports the failures of the command", func(ctx context.Context) {
+		Expect(Run(ctx, []string{"true", "%p"}, "path", "name")).To(Succeed())
+		Expect(Run(ctx, []string{"false", "%p"}, "path", "name")).ToNot(Succeed())
+		Expect(Run(ctx, nil, "path", "name")).ToNot(Succeed())
+	})
+})
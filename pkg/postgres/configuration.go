@@ -36,7 +36,10 @@ local all all peer map=local
 hostssl postgres streaming_replica all cert
 hostssl replication streaming_replica all cert
 hostssl all cnpg_pooler_pgbouncer all cert
-
+{{ if .ApplicationCertificateRule }}
+# Require client certificate authentication for the application user
+{{.ApplicationCertificateRule}}
+{{ end }}
 {{ range $rule := .UserRules }}
 {{ $rule -}}
 {{ end }}
@@ -144,8 +147,39 @@ host all all all {{.DefaultAuthenticationMethod}}
 
 	// SynchronousStandbyNames is the postgresql parameter key for synchronous standbys
 	SynchronousStandbyNames = "synchronous_standby_names"
+
+	// HotStandbyFeedback is the postgresql parameter key controlling the
+	// feedback sent by a standby about the queries it is running
+	HotStandbyFeedback = "hot_standby_feedback"
+
+	// RecoveryMinApplyDelay is the postgresql parameter key for the delay
+	// used by a standby to apply the WAL files
+	RecoveryMinApplyDelay = "recovery_min_apply_delay"
+
+	// WalSenderTimeout is the postgresql parameter key for the time after
+	// which an inactive replication connection is terminated by the primary
+	WalSenderTimeout = "wal_sender_timeout"
+
+	// WalReceiverTimeout is the postgresql parameter key for the time after
+	// which an inactive replication connection is terminated by the standby
+	WalReceiverTimeout = "wal_receiver_timeout"
+
+	// StatementTimeout is the postgresql parameter key for the time after
+	// which any statement is aborted
+	StatementTimeout = "statement_timeout"
+
+	// IdleInTransactionSessionTimeout is the postgresql parameter key for
+	// the time after which a session idling in a transaction is terminated
+	IdleInTransactionSessionTimeout = "idle_in_transaction_session_timeout"
 )
 
+// StandbySettings are the parameters managed by the operator only on the
+// standby instances, through the postgresql.auto.conf file
+var StandbySettings = []string{
+	HotStandbyFeedback,
+	RecoveryMinApplyDelay,
+}
+
 // hbaTemplate is the template used to create the HBA configuration
 var hbaTemplate = template.Must(template.New("pg_hba.conf").Parse(hbaTemplateString))
 
@@ -216,6 +250,10 @@ type ConfigurationInfo struct {
 	// The number of desired number of synchronous replicas
 	SyncReplicas int
 
+	// The method used to choose the synchronous replicas,
+	// either "ANY" or "FIRST". Defaults to "ANY"
+	SyncReplicasMethod string
+
 	// If the generated configuration should contain shared_preload_libraries too or no
 	IncludingSharedPreloadLibraries bool
 
@@ -224,6 +262,22 @@ type ConfigurationInfo struct {
 
 	// Is this a replica cluster?
 	IsReplicaCluster bool
+
+	// The value of wal_sender_timeout in seconds, if set by the user
+	WalSenderTimeout *int32
+
+	// The value of wal_receiver_timeout in seconds, if set by the user
+	WalReceiverTimeout *int32
+
+	// The value of statement_timeout in seconds, if set by the user
+	StatementTimeout *int32
+
+	// The value of idle_in_transaction_session_timeout in seconds, if set by the user
+	IdleInTransactionSessionTimeout *int32
+
+	// The value of listen_addresses, overriding the mandatory one listening
+	// on every network interface. Used only when IncludingMandatory is true
+	ListenAddresses string
 }
 
 // ManagedExtension defines all the information about a managed extension
@@ -406,7 +460,7 @@ var (
 // CreateHBARules will create the content of pg_hba.conf file given
 // the rules set by the cluster spec
 func CreateHBARules(hba []string,
-	defaultAuthenticationMethod, ldapConfigString string,
+	defaultAuthenticationMethod, ldapConfigString, applicationCertificateRule string,
 ) (string, error) {
 	var hbaContent bytes.Buffer
 
@@ -414,10 +468,12 @@ func CreateHBARules(hba []string,
 		UserRules                   []string
 		LDAPConfiguration           string
 		DefaultAuthenticationMethod string
+		ApplicationCertificateRule  string
 	}{
 		UserRules:                   hba,
 		LDAPConfiguration:           ldapConfigString,
 		DefaultAuthenticationMethod: defaultAuthenticationMethod,
+		ApplicationCertificateRule:  applicationCertificateRule,
 	}
 
 	if err := hbaTemplate.Execute(&hbaContent, templateData); err != nil {
@@ -427,6 +483,22 @@ func CreateHBARules(hba []string,
 	return hbaContent.String(), nil
 }
 
+// CreateApplicationCertificateHBARule creates the pg_hba.conf rules requiring
+// the owner of the application database to authenticate with a TLS
+// client certificate, rejecting its connections without TLS.
+// As pg_hba.conf has no way to escape a double quote inside a quoted
+// name, the names containing one are rejected
+func CreateApplicationCertificateHBARule(database, owner string) (string, error) {
+	for _, name := range []string{database, owner} {
+		if strings.Contains(name, `"`) {
+			return "", fmt.Errorf("name %q cannot be used in pg_hba.conf as it contains a double quote", name)
+		}
+	}
+
+	return fmt.Sprintf("hostssl \"%[1]s\" \"%[2]s\" all cert\nhostnossl \"%[1]s\" \"%[2]s\" all reject",
+		database, owner), nil
+}
+
 // PgConfiguration wraps configuration parameters with some checks
 type PgConfiguration struct {
 	configs map[string]string
@@ -452,8 +524,10 @@ func (p *PgConfiguration) AddSharedPreloadLibrary(newLibrary string) {
 	if len(newLibrary) == 0 {
 		return
 	}
-	if strings.Contains(p.configs[SharedPreloadLibraries], newLibrary) {
-		return
+	for _, library := range strings.Split(p.configs[SharedPreloadLibraries], ",") {
+		if strings.TrimSpace(library) == newLibrary {
+			return
+		}
 	}
 	if libraries, ok := p.configs[SharedPreloadLibraries]; ok &&
 		libraries != "" {
@@ -501,11 +575,18 @@ func CreatePostgresqlConfiguration(info ConfigurationInfo) *PgConfiguration {
 		configuration.OverwriteConfig(key, value)
 	}
 
+	// Apply the timeouts, overriding the defaults
+	setTimeouts(info, configuration)
+
 	// Apply all mandatory settings, on top of defaults and user settings
 	if info.IncludingMandatory {
 		for key, value := range info.Settings.MandatorySettings {
 			configuration.OverwriteConfig(key, value)
 		}
+
+		if info.ListenAddresses != "" {
+			configuration.OverwriteConfig("listen_addresses", info.ListenAddresses)
+		}
 	}
 
 	// Apply the correct archive_mode
@@ -570,6 +651,7 @@ func setUserSharedPreloadLibraries(info ConfigurationInfo, configuration *PgConf
 	var libraries []string
 	for _, library := range append(info.AdditionalSharedPreloadLibraries, oldLibraries...) {
 		// if any, delete empty string
+		library = strings.TrimSpace(library)
 		if library == "" {
 			continue
 		}
@@ -590,8 +672,13 @@ func setReplicasListConfigurations(info ConfigurationInfo, configuration *PgConf
 		for idx, name := range info.SyncReplicasElectable {
 			escapedReplicas[idx] = escapePostgresConfLiteral(name)
 		}
+		method := info.SyncReplicasMethod
+		if method == "" {
+			method = "ANY"
+		}
 		configuration.OverwriteConfig(SynchronousStandbyNames, fmt.Sprintf(
-			"ANY %v (%v)",
+			"%v %v (%v)",
+			method,
 			info.SyncReplicas,
 			strings.Join(escapedReplicas, ",")))
 	}
@@ -602,6 +689,27 @@ func setReplicasListConfigurations(info ConfigurationInfo, configuration *PgConf
 	}
 }
 
+// setTimeouts sets the timeouts requested by the user
+func setTimeouts(info ConfigurationInfo, configuration *PgConfiguration) {
+	timeouts := map[string]*int32{
+		WalSenderTimeout:                info.WalSenderTimeout,
+		WalReceiverTimeout:              info.WalReceiverTimeout,
+		StatementTimeout:                info.StatementTimeout,
+		IdleInTransactionSessionTimeout: info.IdleInTransactionSessionTimeout,
+	}
+
+	for parameter, value := range timeouts {
+		if value != nil {
+			configuration.OverwriteConfig(parameter, FormatSeconds(*value))
+		}
+	}
+}
+
+// FormatSeconds formats a number of seconds as a PostgreSQL time value
+func FormatSeconds(seconds int32) string {
+	return fmt.Sprintf("%ds", seconds)
+}
+
 // CreatePostgresqlConfFile creates the contents of the postgresql.conf file
 func CreatePostgresqlConfFile(configuration *PgConfiguration) (string, string) {
 	// We need to be able to compare two configurations generated
@@ -179,6 +179,75 @@ var _ = Describe("PostgreSQL configuration creation", func() {
 			Expect(config.GetConfig("synchronous_standby_names")).
 				To(Equal("ANY 2 (\"one\",\"two\",\"three\")"))
 		})
+
+		It("uses the requested synchronous replication method", func() {
+			info := ConfigurationInfo{
+				Settings:              CnpgConfigurationSettings,
+				MajorVersion:          130000,
+				UserSettings:          settings,
+				IncludingMandatory:    true,
+				SyncReplicasElectable: []string{"two", "one"},
+				SyncReplicas:          1,
+				SyncReplicasMethod:    "FIRST",
+			}
+			config := CreatePostgresqlConfiguration(info)
+			Expect(config.GetConfig("synchronous_standby_names")).
+				To(Equal("FIRST 1 (\"two\",\"one\")"))
+		})
+	})
+
+	It("applies the replication timeouts overriding the user settings", func() {
+		senderTimeout := int32(30)
+		receiverTimeout := int32(0)
+		info := ConfigurationInfo{
+			Settings:     CnpgConfigurationSettings,
+			MajorVersion: 130000,
+			UserSettings: map[string]string{
+				"wal_sender_timeout": "10s",
+			},
+			IncludingMandatory: true,
+			WalSenderTimeout:   &senderTimeout,
+			WalReceiverTimeout: &receiverTimeout,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("wal_sender_timeout")).To(Equal("30s"))
+		Expect(config.GetConfig("wal_receiver_timeout")).To(Equal("0s"))
+	})
+
+	It("applies the session timeouts only when they are set", func() {
+		statementTimeout := int32(300)
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       130000,
+			IncludingMandatory: true,
+			StatementTimeout:   &statementTimeout,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("statement_timeout")).To(Equal("300s"))
+		Expect(config.GetConfig("idle_in_transaction_session_timeout")).To(BeEmpty())
+	})
+
+	It("listens on every interface unless requested otherwise", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       130000,
+			IncludingMandatory: true,
+		}
+		Expect(CreatePostgresqlConfiguration(info).GetConfig("listen_addresses")).To(Equal("*"))
+
+		info.ListenAddresses = "10.1.2.3"
+		Expect(CreatePostgresqlConfiguration(info).GetConfig("listen_addresses")).To(Equal("10.1.2.3"))
+	})
+
+	It("uses the default replication timeouts when they are not set", func() {
+		info := ConfigurationInfo{
+			Settings:           CnpgConfigurationSettings,
+			MajorVersion:       130000,
+			IncludingMandatory: true,
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig("wal_sender_timeout")).To(Equal("5s"))
+		Expect(config.GetConfig("wal_receiver_timeout")).To(Equal("5s"))
 	})
 
 	It("checks if PreserveFixedSettingsFromUser works properly", func() {
@@ -233,19 +302,42 @@ var _ = Describe("pg_hba.conf generation", func() {
 	}
 
 	It("insert the spec configuration between an header and a footer when the version can not be parsed", func() {
-		Expect(CreateHBARules(specRules, "md5", "")).To(
+		Expect(CreateHBARules(specRules, "md5", "", "")).To(
 			ContainSubstring("\ntwo\n"))
 	})
 
 	It("really use the passed default authentication method", func() {
-		Expect(CreateHBARules(specRules, "this-one", "")).To(
+		Expect(CreateHBARules(specRules, "this-one", "", "")).To(
 			ContainSubstring("\nhost all all all this-one\n"))
 	})
 
 	It("really uses the ldapConfigString", func() {
-		Expect(CreateHBARules(specRules, "defaultAuthenticationMethod", "ldapConfigString")).To(
+		Expect(CreateHBARules(specRules, "defaultAuthenticationMethod", "ldapConfigString", "")).To(
 			ContainSubstring("\nldapConfigString\n"))
 	})
+
+	It("requires a client certificate for the application user when requested", func() {
+		rule, err := CreateApplicationCertificateHBARule("app", "app")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(rule).To(Equal("hostssl \"app\" \"app\" all cert\nhostnossl \"app\" \"app\" all reject"))
+
+		hba, err := CreateHBARules(specRules, "md5", "", rule)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hba).To(ContainSubstring("\n" + rule + "\n"))
+		Expect(strings.Index(hba, rule)).To(BeNumerically("<", strings.Index(hba, "\none\n")))
+
+		hba, err = CreateHBARules(specRules, "md5", "", "")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(hba).ToNot(ContainSubstring("application user"))
+	})
+
+	It("rejects the names that cannot be quoted in the application certificate rule", func() {
+		_, err := CreateApplicationCertificateHBARule(`my"db`, "owner")
+		Expect(err).To(HaveOccurred())
+
+		_, err = CreateApplicationCertificateHBARule("app", `my"owner`)
+		Expect(err).To(HaveOccurred())
+	})
 })
 
 var _ = Describe("pgaudit", func() {
@@ -285,6 +377,18 @@ var _ = Describe("pgaudit", func() {
 		Expect(libraries).ToNot(ContainElement(""))
 		Expect(libraries).To(ContainElements("pgaudit", "other_library"))
 	})
+	It("doesn't confuse a managed library with a user one having a similar name", func() {
+		info := ConfigurationInfo{
+			Settings:                         CnpgConfigurationSettings,
+			MajorVersion:                     130000,
+			UserSettings:                     map[string]string{"pgaudit.something": "something"},
+			IncludingSharedPreloadLibraries:  true,
+			IncludingMandatory:               true,
+			AdditionalSharedPreloadLibraries: []string{" pgaudit_custom", "pgaudit "},
+		}
+		config := CreatePostgresqlConfiguration(info)
+		Expect(config.GetConfig(SharedPreloadLibraries)).To(Equal("pgaudit_custom,pgaudit"))
+	})
 	It("adds pg_stat_statements to shared_preload_library", func() {
 		info := ConfigurationInfo{
 			Settings:                        CnpgConfigurationSettings,
@@ -28,20 +28,24 @@ import (
 
 // PostgresqlStatus defines a status for every instance in the cluster
 type PostgresqlStatus struct {
-	CurrentLsn                LSN        `json:"currentLsn,omitempty"`
-	ReceivedLsn               LSN        `json:"receivedLsn,omitempty"`
-	ReplayLsn                 LSN        `json:"replayLsn,omitempty"`
-	SystemID                  string     `json:"systemID"`
-	IsPrimary                 bool       `json:"isPrimary"`
-	ReplayPaused              bool       `json:"replayPaused"`
-	PendingRestart            bool       `json:"pendingRestart"`
-	PendingRestartForDecrease bool       `json:"pendingRestartForDecrease"`
-	IsWalReceiverActive       bool       `json:"isWalReceiverActive"`
-	Node                      string     `json:"node"`
-	Pod                       corev1.Pod `json:"pod"`
-	IsPgRewindRunning         bool       `json:"isPgRewindRunning"`
-	TotalInstanceSize         string     `json:"totalInstanceSize"`
-	MightBeUnavailable        bool       `json:"mightBeUnavailable"`
+	CurrentLsn                LSN    `json:"currentLsn,omitempty"`
+	ReceivedLsn               LSN    `json:"receivedLsn,omitempty"`
+	ReplayLsn                 LSN    `json:"replayLsn,omitempty"`
+	SystemID                  string `json:"systemID"`
+	IsPrimary                 bool   `json:"isPrimary"`
+	ReplayPaused              bool   `json:"replayPaused"`
+	TimelineDiverged          bool   `json:"timelineDiverged"`
+	RecoveryMinApplyDelay     string `json:"recoveryMinApplyDelay,omitempty"`
+	PendingRestart            bool   `json:"pendingRestart"`
+	PendingRestartForDecrease bool   `json:"pendingRestartForDecrease"`
+	// the settings whose change requires a restart
+	PendingRestartSettings []string   `json:"pendingRestartSettings,omitempty"`
+	IsWalReceiverActive    bool       `json:"isWalReceiverActive"`
+	Node                   string     `json:"node"`
+	Pod                    corev1.Pod `json:"pod"`
+	IsPgRewindRunning      bool       `json:"isPgRewindRunning"`
+	TotalInstanceSize      string     `json:"totalInstanceSize"`
+	MightBeUnavailable     bool       `json:"mightBeUnavailable"`
 	// populated when MightBeUnavailable reported a healthy status even if it found an error
 	MightBeUnavailableMaskedError string `json:"mightBeUnavailableMaskedError,omitempty"`
 
@@ -68,6 +72,10 @@ type PostgresqlStatus struct {
 	// SELECT timeline_id FROM pg_control_checkpoint()
 	TimeLineID int `json:"timeLineID,omitempty"`
 
+	// The timeline switches leading to the current timeline, as
+	// recorded in its history file
+	TimelineHistory []TimelineHistoryEntry `json:"timelineHistory,omitempty"`
+
 	// This field is set when there is an error while extracting the
 	// status of a Pod
 	Error error `json:"-"`
@@ -265,6 +273,24 @@ func (list *PostgresqlStatusList) Less(i, j int) bool {
 		return false
 	}
 
+	// Replicas whose WAL replay is paused go after the others,
+	// as they are not aligned with the received WAL
+	switch {
+	case list.Items[i].ReplayPaused && !list.Items[j].ReplayPaused:
+		return false
+	case !list.Items[i].ReplayPaused && list.Items[j].ReplayPaused:
+		return true
+	}
+
+	// Replicas on a diverged timeline go after the others, as they
+	// are the worst candidates to be promoted
+	switch {
+	case list.Items[i].TimelineDiverged && !list.Items[j].TimelineDiverged:
+		return false
+	case !list.Items[i].TimelineDiverged && list.Items[j].TimelineDiverged:
+		return true
+	}
+
 	// Compare received LSN (bigger LSN orders first)
 	if list.Items[i].ReceivedLsn != list.Items[j].ReceivedLsn {
 		return !list.Items[i].ReceivedLsn.Less(list.Items[j].ReceivedLsn)
@@ -294,6 +320,22 @@ func (list PostgresqlStatusList) AreWalReceiversDown(primaryName string) bool {
 	return true
 }
 
+// AreReplicasStreaming checks if every replica of the cluster is
+// streaming from the primary, ignoring the instances that might be
+// temporarily unavailable
+func (list PostgresqlStatusList) AreReplicasStreaming(primaryName string) bool {
+	for idx := range list.Items {
+		if list.Items[idx].Pod.Name == primaryName || list.Items[idx].MightBeUnavailable {
+			continue
+		}
+		if list.Items[idx].Error != nil || !list.Items[idx].IsWalReceiverActive {
+			return false
+		}
+	}
+
+	return true
+}
+
 // IsPodReporting if a pod is ready
 func (list PostgresqlStatusList) IsPodReporting(podname string) bool {
 	for _, item := range list.Items {
@@ -107,6 +107,32 @@ var _ = Describe("PostgreSQL status", func() {
 		Expect(podList.ArePodsUpgradingInstanceManager()).To(BeTrue())
 	})
 
+	It("checks whether every replica is streaming", func() {
+		podList := PostgresqlStatusList{
+			Items: []PostgresqlStatus{
+				{
+					Pod:       corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-10"}},
+					IsPrimary: true,
+				},
+				{
+					Pod:                 corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-20"}},
+					IsWalReceiverActive: true,
+				},
+				{
+					Pod: corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-30"}},
+				},
+			},
+		}
+		Expect(podList.AreReplicasStreaming("server-10")).To(BeFalse())
+		podList.Items[2].MightBeUnavailable = true
+		Expect(podList.AreReplicasStreaming("server-10")).To(BeTrue())
+		podList.Items[2].MightBeUnavailable = false
+		podList.Items[2].IsWalReceiverActive = true
+		Expect(podList.AreReplicasStreaming("server-10")).To(BeTrue())
+		podList.Items[1].Error = errCannotConnectToPostgres
+		Expect(podList.AreReplicasStreaming("server-10")).To(BeFalse())
+	})
+
 	It("checks for pods on which fencing is enabled", func() {
 		podList := PostgresqlStatusList{
 			Items: []PostgresqlStatus{
@@ -191,3 +217,51 @@ var _ = Describe("PostgreSQL status real", func() {
 		})
 	})
 })
+
+var _ = Describe("PostgreSQL status with paused replicas", func() {
+	list := PostgresqlStatusList{
+		Items: []PostgresqlStatus{
+			{
+				Pod:          corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-01"}},
+				ReceivedLsn:  "1/23",
+				ReplayLsn:    "1/21",
+				ReplayPaused: true,
+			},
+			{
+				Pod:         corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-02"}},
+				ReceivedLsn: "1/22",
+				ReplayLsn:   "1/22",
+			},
+		},
+	}
+
+	It("puts the replicas whose WAL replay is paused after the others", func() {
+		sort.Sort(&list)
+		Expect(list.Items[0].Pod.Name).To(Equal("server-02"))
+		Expect(list.Items[1].Pod.Name).To(Equal("server-01"))
+	})
+})
+
+var _ = Describe("PostgreSQL status with diverged replicas", func() {
+	list := PostgresqlStatusList{
+		Items: []PostgresqlStatus{
+			{
+				Pod:              corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-01"}},
+				ReceivedLsn:      "2/10",
+				ReplayLsn:        "2/10",
+				TimelineDiverged: true,
+			},
+			{
+				Pod:         corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "server-02"}},
+				ReceivedLsn: "1/22",
+				ReplayLsn:   "1/22",
+			},
+		},
+	}
+
+	It("puts the replicas on a diverged timeline after the others", func() {
+		sort.Sort(&list)
+		Expect(list.Items[0].Pod.Name).To(Equal("server-02"))
+		Expect(list.Items[1].Pod.Name).To(Equal("server-01"))
+	})
+})
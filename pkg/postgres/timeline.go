@@ -0,0 +1,88 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TimelineHistoryEntry is a timeline switch, as recorded in the
+// history file of a timeline
+type TimelineHistoryEntry struct {
+	// The timeline that was left
+	ParentTimelineID int `json:"parentTimelineID"`
+
+	// The LSN where the switch happened
+	SwitchPoint LSN `json:"switchPoint"`
+
+	// The reason of the timeline switch
+	Reason string `json:"reason,omitempty"`
+}
+
+// TimelineHistoryFileName gets the name of the history file of a timeline
+func TimelineHistoryFileName(timelineID int) string {
+	return fmt.Sprintf("%08X.history", timelineID)
+}
+
+// ParseTimelineHistory parses the content of a timeline history file.
+// Every line of the file records a timeline switch in the following
+// format:
+//
+//	<parent timeline ID> <switch point LSN> <reason>
+//
+// Empty lines and the ones starting with '#' are ignored.
+func ParseTimelineHistory(content string) ([]TimelineHistoryEntry, error) {
+	var result []TimelineHistoryEntry
+
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: expected parent timeline and switch point, got %q", lineNumber, line)
+		}
+
+		parentTimelineID, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid parent timeline ID: %w", lineNumber, err)
+		}
+
+		switchPoint := LSN(fields[1])
+		if _, err := switchPoint.Parse(); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber, err)
+		}
+
+		result = append(result, TimelineHistoryEntry{
+			ParentTimelineID: parentTimelineID,
+			SwitchPoint:      switchPoint,
+			Reason:           strings.Join(fields[2:], " "),
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,73 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package postgres
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Timeline history", func() {
+	It("generates the history file name", func() {
+		Expect(TimelineHistoryFileName(2)).To(Equal("00000002.history"))
+		Expect(TimelineHistoryFileName(26)).To(Equal("0000001A.history"))
+	})
+
+	It("parses the content of a history file", func() {
+		content := "1\t0/5000000\tno recovery target specified\n" +
+			"\n" +
+			"# a comment\n" +
+			"2\t0/7000148\tat restore point \"before_upgrade\"\n"
+		history, err := ParseTimelineHistory(content)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(history).To(Equal([]TimelineHistoryEntry{
+			{
+				ParentTimelineID: 1,
+				SwitchPoint:      "0/5000000",
+				Reason:           "no recovery target specified",
+			},
+			{
+				ParentTimelineID: 2,
+				SwitchPoint:      "0/7000148",
+				Reason:           "at restore point \"before_upgrade\"",
+			},
+		}))
+	})
+
+	It("accepts entries without a reason", func() {
+		history, err := ParseTimelineHistory("1\t0/5000000\n")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(history).To(Equal([]TimelineHistoryEntry{{ParentTimelineID: 1, SwitchPoint: "0/5000000"}}))
+	})
+
+	It("returns an empty history for an empty file", func() {
+		history, err := ParseTimelineHistory("")
+		Expect(err).ToNot(HaveOccurred())
+		Expect(history).To(BeEmpty())
+	})
+
+	It("complains about malformed lines", func() {
+		_, err := ParseTimelineHistory("1\n")
+		Expect(err).To(HaveOccurred())
+
+		_, err = ParseTimelineHistory("one\t0/5000000\treason\n")
+		Expect(err).To(HaveOccurred())
+
+		_, err = ParseTimelineHistory("1\tnot-an-lsn\treason\n")
+		Expect(err).To(HaveOccurred())
+	})
+})
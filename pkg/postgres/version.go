@@ -125,6 +125,12 @@ func CanUpgrade(fromImage, toImage string) (bool, error) {
 		return false, nil
 	}
 
+	if fromTag == "" || toTag == "" {
+		// The image is referenced only by its digest, so we can't detect
+		// its major version and we have to trust the user
+		return true, nil
+	}
+
 	fromVersion, err := GetPostgresVersionFromTag(fromTag)
 	if err != nil {
 		return false, err
@@ -102,6 +102,12 @@ var _ = Describe("PostgreSQL version handling", func() {
 			Expect(CanUpgrade("postgres:10.0", "postgres:latest")).To(BeFalse())
 		})
 
+		It("allows changing the images referenced only by their digest", func() {
+			digestImage := "postgres@sha256:cff94de382ca538861622bbe84cfe03f44f307a9846a5c5eda672cf4dc692866"
+			Expect(CanUpgrade(digestImage, "postgres:10.3")).To(BeTrue())
+			Expect(CanUpgrade("postgres:10.3", digestImage)).To(BeTrue())
+		})
+
 		It("prevent upgrading to a different major version", func() {
 			Expect(CanUpgrade("postgres:10.3", "postgres:11.3")).To(BeFalse())
 			Expect(CanUpgrade("postgres:9.6.4", "postgres:10")).To(BeFalse())
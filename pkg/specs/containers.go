@@ -28,8 +28,8 @@ import (
 )
 
 // createBootstrapContainer creates the init container bootstrapping the operator
-// executable inside the generated Pods
-func createBootstrapContainer(cluster apiv1.Cluster) corev1.Container {
+// executable inside the generated Pods, with the passed resource requirements
+func createBootstrapContainer(cluster apiv1.Cluster, resources corev1.ResourceRequirements) corev1.Container {
 	container := corev1.Container{
 		Name:            BootstrapControllerContainerName,
 		Image:           configuration.Current.OperatorImageName,
@@ -40,8 +40,8 @@ func createBootstrapContainer(cluster apiv1.Cluster) corev1.Container {
 			"/controller/manager",
 		},
 		VolumeMounts:    createPostgresVolumeMounts(cluster),
-		Resources:       cluster.Spec.Resources,
-		SecurityContext: CreateContainerSecurityContext(),
+		Resources:       resources,
+		SecurityContext: CreatePostgresContainerSecurityContext(cluster),
 	}
 
 	addManagerLoggingOptions(cluster, &container)
@@ -83,3 +83,15 @@ func CreateContainerSecurityContext() *corev1.SecurityContext {
 		SeccompProfile:           seccompProfile,
 	}
 }
+
+// CreatePostgresContainerSecurityContext initializes the security context
+// of the containers of the PostgreSQL pods, applying the seccomp profile
+// requested in the cluster
+func CreatePostgresContainerSecurityContext(cluster apiv1.Cluster) *corev1.SecurityContext {
+	securityContext := CreateContainerSecurityContext()
+	if cluster.Spec.SeccompProfile != nil {
+		securityContext.SeccompProfile = cluster.Spec.SeccompProfile.DeepCopy()
+	}
+
+	return securityContext
+}
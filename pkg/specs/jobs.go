@@ -214,6 +214,7 @@ func buildCommonInitJobFlags(cluster apiv1.Cluster) []string {
 func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role string, initCommand []string) *batchv1.Job {
 	instanceName := GetInstanceName(cluster.Name, nodeSerial)
 	jobName := GetJobName(cluster.Name, nodeSerial, role)
+	resources := cluster.GetBootstrapResources()
 
 	job := &batchv1.Job{
 		ObjectMeta: metav1.ObjectMeta{
@@ -236,32 +237,41 @@ func createPrimaryJob(cluster apiv1.Cluster, nodeSerial int, role string, initCo
 					Hostname:  jobName,
 					Subdomain: cluster.GetServiceAnyName(),
 					InitContainers: []corev1.Container{
-						createBootstrapContainer(cluster),
+						createBootstrapContainer(cluster, resources),
 					},
 					Containers: []corev1.Container{
 						{
 							Name:            role,
 							Image:           cluster.GetImageName(),
-							ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+							ImagePullPolicy: cluster.GetImagePullPolicy(),
 							Env:             createEnvVarPostgresContainer(cluster, instanceName),
+							EnvFrom:         cluster.Spec.EnvFrom,
 							Command:         initCommand,
 							VolumeMounts:    createPostgresVolumeMounts(cluster),
-							Resources:       cluster.Spec.Resources,
-							SecurityContext: CreateContainerSecurityContext(),
+							Resources:       resources,
+							SecurityContext: CreatePostgresContainerSecurityContext(cluster),
 						},
 					},
-					Volumes:            createPostgresVolumes(cluster, instanceName),
-					SecurityContext:    CreatePodSecurityContext(cluster.GetPostgresUID(), cluster.GetPostgresGID()),
-					Affinity:           CreateAffinitySection(cluster.Name, cluster.Spec.Affinity),
-					Tolerations:        cluster.Spec.Affinity.Tolerations,
-					ServiceAccountName: cluster.Name,
-					RestartPolicy:      corev1.RestartPolicyNever,
-					NodeSelector:       cluster.Spec.Affinity.NodeSelector,
+					Volumes:                   createPostgresVolumes(cluster, instanceName),
+					SecurityContext:           CreatePostgresPodSecurityContext(cluster),
+					Affinity:                  CreateAffinitySection(cluster.Name, cluster.Spec.Affinity),
+					Tolerations:               CreateTolerations(cluster.Spec.Affinity),
+					TopologySpreadConstraints: CreateTopologySpreadConstraints(cluster),
+					ServiceAccountName:        cluster.Name,
+					RestartPolicy:             corev1.RestartPolicyNever,
+					NodeSelector:              cluster.Spec.Affinity.NodeSelector,
+					PriorityClassName:         cluster.Spec.PriorityClassName,
 				},
 			},
 		},
 	}
 
+	// The bootstrap timeout only applies to the jobs creating the primary
+	// instance, as the cluster can't be created without them
+	if cluster.Spec.BootstrapTimeout > 0 && role != "join" {
+		job.Spec.ActiveDeadlineSeconds = &cluster.Spec.BootstrapTimeout
+	}
+
 	utils.LabelJobRole(&job.ObjectMeta, role)
 	utils.LabelClusterName(&job.ObjectMeta, cluster.Name)
 	addManagerLoggingOptions(cluster, &job.Spec.Template.Spec.Containers[0])
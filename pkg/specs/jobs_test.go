@@ -19,6 +19,7 @@ package specs
 import (
 	v1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -165,3 +166,83 @@ var _ = Describe("Job created via InitDB", func() {
 		Expect(job.Spec.Template.Spec.Containers[0].Command).Should(ContainElement(postInitApplicationSQLRefsFolder))
 	})
 })
+
+var _ = Describe("Bootstrap job resources", func() {
+	It("uses the bootstrap resources for the job containers", func() {
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("1Gi"),
+					},
+				},
+				BootstrapResources: &corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{
+						corev1.ResourceMemory: resource.MustParse("4Gi"),
+					},
+				},
+			},
+		}
+
+		job := JoinReplicaInstance(cluster, 2)
+		Expect(job.Spec.Template.Spec.Containers[0].Resources.Requests.Memory().String()).To(Equal("4Gi"))
+		Expect(job.Spec.Template.Spec.InitContainers[0].Resources.Requests.Memory().String()).To(Equal("4Gi"))
+
+		pod := PodWithExistingStorage(cluster, 2)
+		Expect(pod.Spec.Containers[0].Resources.Requests.Memory().String()).To(Equal("1Gi"))
+	})
+})
+
+var _ = Describe("Priority class", func() {
+	It("is applied to the jobs and to the pods of the instances", func() {
+		cluster := apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"},
+			Spec: apiv1.ClusterSpec{
+				PriorityClassName: "business-critical",
+			},
+		}
+
+		Expect(JoinReplicaInstance(cluster, 2).Spec.Template.Spec.PriorityClassName).To(Equal("business-critical"))
+		Expect(PodWithExistingStorage(cluster, 2).Spec.PriorityClassName).To(Equal("business-critical"))
+	})
+
+	It("is not set by default", func() {
+		cluster := apiv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example"}}
+		Expect(PodWithExistingStorage(cluster, 1).Spec.PriorityClassName).To(BeEmpty())
+	})
+})
+
+var _ = Describe("Bootstrap timeout", func() {
+	It("doesn't limit the bootstrap jobs by default", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{},
+				},
+			},
+		}
+		Expect(CreatePrimaryJobViaInitdb(cluster, 1).Spec.ActiveDeadlineSeconds).To(BeNil())
+	})
+
+	It("limits the jobs bootstrapping the primary instance", func() {
+		cluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				BootstrapTimeout: 7200,
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{},
+				},
+			},
+		}
+
+		deadline := CreatePrimaryJobViaInitdb(cluster, 1).Spec.ActiveDeadlineSeconds
+		Expect(deadline).ToNot(BeNil())
+		Expect(*deadline).To(BeEquivalentTo(7200))
+
+		deadline = CreatePrimaryJobViaPgBaseBackup(cluster, 1).Spec.ActiveDeadlineSeconds
+		Expect(deadline).ToNot(BeNil())
+		Expect(*deadline).To(BeEquivalentTo(7200))
+
+		Expect(JoinReplicaInstance(cluster, 2).Spec.ActiveDeadlineSeconds).To(BeNil())
+	})
+})
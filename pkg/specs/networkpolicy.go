@@ -0,0 +1,120 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/management/url"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+)
+
+const (
+	// namespaceNameLabelName is the label that Kubernetes sets
+	// on every namespace, containing its name
+	namespaceNameLabelName = "kubernetes.io/metadata.name"
+)
+
+// BuildNetworkPolicy creates a network policy allowing connections to the
+// PostgreSQL port of the instances only from the pods of the cluster, the
+// poolers matched by poolersSelector, the operator and the clients allowed
+// by the user. The other ports of the instances, like the one used to export
+// the metrics, are not restricted
+func BuildNetworkPolicy(
+	cluster *apiv1.Cluster,
+	poolersSelector *metav1.LabelSelector,
+	operatorNamespace string,
+) *networkingv1.NetworkPolicy {
+	if cluster == nil || !cluster.IsNetworkPolicyEnabled() {
+		return nil
+	}
+
+	postgresPort := intstr.FromInt(postgres.ServerPort)
+	statusPort := intstr.FromInt(url.StatusPort)
+	metricsPort := intstr.FromInt(url.PostgresMetricsPort)
+	tcp := corev1.ProtocolTCP
+
+	allowedPeers := []networkingv1.NetworkPolicyPeer{
+		{
+			// The other instances and the jobs of this cluster
+			PodSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					utils.ClusterLabelName: cluster.Name,
+				},
+			},
+		},
+	}
+
+	if poolersSelector != nil {
+		// The poolers of this cluster, living in the same namespace
+		allowedPeers = append(allowedPeers, networkingv1.NetworkPolicyPeer{
+			PodSelector: poolersSelector.DeepCopy(),
+		})
+	}
+
+	if operatorNamespace != "" {
+		allowedPeers = append(allowedPeers, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					namespaceNameLabelName: operatorNamespace,
+				},
+			},
+		})
+	}
+
+	for _, allowedClient := range cluster.Spec.NetworkPolicy.AllowedClients {
+		allowedPeers = append(allowedPeers, networkingv1.NetworkPolicyPeer{
+			NamespaceSelector: allowedClient.NamespaceSelector.DeepCopy(),
+			PodSelector:       allowedClient.PodSelector.DeepCopy(),
+		})
+	}
+
+	return &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cluster.Name,
+			Namespace: cluster.Namespace,
+		},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					utils.ClusterLabelName: cluster.Name,
+					utils.PodRoleLabelName: string(utils.PodRoleInstance),
+				},
+			},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress},
+			Ingress: []networkingv1.NetworkPolicyIngressRule{
+				{
+					Ports: []networkingv1.NetworkPolicyPort{
+						{Protocol: &tcp, Port: &postgresPort},
+					},
+					From: allowedPeers,
+				},
+				{
+					Ports: []networkingv1.NetworkPolicyPort{
+						{Protocol: &tcp, Port: &statusPort},
+						{Protocol: &tcp, Port: &metricsPort},
+					},
+				},
+			},
+		},
+	}
+}
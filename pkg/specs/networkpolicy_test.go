@@ -0,0 +1,106 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package specs
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/postgres"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Network policy specifications", func() {
+	newCluster := func(networkPolicy *apiv1.NetworkPolicyConfiguration) *apiv1.Cluster {
+		return &apiv1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "thistest",
+				Namespace: "default",
+			},
+			Spec: apiv1.ClusterSpec{
+				Instances:     3,
+				NetworkPolicy: networkPolicy,
+			},
+		}
+	}
+
+	It("is not generated when not enabled", func() {
+		Expect(BuildNetworkPolicy(newCluster(nil), nil, "operator")).To(BeNil())
+		Expect(BuildNetworkPolicy(newCluster(&apiv1.NetworkPolicyConfiguration{}), nil, "operator")).To(BeNil())
+	})
+
+	It("selects the instances of the cluster", func() {
+		cluster := newCluster(&apiv1.NetworkPolicyConfiguration{Enabled: true})
+		result := BuildNetworkPolicy(cluster, nil, "operator")
+		Expect(result.Name).To(Equal(cluster.Name))
+		Expect(result.Namespace).To(Equal(cluster.Namespace))
+		Expect(result.Spec.PodSelector.MatchLabels).To(Equal(map[string]string{
+			utils.ClusterLabelName: cluster.Name,
+			utils.PodRoleLabelName: string(utils.PodRoleInstance),
+		}))
+	})
+
+	It("restricts the PostgreSQL port to the instances, the poolers, the operator and the allowed clients", func() {
+		cluster := newCluster(&apiv1.NetworkPolicyConfiguration{
+			Enabled: true,
+			AllowedClients: []apiv1.NetworkPolicyClient{
+				{
+					PodSelector: &metav1.LabelSelector{
+						MatchLabels: map[string]string{"app": "web"},
+					},
+				},
+			},
+		})
+		poolersSelector := &metav1.LabelSelector{
+			MatchLabels: map[string]string{"pooler": "thistest-rw"},
+		}
+		result := BuildNetworkPolicy(cluster, poolersSelector, "operator")
+		Expect(result.Spec.Ingress).To(HaveLen(2))
+
+		postgresRule := result.Spec.Ingress[0]
+		Expect(postgresRule.Ports).To(HaveLen(1))
+		Expect(postgresRule.Ports[0].Port.IntValue()).To(Equal(postgres.ServerPort))
+		Expect(postgresRule.From).To(HaveLen(4))
+		Expect(postgresRule.From[0].PodSelector.MatchLabels).To(HaveKeyWithValue(utils.ClusterLabelName, cluster.Name))
+		Expect(postgresRule.From[1].PodSelector).To(Equal(poolersSelector))
+		Expect(postgresRule.From[2].NamespaceSelector.MatchLabels).
+			To(HaveKeyWithValue(namespaceNameLabelName, "operator"))
+		Expect(postgresRule.From[3].PodSelector.MatchLabels).To(HaveKeyWithValue("app", "web"))
+		Expect(postgresRule.From[3].NamespaceSelector).To(BeNil())
+
+		Expect(result.Spec.Ingress[1].From).To(BeEmpty())
+	})
+
+	It("doesn't allow the operator namespace when it is not known", func() {
+		cluster := newCluster(&apiv1.NetworkPolicyConfiguration{Enabled: true})
+		result := BuildNetworkPolicy(cluster, nil, "")
+		Expect(result.Spec.Ingress[0].From).To(HaveLen(1))
+	})
+
+	It("doesn't allow any pooler when the cluster has none", func() {
+		cluster := newCluster(&apiv1.NetworkPolicyConfiguration{Enabled: true})
+		result := BuildNetworkPolicy(cluster, nil, "operator")
+		Expect(result.Spec.Ingress[0].From).To(HaveLen(2))
+		Expect(result.Spec.Ingress[0].From[0].PodSelector.MatchLabels).
+			To(HaveKeyWithValue(utils.ClusterLabelName, cluster.Name))
+		Expect(result.Spec.Ingress[0].From[1].NamespaceSelector.MatchLabels).
+			To(HaveKeyWithValue(namespaceNameLabelName, "operator"))
+	})
+})
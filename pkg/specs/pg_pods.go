@@ -22,6 +22,8 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
 )
 
 // GetNodeSerial get the serial number of an object created by the operator
@@ -50,6 +52,35 @@ func IsPodPrimary(pod corev1.Pod) bool {
 	return role == ClusterRoleLabelPrimary
 }
 
+// GetPrimaryPodName gets the name of the pod running the current primary of
+// the cluster, as reported in its status. An empty string is returned when
+// the primary has not been elected yet, or during a switchover or a failover,
+// since the current primary is going to be replaced by the target one
+func GetPrimaryPodName(cluster *apiv1.Cluster) string {
+	if cluster.Status.CurrentPrimary != cluster.Status.TargetPrimary {
+		return ""
+	}
+
+	return cluster.Status.CurrentPrimary
+}
+
+// FindPrimaryPod finds the pod running the current primary of the cluster
+// in the passed list, returning nil when it isn't there
+func FindPrimaryPod(cluster *apiv1.Cluster, pods []corev1.Pod) *corev1.Pod {
+	primaryPodName := GetPrimaryPodName(cluster)
+	if primaryPodName == "" {
+		return nil
+	}
+
+	for idx := range pods {
+		if pods[idx].Name == primaryPodName {
+			return &pods[idx]
+		}
+	}
+
+	return nil
+}
+
 // IsPodStandby check if a certain pod belongs to a standby
 func IsPodStandby(pod corev1.Pod) bool {
 	return !IsPodPrimary(pod)
@@ -17,6 +17,7 @@ limitations under the License.
 package specs
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -43,3 +44,36 @@ var _ = Describe("Extract the used image name", func() {
 		Expect(GetBootstrapControllerImageName(*pod)).To(Equal(configuration.Current.OperatorImageName))
 	})
 })
+
+var _ = Describe("Primary pod discovery", func() {
+	cluster := &apiv1.Cluster{
+		Status: apiv1.ClusterStatus{
+			CurrentPrimary: "cluster-example-1",
+			TargetPrimary:  "cluster-example-1",
+		},
+	}
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-1"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "cluster-example-2"}},
+	}
+
+	It("gets the current primary from the status", func() {
+		Expect(GetPrimaryPodName(cluster)).To(Equal("cluster-example-1"))
+		Expect(FindPrimaryPod(cluster, pods)).To(Equal(&pods[0]))
+	})
+
+	It("doesn't report any primary during a switchover", func() {
+		switchingCluster := cluster.DeepCopy()
+		switchingCluster.Status.TargetPrimary = "cluster-example-2"
+		Expect(GetPrimaryPodName(switchingCluster)).To(BeEmpty())
+		Expect(FindPrimaryPod(switchingCluster, pods)).To(BeNil())
+	})
+
+	It("doesn't report any primary before the bootstrap", func() {
+		Expect(GetPrimaryPodName(&apiv1.Cluster{})).To(BeEmpty())
+	})
+
+	It("doesn't find a primary missing from the list", func() {
+		Expect(FindPrimaryPod(cluster, pods[1:])).To(BeNil())
+	})
+})
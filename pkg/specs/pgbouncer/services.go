@@ -17,6 +17,8 @@ limitations under the License.
 package pgbouncer
 
 import (
+	"sort"
+
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -49,3 +51,31 @@ func Service(pooler *apiv1.Pooler) *corev1.Service {
 		},
 	}
 }
+
+// PodSelector creates a label selector matching the pods of the
+// passed poolers, whose names are sorted. It returns nil when there
+// are no poolers, as a selector with an empty set of values is not valid
+func PodSelector(poolers []apiv1.Pooler) *metav1.LabelSelector {
+	if len(poolers) == 0 {
+		return nil
+	}
+
+	poolerNames := make([]string, 0, len(poolers))
+	for _, pooler := range poolers {
+		poolerNames = append(poolerNames, pooler.Name)
+	}
+
+	// The order of the values in the list of poolers is not stable, and
+	// sorting them avoids updating the network policy at every reconciliation
+	sort.Strings(poolerNames)
+
+	return &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      PgbouncerNameLabel,
+				Operator: metav1.LabelSelectorOpIn,
+				Values:   poolerNames,
+			},
+		},
+	}
+}
@@ -18,9 +18,11 @@ package specs
 
 import (
 	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/certs"
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 )
 
@@ -37,9 +39,7 @@ func CreatePodMonitor(cluster *apiv1.Cluster) *monitoringv1.PodMonitor {
 			MatchLabels: meta.Labels,
 		},
 		PodMetricsEndpoints: []monitoringv1.PodMetricsEndpoint{
-			{
-				Port: "metrics",
-			},
+			createPodMetricsEndpoint(cluster),
 		},
 	}
 
@@ -48,3 +48,34 @@ func CreatePodMonitor(cluster *apiv1.Cluster) *monitoringv1.PodMonitor {
 		Spec:       spec,
 	}
 }
+
+// createPodMetricsEndpoint creates the endpoint scraping the metrics of
+// the instances, verifying the server certificate when TLS is enabled
+func createPodMetricsEndpoint(cluster *apiv1.Cluster) monitoringv1.PodMetricsEndpoint {
+	endpoint := monitoringv1.PodMetricsEndpoint{
+		Port: "metrics",
+	}
+
+	if !cluster.IsMetricsTLSEnabled() {
+		return endpoint
+	}
+
+	endpoint.Scheme = "https"
+	endpoint.TLSConfig = &monitoringv1.PodMetricsEndpointTLSConfig{
+		SafeTLSConfig: monitoringv1.SafeTLSConfig{
+			CA: monitoringv1.SecretOrConfigMap{
+				Secret: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{
+						Name: cluster.GetServerCASecretName(),
+					},
+					Key: certs.CACertKey,
+				},
+			},
+			// The pods are scraped through their IP address, which isn't
+			// among the names of the server certificate
+			ServerName: cluster.GetServiceReadWriteName(),
+		},
+	}
+
+	return endpoint
+}
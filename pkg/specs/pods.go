@@ -21,6 +21,7 @@ package specs
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -48,6 +49,10 @@ const (
 	// latest required restart time
 	ClusterReloadAnnotationName = MetadataNamespace + "/reloadedAt"
 
+	// AdditionalVolumesAnnotationName is the name of the annotation containing
+	// the comma-separated names of the additional volumes added to the pod
+	AdditionalVolumesAnnotationName = MetadataNamespace + "/additionalVolumes"
+
 	// ClusterRoleLabelName label is applied to Pods to mark primary ones
 	ClusterRoleLabelName = "role"
 
@@ -87,6 +92,9 @@ const (
 
 	// ReadinessProbePeriod is the period set for the postgres instance readiness probe
 	ReadinessProbePeriod = 10
+
+	// StartupProbePeriod is the default period set for the postgres instance startup probe
+	StartupProbePeriod = 10
 )
 
 func createEnvVarPostgresContainer(cluster apiv1.Cluster, podName string) []corev1.EnvVar {
@@ -115,55 +123,66 @@ func createEnvVarPostgresContainer(cluster apiv1.Cluster, podName string) []core
 			Name:  "PGHOST",
 			Value: postgres.SocketDirectory,
 		},
+		{
+			Name: "POD_IP",
+			ValueFrom: &corev1.EnvVarSource{
+				FieldRef: &corev1.ObjectFieldSelector{
+					APIVersion: "v1",
+					FieldPath:  "status.podIP",
+				},
+			},
+		},
+	}
+
+	// The environment variables requested by the user come after the ones
+	// of the operator, which can't be overridden thanks to the webhook
+	for _, userEnvVar := range cluster.Spec.Env {
+		envVar = append(envVar, *userEnvVar.DeepCopy())
 	}
 
 	return envVar
 }
 
+// GetPostgresContainerCommand gets the command of the PostgreSQL container,
+// which is the one running the instance manager unless the user overrode it
+func GetPostgresContainerCommand(cluster apiv1.Cluster) []string {
+	if len(cluster.Spec.CommandOverride) > 0 {
+		return cluster.Spec.CommandOverride
+	}
+
+	command := []string{
+		"/controller/manager",
+		"instance",
+		"run",
+	}
+	if cluster.IsMetricsTLSEnabled() {
+		command = append(command, apiv1.MetricsPortTLSOption)
+	}
+
+	return command
+}
+
 // createPostgresContainers create the PostgreSQL containers that are
 // used for every instance
 func createPostgresContainers(
 	cluster apiv1.Cluster,
 	podName string,
 ) []corev1.Container {
+	startupProbe, livenessProbe, readinessProbe := GetPostgresContainerProbes(cluster)
 	containers := []corev1.Container{
 		{
 			Name:            PostgresContainerName,
 			Image:           cluster.GetImageName(),
-			ImagePullPolicy: cluster.Spec.ImagePullPolicy,
+			ImagePullPolicy: cluster.GetImagePullPolicy(),
 			Env:             createEnvVarPostgresContainer(cluster, podName),
+			EnvFrom:         cluster.Spec.EnvFrom,
 			VolumeMounts:    createPostgresVolumeMounts(cluster),
-			ReadinessProbe: &corev1.Probe{
-				TimeoutSeconds: 5,
-				PeriodSeconds:  ReadinessProbePeriod,
-				ProbeHandler: corev1.ProbeHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Path: url.PathReady,
-						Port: intstr.FromInt(url.StatusPort),
-					},
-				},
-			},
-			// From K8s 1.17 and newer, startup probes will be available for
-			// all users and not just protected from feature gates. For now
-			// let's use the LivenessProbe. When we will drop support for K8s
-			// 1.16, we'll configure a StartupProbe and this will lead to a
-			// better LivenessProbe (without InitialDelaySeconds).
-			LivenessProbe: &corev1.Probe{
-				InitialDelaySeconds: cluster.GetMaxStartDelay(),
-				TimeoutSeconds:      5,
-				ProbeHandler: corev1.ProbeHandler{
-					HTTPGet: &corev1.HTTPGetAction{
-						Path: url.PathHealth,
-						Port: intstr.FromInt(url.StatusPort),
-					},
-				},
-			},
-			Command: []string{
-				"/controller/manager",
-				"instance",
-				"run",
-			},
-			Resources: cluster.Spec.Resources,
+			StartupProbe:    startupProbe,
+			ReadinessProbe:  readinessProbe,
+			LivenessProbe:   livenessProbe,
+			Command:         GetPostgresContainerCommand(cluster),
+			Args:            cluster.Spec.ArgsOverride,
+			Resources:       cluster.GetInstanceResources(podName),
 			Ports: []corev1.ContainerPort{
 				{
 					Name:          "postgresql",
@@ -181,7 +200,7 @@ func createPostgresContainers(
 					Protocol:      "TCP",
 				},
 			},
-			SecurityContext: CreateContainerSecurityContext(),
+			SecurityContext: CreatePostgresContainerSecurityContext(cluster),
 		},
 	}
 
@@ -190,6 +209,69 @@ func createPostgresContainers(
 	return containers
 }
 
+// GetPostgresContainerProbes gets the startup, liveness and readiness probes
+// of the PostgreSQL container, tuned as requested in the probes configuration
+// of the cluster. There's no startup probe unless it has been requested
+func GetPostgresContainerProbes(cluster apiv1.Cluster) (startup, liveness, readiness *corev1.Probe) {
+	readiness = &corev1.Probe{
+		TimeoutSeconds: 5,
+		PeriodSeconds:  ReadinessProbePeriod,
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: url.PathReady,
+				Port: intstr.FromInt(url.StatusPort),
+			},
+		},
+	}
+
+	// Unless a startup probe is requested in the probes
+	// configuration, the LivenessProbe is delayed by the
+	// time allowed for the instance to start
+	liveness = &corev1.Probe{
+		InitialDelaySeconds: cluster.GetMaxStartDelay(),
+		TimeoutSeconds:      apiv1.DefaultLivenessProbeTimeoutSeconds,
+		ProbeHandler: corev1.ProbeHandler{
+			HTTPGet: &corev1.HTTPGetAction{
+				Path: url.PathHealth,
+				Port: intstr.FromInt(url.StatusPort),
+			},
+		},
+	}
+
+	probes := cluster.Spec.Probes
+	if probes == nil {
+		return nil, liveness, readiness
+	}
+
+	if probes.Startup != nil {
+		// The startup probe gets the time allowed for the instance to
+		// start by default, and the liveness probe doesn't need to wait
+		// for it anymore
+		startup = &corev1.Probe{
+			PeriodSeconds:    StartupProbePeriod,
+			TimeoutSeconds:   5,
+			FailureThreshold: getStartupProbeFailureThreshold(cluster.GetMaxStartDelay()),
+			ProbeHandler:     liveness.ProbeHandler,
+		}
+		probes.Startup.ApplyInto(startup)
+		liveness.InitialDelaySeconds = 0
+	}
+
+	probes.Liveness.GetProbe().ApplyInto(liveness)
+	probes.Readiness.ApplyInto(readiness)
+
+	return startup, liveness, readiness
+}
+
+// getStartupProbeFailureThreshold gets the failure threshold of the
+// startup probe allowing the instance to start in the given time
+func getStartupProbeFailureThreshold(startDelay int32) int32 {
+	if startDelay <= StartupProbePeriod {
+		return 1
+	}
+	return (startDelay + StartupProbePeriod - 1) / StartupProbePeriod
+}
+
 // CreateAffinitySection creates the affinity sections for Pods, given the configuration
 // from the user
 func CreateAffinitySection(clusterName string, config apiv1.AffinityConfiguration) *corev1.Affinity {
@@ -197,7 +279,8 @@ func CreateAffinitySection(clusterName string, config apiv1.AffinityConfiguratio
 	affinity := CreateGeneratedAntiAffinity(clusterName, config)
 
 	if config.AdditionalPodAffinity == nil &&
-		config.AdditionalPodAntiAffinity == nil {
+		config.AdditionalPodAntiAffinity == nil &&
+		config.DedicatedNodesTaintKey == "" {
 		return affinity
 	}
 
@@ -205,6 +288,10 @@ func CreateAffinitySection(clusterName string, config apiv1.AffinityConfiguratio
 		affinity = &corev1.Affinity{}
 	}
 
+	if config.DedicatedNodesTaintKey != "" {
+		affinity.NodeAffinity = createDedicatedNodesAffinity(config.DedicatedNodesTaintKey)
+	}
+
 	if config.AdditionalPodAffinity != nil {
 		affinity.PodAffinity = config.AdditionalPodAffinity
 	}
@@ -223,6 +310,65 @@ func CreateAffinitySection(clusterName string, config apiv1.AffinityConfiguratio
 	return affinity
 }
 
+// createDedicatedNodesAffinity creates the node affinity requiring the pods
+// to be scheduled on the nodes labelled with the dedicated nodes taint key
+func createDedicatedNodesAffinity(taintKey string) *corev1.NodeAffinity {
+	return &corev1.NodeAffinity{
+		RequiredDuringSchedulingIgnoredDuringExecution: &corev1.NodeSelector{
+			NodeSelectorTerms: []corev1.NodeSelectorTerm{
+				{
+					MatchExpressions: []corev1.NodeSelectorRequirement{
+						{
+							Key:      taintKey,
+							Operator: corev1.NodeSelectorOpExists,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// CreateTopologySpreadConstraints creates the topology spread constraints
+// of the pods of the cluster, defaulting the label selector of the
+// constraints not having one to the instances of the cluster
+func CreateTopologySpreadConstraints(cluster apiv1.Cluster) []corev1.TopologySpreadConstraint {
+	if len(cluster.Spec.TopologySpreadConstraints) == 0 {
+		return nil
+	}
+
+	constraints := make([]corev1.TopologySpreadConstraint, len(cluster.Spec.TopologySpreadConstraints))
+	for idx := range cluster.Spec.TopologySpreadConstraints {
+		constraint := cluster.Spec.TopologySpreadConstraints[idx].DeepCopy()
+		if constraint.LabelSelector == nil {
+			constraint.LabelSelector = &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					utils.ClusterLabelName: cluster.Name,
+					utils.PodRoleLabelName: string(utils.PodRoleInstance),
+				},
+			}
+		}
+		constraints[idx] = *constraint
+	}
+
+	return constraints
+}
+
+// CreateTolerations creates the tolerations for Pods, adding the one
+// needed to run on the dedicated nodes to the ones provided by the user
+func CreateTolerations(config apiv1.AffinityConfiguration) []corev1.Toleration {
+	if config.DedicatedNodesTaintKey == "" {
+		return config.Tolerations
+	}
+
+	tolerations := make([]corev1.Toleration, 0, len(config.Tolerations)+1)
+	tolerations = append(tolerations, config.Tolerations...)
+	return append(tolerations, corev1.Toleration{
+		Key:      config.DedicatedNodesTaintKey,
+		Operator: corev1.TolerationOpExists,
+	})
+}
+
 // CreateGeneratedAntiAffinity generates the affinity terms the operator is in charge for if enabled,
 // return nil if disabled or an error occurred, as invalid values should be validated before this method is called
 func CreateGeneratedAntiAffinity(clusterName string, config apiv1.AffinityConfiguration) *corev1.Affinity {
@@ -297,6 +443,26 @@ func CreatePodSecurityContext(user, group int64) *corev1.PodSecurityContext {
 	}
 }
 
+// CreatePostgresPodSecurityContext defines the security context of the pods
+// running PostgreSQL, applying the fsGroup and the seccomp profile requested
+// in the cluster
+func CreatePostgresPodSecurityContext(cluster apiv1.Cluster) *corev1.PodSecurityContext {
+	securityContext := CreatePodSecurityContext(cluster.GetPostgresUID(), cluster.GetPostgresGID())
+	if securityContext == nil {
+		return nil
+	}
+
+	if cluster.Spec.FSGroup != nil {
+		fsGroup := *cluster.Spec.FSGroup
+		securityContext.FSGroup = &fsGroup
+	}
+	if cluster.Spec.SeccompProfile != nil {
+		securityContext.SeccompProfile = cluster.Spec.SeccompProfile.DeepCopy()
+	}
+
+	return securityContext
+}
+
 // PodWithExistingStorage create a new instance with an existing storage
 func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
 	podName := GetInstanceName(cluster.Name, nodeSerial)
@@ -320,25 +486,51 @@ func PodWithExistingStorage(cluster apiv1.Cluster, nodeSerial int) *corev1.Pod {
 			Hostname:  podName,
 			Subdomain: cluster.GetServiceAnyName(),
 			InitContainers: []corev1.Container{
-				createBootstrapContainer(cluster),
+				createBootstrapContainer(cluster, cluster.GetInstanceResources(podName)),
 			},
 			Containers:                    createPostgresContainers(cluster, podName),
 			Volumes:                       createPostgresVolumes(cluster, podName),
-			SecurityContext:               CreatePodSecurityContext(cluster.GetPostgresUID(), cluster.GetPostgresGID()),
+			SecurityContext:               CreatePostgresPodSecurityContext(cluster),
 			Affinity:                      CreateAffinitySection(cluster.Name, cluster.Spec.Affinity),
-			Tolerations:                   cluster.Spec.Affinity.Tolerations,
+			Tolerations:                   CreateTolerations(cluster.Spec.Affinity),
+			TopologySpreadConstraints:     CreateTopologySpreadConstraints(cluster),
 			ServiceAccountName:            cluster.Name,
 			NodeSelector:                  cluster.Spec.Affinity.NodeSelector,
 			TerminationGracePeriodSeconds: &gracePeriod,
+			PriorityClassName:             cluster.Spec.PriorityClassName,
 		},
 	}
 
+	if names := GetAdditionalVolumeNames(cluster); len(names) > 0 {
+		pod.Annotations[AdditionalVolumesAnnotationName] = strings.Join(names, ",")
+	}
+
 	if utils.IsAnnotationAppArmorPresent(cluster.Annotations) {
 		utils.AnnotateAppArmor(&pod.ObjectMeta, cluster.Annotations)
 	}
 	return pod
 }
 
+// GetAdditionalVolumeNames gets the names of the additional volumes
+// requested in the cluster
+func GetAdditionalVolumeNames(cluster apiv1.Cluster) []string {
+	names := make([]string, len(cluster.Spec.AdditionalVolumes))
+	for idx, volume := range cluster.Spec.AdditionalVolumes {
+		names[idx] = volume.Name
+	}
+	return names
+}
+
+// GetAppliedAdditionalVolumeNames gets the names of the additional
+// volumes which have been added to the pod
+func GetAppliedAdditionalVolumeNames(pod corev1.Pod) []string {
+	names := pod.Annotations[AdditionalVolumesAnnotationName]
+	if names == "" {
+		return nil
+	}
+	return strings.Split(names, ",")
+}
+
 // GetInstanceName returns a string indicating the instance name
 func GetInstanceName(clusterName string, nodeSerial int) string {
 	return fmt.Sprintf("%s-%v", clusterName, nodeSerial)
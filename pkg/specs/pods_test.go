@@ -18,9 +18,13 @@ package specs
 
 import (
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/utils/pointer"
 
 	v1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/utils"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -53,6 +57,271 @@ var _ = Describe("The PostgreSQL security context", func() {
 	It("allows the container to create its own PGDATA", func() {
 		Expect(securityContext.RunAsUser).To(Equal(securityContext.FSGroup))
 	})
+
+	It("applies the fsGroup and the seccomp profile requested in the cluster", func() {
+		fsGroup := int64(1000)
+		localhostProfile := "profiles/postgres.json"
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				FSGroup: &fsGroup,
+				SeccompProfile: &corev1.SeccompProfile{
+					Type:             corev1.SeccompProfileTypeLocalhost,
+					LocalhostProfile: &localhostProfile,
+				},
+			},
+		}
+
+		podSecurityContext := CreatePostgresPodSecurityContext(cluster)
+		Expect(*podSecurityContext.RunAsNonRoot).To(BeTrue())
+		Expect(*podSecurityContext.RunAsUser).To(BeEquivalentTo(26))
+		Expect(*podSecurityContext.FSGroup).To(Equal(fsGroup))
+		Expect(podSecurityContext.SeccompProfile).To(Equal(cluster.Spec.SeccompProfile))
+
+		containerSecurityContext := CreatePostgresContainerSecurityContext(cluster)
+		Expect(*containerSecurityContext.RunAsNonRoot).To(BeTrue())
+		Expect(containerSecurityContext.SeccompProfile).To(Equal(cluster.Spec.SeccompProfile))
+	})
+
+	It("uses the GID of the postgres user as fsGroup by default", func() {
+		podSecurityContext := CreatePostgresPodSecurityContext(v1.Cluster{})
+		Expect(*podSecurityContext.FSGroup).To(BeEquivalentTo(26))
+	})
+})
+
+var _ = Describe("The PostgreSQL container command", func() {
+	It("runs the instance manager by default", func() {
+		Expect(GetPostgresContainerCommand(v1.Cluster{})).To(Equal([]string{
+			"/controller/manager", "instance", "run",
+		}))
+	})
+
+	It("asks the instance manager to serve the metrics over TLS", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				Monitoring: &v1.MonitoringConfiguration{
+					TLSConfig: &v1.ClusterMonitoringTLSConfiguration{Enabled: true},
+				},
+			},
+		}
+		Expect(GetPostgresContainerCommand(cluster)).To(Equal([]string{
+			"/controller/manager", "instance", "run", "--metrics-port-tls",
+		}))
+	})
+
+	It("uses the command and the arguments overridden by the user", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				CommandOverride:            []string{"/usr/bin/tini", "--"},
+				ArgsOverride:               []string{"/controller/manager", "instance", "run"},
+				AcknowledgeCommandOverride: true,
+			},
+		}
+		containers := createPostgresContainers(cluster, "cluster-1")
+		Expect(containers[0].Command).To(Equal([]string{"/usr/bin/tini", "--"}))
+		Expect(containers[0].Args).To(Equal([]string{"/controller/manager", "instance", "run"}))
+	})
+})
+
+var _ = Describe("The PostgreSQL container probes", func() {
+	It("doesn't use a startup probe by default", func() {
+		cluster := v1.Cluster{Spec: v1.ClusterSpec{MaxStartDelay: 120}}
+		containers := createPostgresContainers(cluster, "cluster-1")
+		Expect(containers[0].StartupProbe).To(BeNil())
+		Expect(containers[0].LivenessProbe.InitialDelaySeconds).To(BeEquivalentTo(120))
+		Expect(containers[0].ReadinessProbe.PeriodSeconds).To(BeEquivalentTo(ReadinessProbePeriod))
+	})
+
+	It("adds a startup probe waiting for the start delay", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				MaxStartDelay: 125,
+				Probes: &v1.ProbesConfiguration{
+					Startup: &v1.Probe{},
+				},
+			},
+		}
+		containers := createPostgresContainers(cluster, "cluster-1")
+		Expect(containers[0].StartupProbe).ToNot(BeNil())
+		Expect(containers[0].StartupProbe.PeriodSeconds).To(BeEquivalentTo(StartupProbePeriod))
+		Expect(containers[0].StartupProbe.FailureThreshold).To(BeEquivalentTo(13))
+		Expect(containers[0].StartupProbe.HTTPGet).To(Equal(containers[0].LivenessProbe.HTTPGet))
+		Expect(containers[0].LivenessProbe.InitialDelaySeconds).To(BeZero())
+	})
+
+	It("applies the probes tuning requested by the user", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				Probes: &v1.ProbesConfiguration{
+					Startup: &v1.Probe{PeriodSeconds: pointer.Int32(20), FailureThreshold: pointer.Int32(60)},
+					Liveness: &v1.LivenessProbe{
+						Probe: v1.Probe{TimeoutSeconds: pointer.Int32(10), FailureThreshold: pointer.Int32(5)},
+					},
+					Readiness: &v1.Probe{InitialDelaySeconds: pointer.Int32(15)},
+				},
+			},
+		}
+		containers := createPostgresContainers(cluster, "cluster-1")
+		Expect(containers[0].StartupProbe.PeriodSeconds).To(BeEquivalentTo(20))
+		Expect(containers[0].StartupProbe.FailureThreshold).To(BeEquivalentTo(60))
+		Expect(containers[0].LivenessProbe.TimeoutSeconds).To(BeEquivalentTo(10))
+		Expect(containers[0].LivenessProbe.FailureThreshold).To(BeEquivalentTo(5))
+		Expect(containers[0].ReadinessProbe.InitialDelaySeconds).To(BeEquivalentTo(15))
+		Expect(containers[0].ReadinessProbe.TimeoutSeconds).To(BeEquivalentTo(5))
+	})
+
+	It("allows the liveness probe to start right away", func() {
+		cluster := v1.Cluster{
+			Spec: v1.ClusterSpec{
+				MaxStartDelay: 125,
+				Probes: &v1.ProbesConfiguration{
+					Liveness: &v1.LivenessProbe{Probe: v1.Probe{InitialDelaySeconds: pointer.Int32(0)}},
+				},
+			},
+		}
+		containers := createPostgresContainers(cluster, "cluster-1")
+		Expect(containers[0].StartupProbe).To(BeNil())
+		Expect(containers[0].LivenessProbe.InitialDelaySeconds).To(BeZero())
+	})
+})
+
+var _ = Describe("The PostgreSQL container resources", func() {
+	It("applies the resources override of the instance", func() {
+		cluster := v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec: v1.ClusterSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+				ResourcesOverrides: map[string]corev1.ResourceRequirements{
+					"cluster-2": {
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+					},
+				},
+			},
+		}
+		Expect(createPostgresContainers(cluster, "cluster-1")[0].Resources.Requests.Memory().String()).
+			To(Equal("1Gi"))
+		Expect(createPostgresContainers(cluster, "cluster-2")[0].Resources.Requests.Memory().String()).
+			To(Equal("4Gi"))
+	})
+
+	It("applies the resources override of the instance to the bootstrap container", func() {
+		cluster := v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec: v1.ClusterSpec{
+				Resources: corev1.ResourceRequirements{
+					Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("1Gi")},
+				},
+				ResourcesOverrides: map[string]corev1.ResourceRequirements{
+					"cluster-2": {
+						Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+					},
+				},
+			},
+		}
+		pod := PodWithExistingStorage(cluster, 2)
+		Expect(pod.Spec.InitContainers[0].Resources.Requests.Memory().String()).To(Equal("4Gi"))
+	})
+})
+
+var _ = Describe("The PostgreSQL container environment", func() {
+	It("reserves the environment variables set by the operator", func() {
+		cluster := v1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster"}}
+		for _, envVar := range createEnvVarPostgresContainer(cluster, "cluster-1") {
+			Expect(v1.ReservedEnvironmentVariables).To(ContainElement(envVar.Name))
+		}
+	})
+
+	It("adds the environment variables requested by the user", func() {
+		cluster := v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec: v1.ClusterSpec{
+				Env: []corev1.EnvVar{{Name: "HTTPS_PROXY", Value: "http://proxy:3128"}},
+				EnvFrom: []corev1.EnvFromSource{
+					{ConfigMapRef: &corev1.ConfigMapEnvSource{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "proxy"},
+					}},
+				},
+			},
+		}
+		container := createPostgresContainers(cluster, "cluster-1")[0]
+		Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "HTTPS_PROXY", Value: "http://proxy:3128"}))
+		Expect(container.Env[len(container.Env)-1].Name).To(Equal("HTTPS_PROXY"))
+		Expect(container.EnvFrom).To(Equal(cluster.Spec.EnvFrom))
+	})
+})
+
+var _ = Describe("Create topology spread constraints", func() {
+	It("doesn't set any constraint by default", func() {
+		Expect(CreateTopologySpreadConstraints(v1.Cluster{})).To(BeNil())
+	})
+
+	It("selects the instances of the cluster when the selector is not set", func() {
+		userSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "test"}}
+		cluster := v1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "cluster"},
+			Spec: v1.ClusterSpec{
+				TopologySpreadConstraints: []corev1.TopologySpreadConstraint{
+					{
+						MaxSkew:           1,
+						TopologyKey:       "topology.kubernetes.io/zone",
+						WhenUnsatisfiable: corev1.DoNotSchedule,
+					},
+					{
+						MaxSkew:           1,
+						TopologyKey:       "kubernetes.io/hostname",
+						WhenUnsatisfiable: corev1.ScheduleAnyway,
+						LabelSelector:     userSelector,
+					},
+				},
+			},
+		}
+
+		constraints := CreateTopologySpreadConstraints(cluster)
+		Expect(constraints).To(HaveLen(2))
+		Expect(constraints[0].LabelSelector.MatchLabels).To(Equal(map[string]string{
+			utils.ClusterLabelName: "cluster",
+			utils.PodRoleLabelName: string(utils.PodRoleInstance),
+		}))
+		Expect(constraints[1].LabelSelector).To(Equal(userSelector))
+		Expect(cluster.Spec.TopologySpreadConstraints[0].LabelSelector).To(BeNil())
+
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.TopologySpreadConstraints).To(Equal(constraints))
+		Expect(labels.SelectorFromSet(constraints[0].LabelSelector.MatchLabels).
+			Matches(labels.Set(pod.Labels))).To(BeTrue())
+	})
+})
+
+var _ = Describe("Create tolerations", func() {
+	userToleration := corev1.Toleration{
+		Key:      "test",
+		Operator: corev1.TolerationOpEqual,
+		Value:    "value",
+		Effect:   corev1.TaintEffectNoSchedule,
+	}
+
+	It("uses the user tolerations when there are no dedicated nodes", func() {
+		config := v1.AffinityConfiguration{
+			Tolerations: []corev1.Toleration{userToleration},
+		}
+		Expect(CreateTolerations(config)).To(ConsistOf(userToleration))
+	})
+
+	It("tolerates the dedicated nodes taint", func() {
+		config := v1.AffinityConfiguration{
+			Tolerations:            []corev1.Toleration{userToleration},
+			DedicatedNodesTaintKey: "node-role.kubernetes.io/postgres",
+		}
+		Expect(CreateTolerations(config)).To(ConsistOf(
+			userToleration,
+			corev1.Toleration{
+				Key:      "node-role.kubernetes.io/postgres",
+				Operator: corev1.TolerationOpExists,
+			},
+		))
+		Expect(config.Tolerations).To(HaveLen(1))
+	})
 })
 
 var _ = Describe("Create affinity section", func() {
@@ -107,6 +376,23 @@ var _ = Describe("Create affinity section", func() {
 		Expect(affinity).To(BeNil())
 	})
 
+	It("requires the dedicated nodes when the taint key is set", func() {
+		config := v1.AffinityConfiguration{
+			EnablePodAntiAffinity:  pointerToBool(false),
+			DedicatedNodesTaintKey: "node-role.kubernetes.io/postgres",
+		}
+		affinity := CreateAffinitySection(clusterName, config)
+		Expect(affinity).NotTo(BeNil())
+		Expect(affinity.PodAntiAffinity).To(BeNil())
+		Expect(affinity.NodeAffinity).NotTo(BeNil())
+		terms := affinity.NodeAffinity.RequiredDuringSchedulingIgnoredDuringExecution.NodeSelectorTerms
+		Expect(terms).To(HaveLen(1))
+		Expect(terms[0].MatchExpressions).To(ConsistOf(corev1.NodeSelectorRequirement{
+			Key:      "node-role.kubernetes.io/postgres",
+			Operator: corev1.NodeSelectorOpExists,
+		}))
+	})
+
 	When("given additional affinity terms", func() {
 		When("generated pod anti-affinity is enabled", func() {
 			It("sets both pod affinity and anti-affinity correctly if passed and set to required", func() {
@@ -53,6 +53,7 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 
 	involvedSecretNames = append(involvedSecretNames, backupSecrets(cluster, backupOrigin)...)
 	involvedSecretNames = append(involvedSecretNames, externalClusterSecrets(cluster)...)
+	involvedSecretNames = append(involvedSecretNames, managedRolesSecrets(cluster)...)
 
 	rules := []rbacv1.PolicyRule{
 		{
@@ -163,6 +164,20 @@ func CreateRole(cluster apiv1.Cluster, backupOrigin *apiv1.Backup) rbacv1.Role {
 	}
 }
 
+func managedRolesSecrets(cluster apiv1.Cluster) []string {
+	if cluster.Spec.Managed == nil {
+		return nil
+	}
+
+	var result []string
+	for _, role := range cluster.Spec.Managed.Roles {
+		if role.PasswordSecret != nil {
+			result = append(result, role.PasswordSecret.Name)
+		}
+	}
+	return result
+}
+
 func externalClusterSecrets(cluster apiv1.Cluster) []string {
 	var result []string
 
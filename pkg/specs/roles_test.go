@@ -182,6 +182,19 @@ var _ = Describe("Roles", func() {
 			"testPassword",
 		))
 	})
+
+	It("should contain the password secrets of the managed roles", func() {
+		managedCluster := cluster.DeepCopy()
+		managedCluster.Spec.Managed = &apiv1.ManagedConfiguration{
+			Roles: []apiv1.RoleConfiguration{
+				{Name: "reporting", PasswordSecret: &apiv1.LocalObjectReference{Name: "testReportingPassword"}},
+				{Name: "dashboard"},
+			},
+		}
+		serviceAccount := CreateRole(*managedCluster, nil)
+		Expect(serviceAccount.Rules[1].ResourceNames).To(ContainElement("testReportingPassword"))
+		Expect(serviceAccount.Rules[1].ResourceNames).To(HaveLen(len(CreateRole(cluster, nil).Rules[1].ResourceNames) + 1))
+	})
 })
 
 var _ = Describe("Secrets", func() {
@@ -57,7 +57,7 @@ func CreateClusterAnyService(cluster apiv1.Cluster) *corev1.Service {
 
 // CreateClusterReadService create a service insisting on all the ready pods
 func CreateClusterReadService(cluster apiv1.Cluster) *corev1.Service {
-	return &corev1.Service{
+	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cluster.GetServiceReadName(),
 			Namespace: cluster.Namespace,
@@ -70,11 +70,14 @@ func CreateClusterReadService(cluster apiv1.Cluster) *corev1.Service {
 			},
 		},
 	}
+
+	cluster.GetManagedServices().Read.MergeInto(service)
+	return service
 }
 
 // CreateClusterReadOnlyService create a service insisting on all the ready pods
 func CreateClusterReadOnlyService(cluster apiv1.Cluster) *corev1.Service {
-	return &corev1.Service{
+	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cluster.GetServiceReadOnlyName(),
 			Namespace: cluster.Namespace,
@@ -88,11 +91,14 @@ func CreateClusterReadOnlyService(cluster apiv1.Cluster) *corev1.Service {
 			},
 		},
 	}
+
+	cluster.GetManagedServices().ReadOnly.MergeInto(service)
+	return service
 }
 
 // CreateClusterReadWriteService create a service insisting on the primary pod
 func CreateClusterReadWriteService(cluster apiv1.Cluster) *corev1.Service {
-	return &corev1.Service{
+	service := &corev1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      cluster.GetServiceReadWriteName(),
 			Namespace: cluster.Namespace,
@@ -106,4 +112,7 @@ func CreateClusterReadWriteService(cluster apiv1.Cluster) *corev1.Service {
 			},
 		},
 	}
+
+	cluster.GetManagedServices().ReadWrite.MergeInto(service)
+	return service
 }
@@ -17,6 +17,7 @@ limitations under the License.
 package specs
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1 "github.com/cloudnative-pg/cloudnative-pg/api/v1"
@@ -63,3 +64,51 @@ var _ = Describe("Services specification", func() {
 		Expect(service.Spec.Selector[ClusterRoleLabelName]).To(Equal(ClusterRoleLabelPrimary))
 	})
 })
+
+var _ = Describe("Services customization", func() {
+	postgresql := apiv1.Cluster{
+		ObjectMeta: v1.ObjectMeta{
+			Name: "clustername",
+		},
+		Spec: apiv1.ClusterSpec{
+			Managed: &apiv1.ManagedConfiguration{
+				Services: &apiv1.ManagedServices{
+					ReadWrite: &apiv1.ServiceTemplate{
+						Metadata: apiv1.Metadata{
+							Labels: map[string]string{
+								"team": "db",
+							},
+							Annotations: map[string]string{
+								"service.beta.kubernetes.io/aws-load-balancer-internal": "true",
+							},
+						},
+						Type: corev1.ServiceTypeLoadBalancer,
+					},
+				},
+			},
+		},
+	}
+
+	It("applies the customizations to the -rw service", func() {
+		service := CreateClusterReadWriteService(postgresql)
+		Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeLoadBalancer))
+		Expect(service.Labels).To(HaveKeyWithValue("team", "db"))
+		Expect(service.Annotations).To(HaveKeyWithValue(
+			"service.beta.kubernetes.io/aws-load-balancer-internal", "true"))
+		Expect(service.Spec.Selector).To(Equal(map[string]string{
+			utils.ClusterLabelName: "clustername",
+			ClusterRoleLabelName:   ClusterRoleLabelPrimary,
+		}))
+	})
+
+	It("leaves the services without customizations untouched", func() {
+		for _, service := range []*corev1.Service{
+			CreateClusterReadService(postgresql),
+			CreateClusterReadOnlyService(postgresql),
+		} {
+			Expect(service.Spec.Type).To(Equal(corev1.ServiceTypeClusterIP))
+			Expect(service.Labels).To(BeEmpty())
+			Expect(service.Annotations).To(BeEmpty())
+		}
+	})
+})
@@ -92,6 +92,8 @@ func createPostgresVolumes(cluster apiv1.Cluster, podName string) []corev1.Volum
 			})
 	}
 
+	result = append(result, cluster.Spec.AdditionalVolumes...)
+
 	return result
 }
 
@@ -203,5 +205,7 @@ func createPostgresVolumeMounts(cluster apiv1.Cluster) []corev1.VolumeMount {
 		)
 	}
 
+	volumeMounts = append(volumeMounts, cluster.Spec.AdditionalVolumeMounts...)
+
 	return volumeMounts
 }
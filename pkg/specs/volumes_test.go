@@ -290,3 +290,79 @@ var _ = Describe("test createVolumesAndVolumeMountsForPostInitApplicationSQLRefs
 		}))
 	})
 })
+
+var _ = Describe("additional volumes", func() {
+	cluster := apiv1.Cluster{
+		Spec: apiv1.ClusterSpec{
+			AdditionalVolumes: []corev1.Volume{
+				{
+					Name: "audit",
+					VolumeSource: corev1.VolumeSource{
+						EmptyDir: &corev1.EmptyDirVolumeSource{},
+					},
+				},
+			},
+			AdditionalVolumeMounts: []corev1.VolumeMount{
+				{
+					Name:      "audit",
+					MountPath: "/var/log/audit",
+				},
+			},
+		},
+	}
+
+	It("adds the additional volumes after the ones managed by the operator", func() {
+		volumes := createPostgresVolumes(cluster, "cluster-example-1")
+		Expect(volumes[0].Name).To(Equal("pgdata"))
+		Expect(volumes[len(volumes)-1]).To(Equal(cluster.Spec.AdditionalVolumes[0]))
+	})
+
+	It("adds the additional volume mounts after the ones managed by the operator", func() {
+		volumeMounts := createPostgresVolumeMounts(cluster)
+		Expect(volumeMounts[0].Name).To(Equal("pgdata"))
+		Expect(volumeMounts[len(volumeMounts)-1]).To(Equal(cluster.Spec.AdditionalVolumeMounts[0]))
+	})
+
+	It("reserves the names and the paths of the volumes managed by the operator", func() {
+		fullCluster := apiv1.Cluster{
+			Spec: apiv1.ClusterSpec{
+				Bootstrap: &apiv1.BootstrapConfiguration{
+					InitDB: &apiv1.BootstrapInitDB{Database: "app", Owner: "app"},
+				},
+				WalStorage: &apiv1.StorageConfiguration{},
+			},
+		}
+		for _, volume := range createPostgresVolumes(fullCluster, "cluster-example-1") {
+			Expect(apiv1.ReservedVolumeNames).To(ContainElement(volume.Name))
+		}
+		for _, volumeMount := range createPostgresVolumeMounts(fullCluster) {
+			Expect(apiv1.ReservedVolumeNames).To(ContainElement(volumeMount.Name))
+			Expect(apiv1.ReservedVolumeMountPaths).To(ContainElement(volumeMount.MountPath))
+		}
+		Expect(apiv1.ReservedVolumeMountPaths).To(ContainElement(postInitApplicationSQLRefsFolder))
+
+		volumes, _ := createVolumesAndVolumeMountsForPostInitApplicationSQLRefs(&apiv1.PostInitApplicationSQLRefs{
+			SecretRefs:    []apiv1.SecretKeySelector{{Key: "secret.sql"}},
+			ConfigMapRefs: []apiv1.ConfigMapKeySelector{{Key: "configmap.sql"}},
+		})
+		for _, volume := range volumes {
+			Expect(volume.Name).To(HaveSuffix(apiv1.ReservedVolumeNameSuffixes[0]))
+		}
+	})
+
+	It("mounts the additional volumes in the PostgreSQL container", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Spec.Volumes).To(ContainElement(cluster.Spec.AdditionalVolumes[0]))
+		Expect(pod.Spec.Containers[0].VolumeMounts).To(ContainElement(cluster.Spec.AdditionalVolumeMounts[0]))
+	})
+
+	It("records the names of the additional volumes in the pod", func() {
+		pod := PodWithExistingStorage(cluster, 1)
+		Expect(pod.Annotations).To(HaveKeyWithValue(AdditionalVolumesAnnotationName, "audit"))
+		Expect(GetAppliedAdditionalVolumeNames(*pod)).To(Equal([]string{"audit"}))
+
+		pod = PodWithExistingStorage(apiv1.Cluster{}, 1)
+		Expect(pod.Annotations).ToNot(HaveKey(AdditionalVolumesAnnotationName))
+		Expect(GetAppliedAdditionalVolumeNames(*pod)).To(BeEmpty())
+	})
+})
@@ -24,7 +24,7 @@ import (
 	"github.com/cloudnative-pg/cloudnative-pg/pkg/cnpgerrors"
 )
 
-var regexPolicy = regexp.MustCompile(`([1-9][0-9]*)([dwm])$`)
+var regexPolicy = regexp.MustCompile(`^([1-9][0-9]*)([dwmb])$`)
 
 // ParsePolicy ensure that the policy string follows the
 // rules required by Barman
@@ -39,6 +39,11 @@ func ParsePolicy(policy string) (string, error) {
 		return "", fmt.Errorf("not a valid policy")
 	}
 
+	// the policy keeps a number of backups rather than a recovery window
+	if matches[2] == "b" {
+		return fmt.Sprintf("REDUNDANCY %v", matches[1]), nil
+	}
+
 	return fmt.Sprintf("RECOVERY WINDOW OF %v %v", matches[1], unitName[matches[2]]), nil
 }
 
@@ -27,6 +27,7 @@ var _ = Describe("parsing policy", func() {
 		Expect(ParsePolicy("10w")).To(BeEquivalentTo("RECOVERY WINDOW OF 10 WEEKS"))
 		Expect(ParsePolicy("7w")).To(BeEquivalentTo("RECOVERY WINDOW OF 7 WEEKS"))
 		Expect(ParsePolicy("7d")).To(BeEquivalentTo("RECOVERY WINDOW OF 7 DAYS"))
+		Expect(ParsePolicy("10b")).To(BeEquivalentTo("REDUNDANCY 10"))
 	})
 
 	It("must complain with a wrong policy", func() {
@@ -38,6 +39,12 @@ var _ = Describe("parsing policy", func() {
 
 		_, err = ParsePolicy("00d")
 		Expect(err).ToNot(BeNil())
+
+		_, err = ParsePolicy("x30d")
+		Expect(err).ToNot(BeNil())
+
+		_, err = ParsePolicy("0b")
+		Expect(err).ToNot(BeNil())
 	})
 })
 
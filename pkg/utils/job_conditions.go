@@ -18,6 +18,7 @@ package utils
 
 import (
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 // IsJobComplete check if a certain job is complete
@@ -29,6 +30,19 @@ func IsJobComplete(job batchv1.Job) bool {
 	return job.Status.Succeeded == requestedCompletions
 }
 
+// IsJobDeadlineExceeded check if a certain job has been terminated
+// because it was active for longer than its deadline
+func IsJobDeadlineExceeded(job batchv1.Job) bool {
+	for _, condition := range job.Status.Conditions {
+		if condition.Type == batchv1.JobFailed &&
+			condition.Status == corev1.ConditionTrue &&
+			condition.Reason == "DeadlineExceeded" {
+			return true
+		}
+	}
+	return false
+}
+
 // FilterCompleteJobs returns jobs that are complete
 func FilterCompleteJobs(jobList []batchv1.Job) []batchv1.Job {
 	var result []batchv1.Job
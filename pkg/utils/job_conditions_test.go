@@ -18,6 +18,7 @@ package utils
 
 import (
 	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -47,4 +48,19 @@ var _ = Describe("Job conditions", func() {
 		Expect(CountCompleteJobs([]batchv1.Job{completeJob})).To(Equal(1))
 		Expect(CountCompleteJobs([]batchv1.Job{})).To(Equal(0))
 	})
+
+	It("detects if a certain job exceeded its deadline", func() {
+		failedJob := batchv1.Job{
+			Status: batchv1.JobStatus{
+				Conditions: []batchv1.JobCondition{
+					{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"},
+				},
+			},
+		}
+		Expect(IsJobDeadlineExceeded(nonCompleteJob)).To(BeFalse())
+		Expect(IsJobDeadlineExceeded(failedJob)).To(BeFalse())
+
+		failedJob.Status.Conditions[0].Reason = "DeadlineExceeded"
+		Expect(IsJobDeadlineExceeded(failedJob)).To(BeTrue())
+	})
 })
@@ -26,6 +26,10 @@ import (
 )
 
 const (
+	// MetadataNamespace is the domain used by the operator as the prefix
+	// of the labels and annotations it manages
+	MetadataNamespace = "cnpg.io"
+
 	// ClusterLabelName is the name of cluster which the backup CR belongs to
 	ClusterLabelName = "cnpg.io/cluster"
 
@@ -59,6 +63,20 @@ const (
 	// HibernatePgControlDataAnnotationName contains the pg_controldata output of the hibernated cluster
 	HibernatePgControlDataAnnotationName = "cnpg.io/hibernatePgControlData"
 
+	// HibernationAnnotationName is the name of the annotation controlling
+	// the declarative hibernation of the cluster
+	HibernationAnnotationName = "cnpg.io/hibernation"
+
+	// ProceedAnnotationName is the name of the annotation used to approve
+	// the update of the primary instance with the supervised primary update
+	// strategy. Its value is the name of the primary instance
+	ProceedAnnotationName = "cnpg.io/proceed"
+
+	// TargetPrimaryAnnotationName is the name of the annotation used to
+	// request a switchover to the instance named in its value. The operator
+	// removes it as soon as the request is processed
+	TargetPrimaryAnnotationName = "cnpg.io/targetPrimary"
+
 	// skipEmptyWalArchiveCheck turns off the checks that ensure that the WAL archive is empty before writing data
 	skipEmptyWalArchiveCheck = "cnpg.io/skipEmptyWalArchiveCheck"
 )
@@ -70,6 +88,19 @@ const (
 	annotationStatusEnabled  annotationStatus = "enabled"
 )
 
+// HibernationAnnotationValue describes the status of the hibernation
+type HibernationAnnotationValue string
+
+const (
+	// HibernationAnnotationValueOff is the value of the hibernation annotation
+	// when the cluster is running
+	HibernationAnnotationValueOff HibernationAnnotationValue = "off"
+
+	// HibernationAnnotationValueOn is the value of the hibernation annotation
+	// when the cluster is hibernated
+	HibernationAnnotationValueOn HibernationAnnotationValue = "on"
+)
+
 // PodRole describes the Role of a given pod
 type PodRole string
 
@@ -202,12 +233,31 @@ func IsReconciliationDisabled(object *metav1.ObjectMeta) bool {
 	return object.Annotations[ReconciliationLoopAnnotationName] == string(annotationStatusDisabled)
 }
 
+// IsHibernationRequested checks if the hibernation of the given resource
+// has been requested
+func IsHibernationRequested(object *metav1.ObjectMeta) bool {
+	return object.Annotations[HibernationAnnotationName] == string(HibernationAnnotationValueOn)
+}
+
 // IsEmptyWalArchiveCheckEnabled returns a boolean indicating if we should run the logic that checks if the WAL archive
 // storage is empty
 func IsEmptyWalArchiveCheckEnabled(object *metav1.ObjectMeta) bool {
 	return object.Annotations[skipEmptyWalArchiveCheck] != string(annotationStatusEnabled)
 }
 
+// IsReservedMetadataKey checks if the passed label or annotation name
+// has a prefix reserved to the operator, like "cnpg.io/podRole" or
+// "postgresql.cnpg.io/something"
+func IsReservedMetadataKey(key string) bool {
+	slashIdx := strings.Index(key, "/")
+	if slashIdx == -1 {
+		return false
+	}
+
+	prefix := key[:slashIdx]
+	return prefix == MetadataNamespace || strings.HasSuffix(prefix, "."+MetadataNamespace)
+}
+
 // MergeMap transfers the content of a giver map to a receiver
 func MergeMap(receiver, giver map[string]string) {
 	for key, value := range giver {
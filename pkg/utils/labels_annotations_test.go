@@ -121,3 +121,18 @@ var _ = Describe("Annotate pods management", func() {
 		Expect(pod.ObjectMeta.Annotations[AppArmorAnnotationPrefix+"/apparmor_profile"]).To(Equal("unconfined"))
 	})
 })
+
+var _ = Describe("Reserved metadata keys", func() {
+	It("detects the keys with a prefix reserved to the operator", func() {
+		Expect(IsReservedMetadataKey(PodRoleLabelName)).To(BeTrue())
+		Expect(IsReservedMetadataKey(OperatorVersionAnnotationName)).To(BeTrue())
+		Expect(IsReservedMetadataKey("postgresql.cnpg.io/test")).To(BeTrue())
+	})
+
+	It("accepts the other keys", func() {
+		Expect(IsReservedMetadataKey("app")).To(BeFalse())
+		Expect(IsReservedMetadataKey("example.com/cnpg.io")).To(BeFalse())
+		Expect(IsReservedMetadataKey("notcnpg.io/test")).To(BeFalse())
+		Expect(IsReservedMetadataKey(AppArmorAnnotationPrefix + "/postgres")).To(BeFalse())
+	})
+})
@@ -116,15 +116,21 @@ func ListStatusPods(podList []corev1.Pod) map[PodStatus][]string {
 	podsNames := make(map[PodStatus][]string)
 
 	for _, pod := range podList {
-		switch {
-		case IsPodReady(pod):
-			podsNames[PodHealthy] = append(podsNames[PodHealthy], pod.Name)
-		case IsPodActive(pod):
-			podsNames[PodReplicating] = append(podsNames[PodReplicating], pod.Name)
-		default:
-			podsNames[PodFailed] = append(podsNames[PodFailed], pod.Name)
-		}
+		status := GetPodStatus(pod)
+		podsNames[status] = append(podsNames[status], pod.Name)
 	}
 
 	return podsNames
 }
+
+// GetPodStatus returns the status of a Pod
+func GetPodStatus(pod corev1.Pod) PodStatus {
+	switch {
+	case IsPodReady(pod):
+		return PodHealthy
+	case IsPodActive(pod):
+		return PodReplicating
+	default:
+		return PodFailed
+	}
+}
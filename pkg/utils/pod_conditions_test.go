@@ -134,6 +134,42 @@ var _ = Describe("Pod conditions test suite", func() {
 		})
 	})
 
+	Describe("Must compute the status of a Pod", func() {
+		It("reports ready Pods as healthy", func() {
+			pod := corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+					Conditions: []corev1.PodCondition{
+						{
+							Type:   corev1.ContainersReady,
+							Status: corev1.ConditionTrue,
+						},
+					},
+				},
+			}
+			Expect(GetPodStatus(pod)).To(BeEquivalentTo(PodHealthy))
+		})
+
+		It("reports running Pods which are not ready as replicating", func() {
+			pod := corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase: corev1.PodRunning,
+				},
+			}
+			Expect(GetPodStatus(pod)).To(BeEquivalentTo(PodReplicating))
+		})
+
+		It("reports evicted Pods as failed", func() {
+			pod := corev1.Pod{
+				Status: corev1.PodStatus{
+					Phase:  corev1.PodFailed,
+					Reason: PodReasonEvicted,
+				},
+			}
+			Expect(GetPodStatus(pod)).To(BeEquivalentTo(PodFailed))
+		})
+	})
+
 	Describe("Must detect if a pod has been evicted or not", func() {
 		pod := corev1.Pod{
 			Status: corev1.PodStatus{
@@ -0,0 +1,50 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+
+	"github.com/cloudnative-pg/cloudnative-pg/pkg/stringset"
+)
+
+// ErrorReplayPausedInstancesSyntax is emitted when the replayPausedInstances
+// annotation have an invalid syntax
+var ErrorReplayPausedInstancesSyntax = errors.New("replayPausedInstances annotation has invalid syntax")
+
+// ReplayPausedInstancesAnnotation is the annotation to be used to pause the WAL
+// replay on a set of replicas, the value should be a JSON list of the instances,
+// e.g. `["cluster-example-2","cluster-example-3"]`
+const ReplayPausedInstancesAnnotation = "cnpg.io/replayPausedInstances"
+
+// GetReplayPausedInstances gets the set of replicas whose WAL replay
+// should be paused from the annotations
+func GetReplayPausedInstances(annotations map[string]string) (*stringset.Data, error) {
+	replayPausedInstances, ok := annotations[ReplayPausedInstancesAnnotation]
+	if !ok {
+		return stringset.New(), nil
+	}
+
+	var replayPausedInstancesList []string
+	err := json.Unmarshal([]byte(replayPausedInstances), &replayPausedInstancesList)
+	if err != nil {
+		return nil, ErrorReplayPausedInstancesSyntax
+	}
+
+	return stringset.From(replayPausedInstancesList), nil
+}
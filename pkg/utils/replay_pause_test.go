@@ -0,0 +1,47 @@
+/*
+Copyright The CloudNativePG Contributors
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Replay paused instances annotation handling", func() {
+	It("returns an empty set when the annotation is not present", func() {
+		instances, err := GetReplayPausedInstances(map[string]string{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instances.Len()).To(Equal(0))
+	})
+
+	It("parses the list of instances", func() {
+		instances, err := GetReplayPausedInstances(map[string]string{
+			ReplayPausedInstancesAnnotation: `["cluster-example-2","cluster-example-3"]`,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(instances.Has("cluster-example-2")).To(BeTrue())
+		Expect(instances.Has("cluster-example-3")).To(BeTrue())
+		Expect(instances.Has("cluster-example-1")).To(BeFalse())
+	})
+
+	It("complains when the annotation has an invalid syntax", func() {
+		_, err := GetReplayPausedInstances(map[string]string{
+			ReplayPausedInstancesAnnotation: "cluster-example-2",
+		})
+		Expect(err).To(Equal(ErrorReplayPausedInstancesSyntax))
+	})
+})